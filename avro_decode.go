@@ -0,0 +1,502 @@
+package gopherframe
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
+	"github.com/apache/arrow-go/v18/arrow/decimal256"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// errAvroTruncated is returned when a block ends in the middle of a value.
+var errAvroTruncated = errors.New("unexpected end of Avro block")
+
+// avroDecoder reads binary-encoded Avro values from a block.
+type avroDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *avroDecoder) long() (int64, error) {
+	var val uint64
+	for shift := uint(0); shift < 64; shift += 7 {
+		if d.pos >= len(d.data) {
+			return 0, errAvroTruncated
+		}
+		b := d.data[d.pos]
+		d.pos++
+		val |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			// Zigzag decode
+			return int64(val>>1) ^ -int64(val&1), nil
+		}
+	}
+	return 0, fmt.Errorf("avro varint is too long")
+}
+
+func (d *avroDecoder) fixed(n int) ([]byte, error) {
+	if n < 0 || n > len(d.data)-d.pos {
+		return nil, errAvroTruncated
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+func (d *avroDecoder) bytes() ([]byte, error) {
+	n, err := d.long()
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 || n > int64(len(d.data)-d.pos) {
+		return nil, errAvroTruncated
+	}
+	return d.fixed(int(n))
+}
+
+// number reads an int, long, float or double as a float64, or an int or long
+// as an int64.
+func (d *avroDecoder) number(kind string) (int64, float64, error) {
+	switch kind {
+	case "float":
+		b, err := d.fixed(4)
+		if err != nil {
+			return 0, 0, err
+		}
+		return 0, float64(math.Float32frombits(binary.LittleEndian.Uint32(b))), nil
+	case "double":
+		b, err := d.fixed(8)
+		if err != nil {
+			return 0, 0, err
+		}
+		return 0, math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+	default:
+		n, err := d.long()
+		return n, float64(n), err
+	}
+}
+
+// blocks reads the blocks of an array or map, calling item for each item.
+func (d *avroDecoder) blocks(item func() error) error {
+	for {
+		count, err := d.long()
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return nil
+		}
+		if count < 0 {
+			// A negative count is followed by the block size in bytes
+			count = -count
+			if _, err := d.long(); err != nil {
+				return err
+			}
+		}
+		for i := int64(0); i < count; i++ {
+			if err := item(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// skip reads past a value of type t.
+func (d *avroDecoder) skip(t *avroType) error {
+	var err error
+	switch t.kind {
+	case "null":
+	case "boolean":
+		_, err = d.fixed(1)
+	case "int", "long", "enum":
+		_, err = d.long()
+	case "float":
+		_, err = d.fixed(4)
+	case "double":
+		_, err = d.fixed(8)
+	case "bytes", "string":
+		_, err = d.bytes()
+	case "fixed":
+		_, err = d.fixed(t.size)
+	case "array":
+		err = d.blocks(func() error { return d.skip(t.items) })
+	case "map":
+		err = d.blocks(func() error {
+			if _, err := d.bytes(); err != nil {
+				return err
+			}
+			return d.skip(t.items)
+		})
+	case "record":
+		for _, f := range t.fields {
+			if err = d.skip(f.typ); err != nil {
+				break
+			}
+		}
+	case "union":
+		var branch *avroType
+		if branch, err = d.branch(t); err == nil {
+			err = d.skip(branch)
+		}
+	}
+	return err
+}
+
+// branch reads the branch index of a union value.
+func (d *avroDecoder) branch(t *avroType) (*avroType, error) {
+	idx, err := d.long()
+	if err != nil {
+		return nil, err
+	}
+	if idx < 0 || idx >= int64(len(t.branches)) {
+		return nil, fmt.Errorf("avro union branch %d out of range", idx)
+	}
+	return t.branches[idx], nil
+}
+
+// avroDecodeFunc decodes one value and appends it to a builder of the
+// reader type's Arrow type.
+type avroDecodeFunc func(d *avroDecoder, b array.Builder) error
+
+// compileAvroDecoder returns a function that decodes values written with
+// schema w and appends them as values of reader schema r, following the
+// Avro schema resolution rules: record fields are matched by name or alias,
+// writer fields missing from the reader are skipped, reader fields missing
+// from the writer take their default, and numbers are promoted to wider
+// types.
+func compileAvroDecoder(w, r *avroType) (avroDecodeFunc, error) {
+	if w.kind == "union" {
+		branches := make([]avroDecodeFunc, len(w.branches))
+		for i, branch := range w.branches {
+			decode, err := compileAvroDecoder(branch, r)
+			if err != nil {
+				// Only an error if the data uses the branch
+				decode = func(*avroDecoder, array.Builder) error { return err }
+			}
+			branches[i] = decode
+		}
+		return func(d *avroDecoder, b array.Builder) error {
+			idx, err := d.long()
+			if err != nil {
+				return err
+			}
+			if idx < 0 || idx >= int64(len(branches)) {
+				return fmt.Errorf("avro union branch %d out of range", idx)
+			}
+			return branches[idx](d, b)
+		}, nil
+	}
+
+	if r.kind == "union" {
+		value, nullable, err := avroNonNull(r)
+		if err != nil {
+			return nil, err
+		}
+		if w.kind == "null" {
+			if !nullable {
+				return nil, fmt.Errorf("cannot read null as %s", value.kind)
+			}
+			return func(d *avroDecoder, b array.Builder) error {
+				b.AppendNull()
+				return nil
+			}, nil
+		}
+		return compileAvroDecoder(w, value)
+	}
+
+	if !avroResolves(w, r) {
+		return nil, fmt.Errorf("cannot read Avro %s as %s", avroTypeName(w), avroTypeName(r))
+	}
+
+	switch r.kind {
+	case "null":
+		return func(d *avroDecoder, b array.Builder) error {
+			b.AppendNull()
+			return nil
+		}, nil
+	case "boolean":
+		return func(d *avroDecoder, b array.Builder) error {
+			v, err := d.fixed(1)
+			if err != nil {
+				return err
+			}
+			b.(*array.BooleanBuilder).Append(v[0] != 0)
+			return nil
+		}, nil
+	case "int", "long":
+		return func(d *avroDecoder, b array.Builder) error {
+			n, _, err := d.number(w.kind)
+			if err != nil {
+				return err
+			}
+			appendAvroInteger(b, n)
+			return nil
+		}, nil
+	case "float", "double":
+		return func(d *avroDecoder, b array.Builder) error {
+			_, f, err := d.number(w.kind)
+			if err != nil {
+				return err
+			}
+			if fb, ok := b.(*array.Float32Builder); ok {
+				fb.Append(float32(f))
+			} else {
+				b.(*array.Float64Builder).Append(f)
+			}
+			return nil
+		}, nil
+	case "string", "bytes", "fixed":
+		return func(d *avroDecoder, b array.Builder) error {
+			var v []byte
+			var err error
+			if w.kind == "fixed" {
+				v, err = d.fixed(w.size)
+			} else {
+				v, err = d.bytes()
+			}
+			if err != nil {
+				return err
+			}
+			appendAvroBinary(b, v, r)
+			return nil
+		}, nil
+	case "enum":
+		return compileAvroEnumDecoder(w, r), nil
+	case "array":
+		items, err := compileAvroDecoder(w.items, r.items)
+		if err != nil {
+			return nil, err
+		}
+		return func(d *avroDecoder, b array.Builder) error {
+			lb := b.(*array.ListBuilder)
+			lb.Append(true)
+			values := lb.ValueBuilder()
+			return d.blocks(func() error { return items(d, values) })
+		}, nil
+	case "map":
+		values, err := compileAvroDecoder(w.items, r.items)
+		if err != nil {
+			return nil, err
+		}
+		return func(d *avroDecoder, b array.Builder) error {
+			mb := b.(*array.MapBuilder)
+			mb.Append(true)
+			keys, items := mb.KeyBuilder().(*array.StringBuilder), mb.ItemBuilder()
+			return d.blocks(func() error {
+				key, err := d.bytes()
+				if err != nil {
+					return err
+				}
+				keys.Append(string(key))
+				return values(d, items)
+			})
+		}, nil
+	case "record":
+		return compileAvroRecordDecoder(w, r)
+	default:
+		return nil, fmt.Errorf("unsupported Avro type: %s", r.kind)
+	}
+}
+
+// avroResolves reports whether data written as w can be read as r, where
+// neither is a union.
+func avroResolves(w, r *avroType) bool {
+	switch r.kind {
+	case "int":
+		return w.kind == "int"
+	case "long":
+		return w.kind == "int" || w.kind == "long"
+	case "float":
+		return w.kind == "int" || w.kind == "long" || w.kind == "float"
+	case "double":
+		return w.kind == "int" || w.kind == "long" || w.kind == "float" || w.kind == "double"
+	case "string", "bytes":
+		return w.kind == "string" || w.kind == "bytes"
+	case "fixed":
+		return w.kind == "fixed" && w.size == r.size
+	default:
+		return w.kind == r.kind
+	}
+}
+
+// avroTypeName returns the name of a type for error messages.
+func avroTypeName(t *avroType) string {
+	if t.name != "" {
+		return t.name
+	}
+	return t.kind
+}
+
+// compileAvroEnumDecoder maps writer symbols to reader symbols, using the
+// reader's default for symbols it does not have.
+func compileAvroEnumDecoder(w, r *avroType) avroDecodeFunc {
+	symbols := make([]string, len(w.symbols))
+	for i, symbol := range w.symbols {
+		if slices.Contains(r.symbols, symbol) {
+			symbols[i] = symbol
+		} else {
+			symbols[i] = r.enumDefault
+		}
+	}
+	return func(d *avroDecoder, b array.Builder) error {
+		idx, err := d.long()
+		if err != nil {
+			return err
+		}
+		if idx < 0 || idx >= int64(len(symbols)) {
+			return fmt.Errorf("avro enum index %d out of range", idx)
+		}
+		if symbols[idx] == "" {
+			return fmt.Errorf("enum symbol %s is not in the reader schema", w.symbols[idx])
+		}
+		db := b.(*array.BinaryDictionaryBuilder)
+		if db.DictionarySize() == 0 {
+			// Keep the dictionary in symbol order, so indices match the schema
+			if err := insertAvroSymbols(db, r.symbols); err != nil {
+				return err
+			}
+		}
+		return db.AppendString(symbols[idx])
+	}
+}
+
+func insertAvroSymbols(db *array.BinaryDictionaryBuilder, symbols []string) error {
+	sb := array.NewStringBuilder(memory.NewGoAllocator())
+	defer sb.Release()
+	sb.AppendValues(symbols, nil)
+	arr := sb.NewStringArray()
+	defer arr.Release()
+	return db.InsertStringDictValues(arr)
+}
+
+// avroFieldDefault is a reader field missing from the writer schema.
+type avroFieldDefault struct {
+	index  int
+	data   []byte
+	decode avroDecodeFunc
+}
+
+// compileAvroRecordDecoder reads the writer's fields in order into the
+// matching reader fields, then fills the remaining reader fields with their
+// defaults.
+func compileAvroRecordDecoder(w, r *avroType) (avroDecodeFunc, error) {
+	type step struct {
+		index  int // reader field, or -1 to skip
+		skip   *avroType
+		decode avroDecodeFunc
+	}
+	steps := make([]step, len(w.fields))
+	matched := make([]bool, len(r.fields))
+	for i, wf := range w.fields {
+		steps[i] = step{index: -1, skip: wf.typ}
+		for j, rf := range r.fields {
+			if rf.name != wf.name && !slices.Contains(rf.aliases, wf.name) {
+				continue
+			}
+			decode, err := compileAvroDecoder(wf.typ, rf.typ)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", rf.name, err)
+			}
+			steps[i] = step{index: j, decode: decode}
+			matched[j] = true
+			break
+		}
+	}
+
+	var defaults []avroFieldDefault
+	for j, rf := range r.fields {
+		if matched[j] {
+			continue
+		}
+		if rf.def == nil {
+			return nil, fmt.Errorf("field %s is not in the writer schema and has no default", rf.name)
+		}
+		data, err := encodeAvroDefault(rf.typ, rf.def)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", rf.name, err)
+		}
+		decode, err := compileAvroDecoder(rf.typ, rf.typ)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", rf.name, err)
+		}
+		defaults = append(defaults, avroFieldDefault{index: j, data: data, decode: decode})
+	}
+
+	return func(d *avroDecoder, b array.Builder) error {
+		sb := b.(*array.StructBuilder)
+		sb.Append(true)
+		for _, s := range steps {
+			if s.index < 0 {
+				if err := d.skip(s.skip); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := s.decode(d, sb.FieldBuilder(s.index)); err != nil {
+				return err
+			}
+		}
+		for _, def := range defaults {
+			if err := def.decode(&avroDecoder{data: def.data}, sb.FieldBuilder(def.index)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, nil
+}
+
+// appendAvroInteger appends an int or long to a builder of its Arrow type.
+func appendAvroInteger(b array.Builder, n int64) {
+	switch b := b.(type) {
+	case *array.Int32Builder:
+		b.Append(int32(n))
+	case *array.Int64Builder:
+		b.Append(n)
+	case *array.Date32Builder:
+		b.Append(arrow.Date32(n))
+	case *array.Time32Builder:
+		b.Append(arrow.Time32(n))
+	case *array.Time64Builder:
+		b.Append(arrow.Time64(n))
+	case *array.TimestampBuilder:
+		b.Append(arrow.Timestamp(n))
+	}
+}
+
+// appendAvroBinary appends a string, bytes or fixed value to a builder of the
+// Arrow type of reader type r.
+func appendAvroBinary(b array.Builder, v []byte, r *avroType) {
+	switch b := b.(type) {
+	case *array.StringBuilder:
+		if r.kind == "fixed" {
+			// A fixed uuid holds the 16 bytes of the UUID
+			b.Append(fmt.Sprintf("%x-%x-%x-%x-%x", v[0:4], v[4:6], v[6:8], v[8:10], v[10:16]))
+		} else {
+			b.Append(string(v))
+		}
+	case *array.BinaryBuilder:
+		b.Append(v)
+	case *array.FixedSizeBinaryBuilder:
+		b.Append(v)
+	case *array.Decimal128Builder:
+		b.Append(decimal128.FromBigInt(avroBigInt(v)))
+	case *array.Decimal256Builder:
+		b.Append(decimal256.FromBigInt(avroBigInt(v)))
+	}
+}
+
+// avroBigInt decodes a big-endian two's complement integer.
+func avroBigInt(b []byte) *big.Int {
+	v := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		v.Sub(v, new(big.Int).Lsh(big.NewInt(1), uint(8*len(b))))
+	}
+	return v
+}
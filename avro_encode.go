@@ -0,0 +1,305 @@
+package gopherframe
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"slices"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+// avroEncodeFunc appends the binary encoding of row i of a column.
+type avroEncodeFunc func(buf []byte, i int) []byte
+
+func appendAvroLong(buf []byte, n int64) []byte {
+	// Zigzag encode
+	return binary.AppendUvarint(buf, uint64((n<<1)^(n>>63)))
+}
+
+func appendAvroBytes(buf []byte, b []byte) []byte {
+	return append(appendAvroLong(buf, int64(len(b))), b...)
+}
+
+func appendAvroFloat(buf []byte, f float32) []byte {
+	return binary.LittleEndian.AppendUint32(buf, math.Float32bits(f))
+}
+
+func appendAvroDouble(buf []byte, f float64) []byte {
+	return binary.LittleEndian.AppendUint64(buf, math.Float64bits(f))
+}
+
+// appendAvroDecimal appends the big-endian two's complement encoding of v.
+func appendAvroDecimal(buf []byte, v *big.Int) []byte {
+	if v.Sign() >= 0 {
+		b := v.Bytes()
+		if len(b) == 0 || b[0]&0x80 != 0 {
+			b = append([]byte{0}, b...)
+		}
+		return appendAvroBytes(buf, b)
+	}
+	// 2^(8n) + v in the fewest bytes n that leave room for the sign bit
+	n := new(big.Int).Not(v).BitLen()/8 + 1
+	t := new(big.Int).Lsh(big.NewInt(1), uint(8*n))
+	return appendAvroBytes(buf, t.Add(t, v).Bytes())
+}
+
+// avroNames gives the records, enums and fixed types of a written schema
+// unique names derived from their column path.
+type avroNames struct {
+	used map[string]bool
+}
+
+var avroInvalidNameChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+func (n *avroNames) name(path string) string {
+	name := avroInvalidNameChars.ReplaceAllString(path, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	unique := name
+	for i := 2; n.used[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	n.used[unique] = true
+	return unique
+}
+
+// buildAvroWriter returns the Avro schema of a record and an encoder for each
+// of its columns.
+func buildAvroWriter(record arrow.Record) (map[string]interface{}, []avroEncodeFunc, error) {
+	names := &avroNames{used: map[string]bool{"GopherFrameRecord": true}}
+	fields := make([]interface{}, record.NumCols())
+	encoders := make([]avroEncodeFunc, record.NumCols())
+	for i, field := range record.Schema().Fields() {
+		schema, encode, err := avroFieldEncoder(field, record.Column(i), field.Name, names)
+		if err != nil {
+			return nil, nil, fmt.Errorf("column %s: %w", field.Name, err)
+		}
+		fields[i] = map[string]interface{}{"name": field.Name, "type": schema}
+		encoders[i] = encode
+	}
+	schema := map[string]interface{}{"type": "record", "name": "GopherFrameRecord", "fields": fields}
+	return schema, encoders, nil
+}
+
+// avroFieldEncoder returns the Avro schema and encoder of a column, as a
+// union with null if the field is nullable or the column has nulls.
+func avroFieldEncoder(field arrow.Field, arr arrow.Array, path string, names *avroNames) (interface{}, avroEncodeFunc, error) {
+	schema, encode, err := avroValueEncoder(arr, path, names)
+	if err != nil {
+		return nil, nil, err
+	}
+	if arr.DataType().ID() == arrow.NULL || (!field.Nullable && arr.NullN() == 0) {
+		return schema, encode, nil
+	}
+	return []interface{}{"null", schema}, func(buf []byte, i int) []byte {
+		if arr.IsNull(i) {
+			return appendAvroLong(buf, 0)
+		}
+		return encode(appendAvroLong(buf, 1), i)
+	}, nil
+}
+
+// avroValueEncoder returns the Avro schema and encoder of the non-null values
+// of a column.
+func avroValueEncoder(arr arrow.Array, path string, names *avroNames) (interface{}, avroEncodeFunc, error) {
+	logical := func(kind, logicalType string) map[string]interface{} {
+		return map[string]interface{}{"type": kind, "logicalType": logicalType}
+	}
+
+	switch a := arr.(type) {
+	case *array.Null:
+		return "null", func(buf []byte, i int) []byte { return buf }, nil
+	case *array.Boolean:
+		return "boolean", func(buf []byte, i int) []byte {
+			if a.Value(i) {
+				return append(buf, 1)
+			}
+			return append(buf, 0)
+		}, nil
+	case *array.Int8:
+		return "int", func(buf []byte, i int) []byte { return appendAvroLong(buf, int64(a.Value(i))) }, nil
+	case *array.Int16:
+		return "int", func(buf []byte, i int) []byte { return appendAvroLong(buf, int64(a.Value(i))) }, nil
+	case *array.Int32:
+		return "int", func(buf []byte, i int) []byte { return appendAvroLong(buf, int64(a.Value(i))) }, nil
+	case *array.Int64:
+		return "long", func(buf []byte, i int) []byte { return appendAvroLong(buf, a.Value(i)) }, nil
+	case *array.Uint8:
+		return "int", func(buf []byte, i int) []byte { return appendAvroLong(buf, int64(a.Value(i))) }, nil
+	case *array.Uint16:
+		return "int", func(buf []byte, i int) []byte { return appendAvroLong(buf, int64(a.Value(i))) }, nil
+	case *array.Uint32:
+		return "long", func(buf []byte, i int) []byte { return appendAvroLong(buf, int64(a.Value(i))) }, nil
+	case *array.Uint64:
+		// Avro has no unsigned types; values above math.MaxInt64 wrap
+		return "long", func(buf []byte, i int) []byte { return appendAvroLong(buf, int64(a.Value(i))) }, nil
+	case *array.Float16:
+		return "float", func(buf []byte, i int) []byte { return appendAvroFloat(buf, a.Value(i).Float32()) }, nil
+	case *array.Float32:
+		return "float", func(buf []byte, i int) []byte { return appendAvroFloat(buf, a.Value(i)) }, nil
+	case *array.Float64:
+		return "double", func(buf []byte, i int) []byte { return appendAvroDouble(buf, a.Value(i)) }, nil
+	case *array.String:
+		return "string", func(buf []byte, i int) []byte { return appendAvroBytes(buf, []byte(a.Value(i))) }, nil
+	case *array.LargeString:
+		return "string", func(buf []byte, i int) []byte { return appendAvroBytes(buf, []byte(a.Value(i))) }, nil
+	case *array.Binary:
+		return "bytes", func(buf []byte, i int) []byte { return appendAvroBytes(buf, a.Value(i)) }, nil
+	case *array.LargeBinary:
+		return "bytes", func(buf []byte, i int) []byte { return appendAvroBytes(buf, a.Value(i)) }, nil
+	case *array.FixedSizeBinary:
+		schema := map[string]interface{}{"type": "fixed", "name": names.name(path), "size": a.DataType().(*arrow.FixedSizeBinaryType).ByteWidth}
+		return schema, func(buf []byte, i int) []byte { return append(buf, a.Value(i)...) }, nil
+	case *array.Date32:
+		return logical("int", "date"), func(buf []byte, i int) []byte { return appendAvroLong(buf, int64(a.Value(i))) }, nil
+	case *array.Date64:
+		return logical("int", "date"), func(buf []byte, i int) []byte {
+			return appendAvroLong(buf, int64(a.Value(i))/(24*60*60*1000))
+		}, nil
+	case *array.Time32:
+		scale := int64(1)
+		if a.DataType().(*arrow.Time32Type).Unit == arrow.Second {
+			scale = 1000
+		}
+		return logical("int", "time-millis"), func(buf []byte, i int) []byte { return appendAvroLong(buf, int64(a.Value(i))*scale) }, nil
+	case *array.Time64:
+		divisor := int64(1)
+		if a.DataType().(*arrow.Time64Type).Unit == arrow.Nanosecond {
+			divisor = 1000
+		}
+		return logical("long", "time-micros"), func(buf []byte, i int) []byte { return appendAvroLong(buf, int64(a.Value(i))/divisor) }, nil
+	case *array.Timestamp:
+		dt := a.DataType().(*arrow.TimestampType)
+		logicalType, scale := "timestamp-millis", int64(1)
+		switch dt.Unit {
+		case arrow.Second:
+			scale = 1000
+		case arrow.Microsecond:
+			logicalType = "timestamp-micros"
+		case arrow.Nanosecond:
+			logicalType = "timestamp-nanos"
+		}
+		if dt.TimeZone == "" {
+			logicalType = "local-" + logicalType
+		}
+		return logical("long", logicalType), func(buf []byte, i int) []byte { return appendAvroLong(buf, int64(a.Value(i))*scale) }, nil
+	case *array.Decimal128:
+		dt := a.DataType().(*arrow.Decimal128Type)
+		schema := logical("bytes", "decimal")
+		schema["precision"], schema["scale"] = dt.Precision, dt.Scale
+		return schema, func(buf []byte, i int) []byte { return appendAvroDecimal(buf, a.Value(i).BigInt()) }, nil
+	case *array.Decimal256:
+		dt := a.DataType().(*arrow.Decimal256Type)
+		schema := logical("bytes", "decimal")
+		schema["precision"], schema["scale"] = dt.Precision, dt.Scale
+		return schema, func(buf []byte, i int) []byte { return appendAvroDecimal(buf, a.Value(i).BigInt()) }, nil
+	case *array.Dictionary:
+		return avroDictionaryEncoder(a, path, names)
+	case *array.Struct:
+		return avroStructEncoder(a, path, names)
+	case *array.Map:
+		// A map is also a list, so it is matched first
+		if a.Keys().DataType().ID() != arrow.STRING {
+			return nil, nil, fmt.Errorf("map keys must be strings to write as Avro, got %s", a.Keys().DataType())
+		}
+		keys := a.Keys().(*array.String)
+		itemField := a.DataType().(*arrow.MapType).ItemField()
+		itemSchema, encodeItem, err := avroFieldEncoder(itemField, a.Items(), path, names)
+		if err != nil {
+			return nil, nil, err
+		}
+		schema := map[string]interface{}{"type": "map", "values": itemSchema}
+		return schema, func(buf []byte, i int) []byte {
+			start, end := a.ValueOffsets(i)
+			if end > start {
+				buf = appendAvroLong(buf, end-start)
+				for j := int(start); j < int(end); j++ {
+					buf = appendAvroBytes(buf, []byte(keys.Value(j)))
+					buf = encodeItem(buf, j)
+				}
+			}
+			return appendAvroLong(buf, 0)
+		}, nil
+	case array.ListLike:
+		elemField := a.DataType().(arrow.ListLikeType).ElemField()
+		itemSchema, encodeItem, err := avroFieldEncoder(elemField, a.ListValues(), path, names)
+		if err != nil {
+			return nil, nil, err
+		}
+		schema := map[string]interface{}{"type": "array", "items": itemSchema}
+		return schema, func(buf []byte, i int) []byte {
+			start, end := a.ValueOffsets(i)
+			if end > start {
+				buf = appendAvroLong(buf, end-start)
+				for j := int(start); j < int(end); j++ {
+					buf = encodeItem(buf, j)
+				}
+			}
+			return appendAvroLong(buf, 0)
+		}, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported column type for Avro: %s", arr.DataType())
+	}
+}
+
+// avroStructEncoder writes a struct column as an Avro record.
+func avroStructEncoder(a *array.Struct, path string, names *avroNames) (interface{}, avroEncodeFunc, error) {
+	structType := a.DataType().(*arrow.StructType)
+	name := names.name(path)
+	fields := make([]interface{}, a.NumField())
+	encoders := make([]avroEncodeFunc, a.NumField())
+	for j, field := range structType.Fields() {
+		schema, encode, err := avroFieldEncoder(field, a.Field(j), path+"_"+field.Name, names)
+		if err != nil {
+			return nil, nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		fields[j] = map[string]interface{}{"name": field.Name, "type": schema}
+		encoders[j] = encode
+	}
+	schema := map[string]interface{}{"type": "record", "name": name, "fields": fields}
+	return schema, func(buf []byte, i int) []byte {
+		for _, encode := range encoders {
+			buf = encode(buf, i)
+		}
+		return buf
+	}, nil
+}
+
+// avroDictionaryEncoder writes a dictionary of strings that are valid Avro
+// symbols as an enum, and other dictionaries as their values.
+func avroDictionaryEncoder(a *array.Dictionary, path string, names *avroNames) (interface{}, avroEncodeFunc, error) {
+	if symbols, ok := avroEnumSymbols(a.Dictionary()); ok {
+		schema := map[string]interface{}{"type": "enum", "name": names.name(path), "symbols": symbols}
+		return schema, func(buf []byte, i int) []byte { return appendAvroLong(buf, int64(a.GetValueIndex(i))) }, nil
+	}
+
+	dict := a.Dictionary()
+	valueField := arrow.Field{Type: dict.DataType(), Nullable: dict.NullN() > 0}
+	schema, encode, err := avroFieldEncoder(valueField, dict, path, names)
+	if err != nil {
+		return nil, nil, err
+	}
+	return schema, func(buf []byte, i int) []byte { return encode(buf, a.GetValueIndex(i)) }, nil
+}
+
+// avroEnumSymbols returns the values of a dictionary if they can be the
+// symbols of an Avro enum: unique, non-null strings that are valid names.
+func avroEnumSymbols(dict arrow.Array) ([]string, bool) {
+	values, ok := dict.(*array.String)
+	if !ok || values.Len() == 0 || values.NullN() > 0 {
+		return nil, false
+	}
+	symbols := make([]string, values.Len())
+	for i := range symbols {
+		symbols[i] = values.Value(i)
+		if !avroNamePattern.MatchString(symbols[i]) || slices.Contains(symbols[:i], symbols[i]) {
+			return nil, false
+		}
+	}
+	return symbols, true
+}
@@ -1,36 +1,92 @@
 package gopherframe
 
 import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/flate"
+	"crypto/rand"
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"hash/crc32"
 	"io"
-	"math"
 	"os"
+	"strings"
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	dsnetbzip2 "github.com/dsnet/compress/bzip2"
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
 )
 
 // Avro OCF magic bytes
 var avroMagic = []byte{'O', 'b', 'j', 1}
 
-// avroSchema represents a simplified Avro schema for reading.
-type avroSchema struct {
-	Type   string            `json:"type"`
-	Name   string            `json:"name,omitempty"`
-	Fields []avroSchemaField `json:"fields,omitempty"`
+// Avro block codecs.
+const (
+	AvroCodecNull    = "null"
+	AvroCodecDeflate = "deflate"
+	AvroCodecSnappy  = "snappy"
+	AvroCodecZstd    = "zstandard"
+	AvroCodecBzip2   = "bzip2"
+	AvroCodecXZ      = "xz"
+)
+
+// defaultAvroBlockSize is the uncompressed size at which a block is written.
+const defaultAvroBlockSize = 64 * 1024
+
+// AvroReadOptions configures reading Avro files.
+type AvroReadOptions struct {
+	// ReaderSchema is the Avro schema, as JSON, to read the data as. Fields
+	// are matched to the file's schema by name or alias; fields the file
+	// does not have take their default, fields the reader schema does not
+	// have are dropped, and numbers are promoted to wider types. Empty reads
+	// the data as written.
+	ReaderSchema string
 }
 
-type avroSchemaField struct {
-	Name string          `json:"name"`
-	Type json.RawMessage `json:"type"`
+// AvroWriteOptions configures writing Avro files.
+type AvroWriteOptions struct {
+	// Codec compresses each block: "null" (the default), "deflate",
+	// "snappy", "zstandard" (or "zstd"), "bzip2" or "xz".
+	Codec string
+	// BlockSize is the uncompressed size in bytes at which a block is
+	// written. The default is 64 KiB.
+	BlockSize int
 }
 
 // ReadAvro reads an Avro Object Container File into a DataFrame.
-// Supports primitive Avro types: null, boolean, int, long, float, double, string, bytes.
-// Union types like ["null", "string"] are supported for nullable columns.
+//
+// Records are read as struct columns, arrays as lists, maps as maps with
+// string keys, enums as dictionaries of their symbols, and fixed as
+// fixed-size binary. The logical types date, time-millis, time-micros,
+// timestamp-millis/micros/nanos and their local variants, decimal and uuid
+// are read as date32, time32, time64, timestamp (UTC, or without a time zone
+// for local timestamps), decimal128 (decimal256 above 38 digits) and string.
+// Unions of null and one other type are read as nullable columns. Blocks
+// compressed with the deflate, snappy, zstandard, bzip2 and xz codecs are
+// decompressed.
 func ReadAvro(filename string) (*DataFrame, error) {
+	return ReadAvroWithOptions(filename, AvroReadOptions{})
+}
+
+// ReadAvroWithOptions reads an Avro Object Container File into a DataFrame,
+// resolving the file's schema against opts.ReaderSchema.
+//
+// Example:
+//
+//	df, err := ReadAvroWithOptions("users.avro", AvroReadOptions{
+//	    ReaderSchema: `{"type": "record", "name": "User", "fields": [
+//	        {"name": "id", "type": "long"},
+//	        {"name": "country", "type": "string", "default": "unknown"}
+//	    ]}`,
+//	})
+func ReadAvroWithOptions(filename string, opts AvroReadOptions) (*DataFrame, error) {
 	if err := validateFilePath(filename); err != nil {
 		return nil, err
 	}
@@ -41,12 +97,21 @@ func ReadAvro(filename string) (*DataFrame, error) {
 	}
 	defer func() { _ = f.Close() }()
 
+	return ReadAvroFrom(f, opts)
+}
+
+// ReadAvroFrom reads an Avro Object Container File from r into a DataFrame.
+// It supports the same types and options as ReadAvroWithOptions.
+func ReadAvroFrom(r io.Reader, opts AvroReadOptions) (*DataFrame, error) {
+	// Varints are read a byte at a time
+	f := bufio.NewReader(r)
+
 	// Read magic
 	magic := make([]byte, 4)
 	if _, err := io.ReadFull(f, magic); err != nil {
 		return nil, fmt.Errorf("failed to read Avro magic: %w", err)
 	}
-	if string(magic) != string(avroMagic) {
+	if !bytes.Equal(magic, avroMagic) {
 		return nil, fmt.Errorf("not a valid Avro file (invalid magic bytes)")
 	}
 
@@ -56,20 +121,40 @@ func ReadAvro(filename string) (*DataFrame, error) {
 		return nil, fmt.Errorf("failed to read Avro metadata: %w", err)
 	}
 
-	// Parse schema
+	// Parse schemas
 	schemaJSON, ok := meta["avro.schema"]
 	if !ok {
 		return nil, fmt.Errorf("avro file missing schema in metadata")
 	}
-
-	var schema avroSchema
-	if err := json.Unmarshal([]byte(schemaJSON), &schema); err != nil {
+	writerSchema, err := parseAvroSchema(schemaJSON)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse Avro schema: %w", err)
 	}
+	readerSchema := writerSchema
+	if opts.ReaderSchema != "" {
+		if readerSchema, err = parseAvroSchema(opts.ReaderSchema); err != nil {
+			return nil, fmt.Errorf("failed to parse Avro reader schema: %w", err)
+		}
+	}
+	for _, schema := range []*avroType{writerSchema, readerSchema} {
+		if schema.kind != "record" {
+			return nil, fmt.Errorf("avro schema type must be 'record', got %q", schema.kind)
+		}
+	}
+	dt, _, err := avroArrowType(readerSchema, make(map[*avroType]bool))
+	if err != nil {
+		return nil, fmt.Errorf("unsupported Avro schema: %w", err)
+	}
+	decode, err := compileAvroDecoder(writerSchema, readerSchema)
+	if err != nil {
+		return nil, fmt.Errorf("avro reader schema does not match the file: %w", err)
+	}
 
-	if schema.Type != "record" {
-		return nil, fmt.Errorf("avro schema type must be 'record', got %q", schema.Type)
+	codec, err := newAvroBlockCodec(meta["avro.codec"])
+	if err != nil {
+		return nil, err
 	}
+	defer codec.close()
 
 	// Read sync marker (16 bytes)
 	syncMarker := make([]byte, 16)
@@ -77,49 +162,79 @@ func ReadAvro(filename string) (*DataFrame, error) {
 		return nil, fmt.Errorf("failed to read sync marker: %w", err)
 	}
 
+	builder := array.NewStructBuilder(memory.NewGoAllocator(), dt.(*arrow.StructType))
+	defer builder.Release()
+
 	// Read data blocks
-	var allRows []map[string]interface{}
-	for {
-		// Read block count (varint)
+	for rows := int64(0); ; {
 		blockCount, err := readAvroLong(f)
-		if err != nil {
-			break // EOF
-		}
-		if blockCount <= 0 {
+		if errors.Is(err, io.EOF) {
 			break
 		}
-
-		// Read block size
+		if err != nil {
+			return nil, fmt.Errorf("failed to read block count: %w", err)
+		}
 		blockSize, err := readAvroLong(f)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read block size: %w", err)
 		}
+		if blockCount < 0 || blockSize < 0 {
+			return nil, fmt.Errorf("invalid Avro block header")
+		}
 
-		// Read block data
-		blockData := make([]byte, blockSize)
-		if _, err := io.ReadFull(f, blockData); err != nil {
-			return nil, fmt.Errorf("failed to read block data: %w", err)
+		// Read block data, without trusting the size to allocate
+		blockData, err := io.ReadAll(io.LimitReader(f, blockSize))
+		if err != nil || int64(len(blockData)) != blockSize {
+			return nil, fmt.Errorf("failed to read block data: %w", errors.Join(err, io.ErrUnexpectedEOF))
+		}
+		if blockData, err = codec.decompress(blockData); err != nil {
+			return nil, fmt.Errorf("failed to decompress Avro block: %w", err)
 		}
 
-		// Parse records from block
-		rows, err := parseAvroBlock(blockData, schema.Fields, int(blockCount))
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse Avro block: %w", err)
+		d := &avroDecoder{data: blockData}
+		for i := int64(0); i < blockCount; i++ {
+			if err := decode(d, builder); err != nil {
+				return nil, fmt.Errorf("failed to decode Avro record %d: %w", rows+i, err)
+			}
 		}
-		allRows = append(allRows, rows...)
+		rows += blockCount
 
-		// Read sync marker
 		sync := make([]byte, 16)
 		if _, err := io.ReadFull(f, sync); err != nil {
-			break
+			return nil, fmt.Errorf("failed to read sync marker: %w", err)
+		}
+		if !bytes.Equal(sync, syncMarker) {
+			return nil, fmt.Errorf("avro sync marker mismatch after record %d", rows)
 		}
 	}
 
-	return jsonRecordsToDataFrame(allRows)
+	rows := builder.NewStructArray()
+	defer rows.Release()
+	structType := dt.(*arrow.StructType)
+	columns := make([]arrow.Array, rows.NumField())
+	for i := range columns {
+		columns[i] = rows.Field(i)
+	}
+	record := array.NewRecord(arrow.NewSchema(structType.Fields(), nil), columns, int64(rows.Len()))
+	defer record.Release()
+	return NewDataFrame(record), nil
 }
 
-// WriteAvro writes a DataFrame to an Avro Object Container File.
+// WriteAvro writes a DataFrame to an Avro Object Container File without
+// compression. Struct, list, map, dictionary, decimal, date, time and
+// timestamp columns are written as the Avro types ReadAvro reads them from;
+// nullable columns are written as unions with null.
 func WriteAvro(df *DataFrame, filename string) error {
+	return WriteAvroWithOptions(df, filename, AvroWriteOptions{})
+}
+
+// WriteAvroWithOptions writes a DataFrame to an Avro Object Container File,
+// with blocks compressed by opts.Codec.
+//
+// Example:
+//
+//	err := WriteAvroWithOptions(df, "events.avro", AvroWriteOptions{Codec: AvroCodecZstd})
+func WriteAvroWithOptions(df *DataFrame, filename string, opts AvroWriteOptions) error {
 	if err := validateFilePath(filename); err != nil {
 		return err
 	}
@@ -131,18 +246,40 @@ func WriteAvro(df *DataFrame, filename string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create Avro file: %w", err)
 	}
-	defer func() { _ = f.Close() }()
+	if err := WriteAvroTo(df, f, opts); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
 
-	record := df.coreDF.Record()
-	schema := record.Schema()
+// WriteAvroTo writes a DataFrame to w as an Avro Object Container File.
+func WriteAvroTo(df *DataFrame, w io.Writer, opts AvroWriteOptions) error {
+	if df.err != nil {
+		return df.err
+	}
+	blockSize := opts.BlockSize
+	if blockSize <= 0 {
+		blockSize = defaultAvroBlockSize
+	}
+	codec, err := newAvroBlockCodec(opts.Codec)
+	if err != nil {
+		return err
+	}
+	defer codec.close()
 
-	// Build Avro schema
-	avroSch := buildAvroSchema(schema)
+	record := df.coreDF.Record()
+	avroSch, encoders, err := buildAvroWriter(record)
+	if err != nil {
+		return err
+	}
 	schemaJSON, err := json.Marshal(avroSch)
 	if err != nil {
 		return fmt.Errorf("failed to marshal Avro schema: %w", err)
 	}
 
+	f := bufio.NewWriter(w)
+
 	// Write magic
 	if _, err := f.Write(avroMagic); err != nil {
 		return err
@@ -151,7 +288,7 @@ func WriteAvro(df *DataFrame, filename string) error {
 	// Write metadata
 	meta := map[string]string{
 		"avro.schema": string(schemaJSON),
-		"avro.codec":  "null",
+		"avro.codec":  codec.name,
 	}
 	if err := writeAvroMap(f, meta); err != nil {
 		return err
@@ -159,83 +296,205 @@ func WriteAvro(df *DataFrame, filename string) error {
 
 	// Write sync marker
 	syncMarker := make([]byte, 16)
-	for i := range syncMarker {
-		syncMarker[i] = byte(i + 1)
+	if _, err := rand.Read(syncMarker); err != nil {
+		return fmt.Errorf("failed to generate sync marker: %w", err)
 	}
 	if _, err := f.Write(syncMarker); err != nil {
 		return err
 	}
 
-	// Write data as a single block
-	numRows := int(record.NumRows())
-	if numRows > 0 {
-		var blockBuf []byte
-		for i := 0; i < numRows; i++ {
-			for j, field := range schema.Fields() {
-				col := record.Column(j)
-				b := encodeAvroValue(col, i, field.Type)
-				blockBuf = append(blockBuf, b...)
+	var block []byte
+	blockRows := 0
+	writeBlock := func() error {
+		data, err := codec.compress(block)
+		if err != nil {
+			return fmt.Errorf("failed to compress Avro block: %w", err)
+		}
+		header := appendAvroLong(appendAvroLong(nil, int64(blockRows)), int64(len(data)))
+		for _, b := range [][]byte{header, data, syncMarker} {
+			if _, err := f.Write(b); err != nil {
+				return err
 			}
 		}
+		block, blockRows = block[:0], 0
+		return nil
+	}
 
-		// Write block count
-		writeAvroLong(f, int64(numRows))
-		// Write block size
-		writeAvroLong(f, int64(len(blockBuf)))
-		// Write block data
-		if _, err := f.Write(blockBuf); err != nil {
-			return err
+	for i := 0; i < int(record.NumRows()); i++ {
+		for _, encode := range encoders {
+			block = encode(block, i)
+		}
+		blockRows++
+		if len(block) >= blockSize {
+			if err := writeBlock(); err != nil {
+				return err
+			}
 		}
-		// Write sync marker
-		if _, err := f.Write(syncMarker); err != nil {
+	}
+	if blockRows > 0 {
+		if err := writeBlock(); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	return f.Flush()
 }
 
-// --- Avro encoding helpers ---
+// avroBlockCodec compresses and decompresses the blocks of a file.
+type avroBlockCodec struct {
+	name    string
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+}
+
+func newAvroBlockCodec(name string) (*avroBlockCodec, error) {
+	name = strings.ToLower(name)
+	switch name {
+	case "", AvroCodecNull:
+		return &avroBlockCodec{name: AvroCodecNull}, nil
+	case AvroCodecDeflate, AvroCodecSnappy, AvroCodecBzip2, AvroCodecXZ:
+		return &avroBlockCodec{name: name}, nil
+	case AvroCodecZstd, CompressionZstd:
+		return &avroBlockCodec{name: AvroCodecZstd}, nil
+	default:
+		return nil, fmt.Errorf("unsupported Avro codec: %s", name)
+	}
+}
 
-func readAvroLong(r io.Reader) (int64, error) {
+func (c *avroBlockCodec) compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch c.name {
+	case AvroCodecNull:
+		return data, nil
+	case AvroCodecSnappy:
+		// Snappy blocks are followed by the CRC32 of the uncompressed data
+		return binary.BigEndian.AppendUint32(snappy.Encode(nil, data), crc32.ChecksumIEEE(data)), nil
+	case AvroCodecZstd:
+		if c.encoder == nil {
+			encoder, err := zstd.NewWriter(nil)
+			if err != nil {
+				return nil, err
+			}
+			c.encoder = encoder
+		}
+		return c.encoder.EncodeAll(data, nil), nil
+	case AvroCodecDeflate:
+		w, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+	case AvroCodecBzip2:
+		writer, err := dsnetbzip2.NewWriter(&buf, nil)
+		if err != nil {
+			return nil, err
+		}
+		w = writer
+	case AvroCodecXZ:
+		writer, err := xz.NewWriter(&buf)
+		if err != nil {
+			return nil, err
+		}
+		w = writer
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (c *avroBlockCodec) decompress(data []byte) ([]byte, error) {
+	var r io.Reader
+	switch c.name {
+	case AvroCodecNull:
+		return data, nil
+	case AvroCodecSnappy:
+		if len(data) < 4 {
+			return nil, errAvroTruncated
+		}
+		decoded, err := snappy.Decode(nil, data[:len(data)-4])
+		if err != nil {
+			return nil, err
+		}
+		if crc32.ChecksumIEEE(decoded) != binary.BigEndian.Uint32(data[len(data)-4:]) {
+			return nil, fmt.Errorf("snappy block checksum mismatch")
+		}
+		return decoded, nil
+	case AvroCodecZstd:
+		if c.decoder == nil {
+			decoder, err := zstd.NewReader(nil)
+			if err != nil {
+				return nil, err
+			}
+			c.decoder = decoder
+		}
+		return c.decoder.DecodeAll(data, nil)
+	case AvroCodecDeflate:
+		fr := flate.NewReader(bytes.NewReader(data))
+		defer func() { _ = fr.Close() }()
+		r = fr
+	case AvroCodecBzip2:
+		r = bzip2.NewReader(bytes.NewReader(data))
+	case AvroCodecXZ:
+		xr, err := xz.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		r = xr
+	}
+	return io.ReadAll(r)
+}
+
+func (c *avroBlockCodec) close() {
+	if c.encoder != nil {
+		_ = c.encoder.Close()
+	}
+	if c.decoder != nil {
+		c.decoder.Close()
+	}
+}
+
+// --- Avro container helpers ---
+
+func readAvroLong(r io.ByteReader) (int64, error) {
 	var val uint64
-	var shift uint
-	for {
-		b := make([]byte, 1)
-		if _, err := io.ReadFull(r, b); err != nil {
+	for shift := uint(0); shift < 64; shift += 7 {
+		b, err := r.ReadByte()
+		if err != nil {
+			if shift > 0 && errors.Is(err, io.EOF) {
+				return 0, io.ErrUnexpectedEOF
+			}
 			return 0, err
 		}
-		val |= uint64(b[0]&0x7f) << shift
-		if b[0]&0x80 == 0 {
-			break
+		val |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			// Zigzag decode
+			return int64(val>>1) ^ -int64(val&1), nil
 		}
-		shift += 7
 	}
-	// Zigzag decode
-	return int64(val>>1) ^ -int64(val&1), nil
+	return 0, fmt.Errorf("avro varint is too long")
 }
 
 func writeAvroLong(w io.Writer, n int64) {
-	// Zigzag encode
-	val := uint64((n << 1) ^ (n >> 63))
-	var buf [10]byte
-	i := 0
-	for val > 0x7f {
-		buf[i] = byte(val&0x7f) | 0x80
-		val >>= 7
-		i++
-	}
-	buf[i] = byte(val)
-	_, _ = w.Write(buf[:i+1])
+	_, _ = w.Write(appendAvroLong(nil, n))
 }
 
-func readAvroMap(r io.Reader) (map[string]string, error) {
+func readAvroMap(r *bufio.Reader) (map[string]string, error) {
 	result := make(map[string]string)
-	count, err := readAvroLong(r)
-	if err != nil {
-		return nil, err
-	}
-	for count > 0 {
+	for {
+		count, err := readAvroLong(r)
+		if err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return result, nil
+		}
+		if count < 0 {
+			count = -count
+			if _, err := readAvroLong(r); err != nil {
+				return nil, err
+			}
+		}
 		for i := int64(0); i < count; i++ {
 			key, err := readAvroString(r)
 			if err != nil {
@@ -247,27 +506,22 @@ func readAvroMap(r io.Reader) (map[string]string, error) {
 			}
 			result[key] = val
 		}
-		count, err = readAvroLong(r)
-		if err != nil {
-			return nil, err
-		}
 	}
-	return result, nil
 }
 
 func writeAvroMap(w io.Writer, m map[string]string) error {
 	if len(m) > 0 {
 		writeAvroLong(w, int64(len(m)))
-		for k, v := range m {
+		for _, k := range sortedKeys(m) {
 			writeAvroString(w, k)
-			writeAvroString(w, v)
+			writeAvroString(w, m[k])
 		}
 	}
 	writeAvroLong(w, 0) // End of map
 	return nil
 }
 
-func readAvroString(r io.Reader) (string, error) {
+func readAvroString(r *bufio.Reader) (string, error) {
 	length, err := readAvroLong(r)
 	if err != nil {
 		return "", err
@@ -275,159 +529,17 @@ func readAvroString(r io.Reader) (string, error) {
 	if length <= 0 {
 		return "", nil
 	}
-	buf := make([]byte, length)
-	if _, err := io.ReadFull(r, buf); err != nil {
+	data, err := io.ReadAll(io.LimitReader(r, length))
+	if err != nil {
 		return "", err
 	}
-	return string(buf), nil
+	if int64(len(data)) != length {
+		return "", io.ErrUnexpectedEOF
+	}
+	return string(data), nil
 }
 
 func writeAvroString(w io.Writer, s string) {
 	writeAvroLong(w, int64(len(s)))
 	_, _ = w.Write([]byte(s))
 }
-
-func buildAvroSchema(schema *arrow.Schema) avroSchema {
-	avroSch := avroSchema{
-		Type: "record",
-		Name: "GopherFrameRecord",
-	}
-	for _, field := range schema.Fields() {
-		avroType := arrowToAvroType(field.Type)
-		raw, _ := json.Marshal(avroType)
-		avroSch.Fields = append(avroSch.Fields, avroSchemaField{
-			Name: field.Name,
-			Type: raw,
-		})
-	}
-	return avroSch
-}
-
-func arrowToAvroType(dt arrow.DataType) interface{} {
-	switch dt.ID() {
-	case arrow.FLOAT64:
-		return "double"
-	case arrow.INT64:
-		return "long"
-	case arrow.STRING:
-		return "string"
-	case arrow.BOOL:
-		return "boolean"
-	default:
-		return "string"
-	}
-}
-
-func encodeAvroValue(col arrow.Array, i int, dt arrow.DataType) []byte {
-	switch a := col.(type) {
-	case *array.Float64:
-		var buf [8]byte
-		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(a.Value(i)))
-		return buf[:]
-	case *array.Int64:
-		var buf [10]byte
-		val := a.Value(i)
-		n := binary.PutVarint(buf[:], val)
-		// Use zigzag encoding
-		uval := uint64((val << 1) ^ (val >> 63))
-		idx := 0
-		for uval > 0x7f {
-			buf[idx] = byte(uval&0x7f) | 0x80
-			uval >>= 7
-			idx++
-		}
-		buf[idx] = byte(uval)
-		_ = n
-		return buf[:idx+1]
-	case *array.String:
-		s := a.Value(i)
-		var buf []byte
-		// Length as varint
-		l := int64(len(s))
-		uval := uint64((l << 1) ^ (l >> 63))
-		for uval > 0x7f {
-			buf = append(buf, byte(uval&0x7f)|0x80)
-			uval >>= 7
-		}
-		buf = append(buf, byte(uval))
-		buf = append(buf, []byte(s)...)
-		return buf
-	case *array.Boolean:
-		if a.Value(i) {
-			return []byte{1}
-		}
-		return []byte{0}
-	default:
-		return nil
-	}
-}
-
-func parseAvroBlock(data []byte, fields []avroSchemaField, count int) ([]map[string]interface{}, error) {
-	rows := make([]map[string]interface{}, 0, count)
-	offset := 0
-	for i := 0; i < count && offset < len(data); i++ {
-		row := make(map[string]interface{})
-		for _, field := range fields {
-			var typeStr string
-			if err := json.Unmarshal(field.Type, &typeStr); err != nil {
-				// Could be union type, skip
-				typeStr = "string"
-			}
-			switch typeStr {
-			case "double":
-				if offset+8 > len(data) {
-					return rows, nil
-				}
-				bits := binary.LittleEndian.Uint64(data[offset : offset+8])
-				row[field.Name] = math.Float64frombits(bits)
-				offset += 8
-			case "long":
-				val, n := decodeAvroVarlong(data[offset:])
-				row[field.Name] = float64(val) // Store as float64 for consistency
-				offset += n
-			case "string":
-				length, n := decodeAvroVarlong(data[offset:])
-				offset += n
-				if offset+int(length) > len(data) {
-					return rows, nil
-				}
-				row[field.Name] = string(data[offset : offset+int(length)])
-				offset += int(length)
-			case "boolean":
-				if offset >= len(data) {
-					return rows, nil
-				}
-				row[field.Name] = data[offset] != 0
-				offset++
-			case "int", "float":
-				offset += 4 // Skip for now
-			default:
-				// Try to read as string
-				length, n := decodeAvroVarlong(data[offset:])
-				offset += n
-				if length > 0 && offset+int(length) <= len(data) {
-					row[field.Name] = string(data[offset : offset+int(length)])
-					offset += int(length)
-				}
-			}
-		}
-		rows = append(rows, row)
-	}
-	return rows, nil
-}
-
-func decodeAvroVarlong(data []byte) (int64, int) {
-	var val uint64
-	var shift uint
-	i := 0
-	for i < len(data) {
-		b := data[i]
-		val |= uint64(b&0x7f) << shift
-		i++
-		if b&0x80 == 0 {
-			break
-		}
-		shift += 7
-	}
-	return int64(val>>1) ^ -int64(val&1), i
-}
@@ -1,8 +1,10 @@
 package gopherframe
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/apache/arrow-go/v18/arrow"
@@ -153,3 +155,199 @@ func TestWriteReadAvro_BoolColumn(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, int64(3), df2.NumRows())
 }
+
+// avroTestRecord has a column of every kind of type WriteAvro maps to Avro.
+func avroTestRecord(t *testing.T) arrow.Record {
+	t.Helper()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int32},
+		{Name: "user", Type: arrow.StructOf(
+			arrow.Field{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+			arrow.Field{Name: "tags", Type: arrow.ListOf(arrow.BinaryTypes.String), Nullable: true},
+		), Nullable: true},
+		{Name: "attrs", Type: arrow.MapOf(arrow.BinaryTypes.String, arrow.PrimitiveTypes.Int64), Nullable: true},
+		{Name: "status", Type: &arrow.DictionaryType{IndexType: arrow.PrimitiveTypes.Int32, ValueType: arrow.BinaryTypes.String}, Nullable: true},
+		{Name: "amount", Type: &arrow.Decimal128Type{Precision: 10, Scale: 2}, Nullable: true},
+		{Name: "day", Type: arrow.FixedWidthTypes.Date32, Nullable: true},
+		{Name: "ts", Type: &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}, Nullable: true},
+		{Name: "local_ts", Type: &arrow.TimestampType{Unit: arrow.Millisecond}, Nullable: true},
+		{Name: "hash", Type: &arrow.FixedSizeBinaryType{ByteWidth: 4}, Nullable: true},
+		{Name: "ratio", Type: arrow.PrimitiveTypes.Float32, Nullable: true},
+	}, nil)
+	rows := `[
+		{"id": 1, "user": {"name": "ann", "tags": ["a", "b"]}, "attrs": [{"key": "x", "value": 1}], "status": "active",
+		 "amount": "-1234.56", "day": 19000, "ts": 1700000000123456, "local_ts": 1700000000123, "hash": "AAECAw==", "ratio": 0.5},
+		{"id": 2, "user": null, "attrs": null, "status": null, "amount": null, "day": null, "ts": null, "local_ts": null, "hash": null, "ratio": null},
+		{"id": 3, "user": {"name": "cy", "tags": null}, "attrs": [], "status": "closed",
+		 "amount": "99999999.99", "day": -1, "ts": 0, "local_ts": -1, "hash": "/////w==", "ratio": -2.25}
+	]`
+	record, _, err := array.RecordFromJSON(memory.NewGoAllocator(), schema, strings.NewReader(rows))
+	require.NoError(t, err)
+	return record
+}
+
+func TestWriteReadAvro_NestedAndLogicalTypes(t *testing.T) {
+	record := avroTestRecord(t)
+	defer record.Release()
+	df := NewDataFrame(record)
+	defer df.Release()
+
+	codecs := []string{AvroCodecNull, AvroCodecDeflate, AvroCodecSnappy, AvroCodecZstd, AvroCodecBzip2, AvroCodecXZ}
+	for _, codec := range codecs {
+		t.Run(codec, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, WriteAvroTo(df, &buf, AvroWriteOptions{Codec: codec, BlockSize: 1}))
+
+			result, err := ReadAvroFrom(&buf, AvroReadOptions{})
+			require.NoError(t, err)
+			defer result.Release()
+			assert.True(t, result.Record().Schema().Equal(record.Schema()), "schema: %s", result.Record().Schema())
+			assert.True(t, array.RecordEqual(record, result.Record()), "record: %v", result.Record())
+		})
+	}
+}
+
+func TestWriteAvro_Errors(t *testing.T) {
+	df := createSampleDataFrame()
+	defer df.Release()
+
+	var buf bytes.Buffer
+	err := WriteAvroTo(df, &buf, AvroWriteOptions{Codec: "lzo"})
+	assert.ErrorContains(t, err, "unsupported Avro codec")
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "m", Type: arrow.MapOf(arrow.PrimitiveTypes.Int64, arrow.PrimitiveTypes.Int64)},
+	}, nil)
+	record, _, err := array.RecordFromJSON(memory.NewGoAllocator(), schema, strings.NewReader(`[{"m": [{"key": 1, "value": 2}]}]`))
+	require.NoError(t, err)
+	defer record.Release()
+	err = WriteAvroTo(NewDataFrame(record), &buf, AvroWriteOptions{})
+	assert.ErrorContains(t, err, "map keys must be strings")
+}
+
+func TestReadAvro_SchemaEvolution(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int32},
+		{Name: "name", Type: arrow.BinaryTypes.String},
+		{Name: "score", Type: arrow.PrimitiveTypes.Float32},
+		{Name: "dropped", Type: arrow.ListOf(arrow.BinaryTypes.String)},
+	}, nil)
+	record, _, err := array.RecordFromJSON(memory.NewGoAllocator(), schema, strings.NewReader(
+		`[{"id": 1, "name": "ann", "score": 1.5, "dropped": ["x"]}, {"id": 2, "name": "bo", "score": 2, "dropped": []}]`))
+	require.NoError(t, err)
+	defer record.Release()
+	path := filepath.Join(t.TempDir(), "v1.avro")
+	require.NoError(t, WriteAvroWithOptions(NewDataFrame(record), path, AvroWriteOptions{Codec: AvroCodecDeflate}))
+
+	readerSchema := `{"type": "record", "name": "User", "namespace": "com.example", "fields": [
+		{"name": "id", "type": "long"},
+		{"name": "full_name", "aliases": ["name"], "type": "string"},
+		{"name": "score", "type": "double"},
+		{"name": "country", "type": "string", "default": "unknown"},
+		{"name": "rating", "type": ["null", "double"], "default": null},
+		{"name": "address", "default": {"city": "Berlin"}, "type": {"type": "record", "name": "Address", "fields": [
+			{"name": "city", "type": "string"},
+			{"name": "zip", "type": ["null", "string"], "default": null}
+		]}}
+	]}`
+	result, err := ReadAvroWithOptions(path, AvroReadOptions{ReaderSchema: readerSchema})
+	require.NoError(t, err)
+	defer result.Release()
+
+	assert.Equal(t, []string{"id", "full_name", "score", "country", "rating", "address"}, result.ColumnNames())
+	out := result.Record()
+	assert.Equal(t, []int64{1, 2}, out.Column(0).(*array.Int64).Int64Values())
+	assert.Equal(t, "bo", out.Column(1).(*array.String).Value(1))
+	assert.Equal(t, []float64{1.5, 2}, out.Column(2).(*array.Float64).Float64Values())
+	assert.Equal(t, "unknown", out.Column(3).(*array.String).Value(0))
+	assert.Equal(t, 2, out.Column(4).NullN())
+	address := out.Column(5).(*array.Struct)
+	assert.Equal(t, "Berlin", address.Field(0).(*array.String).Value(1))
+	assert.True(t, address.Field(1).IsNull(1))
+
+	// A field without a default must be in the file
+	_, err = ReadAvroWithOptions(path, AvroReadOptions{ReaderSchema: `{"type": "record", "name": "User", "fields": [
+		{"name": "email", "type": "string"}
+	]}`})
+	assert.ErrorContains(t, err, "field email is not in the writer schema and has no default")
+
+	// Strings cannot be read as numbers
+	_, err = ReadAvroWithOptions(path, AvroReadOptions{ReaderSchema: `{"type": "record", "name": "User", "fields": [
+		{"name": "name", "type": "long"}
+	]}`})
+	assert.ErrorContains(t, err, "cannot read Avro string as long")
+}
+
+// avroContainer builds an Avro Object Container File of one block.
+func avroContainer(schema string, count int, block []byte) []byte {
+	var buf bytes.Buffer
+	buf.Write(avroMagic)
+	_ = writeAvroMap(&buf, map[string]string{"avro.schema": schema})
+	sync := bytes.Repeat([]byte{0xab}, 16)
+	buf.Write(sync)
+	buf.Write(appendAvroLong(appendAvroLong(nil, int64(count)), int64(len(block))))
+	buf.Write(block)
+	buf.Write(sync)
+	return buf.Bytes()
+}
+
+func TestReadAvro_ExternalFile(t *testing.T) {
+	// Written as another Avro library would: references to named types by
+	// namespace, uuid and decimal logical types, an array block with a
+	// negative count, and an enum read with a reader default
+	schema := `{"type": "record", "name": "Order", "namespace": "shop", "fields": [
+		{"name": "id", "type": {"type": "string", "logicalType": "uuid"}},
+		{"name": "ref", "type": {"type": "fixed", "name": "Ref", "size": 16, "logicalType": "uuid"}},
+		{"name": "total", "type": {"type": "bytes", "logicalType": "decimal", "precision": 6, "scale": 2}},
+		{"name": "state", "type": {"type": "enum", "name": "State", "symbols": ["NEW", "PAID", "LOST"]}},
+		{"name": "items", "type": {"type": "array", "items": {"type": "record", "name": "Item", "fields": [
+			{"name": "sku", "type": "string"}
+		]}}},
+		{"name": "gift", "type": ["null", "shop.Item"]},
+		{"name": "ignored", "type": ["null", "Ref"]}
+	]}`
+	var block []byte
+	block = appendAvroBytes(block, []byte("123e4567-e89b-12d3-a456-426614174000"))
+	block = append(block, 0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00)
+	block = appendAvroBytes(block, []byte{0xfe, 0xd4}) // -300 = -3.00
+	block = appendAvroLong(block, 2)                   // LOST
+	item := appendAvroBytes(nil, []byte("sku-1"))
+	block = appendAvroLong(block, -1)
+	block = appendAvroLong(block, int64(len(item)))
+	block = append(block, item...)
+	block = appendAvroLong(block, 0)
+	block = appendAvroLong(block, 1)
+	block = appendAvroBytes(block, []byte("sku-2"))
+	block = appendAvroLong(block, 0)
+
+	data := avroContainer(schema, 1, block)
+	df, err := ReadAvroFrom(bytes.NewReader(data), AvroReadOptions{})
+	require.NoError(t, err)
+	defer df.Release()
+
+	record := df.Record()
+	assert.Equal(t, "123e4567-e89b-12d3-a456-426614174000", record.Column(0).(*array.String).Value(0))
+	assert.Equal(t, "123e4567-e89b-12d3-a456-426614174000", record.Column(1).(*array.String).Value(0))
+	assert.Equal(t, "-300", record.Column(2).(*array.Decimal128).Value(0).BigInt().String())
+	assert.Equal(t, &arrow.Decimal128Type{Precision: 6, Scale: 2}, record.Column(2).DataType())
+	assert.Equal(t, "LOST", record.Column(3).ValueStr(0))
+	assert.Equal(t, `[{"sku":"sku-1"}]`, record.Column(4).ValueStr(0))
+	assert.Equal(t, "sku-2", record.Column(5).(*array.Struct).Field(0).ValueStr(0))
+	assert.Equal(t, arrow.BinaryTypes.String, record.Column(6).DataType())
+
+	readerSchema := strings.Replace(schema, `"LOST"]`, `"UNKNOWN"], "default": "UNKNOWN"`, 1)
+	df, err = ReadAvroFrom(bytes.NewReader(data), AvroReadOptions{ReaderSchema: readerSchema})
+	require.NoError(t, err)
+	defer df.Release()
+	assert.Equal(t, "UNKNOWN", df.Record().Column(3).ValueStr(0))
+}
+
+func TestReadAvro_CorruptBlock(t *testing.T) {
+	schema := `{"type": "record", "name": "R", "fields": [{"name": "s", "type": "string"}]}`
+	data := avroContainer(schema, 2, appendAvroBytes(nil, []byte("only one")))
+	_, err := ReadAvroFrom(bytes.NewReader(data), AvroReadOptions{})
+	assert.ErrorContains(t, err, "failed to decode Avro record 1")
+
+	_, err = ReadAvroFrom(bytes.NewReader(data[:len(data)-3]), AvroReadOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,522 @@
+package gopherframe
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// avroType is a node of a parsed Avro schema. Named types (records, enums
+// and fixed) are shared by every reference to their name.
+type avroType struct {
+	// kind is a primitive type name, or record, enum, array, map, fixed or
+	// union.
+	kind string
+	// name is the full name of a record, enum or fixed type.
+	name string
+	// logical is the logical type, kept only if it is valid for the kind.
+	logical   string
+	precision int
+	scale     int
+
+	fields      []*avroField // record
+	symbols     []string     // enum
+	enumDefault string       // enum, "" without a default
+	items       *avroType    // array items and map values
+	size        int          // fixed
+	branches    []*avroType  // union
+}
+
+// avroField is a field of an Avro record.
+type avroField struct {
+	name    string
+	aliases []string
+	typ     *avroType
+	// def is the default value as JSON, or nil if the field has none.
+	def json.RawMessage
+}
+
+// avroPrimitives are the names of the primitive Avro types.
+var avroPrimitives = map[string]bool{
+	"null": true, "boolean": true, "int": true, "long": true,
+	"float": true, "double": true, "bytes": true, "string": true,
+}
+
+// avroLogicalTypes lists the logical types read as Arrow types other than the
+// type they annotate, by the kind they annotate.
+var avroLogicalTypes = map[string][]string{
+	"int":    {"date", "time-millis"},
+	"long":   {"time-micros", "timestamp-millis", "timestamp-micros", "timestamp-nanos", "local-timestamp-millis", "local-timestamp-micros", "local-timestamp-nanos"},
+	"bytes":  {"decimal"},
+	"fixed":  {"decimal", "uuid"},
+	"string": {"uuid"},
+}
+
+// avroNamePattern matches valid Avro names and enum symbols.
+var avroNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// parseAvroSchema parses an Avro schema given as JSON.
+func parseAvroSchema(text string) (*avroType, error) {
+	p := avroSchemaParser{named: make(map[string]*avroType)}
+	return p.parse(json.RawMessage(text), "")
+}
+
+// avroSchemaParser parses a schema, resolving references to named types.
+type avroSchemaParser struct {
+	named map[string]*avroType
+}
+
+// avroFullName returns the full name of a named type declared with name in
+// namespace.
+func avroFullName(name, namespace string) string {
+	if strings.Contains(name, ".") || namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+func (p *avroSchemaParser) parse(raw json.RawMessage, namespace string) (*avroType, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("empty Avro schema")
+	}
+	switch raw[0] {
+	case '"':
+		var name string
+		if err := json.Unmarshal(raw, &name); err != nil {
+			return nil, err
+		}
+		return p.lookup(name, namespace)
+	case '[':
+		var branches []json.RawMessage
+		if err := json.Unmarshal(raw, &branches); err != nil {
+			return nil, err
+		}
+		union := &avroType{kind: "union"}
+		for _, branch := range branches {
+			t, err := p.parse(branch, namespace)
+			if err != nil {
+				return nil, err
+			}
+			if t.kind == "union" {
+				return nil, fmt.Errorf("unions may not contain unions")
+			}
+			union.branches = append(union.branches, t)
+		}
+		if len(union.branches) == 0 {
+			return nil, fmt.Errorf("empty union")
+		}
+		return union, nil
+	case '{':
+		return p.parseObject(raw, namespace)
+	default:
+		return nil, fmt.Errorf("invalid Avro schema: %s", raw)
+	}
+}
+
+// lookup returns the primitive or previously defined named type called name.
+func (p *avroSchemaParser) lookup(name, namespace string) (*avroType, error) {
+	if avroPrimitives[name] {
+		return &avroType{kind: name}, nil
+	}
+	if t, ok := p.named[avroFullName(name, namespace)]; ok {
+		return t, nil
+	}
+	if t, ok := p.named[name]; ok {
+		return t, nil
+	}
+	return nil, fmt.Errorf("unknown Avro type: %s", name)
+}
+
+// avroSchemaObject is the JSON form of a complex or annotated type.
+type avroSchemaObject struct {
+	Type      json.RawMessage `json:"type"`
+	Name      string          `json:"name"`
+	Namespace *string         `json:"namespace"`
+	Fields    []struct {
+		Name    string          `json:"name"`
+		Aliases []string        `json:"aliases"`
+		Type    json.RawMessage `json:"type"`
+		Default json.RawMessage `json:"default"`
+	} `json:"fields"`
+	Symbols     []string        `json:"symbols"`
+	Default     json.RawMessage `json:"default"`
+	Items       json.RawMessage `json:"items"`
+	Values      json.RawMessage `json:"values"`
+	Size        int             `json:"size"`
+	LogicalType string          `json:"logicalType"`
+	Precision   int             `json:"precision"`
+	Scale       int             `json:"scale"`
+}
+
+func (p *avroSchemaParser) parseObject(raw json.RawMessage, namespace string) (*avroType, error) {
+	var obj avroSchemaObject
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, err
+	}
+	var kind string
+	if err := json.Unmarshal(obj.Type, &kind); err != nil {
+		// {"type": {...}} or {"type": [...]}
+		return p.parse(obj.Type, namespace)
+	}
+
+	t := &avroType{kind: kind}
+	switch kind {
+	case "record", "error", "enum", "fixed":
+		if obj.Name == "" {
+			return nil, fmt.Errorf("avro %s has no name", kind)
+		}
+		if obj.Namespace != nil && !strings.Contains(obj.Name, ".") {
+			namespace = *obj.Namespace
+		}
+		t.name = avroFullName(obj.Name, namespace)
+		if _, ok := p.named[t.name]; ok {
+			return nil, fmt.Errorf("avro type %s is defined twice", t.name)
+		}
+		// Registered before the fields are parsed, for recursive types
+		p.named[t.name] = t
+		if i := strings.LastIndex(t.name, "."); i >= 0 {
+			namespace = t.name[:i]
+		} else {
+			namespace = ""
+		}
+	}
+
+	switch kind {
+	case "record", "error":
+		t.kind = "record"
+		for _, f := range obj.Fields {
+			ft, err := p.parse(f.Type, namespace)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", f.Name, err)
+			}
+			t.fields = append(t.fields, &avroField{name: f.Name, aliases: f.Aliases, typ: ft, def: f.Default})
+		}
+	case "enum":
+		if len(obj.Symbols) == 0 {
+			return nil, fmt.Errorf("avro enum %s has no symbols", t.name)
+		}
+		t.symbols = obj.Symbols
+		if obj.Default != nil {
+			if err := json.Unmarshal(obj.Default, &t.enumDefault); err != nil || !slices.Contains(t.symbols, t.enumDefault) {
+				return nil, fmt.Errorf("avro enum %s has an invalid default: %s", t.name, obj.Default)
+			}
+		}
+	case "array", "map":
+		items := obj.Items
+		if kind == "map" {
+			items = obj.Values
+		}
+		if items == nil {
+			return nil, fmt.Errorf("avro %s has no item type", kind)
+		}
+		itemType, err := p.parse(items, namespace)
+		if err != nil {
+			return nil, err
+		}
+		t.items = itemType
+	case "fixed":
+		if obj.Size <= 0 {
+			return nil, fmt.Errorf("avro fixed %s has an invalid size: %d", t.name, obj.Size)
+		}
+		t.size = obj.Size
+	default:
+		if !avroPrimitives[kind] {
+			// A reference to a named type, which has no annotations
+			return p.lookup(kind, namespace)
+		}
+	}
+
+	if slices.Contains(avroLogicalTypes[t.kind], obj.LogicalType) {
+		t.logical, t.precision, t.scale = obj.LogicalType, obj.Precision, obj.Scale
+	}
+	// Invalid logical types are ignored, as the specification requires
+	switch {
+	case t.logical == "decimal" && (t.precision < 1 || t.precision > 76 || t.scale < 0 || t.scale > t.precision):
+		t.logical = ""
+	case t.logical == "decimal" && t.kind == "fixed" && float64(t.precision) > math.Floor(math.Log10(2)*float64(8*t.size-1)):
+		t.logical = ""
+	case t.logical == "uuid" && t.kind == "fixed" && t.size != 16:
+		t.logical = ""
+	}
+	return t, nil
+}
+
+// avroNonNull returns the non-null type of a union of null and one other
+// type, and whether null is allowed. Other types are returned unchanged.
+func avroNonNull(t *avroType) (*avroType, bool, error) {
+	if t.kind != "union" {
+		return t, t.kind == "null", nil
+	}
+	var value *avroType
+	nullable := false
+	for _, branch := range t.branches {
+		if branch.kind == "null" {
+			nullable = true
+			continue
+		}
+		if value != nil {
+			return nil, false, fmt.Errorf("unions of more than one non-null type are not supported")
+		}
+		value = branch
+	}
+	if value == nil {
+		return t.branches[0], true, nil
+	}
+	return value, nullable, nil
+}
+
+// avroArrowType returns the Arrow type that values of t are read as, and
+// whether they can be null. Recursive records cannot be represented and are
+// an error.
+func avroArrowType(t *avroType, visiting map[*avroType]bool) (arrow.DataType, bool, error) {
+	t, nullable, err := avroNonNull(t)
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch t.kind {
+	case "null":
+		return arrow.Null, true, nil
+	case "boolean":
+		return arrow.FixedWidthTypes.Boolean, nullable, nil
+	case "int":
+		switch t.logical {
+		case "date":
+			return arrow.FixedWidthTypes.Date32, nullable, nil
+		case "time-millis":
+			return arrow.FixedWidthTypes.Time32ms, nullable, nil
+		}
+		return arrow.PrimitiveTypes.Int32, nullable, nil
+	case "long":
+		if t.logical == "time-micros" {
+			return arrow.FixedWidthTypes.Time64us, nullable, nil
+		}
+		if unit, tz, ok := avroTimestampUnit(t.logical); ok {
+			return &arrow.TimestampType{Unit: unit, TimeZone: tz}, nullable, nil
+		}
+		return arrow.PrimitiveTypes.Int64, nullable, nil
+	case "float":
+		return arrow.PrimitiveTypes.Float32, nullable, nil
+	case "double":
+		return arrow.PrimitiveTypes.Float64, nullable, nil
+	case "string":
+		return arrow.BinaryTypes.String, nullable, nil
+	case "bytes", "fixed":
+		switch {
+		case t.logical == "decimal" && t.precision <= 38:
+			return &arrow.Decimal128Type{Precision: int32(t.precision), Scale: int32(t.scale)}, nullable, nil
+		case t.logical == "decimal":
+			return &arrow.Decimal256Type{Precision: int32(t.precision), Scale: int32(t.scale)}, nullable, nil
+		case t.logical == "uuid":
+			return arrow.BinaryTypes.String, nullable, nil
+		case t.kind == "fixed":
+			return &arrow.FixedSizeBinaryType{ByteWidth: t.size}, nullable, nil
+		}
+		return arrow.BinaryTypes.Binary, nullable, nil
+	case "enum":
+		return &arrow.DictionaryType{IndexType: arrow.PrimitiveTypes.Int32, ValueType: arrow.BinaryTypes.String}, nullable, nil
+	case "array":
+		item, itemNullable, err := avroArrowType(t.items, visiting)
+		if err != nil {
+			return nil, false, err
+		}
+		return arrow.ListOfField(arrow.Field{Name: "item", Type: item, Nullable: itemNullable}), nullable, nil
+	case "map":
+		item, _, err := avroArrowType(t.items, visiting)
+		if err != nil {
+			return nil, false, err
+		}
+		return arrow.MapOf(arrow.BinaryTypes.String, item), nullable, nil
+	case "record":
+		if visiting[t] {
+			return nil, false, fmt.Errorf("recursive Avro record %s is not supported", t.name)
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+		fields := make([]arrow.Field, len(t.fields))
+		for i, f := range t.fields {
+			dt, fieldNullable, err := avroArrowType(f.typ, visiting)
+			if err != nil {
+				return nil, false, fmt.Errorf("field %s: %w", f.name, err)
+			}
+			fields[i] = arrow.Field{Name: f.name, Type: dt, Nullable: fieldNullable}
+		}
+		return arrow.StructOf(fields...), nullable, nil
+	default:
+		return nil, false, fmt.Errorf("unsupported Avro type: %s", t.kind)
+	}
+}
+
+// avroTimestampUnit returns the Arrow unit and time zone of a timestamp
+// logical type. Local timestamps have no time zone.
+func avroTimestampUnit(logical string) (arrow.TimeUnit, string, bool) {
+	tz := "UTC"
+	if rest, ok := strings.CutPrefix(logical, "local-"); ok {
+		logical, tz = rest, ""
+	}
+	switch logical {
+	case "timestamp-millis":
+		return arrow.Millisecond, tz, true
+	case "timestamp-micros":
+		return arrow.Microsecond, tz, true
+	case "timestamp-nanos":
+		return arrow.Nanosecond, tz, true
+	default:
+		return 0, "", false
+	}
+}
+
+// encodeAvroDefault returns the binary encoding of the default value of a
+// field, given as JSON, so that it can be decoded like data from the file.
+func encodeAvroDefault(t *avroType, def json.RawMessage) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(def))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return appendAvroJSON(nil, t, v)
+}
+
+// appendAvroJSON appends the binary encoding of a JSON value of type t. A
+// union's value is of its first branch, as in Avro defaults.
+func appendAvroJSON(buf []byte, t *avroType, v interface{}) ([]byte, error) {
+	invalid := func() ([]byte, error) {
+		return nil, fmt.Errorf("invalid default %v for Avro type %s", v, t.kind)
+	}
+	switch t.kind {
+	case "union":
+		return appendAvroJSON(appendAvroLong(buf, 0), t.branches[0], v)
+	case "null":
+		if v != nil {
+			return invalid()
+		}
+		return buf, nil
+	case "boolean":
+		b, ok := v.(bool)
+		if !ok {
+			return invalid()
+		}
+		if b {
+			return append(buf, 1), nil
+		}
+		return append(buf, 0), nil
+	case "int", "long":
+		n, ok := v.(json.Number)
+		if !ok {
+			return invalid()
+		}
+		i, err := n.Int64()
+		if err != nil {
+			return invalid()
+		}
+		return appendAvroLong(buf, i), nil
+	case "float", "double":
+		n, ok := v.(json.Number)
+		if !ok {
+			return invalid()
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return invalid()
+		}
+		if t.kind == "float" {
+			return appendAvroFloat(buf, float32(f)), nil
+		}
+		return appendAvroDouble(buf, f), nil
+	case "string", "bytes", "fixed":
+		s, ok := v.(string)
+		if !ok {
+			return invalid()
+		}
+		data := []byte(s)
+		if t.kind != "string" {
+			// Bytes defaults are strings of code points 0-255
+			data = data[:0:0]
+			for _, r := range s {
+				if r > 0xff {
+					return invalid()
+				}
+				data = append(data, byte(r))
+			}
+		}
+		if t.kind == "fixed" {
+			if len(data) != t.size {
+				return invalid()
+			}
+			return append(buf, data...), nil
+		}
+		return appendAvroBytes(buf, data), nil
+	case "enum":
+		s, _ := v.(string)
+		idx := slices.Index(t.symbols, s)
+		if idx < 0 {
+			return invalid()
+		}
+		return appendAvroLong(buf, int64(idx)), nil
+	case "array":
+		items, ok := v.([]interface{})
+		if !ok {
+			return invalid()
+		}
+		if len(items) > 0 {
+			buf = appendAvroLong(buf, int64(len(items)))
+			for _, item := range items {
+				var err error
+				if buf, err = appendAvroJSON(buf, t.items, item); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return appendAvroLong(buf, 0), nil
+	case "map":
+		entries, ok := v.(map[string]interface{})
+		if !ok {
+			return invalid()
+		}
+		if len(entries) > 0 {
+			buf = appendAvroLong(buf, int64(len(entries)))
+			for _, key := range sortedKeys(entries) {
+				var err error
+				buf = appendAvroBytes(buf, []byte(key))
+				if buf, err = appendAvroJSON(buf, t.items, entries[key]); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return appendAvroLong(buf, 0), nil
+	case "record":
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return invalid()
+		}
+		for _, f := range t.fields {
+			value, ok := obj[f.name]
+			if !ok {
+				if f.def == nil {
+					return nil, fmt.Errorf("default for record %s has no value for field %s", t.name, f.name)
+				}
+				fieldDefault, err := encodeAvroDefault(f.typ, f.def)
+				if err != nil {
+					return nil, err
+				}
+				buf = append(buf, fieldDefault...)
+				continue
+			}
+			var err error
+			if buf, err = appendAvroJSON(buf, f.typ, value); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	default:
+		return invalid()
+	}
+}
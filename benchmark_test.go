@@ -137,6 +137,123 @@ func BenchmarkGroupByMultipleAgg(b *testing.B) {
 	}
 }
 
+// BenchmarkGroupByHighCardinality measures GroupBy on an int64 key where
+// every row is its own group
+func BenchmarkGroupByHighCardinality(b *testing.B) {
+	sizes := []int{smallSize, mediumSize, largeSize}
+
+	for _, size := range sizes {
+		df := createBenchmarkDataFrame(size)
+		defer df.Release()
+
+		b.Run(fmt.Sprintf("Size_%d", size), func(b *testing.B) {
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				grouped := df.GroupBy("id").Agg(Sum("value"))
+				grouped.Release()
+			}
+		})
+	}
+}
+
+// BenchmarkGroupByMultiColumn measures GroupBy on a string and a float64 key
+func BenchmarkGroupByMultiColumn(b *testing.B) {
+	sizes := []int{smallSize, mediumSize, largeSize}
+
+	for _, size := range sizes {
+		df := createBenchmarkDataFrame(size)
+		defer df.Release()
+
+		b.Run(fmt.Sprintf("Size_%d", size), func(b *testing.B) {
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				grouped := df.GroupBy("category", "value").Agg(Count("id"))
+				grouped.Release()
+			}
+		})
+	}
+}
+
+// BenchmarkGroupByParallel measures AggWithOptions on one million rows
+// with one, four and GOMAXPROCS goroutines
+func BenchmarkGroupByParallel(b *testing.B) {
+	df := createBenchmarkDataFrame(1000000)
+	defer df.Release()
+
+	for _, parallelism := range []int{1, 4, 0} {
+		b.Run(fmt.Sprintf("Parallelism_%d", parallelism), func(b *testing.B) {
+			opts := AggOptions{Parallelism: parallelism}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				grouped := df.GroupBy("category").AggWithOptions(opts,
+					Sum("value"), Mean("value"), Variance("value"), Count("value"))
+				grouped.Release()
+			}
+		})
+	}
+}
+
+// BenchmarkJoinMulti measures a two-key hash join
+func BenchmarkJoinMulti(b *testing.B) {
+	sizes := []int{smallSize, mediumSize, largeSize}
+
+	for _, size := range sizes {
+		left := createBenchmarkDataFrame(size)
+		defer left.Release()
+		right := createBenchmarkDataFrame(size)
+		defer right.Release()
+
+		b.Run(fmt.Sprintf("Size_%d", size), func(b *testing.B) {
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				joined, err := left.coreDF.InnerJoinMulti(right.coreDF, []string{"id", "category"}, []string{"id", "category"})
+				if err != nil {
+					b.Fatal(err)
+				}
+				joined.Release()
+			}
+		})
+	}
+}
+
+// BenchmarkBroadcastJoin measures a join against a small right table
+func BenchmarkBroadcastJoin(b *testing.B) {
+	sizes := []int{smallSize, mediumSize, largeSize}
+	right := createBenchmarkDataFrame(smallSize)
+	defer right.Release()
+
+	for _, size := range sizes {
+		left := createBenchmarkDataFrame(size)
+		defer left.Release()
+
+		b.Run(fmt.Sprintf("Size_%d", size), func(b *testing.B) {
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				joined := left.BroadcastJoin(right, "id", "id")
+				joined.Release()
+			}
+		})
+	}
+}
+
+// BenchmarkPivot measures pivoting categories into columns
+func BenchmarkPivot(b *testing.B) {
+	sizes := []int{smallSize, mediumSize, largeSize}
+
+	for _, size := range sizes {
+		df := createBenchmarkDataFrame(size)
+		defer df.Release()
+
+		b.Run(fmt.Sprintf("Size_%d", size), func(b *testing.B) {
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				pivoted := df.Pivot([]string{"id"}, "category", "value")
+				pivoted.Release()
+			}
+		})
+	}
+}
+
 // BenchmarkChainedOperations measures performance of chained operations
 func BenchmarkChainedOperations(b *testing.B) {
 	sizes := []int{smallSize, mediumSize}
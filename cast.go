@@ -0,0 +1,31 @@
+package gopherframe
+
+import (
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/felixgeelhaar/GopherFrame/pkg/expr"
+)
+
+// Cast returns a new DataFrame with the given columns converted to new types.
+// Any value that cannot be converted makes the whole operation fail.
+//
+// Example:
+//
+//	typed := df.Cast(map[string]arrow.DataType{
+//		"zip":    arrow.BinaryTypes.String,
+//		"amount": arrow.PrimitiveTypes.Float64,
+//	})
+func (df *DataFrame) Cast(types map[string]arrow.DataType) *DataFrame {
+	return df.castColumns(types, expr.CastStrict)
+}
+
+// TryCast returns a new DataFrame with the given columns converted to new types.
+// Values that cannot be converted become null instead of failing.
+func (df *DataFrame) TryCast(types map[string]arrow.DataType) *DataFrame {
+	return df.castColumns(types, expr.CastSafe)
+}
+
+func (df *DataFrame) castColumns(types map[string]arrow.DataType, mode expr.CastMode) *DataFrame {
+	return replaceColumns(df, types, func(field arrow.Field, dataType arrow.DataType) (expr.Expr, error) {
+		return expr.NewCastExpr(expr.Col(field.Name), dataType, mode), nil
+	})
+}
@@ -0,0 +1,70 @@
+package gopherframe
+
+import (
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataFrame_Cast(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	df := createStringDataFrame(pool, "zip", []string{"01234", "98765"})
+	defer df.Release()
+
+	// Strings with leading zeros parse as base-10 integers
+	asInt := df.Cast(map[string]arrow.DataType{"zip": arrow.PrimitiveTypes.Int64})
+	require.NoError(t, asInt.Err())
+	defer asInt.Release()
+
+	assert.Equal(t, []int64{1234, 98765}, asInt.Record().Column(0).(*array.Int64).Int64Values())
+
+	back := asInt.Cast(map[string]arrow.DataType{"zip": arrow.BinaryTypes.String})
+	require.NoError(t, back.Err())
+	defer back.Release()
+	assert.Equal(t, "1234", back.Record().Column(0).(*array.String).Value(0))
+}
+
+func TestDataFrame_Cast_Errors(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	df := createStringDataFrame(pool, "amount", []string{"12.5", "n/a"})
+	defer df.Release()
+
+	result := df.Cast(map[string]arrow.DataType{"amount": arrow.PrimitiveTypes.Float64})
+	assert.Error(t, result.Err())
+
+	result = df.Cast(map[string]arrow.DataType{"missing": arrow.PrimitiveTypes.Float64})
+	require.Error(t, result.Err())
+	assert.Contains(t, result.Err().Error(), "column not found")
+}
+
+func TestDataFrame_TryCast(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	df := createStringDataFrame(pool, "amount", []string{"12.5", "n/a", "7"})
+	defer df.Release()
+
+	result := df.TryCast(map[string]arrow.DataType{"amount": arrow.PrimitiveTypes.Float64})
+	require.NoError(t, result.Err())
+	defer result.Release()
+
+	amounts := result.Record().Column(0).(*array.Float64)
+	assert.Equal(t, 12.5, amounts.Value(0))
+	assert.True(t, amounts.IsNull(1))
+	assert.Equal(t, 7.0, amounts.Value(2))
+}
+
+func TestDataFrame_Cast_NoColumns(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	df := createStringDataFrame(pool, "zip", []string{"01234"})
+	defer df.Release()
+
+	// The result is a separate DataFrame that can be released on its own
+	result := df.Cast(map[string]arrow.DataType{})
+	require.NoError(t, result.Err())
+	assert.NotSame(t, df, result)
+	result.Release()
+	assert.Equal(t, "01234", df.Record().Column(0).(*array.String).Value(0))
+}
@@ -0,0 +1,239 @@
+package gopherframe
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	dsnetbzip2 "github.com/dsnet/compress/bzip2"
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// Compression codecs for CSV, JSON and NDJSON files.
+const (
+	CompressionGzip  = "gzip"
+	CompressionZstd  = "zstd"
+	CompressionBzip2 = "bzip2"
+	CompressionXZ    = "xz"
+)
+
+// compressionExtensions maps file extensions to compression codecs.
+var compressionExtensions = map[string]string{
+	".gz":   CompressionGzip,
+	".gzip": CompressionGzip,
+	".zst":  CompressionZstd,
+	".zstd": CompressionZstd,
+	".bz2":  CompressionBzip2,
+	".xz":   CompressionXZ,
+}
+
+// compressionMagic holds the signatures at the start of compressed streams.
+var compressionMagic = []struct {
+	codec string
+	magic []byte
+}{
+	{CompressionGzip, []byte{0x1f, 0x8b}},
+	{CompressionZstd, []byte{0x28, 0xb5, 0x2f, 0xfd}},
+	{CompressionXZ, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}},
+}
+
+// compressionFromExtension returns the codec named by the extension of
+// filename, or "" if the extension is not a compression extension.
+func compressionFromExtension(filename string) string {
+	return compressionExtensions[strings.ToLower(filepath.Ext(filename))]
+}
+
+// detectCompression returns the codec whose signature starts header, or ""
+// for uncompressed data.
+func detectCompression(header []byte) string {
+	for _, m := range compressionMagic {
+		if bytes.HasPrefix(header, m.magic) {
+			return m.codec
+		}
+	}
+	// "BZh", the block size digit, then the magic of the first block or of
+	// the end of stream; the longer match keeps text starting with "BZh"
+	// from being taken for bzip2
+	if len(header) >= 10 && bytes.HasPrefix(header, []byte("BZh")) && header[3] >= '1' && header[3] <= '9' {
+		block := header[4:10]
+		if bytes.Equal(block, []byte{0x31, 0x41, 0x59, 0x26, 0x53, 0x59}) ||
+			bytes.Equal(block, []byte{0x17, 0x72, 0x45, 0x38, 0x50, 0x90}) {
+			return CompressionBzip2
+		}
+	}
+	return ""
+}
+
+// NewDecompressionReader returns a reader of the data in r, decompressed if it
+// starts with the signature of a gzip, zstd, bzip2 or xz stream. Other data
+// is returned unchanged. Closing the reader releases the decoder but does
+// not close r.
+func NewDecompressionReader(r io.Reader) (io.ReadCloser, error) {
+	return decompressReader(r, "")
+}
+
+// decompressReader returns a reader of the data in r decompressed with codec,
+// detecting the codec from the leading bytes when it is empty.
+func decompressReader(r io.Reader, codec string) (io.ReadCloser, error) {
+	buffered := bufio.NewReader(r)
+	if codec == "" {
+		// A short stream is returned by Peek along with io.EOF
+		header, err := buffered.Peek(10)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to read data: %w", err)
+		}
+		codec = detectCompression(header)
+	}
+
+	switch codec {
+	case "":
+		return io.NopCloser(buffered), nil
+	case CompressionGzip:
+		reader, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gzip data: %w", err)
+		}
+		return reader, nil
+	case CompressionZstd:
+		decoder, err := zstd.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read zstd data: %w", err)
+		}
+		return decoder.IOReadCloser(), nil
+	case CompressionBzip2:
+		return io.NopCloser(bzip2.NewReader(buffered)), nil
+	case CompressionXZ:
+		reader, err := xz.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read xz data: %w", err)
+		}
+		return io.NopCloser(reader), nil
+	default:
+		return nil, fmt.Errorf("unsupported compression: %s", codec)
+	}
+}
+
+// NewCompressionWriter returns a writer that compresses its input with codec
+// ("gzip", "zstd", "bzip2" or "xz") to w. Close must be called to write the
+// end of the compressed stream; it does not close w. An empty codec returns
+// a writer that passes data through unchanged.
+//
+// Example:
+//
+//	zw, err := NewCompressionWriter(conn, CompressionZstd)
+//	if err != nil {
+//	    return err
+//	}
+//	if err := WriteNDJSONTo(df, zw); err != nil {
+//	    return err
+//	}
+//	return zw.Close()
+func NewCompressionWriter(w io.Writer, codec string) (io.WriteCloser, error) {
+	switch strings.ToLower(codec) {
+	case "", "none":
+		return nopWriteCloser{w}, nil
+	case CompressionGzip:
+		return gzip.NewWriter(w), nil
+	case CompressionZstd:
+		encoder, err := zstd.NewWriter(w)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd writer: %w", err)
+		}
+		return encoder, nil
+	case CompressionBzip2:
+		writer, err := dsnetbzip2.NewWriter(w, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create bzip2 writer: %w", err)
+		}
+		return writer, nil
+	case CompressionXZ:
+		writer, err := xz.NewWriter(w)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create xz writer: %w", err)
+		}
+		return writer, nil
+	default:
+		return nil, fmt.Errorf("unsupported compression: %s", codec)
+	}
+}
+
+// nopWriteCloser adds a no-op Close to an io.Writer.
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// openCompressed opens a file for reading, decompressing it with the codec
+// named by its extension or, failing that, by its leading magic bytes.
+// Closing the returned reader also closes the file.
+func openCompressed(filename string) (io.ReadCloser, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := decompressReader(f, compressionFromExtension(filename))
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return &compressedReader{ReadCloser: reader, file: f}, nil
+}
+
+// createCompressed creates a file for writing, compressing the data with the
+// codec named by its extension. Closing the returned writer finishes the
+// compressed stream and closes the file.
+func createCompressed(filename string, perm os.FileMode) (io.WriteCloser, error) {
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return nil, err
+	}
+	writer, err := NewCompressionWriter(f, compressionFromExtension(filename))
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return &compressedWriter{WriteCloser: writer, file: f}, nil
+}
+
+// compressedReader closes the decoder and then the file it reads from.
+type compressedReader struct {
+	io.ReadCloser
+	file *os.File
+}
+
+func (r *compressedReader) Close() error {
+	return errors.Join(r.ReadCloser.Close(), r.file.Close())
+}
+
+// compressedWriter finishes the compressed stream and then closes the file.
+type compressedWriter struct {
+	io.WriteCloser
+	file *os.File
+}
+
+func (w *compressedWriter) Close() error {
+	return errors.Join(w.WriteCloser.Close(), w.file.Close())
+}
+
+// isNDJSONFile reports whether filename has an NDJSON extension, ignoring a
+// trailing compression extension.
+func isNDJSONFile(filename string) bool {
+	if compressionFromExtension(filename) != "" {
+		filename = strings.TrimSuffix(filename, filepath.Ext(filename))
+	}
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".ndjson", ".jsonl":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,139 @@
+package gopherframe
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressedCSVRoundTrip(t *testing.T) {
+	df := createSampleDataFrame()
+	defer df.Release()
+	dir := t.TempDir()
+
+	for _, ext := range []string{".gz", ".zst", ".bz2", ".xz"} {
+		t.Run(ext, func(t *testing.T) {
+			path := filepath.Join(dir, "data.csv"+ext)
+			require.NoError(t, WriteCSV(df, path))
+
+			raw, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, compressionFromExtension(path), detectCompression(raw), "file is not compressed")
+
+			result, err := ReadCSV(path)
+			require.NoError(t, err)
+			defer result.Release()
+			assert.Equal(t, int64(3), result.NumRows())
+			assert.Equal(t, "Charlie", result.Record().Column(1).(*array.String).Value(2))
+		})
+	}
+}
+
+func TestCompressedNDJSONRoundTrip(t *testing.T) {
+	df := createSampleDataFrame()
+	defer df.Release()
+	path := filepath.Join(t.TempDir(), "feed.ndjson.zst")
+
+	require.NoError(t, WriteNDJSON(df, path))
+	result, err := ReadNDJSON(path)
+	require.NoError(t, err)
+	defer result.Release()
+	assert.Equal(t, int64(3), result.NumRows())
+}
+
+func TestReadCSV_DetectsCompressionFromMagicBytes(t *testing.T) {
+	df := createSampleDataFrame()
+	defer df.Release()
+	dir := t.TempDir()
+
+	// A compressed file without a compression extension
+	compressed := filepath.Join(dir, "data.csv.gz")
+	require.NoError(t, WriteCSV(df, compressed))
+	path := filepath.Join(dir, "data.csv")
+	require.NoError(t, os.Rename(compressed, path))
+
+	result, err := ReadCSV(path)
+	require.NoError(t, err)
+	defer result.Release()
+	assert.Equal(t, int64(3), result.NumRows())
+}
+
+func TestReadCSVChunked_Compressed(t *testing.T) {
+	df := createSampleDataFrame()
+	defer df.Release()
+	path := filepath.Join(t.TempDir(), "data.csv.xz")
+	require.NoError(t, WriteCSV(df, path))
+
+	it, err := ReadCSVChunked(path, 2)
+	require.NoError(t, err)
+	defer func() { _ = it.Close() }()
+
+	var sizes []int64
+	require.NoError(t, it.ForEachChunk(func(chunk *DataFrame) error {
+		sizes = append(sizes, chunk.NumRows())
+		chunk.Release()
+		return nil
+	}))
+	assert.Equal(t, []int64{2, 1}, sizes)
+}
+
+func TestReadParallel_Compressed(t *testing.T) {
+	df := createSampleDataFrame()
+	defer df.Release()
+	dir := t.TempDir()
+
+	csvFiles := []string{filepath.Join(dir, "a.csv.gz"), filepath.Join(dir, "b.csv.bz2")}
+	for _, path := range csvFiles {
+		require.NoError(t, WriteCSV(df, path))
+	}
+	combined, err := ReadCSVParallel(csvFiles, 2)
+	require.NoError(t, err)
+	defer combined.Release()
+	assert.Equal(t, int64(6), combined.NumRows())
+
+	jsonFiles := []string{filepath.Join(dir, "a.json.gz"), filepath.Join(dir, "b.ndjson.zst")}
+	require.NoError(t, WriteJSON(df, jsonFiles[0]))
+	require.NoError(t, WriteNDJSON(df, jsonFiles[1]))
+	combined, err = ReadJSONParallel(jsonFiles, 2)
+	require.NoError(t, err)
+	defer combined.Release()
+	assert.Equal(t, int64(6), combined.NumRows())
+}
+
+func TestCompressionStreams(t *testing.T) {
+	df := createSampleDataFrame()
+	defer df.Release()
+
+	var buf bytes.Buffer
+	zw, err := NewCompressionWriter(&buf, CompressionZstd)
+	require.NoError(t, err)
+	require.NoError(t, WriteCSVTo(df, zw))
+	require.NoError(t, zw.Close())
+
+	result, err := ReadCSVFrom(&buf, CSVReadOptions{})
+	require.NoError(t, err)
+	defer result.Release()
+	assert.Equal(t, int64(3), result.NumRows())
+
+	_, err = NewCompressionWriter(&buf, "lzo")
+	assert.Error(t, err)
+}
+
+func TestNewDecompressionReader_PassesPlainDataThrough(t *testing.T) {
+	// Text starting with the bzip2 signature prefix is not bzip2
+	for _, text := range []string{"BZh9,name\n1,a\n", "a", ""} {
+		r, err := NewDecompressionReader(strings.NewReader(text))
+		require.NoError(t, err)
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, text, string(data))
+		require.NoError(t, r.Close())
+	}
+}
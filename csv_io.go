@@ -0,0 +1,395 @@
+package gopherframe
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/felixgeelhaar/GopherFrame/pkg/expr"
+)
+
+// defaultCSVInferRows is the number of rows sampled to infer column types
+// of chunked and streaming reads when CSVReadOptions.InferSchemaRows is zero.
+const defaultCSVInferRows = 100
+
+// CSVReadOptions configures how CSV files are parsed. The zero value reads
+// comma-separated files with a header row, like ReadCSV.
+type CSVReadOptions struct {
+	// Delimiter separates fields. Zero means ','.
+	Delimiter rune
+
+	// Comment starts a comment line, which is skipped. Zero disables comments.
+	Comment rune
+
+	// NoHeader treats the first row as data. Columns are then named
+	// column_1, column_2, ... unless Schema is set.
+	NoHeader bool
+
+	// SkipRows is the number of lines skipped before the header, for files
+	// that start with a preamble.
+	SkipRows int
+
+	// NullValues are the field values read as null in every column, for
+	// example "NA", "\N" or "". When nil, only empty fields of non-string
+	// columns are null.
+	NullValues []string
+
+	// Schema gives the name and type of every column and disables type
+	// inference. A header row, if present, must have as many fields.
+	Schema *arrow.Schema
+
+	// ColumnTypes overrides the inferred or schema type of single columns.
+	ColumnTypes map[string]arrow.DataType
+
+	// InferSchemaRows is the number of rows sampled to infer column types.
+	// Zero samples every row when the whole file is read and the first 100
+	// rows when it is read in chunks; a negative value samples every row.
+	InferSchemaRows int
+
+	// LazyQuotes allows quotes inside unquoted fields and unescaped quotes
+	// inside quoted fields.
+	LazyQuotes bool
+
+	// AllowRaggedRows accepts rows with a different number of fields than
+	// the header. Missing trailing fields are null and extra fields are
+	// dropped.
+	AllowRaggedRows bool
+}
+
+// ReadCSVWithOptions reads a DataFrame from a CSV file parsed as described by
+// opts. Columns without an explicit type are inferred as int64, float64 or
+// string from every row, or from the first InferSchemaRows rows when it is
+// set; a later value that does not fit the inferred type is then reported
+// with its row number. A file without data rows gives an empty DataFrame.
+//
+// Files compressed with gzip, zstd, bzip2 or xz are decompressed while they
+// are read. The codec is taken from the extension (.gz, .zst, .bz2, .xz) or,
+// for other names, from the magic bytes at the start of the file.
+//
+// Example:
+//
+//	df, err := ReadCSVWithOptions("export.tsv", CSVReadOptions{
+//	    Delimiter:   '\t',
+//	    NullValues:  []string{"", "NA", `\N`},
+//	    ColumnTypes: map[string]arrow.DataType{"zip": arrow.BinaryTypes.String},
+//	})
+func ReadCSVWithOptions(filename string, opts CSVReadOptions) (*DataFrame, error) {
+	if err := validateFilePath(filename); err != nil {
+		return nil, err
+	}
+
+	f, err := openCompressed(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CSV file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	return readCSV(f, opts)
+}
+
+// ReadCSVFrom reads a DataFrame from CSV data in r, parsed as described by
+// opts like ReadCSVWithOptions. Compressed data is decompressed.
+func ReadCSVFrom(r io.Reader, opts CSVReadOptions) (*DataFrame, error) {
+	data, err := NewDecompressionReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = data.Close() }()
+
+	return readCSV(data, opts)
+}
+
+// readCSV reads a DataFrame from uncompressed CSV data.
+func readCSV(r io.Reader, opts CSVReadOptions) (*DataFrame, error) {
+	reader, err := newCSVReader(r, opts)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := reader.readRows(-1)
+	if err != nil {
+		return nil, err
+	}
+	record, err := reader.buildRecord(reader.resolveSchema(rows, -1), rows, 1)
+	if err != nil {
+		return nil, err
+	}
+	defer record.Release()
+	return NewDataFrame(record), nil
+}
+
+// csvReader reads rows from CSV data according to CSVReadOptions.
+type csvReader struct {
+	reader *csv.Reader
+	opts   CSVReadOptions
+	names  []string
+	nulls  map[string]bool
+
+	// pending holds the first data row when it was read to count columns
+	pending  []string
+	rowsRead int
+}
+
+// newCSVReader skips the preamble, reads the header and resolves column names.
+func newCSVReader(r io.Reader, opts CSVReadOptions) (*csvReader, error) {
+	if opts.SkipRows < 0 {
+		return nil, fmt.Errorf("skip rows must not be negative, got %d", opts.SkipRows)
+	}
+
+	buffered := bufio.NewReader(r)
+	for i := 0; i < opts.SkipRows; i++ {
+		if _, err := buffered.ReadString('\n'); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to skip CSV rows: %w", err)
+		}
+	}
+
+	reader := csv.NewReader(buffered)
+	if opts.Delimiter != 0 {
+		reader.Comma = opts.Delimiter
+	}
+	reader.Comment = opts.Comment
+	reader.LazyQuotes = opts.LazyQuotes
+	if opts.AllowRaggedRows {
+		reader.FieldsPerRecord = -1
+	}
+
+	cr := &csvReader{reader: reader, opts: opts}
+	if opts.NullValues != nil {
+		cr.nulls = make(map[string]bool, len(opts.NullValues))
+		for _, v := range opts.NullValues {
+			cr.nulls[v] = true
+		}
+	}
+
+	var width int
+	switch {
+	case !opts.NoHeader:
+		header, err := reader.Read()
+		if err != nil && (opts.Schema == nil || !errors.Is(err, io.EOF)) {
+			return nil, fmt.Errorf("failed to read CSV header: %w", err)
+		}
+		cr.names = header
+		width = len(header)
+	case opts.Schema == nil:
+		// Without a header or schema, the first row determines the columns
+		row, err := reader.Read()
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to read CSV row 1: %w", err)
+		}
+		cr.pending = row
+		width = len(row)
+		cr.names = make([]string, width)
+		for i := range cr.names {
+			cr.names[i] = "column_" + strconv.Itoa(i+1)
+		}
+	}
+
+	if opts.Schema != nil {
+		if cr.names != nil && width != opts.Schema.NumFields() {
+			return nil, fmt.Errorf("CSV header has %d columns but schema has %d", width, opts.Schema.NumFields())
+		}
+		cr.names = make([]string, opts.Schema.NumFields())
+		for i, field := range opts.Schema.Fields() {
+			cr.names[i] = field.Name
+		}
+	}
+	for name := range opts.ColumnTypes {
+		if !containsString(cr.names, name) {
+			return nil, fmt.Errorf("column not found: %s", name)
+		}
+	}
+	return cr, nil
+}
+
+// next returns the next data row, padded or truncated to the column count
+// when ragged rows are allowed. It returns io.EOF at the end of the data.
+func (cr *csvReader) next() ([]string, error) {
+	row := cr.pending
+	cr.pending = nil
+	if row == nil {
+		var err error
+		row, err = cr.reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV row %d: %w", cr.rowsRead+1, err)
+		}
+	}
+	cr.rowsRead++
+
+	width := len(cr.names)
+	if len(row) != width {
+		if !cr.opts.AllowRaggedRows {
+			return nil, fmt.Errorf("CSV row %d has %d fields, expected %d", cr.rowsRead, len(row), width)
+		}
+		if len(row) > width {
+			row = row[:width]
+		}
+	}
+	return row, nil
+}
+
+// readRows reads up to n data rows, or every remaining row when n is negative.
+func (cr *csvReader) readRows(n int) ([][]string, error) {
+	var rows [][]string
+	for n < 0 || len(rows) < n {
+		row, err := cr.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// resolveSchema returns the column types from the options, inferring the
+// remaining ones from a sample of rows. defaultLimit is the sample size when
+// InferSchemaRows is zero; negative sizes sample every row.
+func (cr *csvReader) resolveSchema(rows [][]string, defaultLimit int) *arrow.Schema {
+	limit := cr.opts.InferSchemaRows
+	if limit == 0 {
+		limit = defaultLimit
+	}
+	sample := rows
+	if limit >= 0 {
+		sample = rows[:min(limit, len(rows))]
+	}
+
+	fields := make([]arrow.Field, len(cr.names))
+	for i, name := range cr.names {
+		var dataType arrow.DataType
+		switch {
+		case cr.opts.ColumnTypes[name] != nil:
+			dataType = cr.opts.ColumnTypes[name]
+		case cr.opts.Schema != nil:
+			dataType = cr.opts.Schema.Field(i).Type
+		default:
+			dataType = inferColumnType(sample, i, cr.isNull)
+		}
+		fields[i] = arrow.Field{Name: name, Type: dataType, Nullable: true}
+	}
+	return arrow.NewSchema(fields, nil)
+}
+
+// isNull reports whether a field value is null in a column. Without
+// NullValues, empty fields are null except in string columns.
+func (cr *csvReader) isNull(value string, stringColumn bool) bool {
+	if cr.nulls == nil {
+		return value == "" && !stringColumn
+	}
+	return cr.nulls[value]
+}
+
+// buildRecord converts rows to a record with the given schema. firstRow is
+// the 1-based row number of rows[0], used in error messages.
+func (cr *csvReader) buildRecord(schema *arrow.Schema, rows [][]string, firstRow int) (arrow.Record, error) {
+	pool := memory.NewGoAllocator()
+	columns := make([]arrow.Array, 0, schema.NumFields())
+	defer func() {
+		for _, col := range columns {
+			col.Release()
+		}
+	}()
+
+	for i, field := range schema.Fields() {
+		stringColumn := field.Type.ID() == arrow.STRING
+		builder := array.NewStringBuilder(pool)
+		builder.Reserve(len(rows))
+		for _, row := range rows {
+			if i >= len(row) || cr.isNull(row[i], stringColumn) {
+				builder.AppendNull()
+			} else {
+				builder.Append(row[i])
+			}
+		}
+		values := builder.NewStringArray()
+		builder.Release()
+
+		if stringColumn {
+			columns = append(columns, values)
+			continue
+		}
+		col, err := castCSVColumn(values, field, firstRow)
+		values.Release()
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, col)
+	}
+
+	return array.NewRecord(schema, columns, int64(len(rows))), nil
+}
+
+// castCSVColumn parses a column of CSV fields as field.Type, reporting the
+// first value that does not parse with its row number.
+func castCSVColumn(values *array.String, field arrow.Field, firstRow int) (arrow.Array, error) {
+	col, err := expr.CastArray(values, field.Type, expr.CastSafe)
+	if err != nil {
+		return nil, fmt.Errorf("column %s: %w", field.Name, err)
+	}
+	if col.NullN() > values.NullN() {
+		for j := 0; j < values.Len(); j++ {
+			if values.IsValid(j) && col.IsNull(j) {
+				col.Release()
+				return nil, fmt.Errorf("column %s: cannot parse %q as %s at row %d",
+					field.Name, values.Value(j), field.Type, firstRow+j)
+			}
+		}
+	}
+	return col, nil
+}
+
+// inferColumnType determines a column type from a sample of rows: int64 if
+// every non-null value is a base-10 integer, float64 if every value is a
+// number, and string otherwise or when the sample has no non-null values.
+func inferColumnType(sample [][]string, colIdx int, isNull func(value string, stringColumn bool) bool) arrow.DataType {
+	hasInt := true
+	hasFloat := true
+	seen := false
+
+	for _, row := range sample {
+		if colIdx >= len(row) || isNull(row[colIdx], false) {
+			continue
+		}
+		seen = true
+		val := row[colIdx]
+
+		// Try parsing as int
+		if _, err := strconv.ParseInt(val, 10, 64); err != nil {
+			hasInt = false
+		}
+
+		// Try parsing as float
+		if _, err := strconv.ParseFloat(val, 64); err != nil {
+			hasFloat = false
+		}
+
+		// If neither int nor float, it's a string
+		if !hasInt && !hasFloat {
+			return arrow.BinaryTypes.String
+		}
+	}
+
+	switch {
+	case !seen:
+		return arrow.BinaryTypes.String
+	case hasInt:
+		return arrow.PrimitiveTypes.Int64
+	case hasFloat:
+		return arrow.PrimitiveTypes.Float64
+	default:
+		return arrow.BinaryTypes.String
+	}
+}
@@ -0,0 +1,196 @@
+package gopherframe
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestCSV(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "data.csv")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestReadCSVWithOptions_DelimiterCommentAndSkipRows(t *testing.T) {
+	path := writeTestCSV(t, "exported by tool v1\n\"generated\" 2024\nid;name\n# a comment\n1;Alice\n2;Bob\n")
+
+	df, err := ReadCSVWithOptions(path, CSVReadOptions{Delimiter: ';', Comment: '#', SkipRows: 2})
+	require.NoError(t, err)
+	defer df.Release()
+
+	assert.Equal(t, []string{"id", "name"}, df.ColumnNames())
+	assert.Equal(t, int64(2), df.NumRows())
+	assert.Equal(t, arrow.PrimitiveTypes.Int64, df.Schema().Field(0).Type)
+	assert.Equal(t, "Bob", df.Record().Column(1).(*array.String).Value(1))
+}
+
+func TestReadCSVWithOptions_NoHeader(t *testing.T) {
+	path := writeTestCSV(t, "1,x\n2,y\n")
+
+	df, err := ReadCSVWithOptions(path, CSVReadOptions{NoHeader: true})
+	require.NoError(t, err)
+	defer df.Release()
+	assert.Equal(t, []string{"column_1", "column_2"}, df.ColumnNames())
+	assert.Equal(t, int64(2), df.NumRows())
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int32},
+		{Name: "code", Type: arrow.BinaryTypes.String},
+	}, nil)
+	typed, err := ReadCSVWithOptions(path, CSVReadOptions{NoHeader: true, Schema: schema})
+	require.NoError(t, err)
+	defer typed.Release()
+	assert.Equal(t, []string{"id", "code"}, typed.ColumnNames())
+	assert.Equal(t, []int32{1, 2}, typed.Record().Column(0).(*array.Int32).Int32Values())
+}
+
+func TestReadCSVWithOptions_NullValues(t *testing.T) {
+	path := writeTestCSV(t, "name,score\nAlice,NA\n,\\N\nCarol,3.5\n")
+
+	df, err := ReadCSVWithOptions(path, CSVReadOptions{NullValues: []string{"", "NA", `\N`}})
+	require.NoError(t, err)
+	defer df.Release()
+
+	names := df.Record().Column(0).(*array.String)
+	scores := df.Record().Column(1).(*array.Float64)
+	assert.True(t, names.IsNull(1), "empty string is a null marker")
+	assert.Equal(t, arrow.PrimitiveTypes.Float64, scores.DataType())
+	assert.Equal(t, 2, scores.NullN())
+	assert.Equal(t, 3.5, scores.Value(2))
+
+	// Without null markers empty strings stay values in string columns
+	plain, err := ReadCSV(writeTestCSV(t, "name,score\n,1\nBob,\n"))
+	require.NoError(t, err)
+	defer plain.Release()
+	assert.False(t, plain.Record().Column(0).IsNull(0))
+	assert.True(t, plain.Record().Column(1).IsNull(1))
+}
+
+func TestReadCSVWithOptions_SchemaAndColumnTypes(t *testing.T) {
+	path := writeTestCSV(t, "zip,joined,active\n01234,2024-01-15,true\n98765,2024-02-01,false\n")
+
+	df, err := ReadCSVWithOptions(path, CSVReadOptions{ColumnTypes: map[string]arrow.DataType{
+		"zip":    arrow.BinaryTypes.String,
+		"joined": arrow.FixedWidthTypes.Date32,
+		"active": arrow.FixedWidthTypes.Boolean,
+	}})
+	require.NoError(t, err)
+	defer df.Release()
+
+	assert.Equal(t, "01234", df.Record().Column(0).(*array.String).Value(0))
+	assert.Equal(t, arrow.FixedWidthTypes.Date32, df.Schema().Field(1).Type)
+	assert.True(t, df.Record().Column(2).(*array.Boolean).Value(0))
+
+	_, err = ReadCSVWithOptions(path, CSVReadOptions{ColumnTypes: map[string]arrow.DataType{"missing": arrow.BinaryTypes.String}})
+	assert.ErrorContains(t, err, "column not found: missing")
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "zip", Type: arrow.BinaryTypes.String}}, nil)
+	_, err = ReadCSVWithOptions(path, CSVReadOptions{Schema: schema})
+	assert.ErrorContains(t, err, "schema has 1")
+}
+
+func TestReadCSVWithOptions_TypeConflictReportsRow(t *testing.T) {
+	path := writeTestCSV(t, "id\n1\n2\n3\nfour\n")
+
+	_, err := ReadCSVWithOptions(path, CSVReadOptions{InferSchemaRows: 2})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `cannot parse "four" as int64 at row 4`)
+
+	// Sampling every row infers a string column instead
+	df, err := ReadCSVWithOptions(path, CSVReadOptions{InferSchemaRows: -1})
+	require.NoError(t, err)
+	defer df.Release()
+	assert.Equal(t, arrow.BinaryTypes.String, df.Schema().Field(0).Type)
+}
+
+func TestReadCSV_InfersFromEveryRow(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("id,qty\n")
+	for i := 1; i <= 150; i++ {
+		fmt.Fprintf(&sb, "%d,%d\n", i, i%7)
+	}
+	sb.WriteString("151,3.5\n")
+	path := writeTestCSV(t, sb.String())
+
+	// A value past the default sample of chunked reads widens the column
+	df, err := ReadCSV(path)
+	require.NoError(t, err)
+	defer df.Release()
+	require.Equal(t, arrow.PrimitiveTypes.Float64, df.Schema().Field(1).Type)
+	qty := df.Record().Column(1).(*array.Float64)
+	assert.Equal(t, 3.5, qty.Value(150))
+	assert.Equal(t, 0, qty.NullN())
+
+	// An explicit sample size keeps the strict per-row check
+	_, err = ReadCSVWithOptions(path, CSVReadOptions{InferSchemaRows: 100})
+	assert.ErrorContains(t, err, `cannot parse "3.5" as int64 at row 151`)
+}
+
+func TestReadCSVWithOptions_RaggedRowsAndQuotes(t *testing.T) {
+	path := writeTestCSV(t, "a,b,c\n1,2\n3,4,5,6\n")
+
+	_, err := ReadCSV(path)
+	assert.Error(t, err)
+
+	df, err := ReadCSVWithOptions(path, CSVReadOptions{AllowRaggedRows: true})
+	require.NoError(t, err)
+	defer df.Release()
+	assert.Equal(t, int64(2), df.NumRows())
+	c := df.Record().Column(2).(*array.Int64)
+	assert.True(t, c.IsNull(0))
+	assert.Equal(t, int64(5), c.Value(1))
+
+	quoted := writeTestCSV(t, "name\nsay \"hi\"\n")
+	_, err = ReadCSV(quoted)
+	assert.Error(t, err)
+	lazy, err := ReadCSVWithOptions(quoted, CSVReadOptions{LazyQuotes: true})
+	require.NoError(t, err)
+	defer lazy.Release()
+	assert.Equal(t, `say "hi"`, lazy.Record().Column(0).(*array.String).Value(0))
+}
+
+func TestReadCSVWithOptions_EmptyFile(t *testing.T) {
+	df, err := ReadCSV(writeTestCSV(t, "id,name\n"))
+	require.NoError(t, err)
+	defer df.Release()
+	assert.Equal(t, []string{"id", "name"}, df.ColumnNames())
+	assert.Equal(t, int64(0), df.NumRows())
+
+	_, err = ReadCSV(writeTestCSV(t, ""))
+	assert.Error(t, err)
+}
+
+func TestReadCSVChunkedWithOptions(t *testing.T) {
+	path := writeTestCSV(t, "id|score\n1|NA\n2|1.5\n3|2.5\n")
+
+	it, err := ReadCSVChunkedWithOptions(path, 2, CSVReadOptions{Delimiter: '|', NullValues: []string{"NA"}})
+	require.NoError(t, err)
+	require.Equal(t, 2, it.Len())
+
+	first := it.Next()
+	second := it.Next()
+	assert.True(t, first.Schema().Equal(second.Schema()), "chunks share the schema of the first chunk")
+	assert.True(t, first.Record().Column(1).IsNull(0))
+
+	all, err := it.Collect()
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), all.NumRows())
+
+	sr, err := ReadCSVStreamingWithOptions(path, 2, 1, CSVReadOptions{Delimiter: '|', NullValues: []string{"NA"}})
+	require.NoError(t, err)
+	var rows int64
+	for chunk := range sr.Chunks() {
+		rows += chunk.NumRows()
+	}
+	require.NoError(t, sr.Err())
+	assert.Equal(t, int64(3), rows)
+}
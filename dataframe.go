@@ -111,6 +111,25 @@ func (df *DataFrame) Select(columnNames ...string) *DataFrame {
 	return &DataFrame{coreDF: newCoreDF}
 }
 
+// Limit returns a new DataFrame with at most the first n rows.
+// The result shares the underlying Arrow buffers (zero-copy).
+func (df *DataFrame) Limit(n int64) *DataFrame {
+	if df.err != nil {
+		return &DataFrame{err: df.err}
+	}
+	if n < 0 {
+		return &DataFrame{err: fmt.Errorf("limit must be non-negative, got %d", n)}
+	}
+	if n > df.NumRows() {
+		n = df.NumRows()
+	}
+
+	sliced := df.coreDF.Record().NewSlice(0, n)
+	defer sliced.Release()
+
+	return NewDataFrame(sliced)
+}
+
 // WithColumn returns a new DataFrame with an additional or replaced column.
 func (df *DataFrame) WithColumn(name string, expression expr.Expr) *DataFrame {
 	if df.err != nil {
@@ -134,6 +153,40 @@ func (df *DataFrame) WithColumn(name string, expression expr.Expr) *DataFrame {
 	return &DataFrame{coreDF: newCoreDF}
 }
 
+// replaceColumns replaces every column named in values with the expression
+// that replacement builds from the column's field and its value. Columns are
+// replaced in sorted order so errors are reproducible, and every intermediate
+// DataFrame is released. The result is always a new DataFrame, even when
+// values is empty.
+func replaceColumns[V any](df *DataFrame, values map[string]V, replacement func(field arrow.Field, value V) (expr.Expr, error)) *DataFrame {
+	if df.err != nil {
+		return &DataFrame{err: df.err}
+	}
+
+	schema := df.Schema()
+	result := NewDataFrame(df.Record())
+	for _, col := range sortedKeys(values) {
+		indices := schema.FieldIndices(col)
+		if len(indices) == 0 {
+			result.Release()
+			return &DataFrame{err: fmt.Errorf("column not found: %s", col)}
+		}
+		expression, err := replacement(schema.Field(indices[0]), values[col])
+		if err != nil {
+			result.Release()
+			return &DataFrame{err: err}
+		}
+
+		next := result.WithColumn(col, expression)
+		result.Release()
+		if next.Err() != nil {
+			return next
+		}
+		result = next
+	}
+	return result
+}
+
 // Col creates a column expression for use in operations like Filter and WithColumn.
 // Example: df.Col("age") returns an expression representing the "age" column
 func (df *DataFrame) Col(name string) expr.Expr {
@@ -46,6 +46,69 @@ func TestDataFrameFilter(t *testing.T) {
 	}
 }
 
+func TestDataFrameFilterCombinedPredicate(t *testing.T) {
+	df := createSampleDataFrame()
+	defer df.Release()
+
+	// score >= 90 AND NOT(name == "Charlie")
+	filtered := df.Filter(Col("score").Ge(Lit(90.0)).And(Col("name").Eq(Lit("Charlie")).Not()))
+	if filtered.Err() != nil {
+		t.Fatalf("Filter failed: %v", filtered.Err())
+	}
+	defer filtered.Release()
+
+	// Should return only Alice
+	if filtered.NumRows() != 1 {
+		t.Errorf("Expected 1 row after filter, got %d", filtered.NumRows())
+	}
+
+	// id <= 1 OR score < 90
+	filtered2 := df.Filter(Col("id").Le(Lit(int64(1))).Or(Col("score").Lt(Lit(90.0))))
+	if filtered2.Err() != nil {
+		t.Fatalf("Filter failed: %v", filtered2.Err())
+	}
+	defer filtered2.Release()
+
+	// Should return Alice and Bob
+	if filtered2.NumRows() != 2 {
+		t.Errorf("Expected 2 rows after filter, got %d", filtered2.NumRows())
+	}
+}
+
+func TestDataFrameConditionalColumn(t *testing.T) {
+	df := createSampleDataFrame()
+	defer df.Release()
+
+	grade := When(Col("score").Ge(Lit(95.0))).Then(Lit("A")).
+		When(Col("score").Ge(Lit(90.0))).Then(Lit("B")).
+		Otherwise(Lit("C"))
+
+	result := df.WithColumn("grade", grade)
+	if result.Err() != nil {
+		t.Fatalf("WithColumn failed: %v", result.Err())
+	}
+	defer result.Release()
+
+	grades := result.Record().Column(3).(*array.String)
+	expected := []string{"A", "C", "B"}
+	for i, exp := range expected {
+		if grades.Value(i) != exp {
+			t.Errorf("Expected grade %s at row %d, got %s", exp, i, grades.Value(i))
+		}
+	}
+
+	// Conditional expressions can be used as filter predicates too
+	filtered := df.Filter(When(Col("id").Eq(Lit(int64(2)))).Then(Lit(true)).Otherwise(Col("score").Gt(Lit(95.0))))
+	if filtered.Err() != nil {
+		t.Fatalf("Filter failed: %v", filtered.Err())
+	}
+	defer filtered.Release()
+
+	if filtered.NumRows() != 2 {
+		t.Errorf("Expected 2 rows after filter, got %d", filtered.NumRows())
+	}
+}
+
 func TestDataFrameSelect(t *testing.T) {
 	df := createSampleDataFrame()
 	defer df.Release()
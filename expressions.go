@@ -17,6 +17,17 @@ func Lit(value interface{}) expr.Expr {
 	return expr.Lit(value)
 }
 
+// Coalesce returns the first non-null value among the given expressions for each row.
+func Coalesce(exprs ...expr.Expr) expr.Expr {
+	return expr.Coalesce(exprs...)
+}
+
+// When starts a conditional CASE WHEN expression.
+// Example: When(Col("amount").Gt(Lit(1000.0))).Then(Lit("large")).Otherwise(Lit("small"))
+func When(condition expr.Expr) *expr.WhenBuilder {
+	return expr.When(condition)
+}
+
 // Expression builder methods
 // These will be added to the core expression types to enable fluent chaining
 
@@ -37,4 +48,16 @@ type ColumnExpr interface {
 	Lt(other expr.Expr) expr.Expr
 	// Eq creates an equality comparison
 	Eq(other expr.Expr) expr.Expr
+	// Ne creates an inequality comparison
+	Ne(other expr.Expr) expr.Expr
+	// Ge creates a greater-than-or-equal comparison
+	Ge(other expr.Expr) expr.Expr
+	// Le creates a less-than-or-equal comparison
+	Le(other expr.Expr) expr.Expr
+	// And creates a logical AND of two boolean expressions
+	And(other expr.Expr) expr.Expr
+	// Or creates a logical OR of two boolean expressions
+	Or(other expr.Expr) expr.Expr
+	// Not creates a logical negation of a boolean expression
+	Not() expr.Expr
 }
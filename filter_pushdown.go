@@ -1,6 +1,8 @@
 package gopherframe
 
 import (
+	"fmt"
+
 	"github.com/felixgeelhaar/GopherFrame/pkg/expr"
 )
 
@@ -16,6 +18,9 @@ import (
 //	    Filter(df.Col("a").Gt(Lit(0.0))).
 //	    WithColumn("d", df.Col("b").Add(df.Col("c")))
 //	result := plan.Execute()
+//
+// Deprecated: Use DataFrame.Lazy, which covers every DataFrame operation and
+// optimizes the whole plan, including joins and aggregations.
 type QueryPlan struct {
 	df         *DataFrame
 	operations []queryOp
@@ -112,8 +117,7 @@ func pushFiltersDown(ops []queryOp) []queryOp {
 			switch prev.opType {
 			case "with_column":
 				// Can push filter before with_column if filter doesn't use the computed column
-				filterStr := result[i].predicate.String()
-				if prev.colName != "" && !containsColumn(filterStr, prev.colName) {
+				if prev.colName != "" && !expr.ReferencesColumn(result[i].predicate, prev.colName) {
 					canPush = true
 				}
 			case "select":
@@ -131,18 +135,37 @@ func pushFiltersDown(ops []queryOp) []queryOp {
 	return result
 }
 
-// containsColumn checks if an expression string references a column name.
-func containsColumn(exprStr, colName string) bool {
-	// Simple heuristic: check if the column name appears in the expression string
-	return len(colName) > 0 && len(exprStr) > 0 &&
-		containsSubstring(exprStr, colName)
+// Explain returns the plan before and after filter pushdown as indented
+// trees, in the same format as LazyFrame.Explain.
+func (qp *QueryPlan) Explain() string {
+	if qp.df.err != nil {
+		return fmt.Sprintf("Invalid plan: %v\n", qp.df.err)
+	}
+	return explainPlans(qp.toPlan(qp.operations), qp.toPlan(pushFiltersDown(qp.operations)))
+}
+
+// ExplainAnalyze executes the optimized plan and returns it annotated with
+// per-operator row counts, elapsed time and bytes allocated, in the same
+// format as LazyFrame.ExplainAnalyze.
+func (qp *QueryPlan) ExplainAnalyze() (string, error) {
+	if qp.df.err != nil {
+		return "", qp.df.err
+	}
+	return explainAnalyzePlan(qp.toPlan(qp.operations), qp.toPlan(pushFiltersDown(qp.operations)))
 }
 
-func containsSubstring(s, sub string) bool {
-	for i := 0; i <= len(s)-len(sub); i++ {
-		if s[i:i+len(sub)] == sub {
-			return true
+// toPlan converts a sequence of operations into a logical plan over the source.
+func (qp *QueryPlan) toPlan(ops []queryOp) planNode {
+	var node planNode = &scanNode{df: qp.df}
+	for _, op := range ops {
+		switch op.opType {
+		case "filter":
+			node = &filterNode{input: node, predicate: op.predicate}
+		case "select":
+			node = &projectNode{input: node, selected: op.columns}
+		case "with_column":
+			node = &withColumnNode{input: node, name: op.colName, expr: op.colExpr}
 		}
 	}
-	return false
+	return node
 }
@@ -97,3 +97,15 @@ func TestPushFiltersDown_NoPushIfDependsOnComputed(t *testing.T) {
 	assert.Equal(t, "with_column", optimized[0].opType)
 	assert.Equal(t, "filter", optimized[1].opType)
 }
+
+func TestPushFiltersDown_ColumnNameIsSubstring(t *testing.T) {
+	ops := []queryOp{
+		{opType: "with_column", colName: "a"},
+		{opType: "filter", predicate: Col("amount").Gt(Lit(0.0))},
+	}
+
+	optimized := pushFiltersDown(ops)
+	// "a" appears in "amount" but the filter does not reference column "a"
+	assert.Equal(t, "filter", optimized[0].opType)
+	assert.Equal(t, "with_column", optimized[1].opType)
+}
@@ -0,0 +1,180 @@
+package gopherframe
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/bitutil"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// Flatten turns struct fields into top-level columns named by their dotted
+// path, such as "user.address.city". Nested structs are flattened down to
+// their leaf fields, and rows in which an enclosing struct is null are null.
+//
+// Without paths, every struct column is replaced by its leaf fields. A path
+// naming a struct column replaces that column; a path to a field inside a
+// struct, such as "payload.user.id", adds columns for that field after the
+// column it is taken from and keeps the column.
+//
+// Example:
+//
+//	events, _ := ReadNDJSON("events.ndjson")
+//	flat := events.Flatten("payload.user.id", "context")
+func (df *DataFrame) Flatten(paths ...string) *DataFrame {
+	if df.err != nil {
+		return &DataFrame{err: df.err}
+	}
+
+	record := df.coreDF.Record()
+	schema := record.Schema()
+	numCols := schema.NumFields()
+
+	// Columns derived from each top-level column, and whether they replace it
+	extracted := make([][]flatColumn, numCols)
+	replaced := make([]bool, numCols)
+	defer func() {
+		for _, columns := range extracted {
+			releaseFlatColumns(columns)
+		}
+	}()
+
+	if len(paths) == 0 {
+		for i, field := range schema.Fields() {
+			if field.Type.ID() == arrow.STRUCT {
+				replaced[i] = true
+				extracted[i] = flattenArray(field.Name, record.Column(i))
+			}
+		}
+	}
+	for _, path := range paths {
+		idx, arr, err := resolveFieldPath(record, path)
+		if err != nil {
+			return &DataFrame{err: err}
+		}
+		if path == schema.Field(idx).Name {
+			if arr.DataType().ID() != arrow.STRUCT {
+				arr.Release()
+				return &DataFrame{err: fmt.Errorf("column %s is not a struct", path)}
+			}
+			replaced[idx] = true
+		}
+		extracted[idx] = append(extracted[idx], flattenArray(path, arr)...)
+		arr.Release()
+	}
+
+	var fields []arrow.Field
+	var columns []arrow.Array
+	seen := make(map[string]bool)
+	for i, field := range schema.Fields() {
+		candidates := extracted[i]
+		if !replaced[i] {
+			candidates = append([]flatColumn{{field: field, array: record.Column(i)}}, candidates...)
+		}
+		for _, col := range candidates {
+			if seen[col.field.Name] {
+				return &DataFrame{err: fmt.Errorf("duplicate column name: %s", col.field.Name)}
+			}
+			seen[col.field.Name] = true
+			fields = append(fields, col.field)
+			columns = append(columns, col.array)
+		}
+	}
+
+	result := array.NewRecord(arrow.NewSchema(fields, nil), columns, record.NumRows())
+	defer result.Release()
+	return NewDataFrame(result)
+}
+
+// flatColumn is a column produced by Flatten.
+type flatColumn struct {
+	field arrow.Field
+	array arrow.Array
+}
+
+func releaseFlatColumns(columns []flatColumn) {
+	for _, col := range columns {
+		col.array.Release()
+	}
+}
+
+// resolveFieldPath returns the index of the top-level column a path starts
+// at and the array of the field it names. A column whose name is the whole
+// path takes precedence over a dotted path into struct fields.
+func resolveFieldPath(record arrow.Record, path string) (int, arrow.Array, error) {
+	schema := record.Schema()
+	if indices := schema.FieldIndices(path); len(indices) > 0 {
+		arr := record.Column(indices[0])
+		arr.Retain()
+		return indices[0], arr, nil
+	}
+
+	parts := strings.Split(path, ".")
+	indices := schema.FieldIndices(parts[0])
+	if len(indices) == 0 {
+		return 0, nil, fmt.Errorf("column not found: %s", path)
+	}
+	arr := record.Column(indices[0])
+	arr.Retain()
+	for _, name := range parts[1:] {
+		structArr, ok := arr.(*array.Struct)
+		if !ok {
+			arr.Release()
+			return 0, nil, fmt.Errorf("column not found: %s", path)
+		}
+		fieldIdx, ok := structArr.DataType().(*arrow.StructType).FieldIdx(name)
+		if !ok {
+			arr.Release()
+			return 0, nil, fmt.Errorf("column not found: %s", path)
+		}
+		child := withParentNulls(structArr.Field(fieldIdx), structArr)
+		arr.Release()
+		arr = child
+	}
+	return indices[0], arr, nil
+}
+
+// flattenArray returns the leaf fields of a struct array as columns named
+// by their path below name, or the array itself if it is not a struct. The
+// returned arrays are retained.
+func flattenArray(name string, arr arrow.Array) []flatColumn {
+	structArr, ok := arr.(*array.Struct)
+	if !ok {
+		arr.Retain()
+		return []flatColumn{{field: arrow.Field{Name: name, Type: arr.DataType(), Nullable: true}, array: arr}}
+	}
+
+	var columns []flatColumn
+	for i, field := range structArr.DataType().(*arrow.StructType).Fields() {
+		child := withParentNulls(structArr.Field(i), structArr)
+		columns = append(columns, flattenArray(name+"."+field.Name, child)...)
+		child.Release()
+	}
+	return columns
+}
+
+// withParentNulls returns child with the rows in which its parent struct is
+// null set to null, reusing the child's value buffers.
+func withParentNulls(child arrow.Array, parent arrow.Array) arrow.Array {
+	if parent.NullN() == 0 {
+		child.Retain()
+		return child
+	}
+
+	data := child.Data()
+	offset := data.Offset()
+	bitmap := memory.NewResizableBuffer(memory.DefaultAllocator)
+	bitmap.Resize(int(bitutil.BytesForBits(int64(offset + child.Len()))))
+	bits := bitmap.Bytes()
+	for i := 0; i < child.Len(); i++ {
+		bitutil.SetBitTo(bits, offset+i, child.IsValid(i) && parent.IsValid(i))
+	}
+
+	buffers := append([]*memory.Buffer{bitmap}, data.Buffers()[1:]...)
+	masked := array.NewData(data.DataType(), data.Len(), buffers, data.Children(), array.UnknownNullCount, offset)
+	bitmap.Release()
+	defer masked.Release()
+	return array.MakeFromData(masked)
+}
@@ -0,0 +1,59 @@
+package gopherframe
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readNestedEvents(t *testing.T) *DataFrame {
+	t.Helper()
+	df, err := ReadNDJSONFrom(strings.NewReader(nestedEventsNDJSON), JSONReadOptions{})
+	require.NoError(t, err)
+	return df
+}
+
+func TestFlatten_AllStructColumns(t *testing.T) {
+	df := readNestedEvents(t)
+	defer df.Release()
+
+	flat := df.Flatten()
+	require.NoError(t, flat.Err())
+	defer flat.Release()
+	assert.Equal(t, []string{"amount", "id", "tags", "user.address.city", "user.name"}, flat.ColumnNames())
+
+	cities := flat.Record().Column(3).(*array.String)
+	assert.Equal(t, "Oslo", cities.Value(0))
+	assert.True(t, cities.IsNull(1))
+	names := flat.Record().Column(4).(*array.String)
+	assert.Equal(t, "Bob", names.Value(1))
+	assert.True(t, names.IsNull(2), "fields of a null struct are null")
+}
+
+func TestFlatten_Paths(t *testing.T) {
+	df := readNestedEvents(t)
+	defer df.Release()
+
+	flat := df.Flatten("user.address.city")
+	require.NoError(t, flat.Err())
+	defer flat.Release()
+	assert.Equal(t, []string{"amount", "id", "tags", "user", "user.address.city"}, flat.ColumnNames())
+	assert.Equal(t, "Oslo", flat.Record().Column(4).(*array.String).Value(0))
+
+	flat2 := df.Flatten("user")
+	require.NoError(t, flat2.Err())
+	defer flat2.Release()
+	assert.Equal(t, []string{"amount", "id", "tags", "user.address.city", "user.name"}, flat2.ColumnNames())
+}
+
+func TestFlatten_Errors(t *testing.T) {
+	df := readNestedEvents(t)
+	defer df.Release()
+
+	assert.ErrorContains(t, df.Flatten("user.email").Err(), "column not found: user.email")
+	assert.ErrorContains(t, df.Flatten("id").Err(), "not a struct")
+	assert.ErrorContains(t, df.Flatten("user", "user.name").Err(), "duplicate column name: user.name")
+}
@@ -4,9 +4,14 @@ go 1.25
 
 require (
 	github.com/apache/arrow-go/v18 v18.0.0
+	github.com/dsnet/compress v0.0.1
 	github.com/go-gota/gota v0.12.0
+	github.com/google/flatbuffers v24.3.25+incompatible
+	github.com/klauspost/compress v1.17.11
 	github.com/leanovate/gopter v0.2.11
 	github.com/stretchr/testify v1.9.0
+	github.com/ulikunitz/xz v0.5.12
+	github.com/zeebo/xxh3 v1.0.2
 )
 
 require (
@@ -16,15 +21,12 @@ require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/goccy/go-json v0.10.3 // indirect
 	github.com/golang/snappy v0.0.4 // indirect
-	github.com/google/flatbuffers v24.3.25+incompatible // indirect
 	github.com/klauspost/asmfmt v1.3.2 // indirect
-	github.com/klauspost/compress v1.17.11 // indirect
 	github.com/klauspost/cpuid/v2 v2.2.8 // indirect
 	github.com/minio/asm2plan9s v0.0.0-20200509001527-cdd76441f9d8 // indirect
 	github.com/minio/c2goasm v0.0.0-20190812172519-36a3d3bbc4f3 // indirect
 	github.com/pierrec/lz4/v4 v4.1.21 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/zeebo/xxh3 v1.0.2 // indirect
 	golang.org/x/exp v0.0.0-20240909161429-701f63a606c0 // indirect
 	golang.org/x/mod v0.30.0 // indirect
 	golang.org/x/net v0.48.0 // indirect
@@ -3,14 +3,13 @@ package gopherframe
 
 import (
 	"fmt"
-	"math"
-	"sort"
-	"strings"
+	"runtime"
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/memory"
-	"github.com/felixgeelhaar/GopherFrame/pkg/core"
+	"github.com/felixgeelhaar/GopherFrame/pkg/domain/aggregation"
+	"github.com/felixgeelhaar/GopherFrame/pkg/expr"
 )
 
 // GroupedDataFrame represents a DataFrame grouped by one or more columns.
@@ -22,9 +21,10 @@ type GroupedDataFrame struct {
 
 // Aggregation represents an aggregation operation on a column.
 type Aggregation struct {
-	column    string
-	operation string
-	alias     string
+	spec aggregation.AggregationSpec
+	// input, if not nil, is aggregated instead of spec.Column
+	input  expr.Expr
+	filter expr.Expr
 }
 
 // GroupBy groups the DataFrame by the specified columns.
@@ -50,8 +50,23 @@ func (df *DataFrame) GroupBy(columns ...string) *GroupedDataFrame {
 	}
 }
 
+// AggOptions configures how AggWithOptions aggregates the groups.
+type AggOptions struct {
+	// Parallelism is the number of goroutines that aggregate rows. Zero
+	// uses runtime.GOMAXPROCS(0) and one aggregates on the calling
+	// goroutine. Results are identical for every setting.
+	Parallelism int
+}
+
 // Agg performs the specified aggregations on the grouped data.
 func (gdf *GroupedDataFrame) Agg(aggregations ...Aggregation) *DataFrame {
+	return gdf.AggWithOptions(AggOptions{Parallelism: 1}, aggregations...)
+}
+
+// AggWithOptions performs the specified aggregations on the grouped data.
+// Rows are split into fixed-size morsels that are aggregated into partial
+// states on up to opts.Parallelism goroutines and then merged in row order.
+func (gdf *GroupedDataFrame) AggWithOptions(opts AggOptions, aggregations ...Aggregation) *DataFrame {
 	if gdf.err != nil {
 		return &DataFrame{err: gdf.err}
 	}
@@ -60,8 +75,15 @@ func (gdf *GroupedDataFrame) Agg(aggregations ...Aggregation) *DataFrame {
 		return &DataFrame{err: fmt.Errorf("no aggregations specified")}
 	}
 
-	// For now, implement a simple groupby for single column with basic aggregations
-	result, err := gdf.performGroupBy(aggregations)
+	parallelism := opts.Parallelism
+	if parallelism < 0 {
+		return &DataFrame{err: fmt.Errorf("parallelism cannot be negative: %d", parallelism)}
+	}
+	if parallelism == 0 {
+		parallelism = runtime.GOMAXPROCS(0)
+	}
+
+	result, err := gdf.performGroupBy(aggregations, parallelism)
 	if err != nil {
 		return &DataFrame{err: err}
 	}
@@ -70,792 +92,421 @@ func (gdf *GroupedDataFrame) Agg(aggregations ...Aggregation) *DataFrame {
 }
 
 // performGroupBy executes the actual groupby logic
-func (gdf *GroupedDataFrame) performGroupBy(aggregations []Aggregation) (*DataFrame, error) {
-	// Handle both single and multi-column groupby
-	if len(gdf.groupByCols) == 1 {
-		return gdf.performSingleColumnGroupBy(aggregations)
-	}
-	return gdf.performMultiColumnGroupBy(aggregations)
-}
-
-// performSingleColumnGroupBy handles single column grouping
-func (gdf *GroupedDataFrame) performSingleColumnGroupBy(aggregations []Aggregation) (*DataFrame, error) {
-	groupCol := gdf.groupByCols[0]
-
-	// Handle empty DataFrame
-	if gdf.df.NumRows() == 0 {
-		pool := memory.NewGoAllocator()
-		resultFields := []arrow.Field{{Name: groupCol, Type: arrow.BinaryTypes.String}}
-		resultColumns := []arrow.Array{array.NewStringBuilder(pool).NewArray()}
-		for _, agg := range aggregations {
-			resultFields = append(resultFields, arrow.Field{Name: agg.Name(), Type: arrow.PrimitiveTypes.Float64})
-			b := array.NewFloat64Builder(pool)
-			resultColumns = append(resultColumns, b.NewArray())
-			b.Release()
+func (gdf *GroupedDataFrame) performGroupBy(aggregations []Aggregation, parallelism int) (*DataFrame, error) {
+	// Resolve the input columns of every aggregation, evaluating
+	// expressions and filters against the whole DataFrame
+	inputs := make([]aggregation.Input, len(aggregations))
+	var evaluated []arrow.Array
+	defer func() {
+		for _, arr := range evaluated {
+			arr.Release()
 		}
-		resultSchema := arrow.NewSchema(resultFields, nil)
-		resultRecord := array.NewRecord(resultSchema, resultColumns, 0)
-		return NewDataFrame(resultRecord), nil
-	}
-
-	// Get the grouping column
-	groupSeries, err := gdf.df.coreDF.Column(groupCol)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get group column: %w", err)
-	}
-	defer groupSeries.Release()
-
-	// Extract unique groups and build group indices
-	groups, groupIndices, err := gdf.extractGroups(groupSeries)
-	if err != nil {
-		return nil, fmt.Errorf("failed to extract groups: %w", err)
-	}
-
-	// Build result columns
-	// First column: group keys
-	resultFields := []arrow.Field{{Name: groupCol, Type: groupSeries.DataType()}}
-	resultColumns := []arrow.Array{groups}
-
-	// Add aggregation columns
-	for _, agg := range aggregations {
-		aggField, aggColumn, err := gdf.performAggregation(agg, groupIndices)
+	}()
+	for i, agg := range aggregations {
+		input, err := gdf.aggregationInput(agg, &evaluated)
 		if err != nil {
 			return nil, fmt.Errorf("failed to perform aggregation %s: %w", agg.Name(), err)
 		}
-
-		resultFields = append(resultFields, aggField)
-		resultColumns = append(resultColumns, aggColumn)
-	}
-
-	// Create result schema and record
-	resultSchema := arrow.NewSchema(resultFields, nil)
-	resultRecord := array.NewRecord(resultSchema, resultColumns, int64(groups.Len()))
-
-	return NewDataFrame(resultRecord), nil
-}
-
-// extractGroups finds unique values and their indices
-func (gdf *GroupedDataFrame) extractGroups(groupSeries *core.Series) (arrow.Array, map[string][]int, error) {
-	// Build map of group value to row indices
-	groupMap := make(map[string][]int)
-
-	for i := 0; i < groupSeries.Len(); i++ {
-		if groupSeries.IsNull(i) {
-			continue // Skip null values for now
-		}
-
-		value, err := groupSeries.GetString(i)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to get string value: %w", err)
-		}
-
-		groupMap[value] = append(groupMap[value], i)
-	}
-
-	// Sort group keys for consistent output
-	var groupKeys []string
-	for key := range groupMap {
-		groupKeys = append(groupKeys, key)
+		inputs[i] = input
 	}
-	sort.Strings(groupKeys)
-
-	// Build result array of group keys
-	pool := memory.NewGoAllocator()
-	builder := array.NewStringBuilder(pool)
-	defer builder.Release()
-
-	// Reorganize indices by sorted keys
-	sortedIndices := make(map[string][]int)
-	for _, key := range groupKeys {
-		builder.Append(key)
-		sortedIndices[key] = groupMap[key]
-	}
-
-	groupArray := builder.NewArray()
-	return groupArray, sortedIndices, nil
-}
 
-// performAggregation executes a single aggregation
-func (gdf *GroupedDataFrame) performAggregation(agg Aggregation, groupIndices map[string][]int) (arrow.Field, arrow.Array, error) {
-	// Get the column to aggregate
-	aggSeries, err := gdf.df.coreDF.Column(agg.column)
+	// Get the grouping columns
+	keyDF, err := gdf.df.coreDF.Select(gdf.groupByCols)
 	if err != nil {
-		return arrow.Field{}, nil, fmt.Errorf("failed to get aggregation column: %w", err)
+		return nil, fmt.Errorf("failed to get group columns: %w", err)
 	}
-	defer aggSeries.Release()
-
-	pool := memory.NewGoAllocator()
-
-	switch agg.operation {
-	case "sum":
-		return gdf.performSum(aggSeries, groupIndices, agg.Name(), pool)
-	case "mean":
-		return gdf.performMean(aggSeries, groupIndices, agg.Name(), pool)
-	case "count":
-		return gdf.performCount(aggSeries, groupIndices, agg.Name(), pool)
-	case "min":
-		return gdf.performMin(aggSeries, groupIndices, agg.Name(), pool)
-	case "max":
-		return gdf.performMax(aggSeries, groupIndices, agg.Name(), pool)
-	case "variance":
-		return gdf.performVariance(aggSeries, groupIndices, agg.Name(), pool)
-	case "stddev":
-		return gdf.performStdDev(aggSeries, groupIndices, agg.Name(), pool)
-	case "custom":
-		return gdf.performCustomAgg(aggSeries, groupIndices, agg.Name(), pool)
-	default:
-		if strings.HasPrefix(agg.operation, "concat:") {
-			sep := strings.TrimPrefix(agg.operation, "concat:")
-			return gdf.performConcatAgg(aggSeries, groupIndices, agg.Name(), sep, pool)
-		}
-		return arrow.Field{}, nil, fmt.Errorf("unsupported aggregation: %s", agg.operation)
-	}
-}
+	defer keyDF.Release()
 
-// performSum calculates sum for each group
-func (gdf *GroupedDataFrame) performSum(series *core.Series, groupIndices map[string][]int, name string, pool memory.Allocator) (arrow.Field, arrow.Array, error) {
-	if series.DataType().ID() != arrow.FLOAT64 {
-		return arrow.Field{}, nil, fmt.Errorf("sum aggregation only supports float64, got %s", series.DataType())
-	}
-
-	builder := array.NewFloat64Builder(pool)
-	defer builder.Release()
-
-	// Sort keys to maintain consistent order
-	var keys []string
-	for key := range groupIndices {
-		keys = append(keys, key)
-	}
-	sort.Strings(keys)
-
-	for _, key := range keys {
-		indices := groupIndices[key]
-		sum := 0.0
-
-		for _, idx := range indices {
-			if !series.IsNull(idx) {
-				val, err := series.GetFloat64(idx)
-				if err != nil {
-					return arrow.Field{}, nil, fmt.Errorf("failed to get float64 value: %w", err)
-				}
-				sum += val
-			}
-		}
-
-		builder.Append(sum)
-	}
-
-	field := arrow.Field{Name: name, Type: arrow.PrimitiveTypes.Float64}
-	return field, builder.NewArray(), nil
-}
-
-// performMean calculates mean for each group
-func (gdf *GroupedDataFrame) performMean(series *core.Series, groupIndices map[string][]int, name string, pool memory.Allocator) (arrow.Field, arrow.Array, error) {
-	// Simplified implementation - reuse sum logic and divide by count
-	_, sumArray, err := gdf.performSum(series, groupIndices, name, pool)
+	resultRecord, err := aggregation.Aggregate(keyDF.Record(), inputs, aggregation.Options{
+		Parallelism: parallelism,
+		Allocator:   memory.NewGoAllocator(),
+	})
 	if err != nil {
-		return arrow.Field{}, nil, err
+		return nil, err
 	}
-	defer sumArray.Release()
-
-	builder := array.NewFloat64Builder(pool)
-	defer builder.Release()
 
-	sumFloat, ok := sumArray.(*array.Float64)
-	if !ok {
-		return arrow.Field{}, nil, fmt.Errorf("unexpected array type for sum result")
-	}
+	return NewDataFrame(resultRecord), nil
+}
 
-	// Sort keys to maintain consistent order
-	var keys []string
-	for key := range groupIndices {
-		keys = append(keys, key)
-	}
-	sort.Strings(keys)
-
-	i := 0
-	for _, key := range keys {
-		indices := groupIndices[key]
-		count := 0
-
-		// Count non-null values
-		for _, idx := range indices {
-			if !series.IsNull(idx) {
-				count++
-			}
+// aggregationInput returns the columns an aggregation reads: its column, or
+// the result of its input expression, its second column and its filter.
+// Evaluated arrays are appended to evaluated for the caller to release.
+func (gdf *GroupedDataFrame) aggregationInput(agg Aggregation, evaluated *[]arrow.Array) (aggregation.Input, error) {
+	input := aggregation.Input{Spec: agg.spec}
+	if agg.input == nil {
+		series, err := gdf.df.coreDF.Column(agg.spec.Column)
+		if err != nil {
+			return input, fmt.Errorf("failed to get aggregation column: %w", err)
 		}
-
-		if count > 0 {
-			mean := sumFloat.Value(i) / float64(count)
-			builder.Append(mean)
-		} else {
-			builder.AppendNull()
+		input.Values = series.Array()
+		series.Release()
+	} else {
+		values, err := agg.input.Evaluate(gdf.df.coreDF)
+		if err != nil {
+			return input, fmt.Errorf("failed to evaluate aggregation input: %w", err)
 		}
-		i++
-	}
-
-	field := arrow.Field{Name: name, Type: arrow.PrimitiveTypes.Float64}
-	return field, builder.NewArray(), nil
-}
-
-// performCount counts non-null values for each group
-func (gdf *GroupedDataFrame) performCount(series *core.Series, groupIndices map[string][]int, name string, pool memory.Allocator) (arrow.Field, arrow.Array, error) {
-	builder := array.NewInt64Builder(pool)
-	defer builder.Release()
-
-	// Sort keys to maintain consistent order
-	var keys []string
-	for key := range groupIndices {
-		keys = append(keys, key)
+		*evaluated = append(*evaluated, values)
+		input.Values = values
 	}
-	sort.Strings(keys)
 
-	for _, key := range keys {
-		indices := groupIndices[key]
-		count := int64(0)
-
-		for _, idx := range indices {
-			if !series.IsNull(idx) {
-				count++
-			}
+	if agg.spec.SecondColumn != "" {
+		series, err := gdf.df.coreDF.Column(agg.spec.SecondColumn)
+		if err != nil {
+			return input, fmt.Errorf("failed to get aggregation column: %w", err)
 		}
-
-		builder.Append(count)
+		input.Second = series.Array()
+		series.Release()
 	}
 
-	field := arrow.Field{Name: name, Type: arrow.PrimitiveTypes.Int64}
-	return field, builder.NewArray(), nil
-}
-
-// performMin finds minimum value for each group
-func (gdf *GroupedDataFrame) performMin(series *core.Series, groupIndices map[string][]int, name string, pool memory.Allocator) (arrow.Field, arrow.Array, error) {
-	if series.DataType().ID() != arrow.FLOAT64 {
-		return arrow.Field{}, nil, fmt.Errorf("min aggregation only supports float64, got %s", series.DataType())
-	}
-
-	builder := array.NewFloat64Builder(pool)
-	defer builder.Release()
-
-	// Sort keys to maintain consistent order
-	var keys []string
-	for key := range groupIndices {
-		keys = append(keys, key)
-	}
-	sort.Strings(keys)
-
-	for _, key := range keys {
-		indices := groupIndices[key]
-		var minVal *float64
-
-		for _, idx := range indices {
-			if !series.IsNull(idx) {
-				val, err := series.GetFloat64(idx)
-				if err != nil {
-					return arrow.Field{}, nil, fmt.Errorf("failed to get float64 value: %w", err)
-				}
-
-				if minVal == nil || val < *minVal {
-					minVal = &val
-				}
-			}
+	if agg.filter != nil {
+		filter, err := agg.filter.Evaluate(gdf.df.coreDF)
+		if err != nil {
+			return input, fmt.Errorf("failed to evaluate filter: %w", err)
 		}
-
-		if minVal != nil {
-			builder.Append(*minVal)
-		} else {
-			builder.AppendNull()
+		*evaluated = append(*evaluated, filter)
+		boolFilter, ok := filter.(*array.Boolean)
+		if !ok {
+			return input, fmt.Errorf("filter must be boolean, got %s", filter.DataType())
 		}
+		input.Filter = boolFilter
 	}
-
-	field := arrow.Field{Name: name, Type: arrow.PrimitiveTypes.Float64}
-	return field, builder.NewArray(), nil
+	return input, nil
 }
 
-// performMax finds maximum value for each group
-func (gdf *GroupedDataFrame) performMax(series *core.Series, groupIndices map[string][]int, name string, pool memory.Allocator) (arrow.Field, arrow.Array, error) {
-	if series.DataType().ID() != arrow.FLOAT64 {
-		return arrow.Field{}, nil, fmt.Errorf("max aggregation only supports float64, got %s", series.DataType())
-	}
-
-	builder := array.NewFloat64Builder(pool)
-	defer builder.Release()
-
-	// Sort keys to maintain consistent order
-	var keys []string
-	for key := range groupIndices {
-		keys = append(keys, key)
-	}
-	sort.Strings(keys)
-
-	for _, key := range keys {
-		indices := groupIndices[key]
-		var maxVal *float64
-
-		for _, idx := range indices {
-			if !series.IsNull(idx) {
-				val, err := series.GetFloat64(idx)
-				if err != nil {
-					return arrow.Field{}, nil, fmt.Errorf("failed to get float64 value: %w", err)
-				}
-
-				if maxVal == nil || val > *maxVal {
-					maxVal = &val
-				}
-			}
-		}
-
-		if maxVal != nil {
-			builder.Append(*maxVal)
-		} else {
-			builder.AppendNull()
-		}
-	}
+// Aggregation builders
 
-	field := arrow.Field{Name: name, Type: arrow.PrimitiveTypes.Float64}
-	return field, builder.NewArray(), nil
+// columnAggregation creates an aggregation of a column, named after the
+// column and the suffix.
+func columnAggregation(column string, aggType aggregation.AggregationType, suffix string) Aggregation {
+	return Aggregation{spec: aggregation.AggregationSpec{
+		Column: column,
+		Type:   aggType,
+		Alias:  column + "_" + suffix,
+	}}
 }
 
-// Aggregation builders
-
 // Sum creates a sum aggregation.
 func Sum(column string) Aggregation {
-	return Aggregation{
-		column:    column,
-		operation: "sum",
-		alias:     column + "_sum",
-	}
+	return columnAggregation(column, aggregation.Sum, "sum")
 }
 
 // Mean creates a mean/average aggregation.
 func Mean(column string) Aggregation {
-	return Aggregation{
-		column:    column,
-		operation: "mean",
-		alias:     column + "_mean",
-	}
+	return columnAggregation(column, aggregation.Mean, "mean")
 }
 
 // Count creates a count aggregation.
 func Count(column string) Aggregation {
-	return Aggregation{
-		column:    column,
-		operation: "count",
-		alias:     column + "_count",
-	}
+	return columnAggregation(column, aggregation.Count, "count")
 }
 
 // Min creates a minimum aggregation.
 func Min(column string) Aggregation {
-	return Aggregation{
-		column:    column,
-		operation: "min",
-		alias:     column + "_min",
-	}
+	return columnAggregation(column, aggregation.Min, "min")
 }
 
 // Max creates a maximum aggregation.
 func Max(column string) Aggregation {
-	return Aggregation{
-		column:    column,
-		operation: "max",
-		alias:     column + "_max",
-	}
+	return columnAggregation(column, aggregation.Max, "max")
 }
 
-// Variance creates a sample variance aggregation (N-1 denominator).
-func Variance(column string) Aggregation {
-	return Aggregation{
-		column:    column,
-		operation: "variance",
-		alias:     column + "_variance",
-	}
+// SumExpr creates a sum aggregation over the values of an expression,
+// such as SumExpr(Col("price").Mul(Col("qty"))).
+func SumExpr(e expr.Expr) Aggregation {
+	return exprAggregation(e, aggregation.Sum)
 }
 
-// StdDev creates a sample standard deviation aggregation (sqrt of sample variance).
-func StdDev(column string) Aggregation {
-	return Aggregation{
-		column:    column,
-		operation: "stddev",
-		alias:     column + "_stddev",
-	}
+// MeanExpr creates a mean/average aggregation over the values of an expression.
+func MeanExpr(e expr.Expr) Aggregation {
+	return exprAggregation(e, aggregation.Mean)
 }
 
-// ConcatAgg creates a string concatenation aggregation (concat_ws).
-// It joins all non-null string values in each group with the given separator.
-func ConcatAgg(column, separator string) Aggregation {
-	return Aggregation{
-		column:    column,
-		operation: "concat:" + separator,
-		alias:     column + "_concat",
-	}
+// CountExpr creates an aggregation that counts the non-null values of an expression.
+func CountExpr(e expr.Expr) Aggregation {
+	return exprAggregation(e, aggregation.Count)
 }
 
-// CustomAggFunc is a function that takes a slice of float64 values and returns a single float64 result.
-type CustomAggFunc func(values []float64) float64
+// MinExpr creates a minimum aggregation over the values of an expression.
+func MinExpr(e expr.Expr) Aggregation {
+	return exprAggregation(e, aggregation.Min)
+}
 
-// customAggregation extends Aggregation with a custom function.
+// MaxExpr creates a maximum aggregation over the values of an expression.
+func MaxExpr(e expr.Expr) Aggregation {
+	return exprAggregation(e, aggregation.Max)
+}
 
-// CustomAgg creates an aggregation with a user-defined function.
-func CustomAgg(column string, alias string, fn CustomAggFunc) Aggregation {
-	// Store the function pointer in the operation field with a marker
-	a := Aggregation{
-		column:    column,
-		operation: "custom",
-		alias:     alias,
+// exprAggregation creates an aggregation over the values of an expression,
+// named after the expression.
+func exprAggregation(e expr.Expr, aggType aggregation.AggregationType) Aggregation {
+	return Aggregation{
+		spec:  aggregation.AggregationSpec{Type: aggType, Alias: e.Name() + "_" + aggType.String()},
+		input: e,
 	}
-	// Register the function in the package-level registry
-	customAggFuncs[alias] = fn
-	return a
 }
 
-// customAggFuncs is a package-level registry for custom aggregation functions.
-var customAggFuncs = make(map[string]CustomAggFunc)
-
-// As sets a custom name for the aggregation result.
-func (a Aggregation) As(alias string) Aggregation {
-	a.alias = alias
-	return a
+// Variance creates a sample variance aggregation (N-1 denominator).
+func Variance(column string) Aggregation {
+	return columnAggregation(column, aggregation.VarianceAgg, "variance")
 }
 
-// Name returns the name of the aggregation result column.
-func (a Aggregation) Name() string {
-	return a.alias
+// StdDev creates a sample standard deviation aggregation (sqrt of sample variance).
+func StdDev(column string) Aggregation {
+	return columnAggregation(column, aggregation.StdDevAgg, "stddev")
 }
 
-// performVariance calculates sample variance for each group using a two-pass algorithm.
-// Returns null for groups with fewer than 2 non-null values.
-func (gdf *GroupedDataFrame) performVariance(series *core.Series, groupIndices map[string][]int, name string, pool memory.Allocator) (arrow.Field, arrow.Array, error) {
-	if series.DataType().ID() != arrow.FLOAT64 {
-		return arrow.Field{}, nil, fmt.Errorf("variance aggregation only supports float64, got %s", series.DataType())
-	}
-
-	builder := array.NewFloat64Builder(pool)
-	defer builder.Release()
-
-	// Sort keys to maintain consistent order
-	var keys []string
-	for key := range groupIndices {
-		keys = append(keys, key)
-	}
-	sort.Strings(keys)
-
-	for _, key := range keys {
-		indices := groupIndices[key]
-
-		// First pass: calculate mean and count non-null values
-		sum := 0.0
-		count := 0
-		for _, idx := range indices {
-			if !series.IsNull(idx) {
-				val, err := series.GetFloat64(idx)
-				if err != nil {
-					return arrow.Field{}, nil, fmt.Errorf("failed to get float64 value: %w", err)
-				}
-				sum += val
-				count++
-			}
-		}
-
-		// Need at least 2 values for sample variance
-		if count < 2 {
-			builder.AppendNull()
-			continue
-		}
-
-		mean := sum / float64(count)
-
-		// Second pass: sum of squared deviations
-		sumSquaredDev := 0.0
-		for _, idx := range indices {
-			if !series.IsNull(idx) {
-				val, err := series.GetFloat64(idx)
-				if err != nil {
-					return arrow.Field{}, nil, fmt.Errorf("failed to get float64 value: %w", err)
-				}
-				diff := val - mean
-				sumSquaredDev += diff * diff
-			}
-		}
+// Skew creates a sample skewness aggregation, bias-corrected like pandas.
+// Groups with fewer than three values or no variance are null.
+func Skew(column string) Aggregation {
+	return columnAggregation(column, aggregation.Skew, "skew")
+}
 
-		// Sample variance uses N-1 denominator (Bessel's correction)
-		variance := sumSquaredDev / float64(count-1)
-		builder.Append(variance)
-	}
+// Kurtosis creates a sample excess kurtosis aggregation, bias-corrected like
+// pandas. Groups with fewer than four values or no variance are null.
+func Kurtosis(column string) Aggregation {
+	return columnAggregation(column, aggregation.Kurtosis, "kurtosis")
+}
 
-	field := arrow.Field{Name: name, Type: arrow.PrimitiveTypes.Float64}
-	return field, builder.NewArray(), nil
+// Percentile creates an aggregation of the p-th quantile (0.0-1.0),
+// interpolating linearly between the closest values.
+func Percentile(column string, p float64) Aggregation {
+	agg := columnAggregation(column, aggregation.Percentile, fmt.Sprintf("p%.0f", p*100))
+	agg.spec.Percentile = p
+	return agg
 }
 
-// performStdDev calculates sample standard deviation for each group.
-// This is the square root of the sample variance.
-func (gdf *GroupedDataFrame) performStdDev(series *core.Series, groupIndices map[string][]int, name string, pool memory.Allocator) (arrow.Field, arrow.Array, error) {
-	// Calculate variance first
-	_, varianceArray, err := gdf.performVariance(series, groupIndices, name, pool)
-	if err != nil {
-		return arrow.Field{}, nil, err
-	}
-	defer varianceArray.Release()
+// Median creates a median (50th percentile) aggregation.
+func Median(column string) Aggregation {
+	return columnAggregation(column, aggregation.Median, "median")
+}
 
-	builder := array.NewFloat64Builder(pool)
-	defer builder.Release()
+// Mode creates an aggregation of the most frequent value, the smallest one
+// on ties.
+func Mode(column string) Aggregation {
+	return columnAggregation(column, aggregation.Mode, "mode")
+}
 
-	varianceFloat, ok := varianceArray.(*array.Float64)
-	if !ok {
-		return arrow.Field{}, nil, fmt.Errorf("unexpected array type for variance result")
-	}
+// Correlation creates a Pearson correlation aggregation of two columns over
+// the rows where both are non-null.
+func Correlation(column1, column2 string) Aggregation {
+	return pairAggregation(column1, column2, aggregation.Correlation, fmt.Sprintf("corr_%s_%s", column1, column2))
+}
 
-	for i := 0; i < varianceFloat.Len(); i++ {
-		if varianceFloat.IsNull(i) {
-			builder.AppendNull()
-		} else {
-			builder.Append(math.Sqrt(varianceFloat.Value(i)))
-		}
-	}
+// Covariance creates a sample covariance aggregation (N-1 denominator) of
+// two columns over the rows where both are non-null.
+func Covariance(column1, column2 string) Aggregation {
+	return pairAggregation(column1, column2, aggregation.Covariance, fmt.Sprintf("cov_%s_%s", column1, column2))
+}
 
-	field := arrow.Field{Name: name, Type: arrow.PrimitiveTypes.Float64}
-	return field, builder.NewArray(), nil
+// pairAggregation creates an aggregation that reads two columns.
+func pairAggregation(column1, column2 string, aggType aggregation.AggregationType, alias string) Aggregation {
+	return Aggregation{spec: aggregation.AggregationSpec{
+		Column:       column1,
+		SecondColumn: column2,
+		Type:         aggType,
+		Alias:        alias,
+	}}
 }
 
-// performMultiColumnGroupBy handles multi-column grouping
-func (gdf *GroupedDataFrame) performMultiColumnGroupBy(aggregations []Aggregation) (*DataFrame, error) {
-	// Get all grouping columns
-	var groupSeries []*core.Series
-	for _, col := range gdf.groupByCols {
-		series, err := gdf.df.coreDF.Column(col)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get group column %s: %w", col, err)
-		}
-		groupSeries = append(groupSeries, series)
-	}
-	defer func() {
-		for _, series := range groupSeries {
-			series.Release()
-		}
-	}()
+// CountDistinct creates an aggregation that counts the distinct non-null
+// values of a column of any type.
+func CountDistinct(column string) Aggregation {
+	return columnAggregation(column, aggregation.CountDistinct, "count_distinct")
+}
 
-	// Build composite keys and group indices
-	groupKeys, groupIndices, err := gdf.extractMultiColumnGroups(groupSeries)
-	if err != nil {
-		return nil, fmt.Errorf("failed to extract multi-column groups: %w", err)
-	}
+// First creates an aggregation of the first non-null value of every group.
+func First(column string) Aggregation {
+	return columnAggregation(column, aggregation.First, "first")
+}
 
-	// Build result columns - start with group columns
-	var resultFields []arrow.Field
-	var resultColumns []arrow.Array
+// Last creates an aggregation of the last non-null value of every group.
+func Last(column string) Aggregation {
+	return columnAggregation(column, aggregation.Last, "last")
+}
 
-	// Add group key columns to result
-	for i, col := range gdf.groupByCols {
-		resultFields = append(resultFields, arrow.Field{Name: col, Type: groupSeries[i].DataType()})
-	}
+// Any creates an aggregation of a boolean column that is true when any
+// non-null value of the group is true.
+func Any(column string) Aggregation {
+	return columnAggregation(column, aggregation.Any, "any")
+}
 
-	// Create arrays for each group column
-	pool := memory.NewGoAllocator()
-	for i, col := range gdf.groupByCols {
-		builder := createBuilderForType(groupSeries[i].DataType(), pool)
-		defer builder.Release()
-
-		for _, key := range groupKeys {
-			if len(groupIndices[key]) > 0 {
-				firstIdx := groupIndices[key][0] // Use first row in group for group key
-				if err := appendValueFromSeries(builder, groupSeries[i], firstIdx); err != nil {
-					return nil, fmt.Errorf("failed to append group value for column %s: %w", col, err)
-				}
-			}
-		}
-		resultColumns = append(resultColumns, builder.NewArray())
-	}
+// All creates an aggregation of a boolean column that is true when every
+// non-null value of the group is true.
+func All(column string) Aggregation {
+	return columnAggregation(column, aggregation.All, "all")
+}
 
-	// Add aggregation columns
-	for _, agg := range aggregations {
-		aggField, aggColumn, err := gdf.performAggregation(agg, groupIndices)
-		if err != nil {
-			return nil, fmt.Errorf("failed to perform aggregation %s: %w", agg.Name(), err)
-		}
-		resultFields = append(resultFields, aggField)
-		resultColumns = append(resultColumns, aggColumn)
-	}
+// ArgMin creates an aggregation of the value of column in the row where by
+// is smallest, the first such row on ties. Rows where by is null are
+// ignored.
+func ArgMin(column, by string) Aggregation {
+	return pairAggregation(column, by, aggregation.ArgMin, column+"_arg_min")
+}
 
-	// Create result schema and record
-	resultSchema := arrow.NewSchema(resultFields, nil)
-	resultRecord := array.NewRecord(resultSchema, resultColumns, int64(len(groupKeys)))
+// ArgMax creates an aggregation of the value of column in the row where by
+// is largest, the first such row on ties. Rows where by is null are
+// ignored.
+func ArgMax(column, by string) Aggregation {
+	return pairAggregation(column, by, aggregation.ArgMax, column+"_arg_max")
+}
 
-	return NewDataFrame(resultRecord), nil
+// CollectList creates an aggregation that collects the non-null values of
+// every group, in row order, into a list.
+func CollectList(column string) Aggregation {
+	return columnAggregation(column, aggregation.CollectList, "list")
 }
 
-// extractMultiColumnGroups creates composite keys from multiple columns
-func (gdf *GroupedDataFrame) extractMultiColumnGroups(groupSeries []*core.Series) ([]string, map[string][]int, error) {
-	if len(groupSeries) == 0 {
-		return nil, nil, fmt.Errorf("no group series provided")
-	}
+// ConcatAgg creates a string concatenation aggregation (concat_ws).
+// It joins all non-null string values in each group with the given separator.
+func ConcatAgg(column, separator string) Aggregation {
+	agg := columnAggregation(column, aggregation.Concat, "concat")
+	agg.spec.Separator = separator
+	return agg
+}
+
+// ApproxCountDistinct creates an aggregation that estimates the number of
+// distinct non-null values of a column of any type with a HyperLogLog
+// sketch of the given precision (4-18; 0 uses 14, a standard error of about
+// 0.8%). Memory per group is 2^precision bytes however many values it has.
+func ApproxCountDistinct(column string, precision int) Aggregation {
+	agg := columnAggregation(column, aggregation.ApproxCountDistinct, "approx_count_distinct")
+	agg.spec.Precision = precision
+	return agg
+}
+
+// ApproxQuantile creates an aggregation that estimates the q-th quantile
+// (0.0-1.0) of a column with a t-digest sketch instead of sorting every
+// value. Accuracy is the t-digest compression, at least 10 (0 uses 100);
+// higher values keep more centroids and are more accurate. Groups of up to
+// a few hundred values are exact.
+func ApproxQuantile(column string, q, accuracy float64) Aggregation {
+	agg := columnAggregation(column, aggregation.ApproxQuantile, fmt.Sprintf("approx_p%.0f", q*100))
+	agg.spec.Percentile = q
+	agg.spec.Accuracy = accuracy
+	return agg
+}
+
+// MergeCountDistinctSketches creates an aggregation that merges the
+// serialized HyperLogLog sketches of a binary column, made by
+// ApproxCountDistinct(...).Sketch(), and estimates the distinct count of
+// their union. The result keeps the column's name.
+func MergeCountDistinctSketches(column string) Aggregation {
+	return Aggregation{spec: aggregation.AggregationSpec{
+		Column:       column,
+		Type:         aggregation.ApproxCountDistinct,
+		Alias:        column,
+		FromSketches: true,
+	}}
+}
+
+// MergeQuantileSketches creates an aggregation that merges the serialized
+// t-digest sketches of a binary column, made by ApproxQuantile(...).Sketch(),
+// and estimates the q-th quantile of their union. The result keeps the
+// column's name.
+func MergeQuantileSketches(column string, q float64) Aggregation {
+	return Aggregation{spec: aggregation.AggregationSpec{
+		Column:       column,
+		Type:         aggregation.ApproxQuantile,
+		Alias:        column,
+		Percentile:   q,
+		FromSketches: true,
+	}}
+}
+
+// Sketch makes an ApproxCountDistinct or ApproxQuantile aggregation, or a
+// merge of their sketches, return the serialized sketch of every group as
+// binary instead of its estimate. Sketches of separate chunks or files can
+// be stored and merged later with MergeCountDistinctSketches and
+// MergeQuantileSketches, which give the same result as aggregating all rows
+// at once.
+func (a Aggregation) Sketch() Aggregation {
+	a.spec.Sketch = true
+	return a
+}
 
-	numRows := groupSeries[0].Len()
-	groupMap := make(map[string][]int)
-
-	// Build composite keys by concatenating column values
-	for i := 0; i < numRows; i++ {
-		var keyParts []string
-		skipRow := false
-
-		for _, series := range groupSeries {
-			if series.IsNull(i) {
-				skipRow = true
-				break
-			}
-
-			value, err := series.GetString(i)
-			if err != nil {
-				return nil, nil, fmt.Errorf("failed to get string value: %w", err)
-			}
-			keyParts = append(keyParts, value)
-		}
+// CustomAggFunc is a function that takes a slice of float64 values and returns a single float64 result.
+type CustomAggFunc = aggregation.CustomFunc
 
-		if !skipRow {
-			key := fmt.Sprintf("%v", keyParts) // Simple string representation of composite key
-			groupMap[key] = append(groupMap[key], i)
-		}
-	}
+// CustomAgg creates an aggregation with a user-defined function. The
+// function is called once per group, with the group's non-null values in
+// row order.
+func CustomAgg(column string, alias string, fn CustomAggFunc) Aggregation {
+	return Aggregation{spec: aggregation.AggregationSpec{
+		Column: column,
+		Type:   aggregation.Custom,
+		Alias:  alias,
+		Func:   fn,
+	}}
+}
 
-	// Sort group keys for consistent output
-	var groupKeys []string
-	for key := range groupMap {
-		groupKeys = append(groupKeys, key)
-	}
-	sort.Strings(groupKeys)
-
-	return groupKeys, groupMap, nil
-}
-
-// createBuilderForType creates appropriate builder for Arrow data type
-func createBuilderForType(dataType arrow.DataType, pool memory.Allocator) array.Builder {
-	switch dataType.ID() {
-	case arrow.STRING:
-		return array.NewStringBuilder(pool)
-	case arrow.INT64:
-		return array.NewInt64Builder(pool)
-	case arrow.FLOAT64:
-		return array.NewFloat64Builder(pool)
-	case arrow.BOOL:
-		return array.NewBooleanBuilder(pool)
-	default:
-		return array.NewStringBuilder(pool) // Fallback to string
-	}
+// As sets a custom name for the aggregation result.
+func (a Aggregation) As(alias string) Aggregation {
+	a.spec.Alias = alias
+	return a
 }
 
-// appendValueFromSeries appends value from series to appropriate builder
-func appendValueFromSeries(builder array.Builder, series *core.Series, index int) error {
-	if series.IsNull(index) {
-		builder.AppendNull()
-		return nil
-	}
+// Of makes the aggregation read the values of an expression instead of its
+// column, like SumExpr does for Sum. The column given to the constructor
+// still names the result, so Variance("revenue").Of(Col("price").Mul(Col("qty")))
+// is named revenue_variance. Aggregations of two columns, such as
+// Correlation or ArgMin, read the expression in place of the first one.
+func (a Aggregation) Of(e expr.Expr) Aggregation {
+	a.input = e
+	return a
+}
 
-	switch b := builder.(type) {
-	case *array.StringBuilder:
-		val, err := series.GetString(index)
-		if err != nil {
-			return err
-		}
-		b.Append(val)
-	case *array.Int64Builder:
-		val, err := series.GetInt64(index)
-		if err != nil {
-			return err
-		}
-		b.Append(val)
-	case *array.Float64Builder:
-		val, err := series.GetFloat64(index)
-		if err != nil {
-			return err
-		}
-		b.Append(val)
-	case *array.BooleanBuilder:
-		val, err := series.GetBool(index)
-		if err != nil {
-			return err
-		}
-		b.Append(val)
-	default:
-		return fmt.Errorf("unsupported builder type")
+// Filter restricts the aggregation to the rows where predicate is true, like
+// SQL's SUM(x) FILTER (WHERE ...). Rows where the predicate is false or null
+// are ignored, but every group is still in the result. Filtering twice
+// keeps the rows that match both predicates.
+func (a Aggregation) Filter(predicate expr.Expr) Aggregation {
+	if a.filter != nil {
+		predicate = a.filter.And(predicate)
 	}
-	return nil
+	a.filter = predicate
+	return a
 }
 
-// performConcatAgg concatenates string values in each group with a separator.
-func (gdf *GroupedDataFrame) performConcatAgg(series *core.Series, groupIndices map[string][]int, name, separator string, pool memory.Allocator) (arrow.Field, arrow.Array, error) {
-	builder := array.NewStringBuilder(pool)
-	defer builder.Release()
-
-	var keys []string
-	for key := range groupIndices {
-		keys = append(keys, key)
+// columns returns the columns the aggregation reads.
+func (a Aggregation) columns() []string {
+	var columns []string
+	if a.input != nil {
+		columns = a.input.Columns()
+	} else {
+		columns = []string{a.spec.Column}
 	}
-	sort.Strings(keys)
-
-	for _, key := range keys {
-		indices := groupIndices[key]
-		var parts []string
-
-		for _, idx := range indices {
-			if !series.IsNull(idx) {
-				val, err := series.GetString(idx)
-				if err != nil {
-					return arrow.Field{}, nil, fmt.Errorf("failed to get string value: %w", err)
-				}
-				parts = append(parts, val)
-			}
-		}
-
-		if len(parts) > 0 {
-			builder.Append(strings.Join(parts, separator))
-		} else {
-			builder.AppendNull()
-		}
+	if a.spec.SecondColumn != "" {
+		columns = append(columns, a.spec.SecondColumn)
 	}
-
-	field := arrow.Field{Name: name, Type: arrow.BinaryTypes.String}
-	return field, builder.NewArray(), nil
+	if a.filter != nil {
+		columns = append(columns, a.filter.Columns()...)
+	}
+	return columns
 }
 
-// performCustomAgg executes a custom aggregation function for each group.
-func (gdf *GroupedDataFrame) performCustomAgg(series *core.Series, groupIndices map[string][]int, name string, pool memory.Allocator) (arrow.Field, arrow.Array, error) {
-	fn, ok := customAggFuncs[name]
-	if !ok {
-		return arrow.Field{}, nil, fmt.Errorf("custom aggregation function not found: %s", name)
+// describe returns the aggregation as SQL-like text for query plans.
+func (a Aggregation) describe() string {
+	input := a.spec.Column
+	if a.input != nil {
+		input = a.input.String()
 	}
-
-	if series.DataType().ID() != arrow.FLOAT64 {
-		return arrow.Field{}, nil, fmt.Errorf("custom aggregation only supports float64, got %s", series.DataType())
+	if a.spec.SecondColumn != "" {
+		input += ", " + a.spec.SecondColumn
 	}
-
-	builder := array.NewFloat64Builder(pool)
-	defer builder.Release()
-
-	var keys []string
-	for key := range groupIndices {
-		keys = append(keys, key)
+	text := fmt.Sprintf("%s(%s)", a.spec.Type, input)
+	if a.spec.FromSketches {
+		text = fmt.Sprintf("%s(merge_sketches(%s))", a.spec.Type, input)
 	}
-	sort.Strings(keys)
-
-	for _, key := range keys {
-		indices := groupIndices[key]
-		var values []float64
-
-		for _, idx := range indices {
-			if !series.IsNull(idx) {
-				val, err := series.GetFloat64(idx)
-				if err != nil {
-					return arrow.Field{}, nil, fmt.Errorf("failed to get float64 value: %w", err)
-				}
-				values = append(values, val)
-			}
-		}
-
-		if len(values) > 0 {
-			builder.Append(fn(values))
-		} else {
-			builder.AppendNull()
-		}
+	if a.spec.Sketch {
+		text = fmt.Sprintf("sketch(%s)", text)
+	}
+	if a.filter != nil {
+		text += fmt.Sprintf(" FILTER (WHERE %s)", a.filter.String())
 	}
+	return text + " AS " + a.Name()
+}
 
-	field := arrow.Field{Name: name, Type: arrow.PrimitiveTypes.Float64}
-	return field, builder.NewArray(), nil
+// Name returns the name of the aggregation result column.
+func (a Aggregation) Name() string {
+	return a.spec.Alias
 }
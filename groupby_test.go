@@ -1,11 +1,14 @@
 package gopherframe
 
 import (
+	"fmt"
+	"math"
 	"testing"
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/felixgeelhaar/GopherFrame/pkg/domain/aggregation"
 )
 
 func TestGroupByBasicAggregation(t *testing.T) {
@@ -234,3 +237,356 @@ func createComplexGroupedDataFrame() *DataFrame {
 
 	return NewDataFrame(record)
 }
+
+func TestGroupByTypedKeys(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "amount", Type: arrow.PrimitiveTypes.Float64},
+	}, nil)
+	builder := array.NewRecordBuilder(pool, schema)
+	defer builder.Release()
+	builder.Field(0).(*array.Int64Builder).AppendValues(
+		[]int64{10, 2, 10, 0, 2, 1}, []bool{true, true, true, false, true, true})
+	builder.Field(1).(*array.Float64Builder).AppendValues([]float64{1, 2, 3, 4, 5, 6}, nil)
+	record := builder.NewRecord()
+	defer record.Release()
+	df := NewDataFrame(record)
+	defer df.Release()
+
+	result := df.GroupBy("id").Agg(Sum("amount"))
+	defer result.Release()
+	if result.Err() != nil {
+		t.Fatalf("GroupBy aggregation failed: %v", result.Err())
+	}
+
+	// Keys keep their type and are ordered by value, not as text
+	ids, ok := result.Record().Column(0).(*array.Int64)
+	if !ok {
+		t.Fatalf("Expected int64 group column, got %s", result.Record().Column(0).DataType())
+	}
+	if got := ids.Int64Values(); len(got) != 3 || got[0] != 1 || got[1] != 2 || got[2] != 10 {
+		t.Errorf("Expected groups [1 2 10], got %v", got)
+	}
+	sums := result.Record().Column(1).(*array.Float64).Float64Values()
+	if sums[0] != 6 || sums[1] != 7 || sums[2] != 4 {
+		t.Errorf("Expected sums [6 7 4], got %v", sums)
+	}
+}
+
+func TestGroupByEmptyDataFrame(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "amount", Type: arrow.PrimitiveTypes.Float64},
+		{Name: "label", Type: arrow.BinaryTypes.String},
+	}, nil)
+	builder := array.NewRecordBuilder(pool, schema)
+	defer builder.Release()
+	builder.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2}, nil)
+	builder.Field(1).(*array.Float64Builder).AppendValues([]float64{1, 2}, nil)
+	builder.Field(2).(*array.StringBuilder).AppendValues([]string{"a", "b"}, nil)
+	record := builder.NewRecord()
+	defer record.Release()
+	df := NewDataFrame(record)
+	defer df.Release()
+	empty := df.Limit(0)
+	defer empty.Release()
+
+	aggregations := []Aggregation{
+		Sum("amount"), CountDistinct("label"), First("label"), CollectList("amount"),
+	}
+	want := df.GroupBy("id").Agg(aggregations...)
+	defer want.Release()
+	got := empty.GroupBy("id").Agg(aggregations...)
+	defer got.Release()
+	if got.Err() != nil {
+		t.Fatalf("GroupBy aggregation failed: %v", got.Err())
+	}
+
+	// An empty DataFrame has no groups, but the result types do not change
+	if got.NumRows() != 0 {
+		t.Errorf("Expected no groups, got %d", got.NumRows())
+	}
+	if !got.Schema().Equal(want.Schema()) {
+		t.Errorf("Expected schema %s, got %s", want.Schema(), got.Schema())
+	}
+
+	missing := empty.GroupBy("id").Agg(Sum("missing"))
+	if missing.Err() == nil {
+		t.Error("Expected error for a missing aggregation column")
+	}
+}
+
+func TestGroupByAggWithOptions_Parallel(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "key", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "value", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "label", Type: arrow.BinaryTypes.String},
+	}, nil)
+	builder := array.NewRecordBuilder(pool, schema)
+	defer builder.Release()
+	// Small morsels so that the rows are aggregated as many partial states
+	defer aggregation.SetMorselRowsForTesting(64)()
+	for i := 0; i < 5000; i++ {
+		keyBuilder := builder.Field(0).(*array.Int64Builder)
+		if i%97 == 0 {
+			keyBuilder.AppendNull()
+		} else {
+			keyBuilder.Append(int64(i*31) % 37)
+		}
+		valueBuilder := builder.Field(1).(*array.Float64Builder)
+		if i%13 == 0 {
+			valueBuilder.AppendNull()
+		} else {
+			valueBuilder.Append(float64(i%101) / 7)
+		}
+		builder.Field(2).(*array.StringBuilder).Append(fmt.Sprintf("r%d", i))
+	}
+	record := builder.NewRecord()
+	defer record.Release()
+	df := NewDataFrame(record)
+	defer df.Release()
+
+	aggregations := []Aggregation{
+		Sum("value"), Mean("value"), Count("value"), Min("value"), Max("value"),
+		Variance("value"), StdDev("value"), ConcatAgg("label", ","),
+		Skew("value"), Kurtosis("value"), Median("value"), CountDistinct("value"),
+		First("label"), Last("label"), ArgMax("label", "value"), CollectList("value"),
+		ApproxCountDistinct("label", 0), ApproxQuantile("value", 0.5, 0),
+		CustomAgg("value", "value_range", func(values []float64) float64 {
+			// Order sensitive, so partial states must be merged in row order
+			return values[len(values)-1] - values[0]
+		}),
+	}
+
+	serial := df.GroupBy("key").Agg(aggregations...)
+	defer serial.Release()
+	if serial.Err() != nil {
+		t.Fatalf("Serial aggregation failed: %v", serial.Err())
+	}
+	if serial.NumRows() != 37 {
+		t.Fatalf("Expected 37 groups, got %d", serial.NumRows())
+	}
+
+	for _, parallelism := range []int{2, 4, 0} {
+		parallel := df.GroupBy("key").AggWithOptions(AggOptions{Parallelism: parallelism}, aggregations...)
+		if parallel.Err() != nil {
+			t.Fatalf("Parallel aggregation failed: %v", parallel.Err())
+		}
+		if !array.RecordEqual(serial.Record(), parallel.Record()) {
+			t.Errorf("Parallelism %d: results differ from the serial aggregation", parallelism)
+		}
+		parallel.Release()
+	}
+
+	// Merging partial states agrees with aggregating each group at once
+	aggregation.SetMorselRowsForTesting(1 << 20)
+	whole := df.GroupBy("key").Agg(aggregations...)
+	defer whole.Release()
+	if whole.Err() != nil {
+		t.Fatalf("Whole aggregation failed: %v", whole.Err())
+	}
+	for col := 1; col < int(whole.NumCols()); col++ {
+		name := whole.Record().ColumnName(col)
+		want, got := whole.Record().Column(col), serial.Record().Column(col)
+		floats, ok := want.(*array.Float64)
+		if !ok {
+			if !array.Equal(want, got) {
+				t.Errorf("Column %s differs", name)
+			}
+			continue
+		}
+		for i := 0; i < floats.Len(); i++ {
+			if floats.IsNull(i) != got.IsNull(i) ||
+				math.Abs(floats.Value(i)-got.(*array.Float64).Value(i)) > 1e-9 {
+				t.Errorf("Column %s group %d: expected %v, got %v",
+					name, i, floats.Value(i), got.(*array.Float64).Value(i))
+			}
+		}
+	}
+
+	invalid := df.GroupBy("key").AggWithOptions(AggOptions{Parallelism: -1}, Sum("value"))
+	if invalid.Err() == nil {
+		t.Error("Expected error for negative parallelism")
+	}
+}
+
+func TestGroupByExpressionAggregations(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "region", Type: arrow.BinaryTypes.String},
+		{Name: "price", Type: arrow.PrimitiveTypes.Float64},
+		{Name: "qty", Type: arrow.PrimitiveTypes.Float64},
+		{Name: "status", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+	builder := array.NewRecordBuilder(pool, schema)
+	defer builder.Release()
+	builder.Field(0).(*array.StringBuilder).AppendValues([]string{"east", "west", "east", "west", "east"}, nil)
+	builder.Field(1).(*array.Float64Builder).AppendValues([]float64{2, 3, 4, 5, 6}, nil)
+	builder.Field(2).(*array.Float64Builder).AppendValues([]float64{10, 1, 5, 2, 1}, nil)
+	builder.Field(3).(*array.StringBuilder).AppendValues(
+		[]string{"paid", "open", "open", "paid", ""}, []bool{true, true, true, true, false})
+	record := builder.NewRecord()
+	defer record.Release()
+	df := NewDataFrame(record)
+	defer df.Release()
+
+	revenue := Col("price").Mul(Col("qty"))
+	paid := Col("status").Eq(Lit("paid"))
+	result := df.GroupBy("region").Agg(
+		SumExpr(revenue).As("revenue"),
+		SumExpr(revenue).Filter(paid).As("paid_revenue"),
+		Count("price").Filter(paid).As("paid_orders"),
+		Max("price").Filter(paid).Filter(Col("qty").Gt(Lit(5.0))).As("max_paid_bulk"),
+		MeanExpr(Col("price").Add(Lit(1.0))),
+	)
+	defer result.Release()
+	if result.Err() != nil {
+		t.Fatalf("Expression aggregation failed: %v", result.Err())
+	}
+
+	// Groups: east (rows 0, 2, 4), west (rows 1, 3)
+	expectedFloat := map[string][]float64{
+		"revenue":      {46, 13},
+		"paid_revenue": {20, 10},
+	}
+	for name, want := range expectedFloat {
+		idx := result.Record().Schema().FieldIndices(name)[0]
+		got := result.Record().Column(idx).(*array.Float64).Float64Values()
+		if got[0] != want[0] || got[1] != want[1] {
+			t.Errorf("%s: expected %v, got %v", name, want, got)
+		}
+	}
+	paidOrders := result.Record().Column(3).(*array.Int64).Int64Values()
+	if paidOrders[0] != 1 || paidOrders[1] != 1 {
+		t.Errorf("paid_orders: expected [1 1], got %v", paidOrders)
+	}
+	// No west row is paid with more than 5 items
+	maxPaidBulk := result.Record().Column(4).(*array.Float64)
+	if maxPaidBulk.Value(0) != 2 || !maxPaidBulk.IsNull(1) {
+		t.Errorf("max_paid_bulk: expected [2 null], got %v", maxPaidBulk)
+	}
+	if name := result.ColumnNames()[5]; name != "(price add Lit(1))_mean" {
+		t.Errorf("Expected the expression's name with _mean, got %s", name)
+	}
+
+	bad := df.GroupBy("region").Agg(Sum("price").Filter(Col("qty")))
+	if bad.Err() == nil {
+		t.Error("Expected error for a non-boolean filter")
+	}
+}
+
+func TestGroupByExpressionAggregations_NumericInputs(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "region", Type: arrow.BinaryTypes.String},
+		{Name: "price", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "qty", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "units", Type: arrow.PrimitiveTypes.Int32},
+	}, nil)
+	builder := array.NewRecordBuilder(pool, schema)
+	defer builder.Release()
+	builder.Field(0).(*array.StringBuilder).AppendValues([]string{"east", "west", "east", "west", "east"}, nil)
+	builder.Field(1).(*array.Int64Builder).AppendValues([]int64{2, 3, 4, 5, 2}, nil)
+	builder.Field(2).(*array.Int64Builder).AppendValues([]int64{10, 1, 5, 2, 1}, nil)
+	builder.Field(3).(*array.Int32Builder).AppendValues([]int32{1, 2, 3, 4, 5}, nil)
+	record := builder.NewRecord()
+	defer record.Release()
+	df := NewDataFrame(record)
+	defer df.Release()
+
+	revenue := Col("price").Mul(Col("qty"))
+	result := df.GroupBy("region").Agg(
+		SumExpr(revenue).As("revenue"),
+		Variance("revenue").Of(revenue),
+		Median("qty_doubled").Of(Col("qty").Mul(Lit(int64(2)))),
+		Max("units"),
+		CountDistinct("qty_parity").Of(Col("qty").Gt(Lit(int64(5)))),
+	)
+	defer result.Release()
+	if result.Err() != nil {
+		t.Fatalf("Aggregation of numeric inputs failed: %v", result.Err())
+	}
+
+	// Groups: east (rows 0, 2, 4), west (rows 1, 3)
+	expected := map[string][]float64{
+		"revenue":            {42, 13},
+		"revenue_variance":   {108, 24.5},
+		"qty_doubled_median": {10, 3},
+		"units_max":          {5, 4},
+	}
+	for name, want := range expected {
+		indices := result.Record().Schema().FieldIndices(name)
+		if len(indices) == 0 {
+			t.Fatalf("Missing result column %s in %v", name, result.ColumnNames())
+		}
+		got := result.Record().Column(indices[0]).(*array.Float64).Float64Values()
+		if got[0] != want[0] || got[1] != want[1] {
+			t.Errorf("%s: expected %v, got %v", name, want, got)
+		}
+	}
+	distinct := result.Record().Column(5).(*array.Int64).Int64Values()
+	if distinct[0] != 2 || distinct[1] != 1 {
+		t.Errorf("qty_parity_count_distinct: expected [2 1], got %v", distinct)
+	}
+}
+
+func TestGroupByStatisticalAggregations(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "team", Type: arrow.BinaryTypes.String},
+		{Name: "player", Type: arrow.BinaryTypes.String},
+		{Name: "score", Type: arrow.PrimitiveTypes.Float64},
+		{Name: "minutes", Type: arrow.PrimitiveTypes.Float64},
+		{Name: "active", Type: arrow.FixedWidthTypes.Boolean},
+	}, nil)
+	builder := array.NewRecordBuilder(pool, schema)
+	defer builder.Release()
+	builder.Field(0).(*array.StringBuilder).AppendValues([]string{"red", "red", "red", "blue", "blue"}, nil)
+	builder.Field(1).(*array.StringBuilder).AppendValues([]string{"ann", "bob", "cid", "dan", "eve"}, nil)
+	builder.Field(2).(*array.Float64Builder).AppendValues([]float64{10, 30, 20, 5, 5}, nil)
+	builder.Field(3).(*array.Float64Builder).AppendValues([]float64{1, 3, 2, 4, 8}, nil)
+	builder.Field(4).(*array.BooleanBuilder).AppendValues([]bool{true, false, true, true, true}, nil)
+	record := builder.NewRecord()
+	defer record.Release()
+	df := NewDataFrame(record)
+	defer df.Release()
+
+	result := df.GroupBy("team").Agg(
+		Median("score"), Percentile("score", 0.25), Mode("score"),
+		Correlation("score", "minutes"), Covariance("score", "minutes"),
+		ArgMax("player", "score"), First("player"), Last("player"),
+		All("active"), CollectList("player"), CountDistinct("score"),
+	)
+	defer result.Release()
+	if result.Err() != nil {
+		t.Fatalf("GroupBy failed: %v", result.Err())
+	}
+
+	// Groups are ordered by key: blue, then red
+	expected := map[string]string{
+		"score_median":         "5 20",
+		"score_p25":            "5 15",
+		"score_mode":           "5 10",
+		"corr_score_minutes":   "(null) 1",
+		"cov_score_minutes":    "0 10",
+		"player_arg_max":       "dan bob",
+		"player_first":         "dan ann",
+		"player_last":          "eve cid",
+		"active_all":           "true false",
+		"player_list":          `["dan","eve"] ["ann","bob","cid"]`,
+		"score_count_distinct": "1 3",
+	}
+	for name, want := range expected {
+		indices := result.Record().Schema().FieldIndices(name)
+		if len(indices) == 0 {
+			t.Fatalf("Missing column %s", name)
+		}
+		column := result.Record().Column(indices[0])
+		if got := column.ValueStr(0) + " " + column.ValueStr(1); got != want {
+			t.Errorf("%s: expected %q, got %q", name, want, got)
+		}
+	}
+}
@@ -3,21 +3,17 @@
 package gopherframe
 
 import (
-	"context"
 	"encoding/csv"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 
 	"github.com/apache/arrow-go/v18/arrow"
-	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/ipc"
-	"github.com/apache/arrow-go/v18/arrow/memory"
-	"github.com/apache/arrow-go/v18/parquet"
-	"github.com/apache/arrow-go/v18/parquet/file"
-	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/felixgeelhaar/GopherFrame/pkg/storage"
 )
 
 // validateFilePath performs basic security validation on file paths
@@ -40,57 +36,29 @@ func validateFilePath(filename string) error {
 
 // ReadParquet reads a DataFrame from a Parquet file.
 // Returns a new DataFrame with the data from the file.
+// Use ReadParquetWithOptions to read only some columns or rows.
 func ReadParquet(filename string) (*DataFrame, error) {
-	if err := validateFilePath(filename); err != nil {
-		return nil, err
-	}
-
-	// Open the Parquet file
-	f, err := os.Open(filename)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open Parquet file: %w", err)
-	}
-	defer func() { _ = f.Close() }()
-
-	// Skip file info - not needed for reading
-
-	// Create Parquet file reader
-	parquetReader, err := file.NewParquetReader(f, file.WithReadProps(parquet.NewReaderProperties(memory.DefaultAllocator)))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Parquet reader: %w", err)
-	}
-	defer func() { _ = parquetReader.Close() }()
-
-	// Create Arrow file reader from Parquet
-	arrowReader, err := pqarrow.NewFileReader(parquetReader, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Arrow reader: %w", err)
-	}
-
-	// Read all row groups into a table with context
-	ctx := context.Background()
-	table, err := arrowReader.ReadTable(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read table: %w", err)
-	}
-	defer table.Release()
-
-	// Convert table to record
-	tr := array.NewTableReader(table, -1)
-	defer tr.Release()
-
-	if !tr.Next() {
-		return nil, fmt.Errorf("no records in Parquet file")
-	}
+	return ReadParquetWithOptions(filename, storage.ReadOptions{})
+}
 
-	record := tr.Record()
-	record.Retain() // Keep the record alive
+// WriteParquet writes a DataFrame to an uncompressed Parquet file with a
+// single row group. Use WriteParquetWithOptions to choose compression,
+// row group size and encodings.
+func WriteParquet(df *DataFrame, filename string) error {
+	return WriteParquetWithOptions(df, filename, ParquetWriteOptions{})
+}
 
-	return NewDataFrame(record), nil
+// ReadCSV reads a DataFrame from a comma-separated CSV file with a header row.
+// Column types are inferred as int64, float64 or string from every row. Use
+// ReadCSVWithOptions for other formats, explicit types or to limit the rows
+// sampled with CSVReadOptions.InferSchemaRows.
+func ReadCSV(filename string) (*DataFrame, error) {
+	return ReadCSVWithOptions(filename, CSVReadOptions{})
 }
 
-// WriteParquet writes a DataFrame to a Parquet file.
-func WriteParquet(df *DataFrame, filename string) error {
+// WriteCSV writes a DataFrame to a CSV file. A .gz, .zst, .bz2 or .xz
+// extension compresses the file with that codec.
+func WriteCSV(df *DataFrame, filename string) error {
 	if df.Err() != nil {
 		return fmt.Errorf("DataFrame has error: %w", df.Err())
 	}
@@ -100,154 +68,28 @@ func WriteParquet(df *DataFrame, filename string) error {
 	}
 
 	// Create the output file
-	f, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
-	}
-	defer func() { _ = f.Close() }()
-
-	// Get the Arrow record from the DataFrame
-	record := df.coreDF.Record()
-
-	// Create Arrow table from record
-	table := array.NewTableFromRecords(record.Schema(), []arrow.Record{record})
-	defer table.Release()
-
-	// Set up Parquet writer properties
-	writerProps := parquet.NewWriterProperties()
-	arrowProps := pqarrow.DefaultWriterProps()
-
-	// Create Parquet writer
-	writer, err := pqarrow.NewFileWriter(record.Schema(), f, writerProps, arrowProps)
+	f, err := createCompressed(filename, 0666)
 	if err != nil {
-		return fmt.Errorf("failed to create Parquet writer: %w", err)
+		return fmt.Errorf("failed to create CSV file: %w", err)
 	}
-	defer func() {
-		if err := writer.Close(); err != nil {
-			// Log error but don't override the main error
-			fmt.Fprintf(os.Stderr, "warning: failed to close Parquet writer: %v\n", err)
-		}
-	}()
-
-	// Write the table with proper chunk size
-	chunkSize := table.NumRows()
-	if chunkSize <= 0 {
-		chunkSize = 1000 // Default chunk size
+	if err := WriteCSVTo(df, f); err != nil {
+		_ = f.Close()
+		return err
 	}
-	if err := writer.WriteTable(table, chunkSize); err != nil {
-		return fmt.Errorf("failed to write table: %w", err)
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write CSV file: %w", err)
 	}
-
 	return nil
 }
 
-// ReadCSV reads a DataFrame from a CSV file.
-// This implementation attempts to infer column types from the data.
-func ReadCSV(filename string) (*DataFrame, error) {
-	if err := validateFilePath(filename); err != nil {
-		return nil, err
-	}
-
-	// Read CSV data
-	header, records, err := readCSVData(filename)
-	if err != nil {
-		return nil, err
-	}
-
-	// Infer column types by checking first non-empty value in each column
-	columnTypes := make([]arrow.DataType, len(header))
-	for i := range header {
-		columnTypes[i] = inferColumnType(records, i)
-	}
-
-	// Build Arrow arrays
-	pool := memory.NewGoAllocator()
-	arrays := make([]arrow.Array, len(header))
-	fields := make([]arrow.Field, len(header))
-
-	for colIdx, colName := range header {
-		fields[colIdx] = arrow.Field{Name: colName, Type: columnTypes[colIdx]}
-		arrays[colIdx] = buildColumnArray(pool, columnTypes[colIdx], records, colIdx)
-	}
-
-	// Create schema and record
-	schema := arrow.NewSchema(fields, nil)
-	record := array.NewRecord(schema, arrays, int64(len(records)))
-
-	// Release arrays as they're now owned by the record
-	for _, arr := range arrays {
-		arr.Release()
-	}
-
-	return NewDataFrame(record), nil
-}
-
-// inferColumnType attempts to determine the column type by sampling values
-func inferColumnType(records [][]string, colIdx int) arrow.DataType {
-	// Sample up to 100 non-empty values to determine type
-	sampleSize := 100
-	if len(records) < sampleSize {
-		sampleSize = len(records)
-	}
-
-	hasInt := true
-	hasFloat := true
-
-	for i := 0; i < sampleSize; i++ {
-		if colIdx >= len(records[i]) || records[i][colIdx] == "" {
-			continue
-		}
-
-		val := records[i][colIdx]
-
-		// Try parsing as int
-		if _, err := strconv.ParseInt(val, 10, 64); err != nil {
-			hasInt = false
-		}
-
-		// Try parsing as float
-		if _, err := strconv.ParseFloat(val, 64); err != nil {
-			hasFloat = false
-		}
-
-		// If neither int nor float, it's a string
-		if !hasInt && !hasFloat {
-			return arrow.BinaryTypes.String
-		}
-	}
-
-	// Prefer int over float if possible
-	if hasInt {
-		return arrow.PrimitiveTypes.Int64
-	}
-	if hasFloat {
-		return arrow.PrimitiveTypes.Float64
-	}
-
-	// Default to string
-	return arrow.BinaryTypes.String
-}
-
-// WriteCSV writes a DataFrame to a CSV file.
-func WriteCSV(df *DataFrame, filename string) error {
+// WriteCSVTo writes a DataFrame to w as CSV with a header row.
+func WriteCSVTo(df *DataFrame, w io.Writer) error {
 	if df.Err() != nil {
 		return fmt.Errorf("DataFrame has error: %w", df.Err())
 	}
 
-	if err := validateFilePath(filename); err != nil {
-		return err
-	}
-
-	// Create the output file
-	f, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("failed to create CSV file: %w", err)
-	}
-	defer func() { _ = f.Close() }()
-
 	// Create CSV writer
-	writer := csv.NewWriter(f)
-	defer writer.Flush()
+	writer := csv.NewWriter(w)
 
 	// Write header row with column names
 	header := df.ColumnNames()
@@ -307,6 +149,10 @@ func WriteCSV(df *DataFrame, filename string) error {
 		}
 	}
 
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV data: %w", err)
+	}
 	return nil
 }
 
@@ -323,8 +169,19 @@ func ReadArrowIPC(filename string) (*DataFrame, error) {
 	}
 	defer func() { _ = f.Close() }()
 
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat Arrow IPC file: %w", err)
+	}
+	return ReadArrowIPCFrom(f, info.Size())
+}
+
+// ReadArrowIPCFrom reads a DataFrame from the first record of Arrow IPC file
+// data of the given size. The IPC file format keeps its footer at the end, so
+// r must support random access; *os.File and *bytes.Reader both do.
+func ReadArrowIPCFrom(r io.ReaderAt, size int64) (*DataFrame, error) {
 	// Create Arrow IPC file reader
-	reader, err := ipc.NewFileReader(f)
+	reader, err := ipc.NewFileReader(io.NewSectionReader(r, 0, size))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Arrow IPC reader: %w", err)
 	}
@@ -364,125 +221,33 @@ func WriteArrowIPC(df *DataFrame, filename string) error {
 	}
 	defer func() { _ = f.Close() }()
 
+	return WriteArrowIPCTo(df, f)
+}
+
+// WriteArrowIPCTo writes a DataFrame to w in the Arrow IPC file format.
+func WriteArrowIPCTo(df *DataFrame, w io.Writer) error {
+	if df.Err() != nil {
+		return fmt.Errorf("DataFrame has error: %w", df.Err())
+	}
+
 	// Get the Arrow record from the DataFrame
 	record := df.coreDF.Record()
 
 	// Create Arrow IPC file writer
-	writer, err := ipc.NewFileWriter(f, ipc.WithSchema(record.Schema()))
+	writer, err := ipc.NewFileWriter(w, ipc.WithSchema(record.Schema()))
 	if err != nil {
 		return fmt.Errorf("failed to create Arrow IPC writer: %w", err)
 	}
-	defer func() {
-		if err := writer.Close(); err != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to close Arrow writer: %v\n", err)
-		}
-	}()
 
 	// Write the record
 	if err := writer.Write(record); err != nil {
+		_ = writer.Close()
 		return fmt.Errorf("failed to write record: %w", err)
 	}
 
-	return nil
-}
-
-// readCSVData reads and parses CSV file data
-func readCSVData(filename string) ([]string, [][]string, error) {
-	// Open the CSV file
-	f, err := os.Open(filename)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to open CSV file: %w", err)
-	}
-	defer func() { _ = f.Close() }()
-
-	// Create CSV reader
-	reader := csv.NewReader(f)
-
-	// Read header row
-	header, err := reader.Read()
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to read CSV header: %w", err)
-	}
-
-	// Read all records to determine types and build columns
-	records, err := reader.ReadAll()
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to read CSV records: %w", err)
-	}
-
-	if len(records) == 0 {
-		return nil, nil, fmt.Errorf("CSV file has no data rows")
-	}
-
-	return header, records, nil
-}
-
-// buildColumnArray creates an Arrow array for a specific column
-func buildColumnArray(pool memory.Allocator, dataType arrow.DataType, records [][]string, colIdx int) arrow.Array {
-	switch dataType.ID() {
-	case arrow.INT64:
-		return buildInt64Array(pool, records, colIdx)
-	case arrow.FLOAT64:
-		return buildFloat64Array(pool, records, colIdx)
-	default: // STRING
-		return buildStringArray(pool, records, colIdx)
-	}
-}
-
-// buildInt64Array creates an int64 Arrow array
-func buildInt64Array(pool memory.Allocator, records [][]string, colIdx int) arrow.Array {
-	builder := array.NewInt64Builder(pool)
-	defer builder.Release()
-
-	for _, row := range records {
-		if colIdx < len(row) && row[colIdx] != "" {
-			val, err := strconv.ParseInt(row[colIdx], 10, 64)
-			if err != nil {
-				builder.AppendNull()
-			} else {
-				builder.Append(val)
-			}
-		} else {
-			builder.AppendNull()
-		}
-	}
-
-	return builder.NewArray()
-}
-
-// buildFloat64Array creates a float64 Arrow array
-func buildFloat64Array(pool memory.Allocator, records [][]string, colIdx int) arrow.Array {
-	builder := array.NewFloat64Builder(pool)
-	defer builder.Release()
-
-	for _, row := range records {
-		if colIdx < len(row) && row[colIdx] != "" {
-			val, err := strconv.ParseFloat(row[colIdx], 64)
-			if err != nil {
-				builder.AppendNull()
-			} else {
-				builder.Append(val)
-			}
-		} else {
-			builder.AppendNull()
-		}
+	// Close writes the footer
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to close Arrow IPC writer: %w", err)
 	}
-
-	return builder.NewArray()
-}
-
-// buildStringArray creates a string Arrow array
-func buildStringArray(pool memory.Allocator, records [][]string, colIdx int) arrow.Array {
-	builder := array.NewStringBuilder(pool)
-	defer builder.Release()
-
-	for _, row := range records {
-		if colIdx < len(row) {
-			builder.Append(row[colIdx])
-		} else {
-			builder.Append("")
-		}
-	}
-
-	return builder.NewArray()
+	return nil
 }
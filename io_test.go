@@ -1,6 +1,7 @@
 package gopherframe
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"testing"
@@ -8,6 +9,7 @@ import (
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/felixgeelhaar/GopherFrame/pkg/storage"
 )
 
 func TestParquetRoundTrip(t *testing.T) {
@@ -136,6 +138,98 @@ func TestCSVRoundTrip(t *testing.T) {
 	}
 }
 
+// closeRecorder is an in-memory writer that records whether it was closed.
+type closeRecorder struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestReaderWriterVariants(t *testing.T) {
+	df := createSampleDataFrame()
+	defer df.Release()
+
+	tests := []struct {
+		name  string
+		write func(*DataFrame, *closeRecorder) error
+		read  func([]byte) (*DataFrame, error)
+	}{
+		{
+			name:  "CSV",
+			write: func(df *DataFrame, w *closeRecorder) error { return WriteCSVTo(df, w) },
+			read: func(data []byte) (*DataFrame, error) {
+				return ReadCSVFrom(bytes.NewReader(data), CSVReadOptions{})
+			},
+		},
+		{
+			name:  "Parquet",
+			write: func(df *DataFrame, w *closeRecorder) error { return WriteParquetTo(df, w, ParquetWriteOptions{}) },
+			read: func(data []byte) (*DataFrame, error) {
+				return ReadParquetFrom(bytes.NewReader(data), int64(len(data)), storage.ReadOptions{})
+			},
+		},
+		{
+			name:  "ArrowIPC",
+			write: func(df *DataFrame, w *closeRecorder) error { return WriteArrowIPCTo(df, w) },
+			read: func(data []byte) (*DataFrame, error) {
+				return ReadArrowIPCFrom(bytes.NewReader(data), int64(len(data)))
+			},
+		},
+		{
+			name:  "JSON",
+			write: func(df *DataFrame, w *closeRecorder) error { return WriteJSONTo(df, w) },
+			read:  func(data []byte) (*DataFrame, error) { return ReadJSONFrom(bytes.NewReader(data), JSONReadOptions{}) },
+		},
+		{
+			name:  "NDJSON",
+			write: func(df *DataFrame, w *closeRecorder) error { return WriteNDJSONTo(df, w) },
+			read:  func(data []byte) (*DataFrame, error) { return ReadNDJSONFrom(bytes.NewReader(data), JSONReadOptions{}) },
+		},
+		{
+			name:  "Avro",
+			write: func(df *DataFrame, w *closeRecorder) error { return WriteAvroTo(df, w, AvroWriteOptions{}) },
+			read:  func(data []byte) (*DataFrame, error) { return ReadAvroFrom(bytes.NewReader(data), AvroReadOptions{}) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf closeRecorder
+			if err := tt.write(df, &buf); err != nil {
+				t.Fatalf("Failed to write %s: %v", tt.name, err)
+			}
+			if buf.closed {
+				t.Errorf("%s writer closed the destination", tt.name)
+			}
+
+			readDF, err := tt.read(buf.Bytes())
+			if err != nil {
+				t.Fatalf("Failed to read %s: %v", tt.name, err)
+			}
+			defer readDF.Release()
+
+			if readDF.NumRows() != df.NumRows() {
+				t.Errorf("Row count mismatch: expected %d, got %d", df.NumRows(), readDF.NumRows())
+			}
+			for _, name := range df.ColumnNames() {
+				if !readDF.HasColumn(name) {
+					t.Errorf("Column %s missing after round trip", name)
+				}
+			}
+
+			record := readDF.Record()
+			names := record.Column(record.Schema().FieldIndices("name")[0]).(*array.String)
+			if got := names.Value(2); got != "Charlie" {
+				t.Errorf("name[2]: expected Charlie, got %s", got)
+			}
+		})
+	}
+}
+
 func TestStorageBackendIntegration(t *testing.T) {
 	// Create DataFrame
 	df := createSampleDataFrame()
@@ -0,0 +1,495 @@
+package gopherframe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/endian"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+// ReadArrowIPCMmap memory-maps an Arrow IPC file and returns an iterator
+// that yields its record batches one DataFrame at a time. The columns of an
+// uncompressed batch point straight into the mapping, so batches are not
+// copied onto the Go heap and the operating system pages them in as they are
+// used; files and batches can be much larger than available memory.
+//
+// Batches written with IPC body compression, and columns of dictionary,
+// union, view and run-end encoded types, cannot be used in place and are
+// decoded into memory instead.
+//
+// The file stays mapped until the iterator is closed and every DataFrame it
+// returned has been released.
+//
+// Example:
+//
+//	it, err := ReadArrowIPCMmap("events.arrow")
+//	if err != nil {
+//	    return err
+//	}
+//	defer it.Close()
+//	for it.HasNext() {
+//	    batch := it.Next()
+//	    process(batch)
+//	    batch.Release()
+//	}
+//	return it.Err()
+func ReadArrowIPCMmap(filename string) (*DataFrameIterator, error) {
+	if err := validateFilePath(filename); err != nil {
+		return nil, err
+	}
+
+	source := &ipcMmapSource{filename: filename}
+	if err := source.open(); err != nil {
+		return nil, err
+	}
+	return &DataFrameIterator{source: source}, nil
+}
+
+// mmapRegion is a memory-mapped file whose bytes back Arrow buffers. As the
+// allocator of those buffers it counts them, so that the file is unmapped
+// only once it is closed and the last buffer has been released.
+type mmapRegion struct {
+	mu     sync.Mutex
+	data   []byte
+	unmap  func() error
+	refs   int
+	closed bool
+}
+
+// buffer returns a buffer of length bytes at offset in the mapping.
+func (m *mmapRegion) buffer(offset, length int64) *memory.Buffer {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.refs++
+	return memory.NewBufferWithAllocator(m.data[offset:offset+length:offset+length], m)
+}
+
+func (m *mmapRegion) Allocate(int) []byte {
+	panic("gopherframe: memory-mapped buffers cannot be allocated")
+}
+
+func (m *mmapRegion) Reallocate(int, []byte) []byte {
+	panic("gopherframe: memory-mapped buffers cannot be resized")
+}
+
+// Free is called when a buffer of the mapping is released.
+func (m *mmapRegion) Free([]byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.refs--
+	if m.closed && m.refs == 0 {
+		_ = m.release()
+	}
+}
+
+// close unmaps the file once no buffer refers to it.
+func (m *mmapRegion) close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	if m.refs == 0 {
+		return m.release()
+	}
+	return nil
+}
+
+func (m *mmapRegion) release() error {
+	if m.data == nil {
+		return nil
+	}
+	m.data = nil
+	return m.unmap()
+}
+
+// ipcBlock locates a message in an Arrow IPC file.
+type ipcBlock struct {
+	offset  int64
+	metaLen int64
+	bodyLen int64
+}
+
+// ipcBuffer is the location of a buffer in a record batch body.
+type ipcBuffer struct {
+	offset int64
+	length int64
+}
+
+// ipcFieldNode is the length and null count of an array in a record batch.
+type ipcFieldNode struct {
+	length    int64
+	nullCount int64
+}
+
+// ipcMmapSource yields the record batches of a memory-mapped IPC file.
+type ipcMmapSource struct {
+	filename string
+	region   *mmapRegion
+	reader   *ipc.FileReader
+	blocks   []ipcBlock
+	// inPlace is whether the schema's columns can be read from the mapping
+	inPlace bool
+	index   int
+}
+
+// open maps the file and reads its footer and schema.
+func (s *ipcMmapSource) open() error {
+	data, unmap, err := mmapFile(s.filename)
+	if err != nil {
+		return fmt.Errorf("failed to map Arrow IPC file: %w", err)
+	}
+	region := &mmapRegion{data: data, unmap: unmap}
+
+	// The reader validates the file and decodes batches that cannot be used
+	// in place; it reads only the footer and schema up front
+	reader, err := ipc.NewFileReader(bytes.NewReader(data))
+	if err != nil {
+		_ = region.close()
+		return fmt.Errorf("failed to create Arrow IPC reader: %w", err)
+	}
+	blocks, err := ipcRecordBlocks(data)
+	if err != nil {
+		_ = reader.Close()
+		_ = region.close()
+		return err
+	}
+
+	schema := reader.Schema()
+	inPlace := schema.Endianness() == endian.NativeEndian
+	for _, field := range schema.Fields() {
+		inPlace = inPlace && ipcMappable(field.Type)
+	}
+	s.region, s.reader, s.blocks, s.inPlace, s.index = region, reader, blocks, inPlace, 0
+	return nil
+}
+
+// ipcMappable reports whether arrays of type dt can be built from the
+// buffers of a record batch without decoding.
+func ipcMappable(dt arrow.DataType) bool {
+	switch dt := dt.(type) {
+	case arrow.FixedWidthDataType, *arrow.NullType:
+		return dt.ID() != arrow.DICTIONARY
+	case *arrow.StringType, *arrow.BinaryType, *arrow.LargeStringType, *arrow.LargeBinaryType:
+		return true
+	case *arrow.StructType:
+		for _, field := range dt.Fields() {
+			if !ipcMappable(field.Type) {
+				return false
+			}
+		}
+		return true
+	case *arrow.ListType, *arrow.LargeListType, *arrow.FixedSizeListType, *arrow.MapType:
+		return ipcMappable(dt.(arrow.ListLikeType).Elem())
+	default:
+		return false
+	}
+}
+
+func (s *ipcMmapSource) next() (*DataFrame, error) {
+	if s.reader == nil {
+		return nil, io.EOF
+	}
+	if s.index >= len(s.blocks) {
+		return nil, io.EOF
+	}
+	i := s.index
+	s.index++
+
+	record, err := s.mapRecord(s.blocks[i])
+	if errors.Is(err, errIPCNotMappable) {
+		record, err = s.reader.RecordAt(i)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read record batch %d: %w", i, err)
+	}
+	df := NewDataFrame(record)
+	record.Release()
+	return df, nil
+}
+
+func (s *ipcMmapSource) reset() error {
+	if s.reader != nil {
+		s.index = 0
+		return nil
+	}
+	// The iterator closes the source at the end of the file
+	return s.open()
+}
+
+func (s *ipcMmapSource) count() int {
+	return len(s.blocks)
+}
+
+func (s *ipcMmapSource) close() error {
+	if s.reader == nil {
+		return nil
+	}
+	err := s.reader.Close()
+	s.reader = nil
+	return errors.Join(err, s.region.close())
+}
+
+// errIPCNotMappable is returned for record batches that must be decoded.
+var errIPCNotMappable = errors.New("record batch cannot be used in place")
+
+// Flatbuffer message header type of record batches
+const ipcHeaderRecordBatch = 3
+
+// mapRecord builds a record whose buffers are slices of the mapping.
+func (s *ipcMmapSource) mapRecord(block ipcBlock) (record arrow.Record, err error) {
+	if !s.inPlace {
+		return nil, errIPCNotMappable
+	}
+	defer func() {
+		// Corrupt flatbuffers make the accessors panic
+		if r := recover(); r != nil {
+			record, err = nil, fmt.Errorf("invalid record batch metadata: %v", r)
+		}
+	}()
+
+	data := s.region.data
+	if block.offset < 0 || block.metaLen < 8 || block.bodyLen < 0 ||
+		block.offset+block.metaLen+block.bodyLen > int64(len(data)) {
+		return nil, fmt.Errorf("record batch block out of range")
+	}
+	meta := data[block.offset : block.offset+block.metaLen]
+	// The metadata is prefixed by its length, after a continuation marker
+	// since format version 0.15
+	prefix := 4
+	if binary.LittleEndian.Uint32(meta) == 0xFFFFFFFF {
+		prefix = 8
+	}
+	msg := flatbufferRoot(meta[prefix:])
+	if msg.byteField(1) != ipcHeaderRecordBatch {
+		return nil, fmt.Errorf("message is not a record batch")
+	}
+	batch, ok := msg.table(2)
+	if !ok {
+		return nil, fmt.Errorf("record batch has no header")
+	}
+	if _, compressed := batch.table(3); compressed {
+		return nil, errIPCNotMappable
+	}
+
+	loader := &ipcBatchLoader{
+		region:  s.region,
+		body:    block.offset + block.metaLen,
+		bodyLen: block.bodyLen,
+	}
+	for _, pos := range batch.structVector(1, 16) {
+		loader.nodes = append(loader.nodes, ipcFieldNode{
+			length:    batch.GetInt64(pos),
+			nullCount: batch.GetInt64(pos + 8),
+		})
+	}
+	for _, pos := range batch.structVector(2, 16) {
+		loader.buffers = append(loader.buffers, ipcBuffer{offset: batch.GetInt64(pos), length: batch.GetInt64(pos + 8)})
+	}
+
+	schema := s.reader.Schema()
+	columns := make([]arrow.Array, 0, schema.NumFields())
+	defer func() {
+		for _, col := range columns {
+			col.Release()
+		}
+	}()
+	for _, field := range schema.Fields() {
+		colData, err := loader.load(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", field.Name, err)
+		}
+		columns = append(columns, array.MakeFromData(colData))
+		colData.Release()
+	}
+	return array.NewRecord(schema, columns, batch.int64Field(0)), nil
+}
+
+// ipcBatchLoader builds array data from the field nodes and buffers of a
+// record batch, which list the arrays of the schema depth first.
+type ipcBatchLoader struct {
+	region  *mmapRegion
+	body    int64
+	bodyLen int64
+	nodes   []ipcFieldNode
+	buffers []ipcBuffer
+}
+
+func (l *ipcBatchLoader) node() (ipcFieldNode, error) {
+	if len(l.nodes) == 0 {
+		return ipcFieldNode{}, fmt.Errorf("record batch has too few field nodes")
+	}
+	node := l.nodes[0]
+	l.nodes = l.nodes[1:]
+	return node, nil
+}
+
+// buffer returns the next buffer of the body, or nil with skip set.
+func (l *ipcBatchLoader) buffer(skip bool) (*memory.Buffer, error) {
+	if len(l.buffers) == 0 {
+		return nil, fmt.Errorf("record batch has too few buffers")
+	}
+	buf := l.buffers[0]
+	l.buffers = l.buffers[1:]
+	if buf.offset < 0 || buf.length < 0 || buf.offset+buf.length > l.bodyLen {
+		return nil, fmt.Errorf("buffer out of range of the record batch body")
+	}
+	if skip {
+		return nil, nil
+	}
+	if buf.length == 0 {
+		return memory.NewBufferBytes(nil), nil
+	}
+	return l.region.buffer(l.body+buf.offset, buf.length), nil
+}
+
+// load builds the data of the next array, of type dt.
+func (l *ipcBatchLoader) load(dt arrow.DataType) (arrow.ArrayData, error) {
+	node, err := l.node()
+	if err != nil {
+		return nil, err
+	}
+	if dt.ID() == arrow.NULL {
+		// Null arrays have no buffers
+		return array.NewData(dt, int(node.length), []*memory.Buffer{nil}, nil, int(node.length), 0), nil
+	}
+
+	// Every other mappable type starts with a validity bitmap, which may be
+	// empty when there are no nulls
+	numBuffers := 1
+	var children []arrow.DataType
+	switch dt := dt.(type) {
+	case *arrow.StringType, *arrow.BinaryType, *arrow.LargeStringType, *arrow.LargeBinaryType:
+		numBuffers = 3
+	case *arrow.ListType, *arrow.LargeListType, *arrow.MapType:
+		numBuffers = 2
+		children = []arrow.DataType{dt.(arrow.ListLikeType).Elem()}
+	case *arrow.FixedSizeListType:
+		children = []arrow.DataType{dt.Elem()}
+	case *arrow.StructType:
+		for _, field := range dt.Fields() {
+			children = append(children, field.Type)
+		}
+	default:
+		numBuffers = 2
+	}
+
+	buffers := make([]*memory.Buffer, numBuffers)
+	childData := make([]arrow.ArrayData, 0, len(children))
+	defer func() {
+		for _, buf := range buffers {
+			if buf != nil {
+				buf.Release()
+			}
+		}
+		for _, child := range childData {
+			child.Release()
+		}
+	}()
+	for i := range buffers {
+		if buffers[i], err = l.buffer(i == 0 && node.nullCount == 0); err != nil {
+			return nil, err
+		}
+	}
+	for _, childType := range children {
+		child, err := l.load(childType)
+		if err != nil {
+			return nil, err
+		}
+		childData = append(childData, child)
+	}
+	return array.NewData(dt, int(node.length), buffers, childData, int(node.nullCount), 0), nil
+}
+
+// ipcRecordBlocks returns the record batch blocks listed in the footer of
+// Arrow IPC file data.
+func ipcRecordBlocks(data []byte) (blocks []ipcBlock, err error) {
+	const magic = "ARROW1"
+	if len(data) < 2*len(magic)+6 || string(data[:len(magic)]) != magic || string(data[len(data)-len(magic):]) != magic {
+		return nil, fmt.Errorf("not an Arrow IPC file")
+	}
+	footerLen := int64(int32(binary.LittleEndian.Uint32(data[len(data)-len(magic)-4:])))
+	footerEnd := int64(len(data) - len(magic) - 4)
+	if footerLen <= 0 || footerLen > footerEnd-8 {
+		return nil, fmt.Errorf("invalid Arrow IPC footer")
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			blocks, err = nil, fmt.Errorf("invalid Arrow IPC footer: %v", r)
+		}
+	}()
+	footer := flatbufferRoot(data[footerEnd-footerLen : footerEnd])
+	// Block: offset int64, metadata length int32 and padding, body length int64
+	for _, pos := range footer.structVector(3, 24) {
+		blocks = append(blocks, ipcBlock{
+			offset:  footer.GetInt64(pos),
+			metaLen: int64(footer.GetInt32(pos + 8)),
+			bodyLen: footer.GetInt64(pos + 16),
+		})
+	}
+	return blocks, nil
+}
+
+// flatbufferTable reads the fields of a flatbuffer table by their index in
+// the schema, which is all the IPC metadata needs.
+type flatbufferTable struct {
+	flatbuffers.Table
+}
+
+func flatbufferRoot(buf []byte) flatbufferTable {
+	return flatbufferTable{flatbuffers.Table{Bytes: buf, Pos: flatbuffers.GetUOffsetT(buf)}}
+}
+
+// slot returns the position of field i relative to the table, or 0 if the
+// field is absent.
+func (t flatbufferTable) slot(i int) flatbuffers.UOffsetT {
+	return flatbuffers.UOffsetT(t.Offset(flatbuffers.VOffsetT(4 + 2*i)))
+}
+
+func (t flatbufferTable) byteField(i int) byte {
+	if o := t.slot(i); o != 0 {
+		return t.GetByte(t.Pos + o)
+	}
+	return 0
+}
+
+func (t flatbufferTable) int64Field(i int) int64 {
+	if o := t.slot(i); o != 0 {
+		return t.GetInt64(t.Pos + o)
+	}
+	return 0
+}
+
+func (t flatbufferTable) table(i int) (flatbufferTable, bool) {
+	o := t.slot(i)
+	if o == 0 {
+		return flatbufferTable{}, false
+	}
+	return flatbufferTable{flatbuffers.Table{Bytes: t.Bytes, Pos: t.Indirect(t.Pos + o)}}, true
+}
+
+// structVector returns the positions of the elements of a vector of structs
+// of the given size.
+func (t flatbufferTable) structVector(i, size int) []flatbuffers.UOffsetT {
+	o := t.slot(i)
+	if o == 0 {
+		return nil
+	}
+	start, n := t.Vector(o), t.VectorLen(o)
+	if int(start)+n*size > len(t.Bytes) {
+		panic("vector out of range")
+	}
+	positions := make([]flatbuffers.UOffsetT, n)
+	for j := range positions {
+		positions[j] = start + flatbuffers.UOffsetT(j*size)
+	}
+	return positions
+}
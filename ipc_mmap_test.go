@@ -0,0 +1,145 @@
+package gopherframe
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var ipcMmapSchema = arrow.NewSchema([]arrow.Field{
+	{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+	{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+	{Name: "ok", Type: arrow.FixedWidthTypes.Boolean},
+	{Name: "point", Type: arrow.StructOf(
+		arrow.Field{Name: "xy", Type: arrow.FixedSizeListOf(2, arrow.PrimitiveTypes.Float64), Nullable: true},
+		arrow.Field{Name: "tags", Type: arrow.ListOf(arrow.BinaryTypes.LargeString), Nullable: true},
+	), Nullable: true},
+	{Name: "attrs", Type: arrow.MapOf(arrow.BinaryTypes.String, arrow.PrimitiveTypes.Int32), Nullable: true},
+	{Name: "nothing", Type: arrow.Null, Nullable: true},
+}, nil)
+
+var ipcMmapBatches = []string{
+	`[{"id": 1, "name": "a", "ok": true, "point": {"xy": [1, 2], "tags": ["x"]}, "attrs": [{"key": "k", "value": 1}]},
+	  {"id": null, "name": null, "ok": false, "point": null, "attrs": null}]`,
+	`[]`,
+	`[{"id": 3, "name": "ccc", "ok": true, "point": {"xy": null, "tags": []}, "attrs": []}]`,
+}
+
+// writeIPCBatches writes each batch of JSON rows as a record batch of an
+// Arrow IPC file and returns the records.
+func writeIPCBatches(t *testing.T, path string, schema *arrow.Schema, batches []string, opts ...ipc.Option) []arrow.Record {
+	t.Helper()
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, f.Close()) }()
+
+	writer, err := ipc.NewFileWriter(f, append(opts, ipc.WithSchema(schema))...)
+	require.NoError(t, err)
+	var records []arrow.Record
+	for _, rows := range batches {
+		record, _, err := array.RecordFromJSON(memory.NewGoAllocator(), schema, strings.NewReader(rows))
+		require.NoError(t, err)
+		require.NoError(t, writer.Write(record))
+		records = append(records, record)
+	}
+	require.NoError(t, writer.Close())
+	return records
+}
+
+func TestReadArrowIPCMmap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "batches.arrow")
+	records := writeIPCBatches(t, path, ipcMmapSchema, ipcMmapBatches)
+
+	it, err := ReadArrowIPCMmap(path)
+	require.NoError(t, err)
+	defer func() { _ = it.Close() }()
+	source := it.source.(*ipcMmapSource)
+	assert.True(t, source.inPlace)
+	assert.Equal(t, 3, it.Len())
+
+	mapping := source.region.data
+	start := uintptr(unsafe.Pointer(unsafe.SliceData(mapping)))
+	end := start + uintptr(len(mapping))
+	for i := 0; it.HasNext(); i++ {
+		batch := it.Next()
+		assert.True(t, array.RecordEqual(records[i], batch.Record()), "batch %d: %v", i, batch.Record())
+
+		// The values are read from the mapping, not copied
+		if values := batch.Record().Column(1).Data().Buffers()[2]; values.Len() > 0 {
+			addr := uintptr(unsafe.Pointer(unsafe.SliceData(values.Bytes())))
+			assert.True(t, addr >= start && addr < end, "batch %d was copied", i)
+		}
+		batch.Release()
+	}
+	require.NoError(t, it.Err())
+
+	// Batches can be read again
+	it.Reset()
+	first := it.Next()
+	require.NotNil(t, first)
+	assert.Equal(t, int64(2), first.NumRows())
+
+	// Closing keeps the file mapped while a batch is in use
+	require.NoError(t, it.Close())
+	assert.NotNil(t, source.region.data)
+	assert.Equal(t, "a", first.Record().Column(1).(*array.String).Value(0))
+	first.Release()
+	assert.Nil(t, source.region.data)
+}
+
+func TestReadArrowIPCMmap_DecodesWhatCannotBeMapped(t *testing.T) {
+	dir := t.TempDir()
+
+	// Compressed bodies
+	path := filepath.Join(dir, "compressed.arrow")
+	records := writeIPCBatches(t, path, ipcMmapSchema, ipcMmapBatches, ipc.WithZstd())
+	it, err := ReadArrowIPCMmap(path)
+	require.NoError(t, err)
+	for i := 0; it.HasNext(); i++ {
+		batch := it.Next()
+		assert.True(t, array.RecordEqual(records[i], batch.Record()), "batch %d", i)
+		batch.Release()
+	}
+	require.NoError(t, it.Err())
+	require.NoError(t, it.Close())
+
+	// Dictionary columns
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "city", Type: &arrow.DictionaryType{IndexType: arrow.PrimitiveTypes.Int8, ValueType: arrow.BinaryTypes.String}},
+	}, nil)
+	path = filepath.Join(dir, "dictionary.arrow")
+	records = writeIPCBatches(t, path, schema, []string{`[{"city": "Oslo"}, {"city": "Rome"}, {"city": "Oslo"}]`})
+	it, err = ReadArrowIPCMmap(path)
+	require.NoError(t, err)
+	defer func() { _ = it.Close() }()
+	assert.False(t, it.source.(*ipcMmapSource).inPlace)
+	batch := it.Next()
+	require.NoError(t, it.Err())
+	defer batch.Release()
+	assert.True(t, array.RecordEqual(records[0], batch.Record()))
+}
+
+func TestReadArrowIPCMmap_Errors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bad.arrow")
+	require.NoError(t, os.WriteFile(path, []byte("not an arrow file"), 0600))
+	_, err := ReadArrowIPCMmap(path)
+	assert.Error(t, err)
+
+	empty := filepath.Join(dir, "empty.arrow")
+	require.NoError(t, os.WriteFile(empty, nil, 0600))
+	_, err = ReadArrowIPCMmap(empty)
+	assert.Error(t, err)
+
+	_, err = ReadArrowIPCMmap(filepath.Join(dir, "missing.arrow"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,197 @@
+package gopherframe
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// ReadArrowIPCStream reads Arrow IPC stream data from r, such as the output
+// of another process on a pipe, into one DataFrame holding every record
+// batch. A stream with a schema and no batches gives an empty DataFrame.
+func ReadArrowIPCStream(r io.Reader) (*DataFrame, error) {
+	it, err := ReadArrowIPCStreamChunked(r)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = it.Close() }()
+
+	var chunks []*DataFrame
+	defer func() {
+		for _, chunk := range chunks {
+			chunk.Release()
+		}
+	}()
+	for it.HasNext() {
+		chunks = append(chunks, it.Next())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(chunks) == 0 {
+		builder := array.NewRecordBuilder(memory.NewGoAllocator(), it.source.(*ipcStreamSource).reader.Schema())
+		defer builder.Release()
+		record := builder.NewRecord()
+		defer record.Release()
+		return NewDataFrame(record), nil
+	}
+	if len(chunks) == 1 {
+		df := chunks[0]
+		chunks = nil
+		return df, nil
+	}
+	return concatDataFrames(chunks)
+}
+
+// ReadArrowIPCStreamChunked returns an iterator that yields each record
+// batch of the Arrow IPC stream data in r as a DataFrame as soon as it has
+// been read. A stream can be read only once, so Reset after the first batch
+// sets an error, and Len returns 0 because the number of batches is not known
+// in advance. Closing the iterator does not close r.
+//
+// Example:
+//
+//	it, err := ReadArrowIPCStreamChunked(os.Stdin)
+//	if err != nil {
+//	    return err
+//	}
+//	defer it.Close()
+//	err = it.ForEachChunk(process)
+func ReadArrowIPCStreamChunked(r io.Reader) (*DataFrameIterator, error) {
+	reader, err := ipc.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Arrow IPC stream reader: %w", err)
+	}
+	return &DataFrameIterator{source: &ipcStreamSource{reader: reader}}, nil
+}
+
+// ipcStreamSource reads the record batches of an Arrow IPC stream.
+type ipcStreamSource struct {
+	reader  *ipc.Reader
+	started bool
+}
+
+func (s *ipcStreamSource) next() (*DataFrame, error) {
+	s.started = true
+	if s.reader.Next() {
+		// NewDataFrame retains the record, which the reader reuses otherwise
+		return NewDataFrame(s.reader.Record()), nil
+	}
+	if err := s.reader.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read Arrow IPC stream: %w", err)
+	}
+	return nil, io.EOF
+}
+
+func (s *ipcStreamSource) reset() error {
+	if !s.started {
+		return nil
+	}
+	return fmt.Errorf("an Arrow IPC stream cannot be reset")
+}
+
+func (s *ipcStreamSource) count() int {
+	return 0
+}
+
+func (s *ipcStreamSource) close() error {
+	s.reader.Release()
+	return nil
+}
+
+// WriteArrowIPCStream writes a DataFrame to w in the Arrow IPC stream format,
+// as a schema message, one record batch and an end-of-stream marker. It does
+// not close w.
+func WriteArrowIPCStream(df *DataFrame, w io.Writer) error {
+	if df.Err() != nil {
+		return fmt.Errorf("DataFrame has error: %w", df.Err())
+	}
+
+	writer, err := NewArrowIPCStreamWriter(w, df.Schema())
+	if err != nil {
+		return err
+	}
+	if err := writer.Write(df); err != nil {
+		_ = writer.Close()
+		return err
+	}
+	return writer.Close()
+}
+
+// ArrowIPCStreamWriter writes DataFrames to an io.Writer as the record
+// batches of one Arrow IPC stream, so that another process can read each
+// batch as soon as it is written.
+//
+// Example:
+//
+//	w, err := NewArrowIPCStreamWriter(os.Stdout, schema)
+//	if err != nil {
+//	    return err
+//	}
+//	for chunk := range chunks {
+//	    if err := w.Write(chunk); err != nil {
+//	        _ = w.Close()
+//	        return err
+//	    }
+//	}
+//	return w.Close()
+type ArrowIPCStreamWriter struct {
+	writer *ipc.Writer
+	schema *arrow.Schema
+	rows   int64
+	closed bool
+}
+
+// NewArrowIPCStreamWriter starts an Arrow IPC stream of DataFrames with the
+// given schema on w.
+func NewArrowIPCStreamWriter(w io.Writer, schema *arrow.Schema) (*ArrowIPCStreamWriter, error) {
+	if schema == nil {
+		return nil, fmt.Errorf("schema cannot be nil")
+	}
+	return &ArrowIPCStreamWriter{writer: ipc.NewWriter(w, ipc.WithSchema(schema)), schema: schema}, nil
+}
+
+// Write writes the rows of df as one record batch. df must have the
+// writer's schema.
+func (w *ArrowIPCStreamWriter) Write(df *DataFrame) error {
+	if w.closed {
+		return fmt.Errorf("arrow IPC stream writer is closed")
+	}
+	if df.Err() != nil {
+		return fmt.Errorf("DataFrame has error: %w", df.Err())
+	}
+	if !df.Schema().Equal(w.schema) {
+		return fmt.Errorf("schema mismatch: writer expects %s, got %s", w.schema, df.Schema())
+	}
+
+	record := df.Record()
+	if err := w.writer.Write(record); err != nil {
+		return fmt.Errorf("failed to write record batch: %w", err)
+	}
+	w.rows += record.NumRows()
+	return nil
+}
+
+// RowsWritten returns the number of rows written so far.
+func (w *ArrowIPCStreamWriter) RowsWritten() int64 {
+	return w.rows
+}
+
+// Close writes the end-of-stream marker. It does not close the underlying
+// writer, and is safe to call more than once.
+func (w *ArrowIPCStreamWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	if err := w.writer.Close(); err != nil {
+		return fmt.Errorf("failed to close Arrow IPC stream writer: %w", err)
+	}
+	return nil
+}
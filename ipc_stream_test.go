@@ -0,0 +1,115 @@
+package gopherframe
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArrowIPCStreamRoundTrip(t *testing.T) {
+	df := createSampleDataFrame()
+	defer df.Release()
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteArrowIPCStream(df, &buf))
+	result, err := ReadArrowIPCStream(&buf)
+	require.NoError(t, err)
+	defer result.Release()
+	assert.True(t, array.RecordEqual(df.Record(), result.Record()))
+}
+
+func TestArrowIPCStreamWriter_Pipe(t *testing.T) {
+	df := createSampleDataFrame()
+	defer df.Release()
+
+	// The reader sees each batch while the writer is still running
+	pr, pw := io.Pipe()
+	written := make(chan error, 1)
+	go func() {
+		w, err := NewArrowIPCStreamWriter(pw, df.Schema())
+		if err == nil {
+			for i := 0; i < 3 && err == nil; i++ {
+				err = w.Write(df)
+			}
+			if err == nil {
+				err = w.Close()
+			}
+		}
+		written <- err
+		_ = pw.CloseWithError(err)
+	}()
+
+	it, err := ReadArrowIPCStreamChunked(pr)
+	require.NoError(t, err)
+	defer func() { _ = it.Close() }()
+	var sizes []int64
+	require.NoError(t, it.ForEachChunk(func(chunk *DataFrame) error {
+		sizes = append(sizes, chunk.NumRows())
+		chunk.Release()
+		return nil
+	}))
+	require.NoError(t, <-written)
+	assert.Equal(t, []int64{3, 3, 3}, sizes)
+
+	it.Reset()
+	assert.Error(t, it.Err())
+}
+
+func TestReadArrowIPCStream_ConcatenatesBatches(t *testing.T) {
+	df := createSampleDataFrame()
+	defer df.Release()
+
+	var buf bytes.Buffer
+	w, err := NewArrowIPCStreamWriter(&buf, df.Schema())
+	require.NoError(t, err)
+	require.NoError(t, w.Write(df))
+	require.NoError(t, w.Write(df))
+	assert.Equal(t, int64(6), w.RowsWritten())
+	require.NoError(t, w.Close())
+	require.NoError(t, w.Close())
+	assert.Error(t, w.Write(df))
+
+	result, err := ReadArrowIPCStream(&buf)
+	require.NoError(t, err)
+	defer result.Release()
+	assert.Equal(t, int64(6), result.NumRows())
+	assert.Equal(t, "Alice", result.Record().Column(1).(*array.String).Value(3))
+}
+
+func TestArrowIPCStream_EmptyAndErrors(t *testing.T) {
+	df := createSampleDataFrame()
+	defer df.Release()
+
+	var buf bytes.Buffer
+	w, err := NewArrowIPCStreamWriter(&buf, df.Schema())
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	result, err := ReadArrowIPCStream(&buf)
+	require.NoError(t, err)
+	defer result.Release()
+	assert.Equal(t, int64(0), result.NumRows())
+	assert.True(t, result.Schema().Equal(df.Schema()))
+
+	other := arrow.NewSchema([]arrow.Field{{Name: "x", Type: arrow.PrimitiveTypes.Int32}}, nil)
+	w, err = NewArrowIPCStreamWriter(&buf, other)
+	require.NoError(t, err)
+	assert.ErrorContains(t, w.Write(df), "schema mismatch")
+
+	_, err = NewArrowIPCStreamWriter(&buf, nil)
+	assert.Error(t, err)
+
+	_, err = ReadArrowIPCStream(strings.NewReader("garbage"))
+	assert.Error(t, err)
+
+	// A truncated stream is an error, not a short read
+	buf.Reset()
+	require.NoError(t, WriteArrowIPCStream(df, &buf))
+	_, err = ReadArrowIPCStream(bytes.NewReader(buf.Bytes()[:buf.Len()-20]))
+	assert.Error(t, err)
+}
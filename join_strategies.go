@@ -8,6 +8,7 @@ import (
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/felixgeelhaar/GopherFrame/pkg/rowhash"
 )
 
 // JoinStrategy specifies the algorithm used for joining.
@@ -53,6 +54,18 @@ func (df *DataFrame) MergeJoin(other *DataFrame, leftKey, rightKey string) *Data
 	numLeftRows := int(leftRecord.NumRows())
 	numRightRows := int(rightRecord.NumRows())
 
+	leftKeys, err := rowhash.NewKeys(leftKeyArr)
+	if err != nil {
+		return &DataFrame{err: fmt.Errorf("failed to hash left key: %w", err)}
+	}
+	rightKeys, err := rowhash.NewKeys(rightKeyArr)
+	if err != nil {
+		return &DataFrame{err: fmt.Errorf("failed to hash right key: %w", err)}
+	}
+	if err := leftKeys.Comparable(rightKeys); err != nil {
+		return &DataFrame{err: fmt.Errorf("cannot merge join %s with %s: %w", leftKey, rightKey, err)}
+	}
+
 	// Merge join: walk both sorted arrays with two pointers
 	var leftMatches, rightMatches []int
 	li, ri := 0, 0
@@ -67,28 +80,23 @@ func (df *DataFrame) MergeJoin(other *DataFrame, leftKey, rightKey string) *Data
 			continue
 		}
 
-		lv := getStringValue(leftKeyArr, li)
-		rv := getStringValue(rightKeyArr, ri)
-
-		if lv < rv {
+		if c := leftKeys.Compare(li, rightKeys, ri); c < 0 {
 			li++
-		} else if lv > rv {
+		} else if c > 0 {
 			ri++
 		} else {
 			// Match found — handle duplicates on both sides
 			matchStart := ri
-			for ri < numRightRows && !rightKeyArr.IsNull(ri) && getStringValue(rightKeyArr, ri) == lv {
+			for ri < numRightRows && rightKeys.Equal(ri, rightKeys, matchStart) {
 				ri++
 			}
-			startLi := li
-			for li < numLeftRows && !leftKeyArr.IsNull(li) && getStringValue(leftKeyArr, li) == lv {
+			for li < numLeftRows && leftKeys.Equal(li, rightKeys, matchStart) {
 				for rj := matchStart; rj < ri; rj++ {
 					leftMatches = append(leftMatches, li)
 					rightMatches = append(rightMatches, rj)
 				}
 				li++
 			}
-			_ = startLi
 		}
 	}
 
@@ -119,31 +127,32 @@ func (df *DataFrame) BroadcastJoin(other *DataFrame, leftKey, rightKey string) *
 	rightKeyIdx := findColIdx(rightSchema, rightKey)
 	rightKeyArr := rightRecord.Column(rightKeyIdx)
 
-	// Build broadcast map: key -> list of right row indices
-	broadcastMap := make(map[string][]int)
-	for i := 0; i < int(rightRecord.NumRows()); i++ {
-		if !rightKeyArr.IsNull(i) {
-			key := getStringValue(rightKeyArr, i)
-			broadcastMap[key] = append(broadcastMap[key], i)
-		}
-	}
-
-	// Scan left table and match
 	leftSchema := leftRecord.Schema()
 	leftKeyIdx := findColIdx(leftSchema, leftKey)
 	leftKeyArr := leftRecord.Column(leftKeyIdx)
 
+	// Build broadcast table: key -> list of right row indices
+	rightKeys, err := rowhash.NewKeys(rightKeyArr)
+	if err != nil {
+		return &DataFrame{err: fmt.Errorf("failed to hash right key: %w", err)}
+	}
+	leftKeys, err := rowhash.NewKeys(leftKeyArr)
+	if err != nil {
+		return &DataFrame{err: fmt.Errorf("failed to hash left key: %w", err)}
+	}
+	broadcast := rowhash.NewGroups(rightKeys)
+	broadcastRows := broadcast.Rows()
+
+	// Scan left table and match
 	var leftMatches, rightMatches []int
 	for i := 0; i < int(leftRecord.NumRows()); i++ {
-		if leftKeyArr.IsNull(i) {
+		id := broadcast.Find(leftKeys, i)
+		if id < 0 {
 			continue
 		}
-		key := getStringValue(leftKeyArr, i)
-		if rIndices, ok := broadcastMap[key]; ok {
-			for _, ri := range rIndices {
-				leftMatches = append(leftMatches, i)
-				rightMatches = append(rightMatches, ri)
-			}
+		for _, ri := range broadcastRows[id] {
+			leftMatches = append(leftMatches, i)
+			rightMatches = append(rightMatches, ri)
 		}
 	}
 
@@ -327,25 +336,34 @@ func (df *DataFrame) CrossTab(rowCol, colCol string) *DataFrame {
 	rowArr := record.Column(rowIdx)
 	colArr := record.Column(colIdx)
 
-	// Count occurrences
-	type key struct{ r, c string }
-	counts := make(map[key]int64)
-	rowValues := make(map[string]bool)
-	colValues := make(map[string]bool)
+	// Group both columns by value; rows with a null in either are skipped
+	rowKeys, err := rowhash.NewKeys(rowArr)
+	if err != nil {
+		return &DataFrame{err: fmt.Errorf("failed to hash row column: %w", err)}
+	}
+	colKeys, err := rowhash.NewKeys(colArr)
+	if err != nil {
+		return &DataFrame{err: fmt.Errorf("failed to hash column column: %w", err)}
+	}
+	rowGroups := rowhash.NewGroups(rowKeys)
+	colGroups := rowhash.NewGroups(colKeys)
 
+	// Count occurrences
+	counts := make([]int64, rowGroups.Len()*colGroups.Len())
+	seenRows := make([]bool, rowGroups.Len())
+	seenCols := make([]bool, colGroups.Len())
 	for i := 0; i < numRows; i++ {
 		if rowArr.IsNull(i) || colArr.IsNull(i) {
 			continue
 		}
-		r := getStringValue(rowArr, i)
-		c := getStringValue(colArr, i)
-		counts[key{r, c}]++
-		rowValues[r] = true
-		colValues[c] = true
+		r, c := rowGroups.GroupOf(i), colGroups.GroupOf(i)
+		counts[r*colGroups.Len()+c]++
+		seenRows[r] = true
+		seenCols[c] = true
 	}
 
-	sortedRows := sortedKeys(rowValues)
-	sortedCols := sortedKeys(colValues)
+	sortedRows := sortedGroups(rowGroups, seenRows)
+	sortedCols := sortedGroups(colGroups, seenCols)
 
 	// Build result
 	var resultFields []arrow.Field
@@ -355,17 +373,17 @@ func (df *DataFrame) CrossTab(rowCol, colCol string) *DataFrame {
 	resultFields = append(resultFields, arrow.Field{Name: rowCol, Type: arrow.BinaryTypes.String})
 	rb := array.NewStringBuilder(pool)
 	for _, r := range sortedRows {
-		rb.Append(r)
+		rb.Append(getStringValue(rowArr, rowGroups.FirstRow(r)))
 	}
 	resultColumns = append(resultColumns, rb.NewArray())
 	rb.Release()
 
 	// One column per unique col value
 	for _, c := range sortedCols {
-		resultFields = append(resultFields, arrow.Field{Name: c, Type: arrow.PrimitiveTypes.Int64})
+		resultFields = append(resultFields, arrow.Field{Name: getStringValue(colArr, colGroups.FirstRow(c)), Type: arrow.PrimitiveTypes.Int64})
 		cb := array.NewInt64Builder(pool)
 		for _, r := range sortedRows {
-			cb.Append(counts[key{r, c}])
+			cb.Append(counts[r*colGroups.Len()+c])
 		}
 		resultColumns = append(resultColumns, cb.NewArray())
 		cb.Release()
@@ -376,7 +394,18 @@ func (df *DataFrame) CrossTab(rowCol, colCol string) *DataFrame {
 	return NewDataFrame(resultRecord)
 }
 
-func sortedKeys(m map[string]bool) []string {
+// sortedGroups returns the groups marked in keep, ordered by key value.
+func sortedGroups(groups *rowhash.Groups, keep []bool) []int {
+	var ids []int
+	for _, id := range groups.Sorted() {
+		if keep[id] {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func sortedKeys[V any](m map[string]V) []string {
 	keys := make([]string, 0, len(m))
 	for k := range m {
 		keys = append(keys, k)
@@ -2,37 +2,98 @@ package gopherframe
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"math"
-	"os"
-	"sort"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/memory"
 )
 
+// JSONReadOptions configures how JSON and NDJSON objects become columns. The
+// zero value infers a column per top-level key, reading nested objects as
+// struct columns and arrays as list columns.
+type JSONReadOptions struct {
+	// Schema gives the name and type of every column and disables type
+	// inference. Keys that are not in the schema are ignored.
+	Schema *arrow.Schema
+
+	// MaxDepth is the number of nesting levels of objects and arrays read as
+	// struct and list columns or flattened; deeper objects and arrays are
+	// read as JSON text. Zero means no limit.
+	MaxDepth int
+
+	// FlattenSeparator, when set, flattens nested objects into top-level
+	// columns named by joining the keys with the separator, such as
+	// "user.address.city" for ".". Arrays are still read as lists.
+	FlattenSeparator string
+}
+
 // ReadJSON reads a JSON file containing an array of objects into a DataFrame.
+// Integral numbers are read as int64 and other numbers as float64, nested
+// objects as struct columns and arrays as list columns; keys whose values
+// have conflicting types are read as strings. Compressed files are
+// decompressed like in ReadCSVWithOptions.
 func ReadJSON(filename string) (*DataFrame, error) {
+	return ReadJSONWithOptions(filename, JSONReadOptions{})
+}
+
+// ReadJSONWithOptions reads a JSON file containing an array of objects into
+// a DataFrame, mapping the objects to columns as described by opts.
+//
+// Example:
+//
+//	df, err := ReadJSONWithOptions("events.json", JSONReadOptions{
+//	    FlattenSeparator: ".",
+//	    MaxDepth:         2,
+//	})
+func ReadJSONWithOptions(filename string, opts JSONReadOptions) (*DataFrame, error) {
 	if err := validateFilePath(filename); err != nil {
 		return nil, err
 	}
 
-	data, err := os.ReadFile(filename)
+	f, err := openCompressed(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open JSON file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	return readJSON(f, opts)
+}
+
+// ReadJSONFrom reads a JSON array of objects from r into a DataFrame, like
+// ReadJSONWithOptions. Compressed data is decompressed.
+func ReadJSONFrom(r io.Reader, opts JSONReadOptions) (*DataFrame, error) {
+	data, err := NewDecompressionReader(r)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read JSON file: %w", err)
+		return nil, err
 	}
+	defer func() { _ = data.Close() }()
+
+	return readJSON(data, opts)
+}
 
+// readJSON reads a DataFrame from an uncompressed JSON array of objects.
+func readJSON(r io.Reader, opts JSONReadOptions) (*DataFrame, error) {
 	var records []map[string]interface{}
-	if err := json.Unmarshal(data, &records); err != nil {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	if err := decoder.Decode(&records); err != nil {
 		return nil, fmt.Errorf("failed to parse JSON: %w", err)
 	}
 
-	return jsonRecordsToDataFrame(records)
+	return jsonRecordsToDataFrame(records, opts)
 }
 
-// WriteJSON writes a DataFrame to a JSON file as an array of objects.
+// WriteJSON writes a DataFrame to a JSON file as an array of objects. A
+// .gz, .zst, .bz2 or .xz extension compresses the file with that codec.
 func WriteJSON(df *DataFrame, filename string) error {
 	if err := validateFilePath(filename); err != nil {
 		return err
@@ -41,6 +102,26 @@ func WriteJSON(df *DataFrame, filename string) error {
 		return df.err
 	}
 
+	f, err := createCompressed(filename, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create JSON file: %w", err)
+	}
+	if err := WriteJSONTo(df, f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write JSON file: %w", err)
+	}
+	return nil
+}
+
+// WriteJSONTo writes a DataFrame to w as an indented JSON array of objects.
+func WriteJSONTo(df *DataFrame, w io.Writer) error {
+	if df.err != nil {
+		return df.err
+	}
+
 	records := dataFrameToJSONRecords(df)
 
 	data, err := json.MarshalIndent(records, "", "  ")
@@ -48,48 +129,73 @@ func WriteJSON(df *DataFrame, filename string) error {
 		return fmt.Errorf("failed to marshal JSON: %w", err)
 	}
 
-	if err := os.WriteFile(filename, data, 0600); err != nil {
-		return fmt.Errorf("failed to write JSON file: %w", err)
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write JSON data: %w", err)
 	}
 
 	return nil
 }
 
-// ReadNDJSON reads a newline-delimited JSON file into a DataFrame.
+// ReadNDJSON reads a newline-delimited JSON file into a DataFrame. Values
+// are typed like in ReadJSON and compressed files are decompressed like in
+// ReadCSVWithOptions.
 func ReadNDJSON(filename string) (*DataFrame, error) {
+	return ReadNDJSONWithOptions(filename, JSONReadOptions{})
+}
+
+// ReadNDJSONWithOptions reads a newline-delimited JSON file into a
+// DataFrame, mapping the objects to columns as described by opts.
+func ReadNDJSONWithOptions(filename string, opts JSONReadOptions) (*DataFrame, error) {
 	if err := validateFilePath(filename); err != nil {
 		return nil, err
 	}
 
-	f, err := os.Open(filename)
+	f, err := openCompressed(filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open NDJSON file: %w", err)
 	}
 	defer func() { _ = f.Close() }()
 
+	return readNDJSON(f, opts)
+}
+
+// ReadNDJSONFrom reads newline-delimited JSON objects from r into a
+// DataFrame, like ReadNDJSONWithOptions. Blank lines are skipped and
+// compressed data is decompressed.
+func ReadNDJSONFrom(r io.Reader, opts JSONReadOptions) (*DataFrame, error) {
+	data, err := NewDecompressionReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = data.Close() }()
+
+	return readNDJSON(data, opts)
+}
+
+// readNDJSON reads a DataFrame from uncompressed newline-delimited JSON.
+func readNDJSON(r io.Reader, opts JSONReadOptions) (*DataFrame, error) {
 	var records []map[string]interface{}
-	scanner := bufio.NewScanner(f)
-	lineNum := 0
-	for scanner.Scan() {
-		lineNum++
-		line := scanner.Text()
-		if line == "" {
-			continue
+	lines := newNDJSONReader(r)
+	for {
+		line, lineNum, err := lines.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
 		}
 		var obj map[string]interface{}
-		if err := json.Unmarshal([]byte(line), &obj); err != nil {
+		if err := unmarshalJSONObject(line, &obj); err != nil {
 			return nil, fmt.Errorf("failed to parse NDJSON at line %d: %w", lineNum, err)
 		}
 		records = append(records, obj)
 	}
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading NDJSON file: %w", err)
-	}
 
-	return jsonRecordsToDataFrame(records)
+	return jsonRecordsToDataFrame(records, opts)
 }
 
-// WriteNDJSON writes a DataFrame as newline-delimited JSON.
+// WriteNDJSON writes a DataFrame as newline-delimited JSON. A .gz, .zst,
+// .bz2 or .xz extension compresses the file with that codec.
 func WriteNDJSON(df *DataFrame, filename string) error {
 	if err := validateFilePath(filename); err != nil {
 		return err
@@ -98,14 +204,29 @@ func WriteNDJSON(df *DataFrame, filename string) error {
 		return df.err
 	}
 
-	f, err := os.Create(filename)
+	f, err := createCompressed(filename, 0666)
 	if err != nil {
 		return fmt.Errorf("failed to create NDJSON file: %w", err)
 	}
-	defer func() { _ = f.Close() }()
+	if err := WriteNDJSONTo(df, f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write NDJSON file: %w", err)
+	}
+	return nil
+}
+
+// WriteNDJSONTo writes a DataFrame to w as newline-delimited JSON, one
+// object per row.
+func WriteNDJSONTo(df *DataFrame, w io.Writer) error {
+	if df.err != nil {
+		return df.err
+	}
 
 	records := dataFrameToJSONRecords(df)
-	writer := bufio.NewWriter(f)
+	writer := bufio.NewWriter(w)
 	for _, rec := range records {
 		data, err := json.Marshal(rec)
 		if err != nil {
@@ -121,152 +242,367 @@ func WriteNDJSON(df *DataFrame, filename string) error {
 	return writer.Flush()
 }
 
+// unmarshalJSONObject parses a JSON object, keeping numbers as json.Number
+// so that integers and floats can be told apart.
+func unmarshalJSONObject(data []byte, obj *map[string]interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(obj); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return fmt.Errorf("unexpected data after JSON object")
+	}
+	return nil
+}
+
 // jsonRecordsToDataFrame converts parsed JSON records to a DataFrame.
-func jsonRecordsToDataFrame(records []map[string]interface{}) (*DataFrame, error) {
-	if len(records) == 0 {
-		// Empty DataFrame
-		schema := arrow.NewSchema([]arrow.Field{}, nil)
-		record := array.NewRecord(schema, nil, 0)
-		return NewDataFrame(record), nil
+func jsonRecordsToDataFrame(records []map[string]interface{}, opts JSONReadOptions) (*DataFrame, error) {
+	records, err := shapeJSONRecords(records, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	// Scan all records for column names and infer types
-	columnNames := make(map[string]bool)
-	columnTypes := make(map[string]arrow.DataType)
+	schema := opts.Schema
+	if schema == nil {
+		schema = inferJSONSchema(records, opts.FlattenSeparator)
+	}
+
+	record, err := buildJSONRecord(schema, records)
+	if err != nil {
+		return nil, err
+	}
+	defer record.Release()
+	return NewDataFrame(record), nil
+}
+
+// shapeJSONRecords applies the flattening and depth limit of opts to records.
+func shapeJSONRecords(records []map[string]interface{}, opts JSONReadOptions) ([]map[string]interface{}, error) {
+	if opts.MaxDepth < 0 {
+		return nil, fmt.Errorf("max depth must not be negative, got %d", opts.MaxDepth)
+	}
+	if opts.FlattenSeparator == "" && opts.MaxDepth == 0 {
+		return records, nil
+	}
+	shaped := make([]map[string]interface{}, len(records))
+	for i, rec := range records {
+		shaped[i] = make(map[string]interface{}, len(rec))
+		shapeJSONObject(shaped[i], "", rec, 0, opts)
+	}
+	return shaped, nil
+}
+
+// jsonRowError reports a JSON value that does not fit its column's type.
+type jsonRowError struct {
+	row    int // 0-based index of the record
+	column string
+	err    error
+}
+
+func (e *jsonRowError) Error() string {
+	return fmt.Sprintf("column %s: %v at row %d", e.column, e.err, e.row+1)
+}
 
+func (e *jsonRowError) Unwrap() error {
+	return e.err
+}
+
+// buildJSONRecord converts records to a record with the given schema. A
+// value that does not fit its column is reported as a *jsonRowError.
+func buildJSONRecord(schema *arrow.Schema, records []map[string]interface{}) (arrow.Record, error) {
+	pool := memory.NewGoAllocator()
+	columns := make([]arrow.Array, 0, schema.NumFields())
+	defer func() {
+		for _, col := range columns {
+			col.Release()
+		}
+	}()
+
+	for _, field := range schema.Fields() {
+		builder := array.NewBuilder(pool, field.Type)
+		builder.Reserve(len(records))
+		for i, rec := range records {
+			if err := appendJSONValue(builder, rec[field.Name]); err != nil {
+				builder.Release()
+				return nil, &jsonRowError{row: i, column: field.Name, err: err}
+			}
+		}
+		columns = append(columns, builder.NewArray())
+		builder.Release()
+	}
+
+	return array.NewRecord(schema, columns, int64(len(records))), nil
+}
+
+// shapeJSONObject copies the keys of obj into out, flattening nested objects
+// when a separator is set and replacing objects and arrays nested deeper
+// than MaxDepth with their JSON text.
+func shapeJSONObject(out map[string]interface{}, prefix string, obj map[string]interface{}, depth int, opts JSONReadOptions) {
+	for key, val := range obj {
+		name := key
+		if prefix != "" {
+			name = prefix + opts.FlattenSeparator + key
+		}
+		nested, isObject := val.(map[string]interface{})
+		if isObject && opts.FlattenSeparator != "" && (opts.MaxDepth == 0 || depth < opts.MaxDepth) {
+			shapeJSONObject(out, name, nested, depth+1, opts)
+			continue
+		}
+		out[name] = limitJSONDepth(val, depth, opts.MaxDepth)
+	}
+}
+
+// limitJSONDepth returns val with the objects and arrays at depth maxDepth
+// and below replaced by their JSON text. A maxDepth of zero means no limit.
+func limitJSONDepth(val interface{}, depth, maxDepth int) interface{} {
+	if maxDepth == 0 {
+		return val
+	}
+	switch v := val.(type) {
+	case map[string]interface{}:
+		if depth >= maxDepth {
+			return jsonText(v)
+		}
+		limited := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			limited[key] = limitJSONDepth(elem, depth+1, maxDepth)
+		}
+		return limited
+	case []interface{}:
+		if depth >= maxDepth {
+			return jsonText(v)
+		}
+		limited := make([]interface{}, len(v))
+		for i, elem := range v {
+			limited[i] = limitJSONDepth(elem, depth+1, maxDepth)
+		}
+		return limited
+	default:
+		return val
+	}
+}
+
+// inferJSONSchema infers a column per key, in sorted order for deterministic
+// output. Columns that are null in every record are strings, unless they
+// are null objects whose fields were flattened into other columns.
+func inferJSONSchema(records []map[string]interface{}, separator string) *arrow.Schema {
+	columnTypes := make(map[string]arrow.DataType)
 	for _, rec := range records {
 		for key, val := range rec {
-			columnNames[key] = true
-			if val == nil {
-				continue
-			}
-			inferredType := inferArrowType(val)
 			existing, ok := columnTypes[key]
 			if !ok {
-				columnTypes[key] = inferredType
-			} else if existing.ID() != inferredType.ID() {
-				// Mixed types default to string
-				columnTypes[key] = arrow.BinaryTypes.String
+				existing = arrow.Null
 			}
+			columnTypes[key] = mergeJSONTypes(existing, inferArrowType(val))
 		}
 	}
 
-	// Sort column names for deterministic output
-	var sortedCols []string
-	for col := range columnNames {
-		sortedCols = append(sortedCols, col)
+	names := sortedKeys(columnTypes)
+	fields := make([]arrow.Field, 0, len(columnTypes))
+	for _, name := range names {
+		if separator != "" && columnTypes[name].ID() == arrow.NULL {
+			prefix := name + separator
+			if j, _ := slices.BinarySearch(names, prefix); j < len(names) && strings.HasPrefix(names[j], prefix) {
+				continue
+			}
+		}
+		fields = append(fields, arrow.Field{Name: name, Type: resolveJSONType(columnTypes[name]), Nullable: true})
 	}
-	sort.Strings(sortedCols)
+	return arrow.NewSchema(fields, nil)
+}
 
-	pool := memory.NewGoAllocator()
-	numRows := len(records)
+// inferArrowType infers an Arrow type from a decoded JSON value: int64 for
+// integral numbers, float64 for other numbers, structs for objects and lists
+// for arrays. Null values have the null type until merged with another type.
+func inferArrowType(val interface{}) arrow.DataType {
+	switch v := val.(type) {
+	case nil:
+		return arrow.Null
+	case bool:
+		return arrow.FixedWidthTypes.Boolean
+	case json.Number:
+		if _, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+			return arrow.PrimitiveTypes.Int64
+		}
+		return arrow.PrimitiveTypes.Float64
+	case int64:
+		return arrow.PrimitiveTypes.Int64
+	case float64:
+		return arrow.PrimitiveTypes.Float64
+	case map[string]interface{}:
+		keys := sortedKeys(v)
+		fields := make([]arrow.Field, len(keys))
+		for i, key := range keys {
+			fields[i] = arrow.Field{Name: key, Type: inferArrowType(v[key]), Nullable: true}
+		}
+		return arrow.StructOf(fields...)
+	case []interface{}:
+		var elem arrow.DataType = arrow.Null
+		for _, item := range v {
+			elem = mergeJSONTypes(elem, inferArrowType(item))
+		}
+		return arrow.ListOf(elem)
+	default:
+		return arrow.BinaryTypes.String
+	}
+}
 
-	var fields []arrow.Field
-	var columns []arrow.Array
+// mergeJSONTypes returns a type that holds values of both types: int64 and
+// float64 merge to float64, structs merge their fields and lists their
+// elements. Other mixed types default to string.
+func mergeJSONTypes(a, b arrow.DataType) arrow.DataType {
+	switch {
+	case a.ID() == arrow.NULL:
+		return b
+	case b.ID() == arrow.NULL:
+		return a
+	case arrow.TypeEqual(a, b):
+		return a
+	}
 
-	for _, colName := range sortedCols {
-		colType, ok := columnTypes[colName]
-		if !ok {
-			colType = arrow.BinaryTypes.String
-		}
-		fields = append(fields, arrow.Field{Name: colName, Type: colType})
-
-		switch colType.ID() {
-		case arrow.FLOAT64:
-			b := array.NewFloat64Builder(pool)
-			for _, rec := range records {
-				val, ok := rec[colName]
-				if !ok || val == nil {
-					b.AppendNull()
-				} else {
-					switch v := val.(type) {
-					case float64:
-						b.Append(v)
-					case json.Number:
-						f, _ := v.Float64()
-						b.Append(f)
-					default:
-						b.AppendNull()
-					}
+	switch {
+	case isJSONNumberType(a) && isJSONNumberType(b):
+		return arrow.PrimitiveTypes.Float64
+	case a.ID() == arrow.STRUCT && b.ID() == arrow.STRUCT:
+		fieldTypes := make(map[string]arrow.DataType)
+		for _, structType := range []*arrow.StructType{a.(*arrow.StructType), b.(*arrow.StructType)} {
+			for _, field := range structType.Fields() {
+				existing, ok := fieldTypes[field.Name]
+				if !ok {
+					existing = arrow.Null
 				}
+				fieldTypes[field.Name] = mergeJSONTypes(existing, field.Type)
 			}
-			columns = append(columns, b.NewArray())
-			b.Release()
-
-		case arrow.INT64:
-			b := array.NewInt64Builder(pool)
-			for _, rec := range records {
-				val, ok := rec[colName]
-				if !ok || val == nil {
-					b.AppendNull()
-				} else {
-					switch v := val.(type) {
-					case float64:
-						b.Append(int64(v))
-					case json.Number:
-						i, _ := v.Int64()
-						b.Append(i)
-					default:
-						b.AppendNull()
-					}
-				}
+		}
+		fields := make([]arrow.Field, 0, len(fieldTypes))
+		for _, name := range sortedKeys(fieldTypes) {
+			fields = append(fields, arrow.Field{Name: name, Type: fieldTypes[name], Nullable: true})
+		}
+		return arrow.StructOf(fields...)
+	case a.ID() == arrow.LIST && b.ID() == arrow.LIST:
+		return arrow.ListOf(mergeJSONTypes(a.(*arrow.ListType).Elem(), b.(*arrow.ListType).Elem()))
+	default:
+		return arrow.BinaryTypes.String
+	}
+}
+
+func isJSONNumberType(dataType arrow.DataType) bool {
+	return dataType.ID() == arrow.INT64 || dataType.ID() == arrow.FLOAT64
+}
+
+// resolveJSONType replaces the null type, left where every value was null,
+// with string.
+func resolveJSONType(dataType arrow.DataType) arrow.DataType {
+	switch t := dataType.(type) {
+	case *arrow.NullType:
+		return arrow.BinaryTypes.String
+	case *arrow.StructType:
+		fields := make([]arrow.Field, t.NumFields())
+		for i, field := range t.Fields() {
+			field.Type = resolveJSONType(field.Type)
+			fields[i] = field
+		}
+		return arrow.StructOf(fields...)
+	case *arrow.ListType:
+		return arrow.ListOf(resolveJSONType(t.Elem()))
+	default:
+		return dataType
+	}
+}
+
+// appendJSONValue appends a decoded JSON value to a builder of any type.
+// Values of types without a direct mapping are parsed from their text, so a
+// schema can read date or timestamp strings as dates and timestamps.
+func appendJSONValue(builder array.Builder, val interface{}) error {
+	if val == nil {
+		builder.AppendNull()
+		return nil
+	}
+
+	switch b := builder.(type) {
+	case *array.Int64Builder:
+		switch v := val.(type) {
+		case json.Number:
+			i, err := strconv.ParseInt(v.String(), 10, 64)
+			if err != nil {
+				return fmt.Errorf("cannot read %s as %s", v, b.Type())
 			}
-			columns = append(columns, b.NewArray())
-			b.Release()
-
-		case arrow.BOOL:
-			b := array.NewBooleanBuilder(pool)
-			for _, rec := range records {
-				val, ok := rec[colName]
-				if !ok || val == nil {
-					b.AppendNull()
-				} else {
-					bv, ok := val.(bool)
-					if ok {
-						b.Append(bv)
-					} else {
-						b.AppendNull()
-					}
-				}
+			b.Append(i)
+			return nil
+		case int64:
+			b.Append(v)
+			return nil
+		}
+	case *array.Float64Builder:
+		switch v := val.(type) {
+		case json.Number:
+			f, err := v.Float64()
+			if err != nil {
+				return fmt.Errorf("cannot read %s as %s", v, b.Type())
 			}
-			columns = append(columns, b.NewArray())
-			b.Release()
-
-		default: // STRING
-			b := array.NewStringBuilder(pool)
-			for _, rec := range records {
-				val, ok := rec[colName]
-				if !ok || val == nil {
-					b.AppendNull()
-				} else {
-					b.Append(fmt.Sprintf("%v", val))
-				}
+			b.Append(f)
+			return nil
+		case float64:
+			b.Append(v)
+			return nil
+		case int64:
+			b.Append(float64(v))
+			return nil
+		}
+	case *array.BooleanBuilder:
+		if v, ok := val.(bool); ok {
+			b.Append(v)
+			return nil
+		}
+	case *array.StringBuilder:
+		b.Append(jsonText(val))
+		return nil
+	case *array.StructBuilder:
+		obj, ok := val.(map[string]interface{})
+		if !ok {
+			break
+		}
+		b.Append(true)
+		for i, field := range b.Type().(*arrow.StructType).Fields() {
+			if err := appendJSONValue(b.FieldBuilder(i), obj[field.Name]); err != nil {
+				return fmt.Errorf("field %s: %w", field.Name, err)
+			}
+		}
+		return nil
+	case *array.ListBuilder:
+		items, ok := val.([]interface{})
+		if !ok {
+			break
+		}
+		b.Append(true)
+		for _, item := range items {
+			if err := appendJSONValue(b.ValueBuilder(), item); err != nil {
+				return err
 			}
-			columns = append(columns, b.NewArray())
-			b.Release()
 		}
+		return nil
+	default:
+		if err := builder.AppendValueFromString(jsonText(val)); err != nil {
+			return fmt.Errorf("cannot read %s as %s: %w", jsonText(val), builder.Type(), err)
+		}
+		return nil
 	}
-
-	schema := arrow.NewSchema(fields, nil)
-	record := array.NewRecord(schema, columns, int64(numRows))
-	return NewDataFrame(record), nil
+	return fmt.Errorf("cannot read %s as %s", jsonText(val), builder.Type())
 }
 
-// inferArrowType infers an Arrow type from a Go value.
-func inferArrowType(val interface{}) arrow.DataType {
+// jsonText returns strings unchanged and other values as JSON text.
+func jsonText(val interface{}) string {
 	switch v := val.(type) {
-	case bool:
-		return arrow.FixedWidthTypes.Boolean
-	case float64:
-		// JSON numbers are float64 by default; check if it's actually an integer
-		if v == math.Trunc(v) && !math.IsInf(v, 0) && !math.IsNaN(v) {
-			return arrow.PrimitiveTypes.Float64 // Keep as float64 since JSON doesn't distinguish
-		}
-		return arrow.PrimitiveTypes.Float64
 	case string:
-		return arrow.BinaryTypes.String
+		return v
+	case json.Number:
+		return v.String()
 	default:
-		return arrow.BinaryTypes.String
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(data)
 	}
 }
 
@@ -280,24 +616,60 @@ func dataFrameToJSONRecords(df *DataFrame) []map[string]interface{} {
 	for i := 0; i < numRows; i++ {
 		records[i] = make(map[string]interface{})
 		for j, field := range schema.Fields() {
-			col := record.Column(j)
-			if col.IsNull(i) {
-				records[i][field.Name] = nil
-				continue
-			}
-			switch a := col.(type) {
-			case *array.Float64:
-				records[i][field.Name] = a.Value(i)
-			case *array.Int64:
-				records[i][field.Name] = a.Value(i)
-			case *array.String:
-				records[i][field.Name] = a.Value(i)
-			case *array.Boolean:
-				records[i][field.Name] = a.Value(i)
-			default:
-				records[i][field.Name] = fmt.Sprintf("%v", a)
-			}
+			records[i][field.Name] = jsonValue(record.Column(j), i)
 		}
 	}
 	return records
 }
+
+// jsonValue converts the value at index i of arr to a value that marshals
+// to JSON: structs become objects and lists arrays. Integral floats keep a
+// fractional part so that they are read back as floats.
+func jsonValue(arr arrow.Array, i int) interface{} {
+	if arr.IsNull(i) {
+		return nil
+	}
+	switch a := arr.(type) {
+	case *array.Float64:
+		return jsonFloat(a.Value(i), 64)
+	case *array.Float32:
+		return jsonFloat(float64(a.Value(i)), 32)
+	case *array.Struct:
+		obj := make(map[string]interface{}, a.NumField())
+		for j, field := range a.DataType().(*arrow.StructType).Fields() {
+			obj[field.Name] = jsonValue(a.Field(j), i)
+		}
+		return obj
+	case *array.Map:
+		return a.GetOneForMarshal(i)
+	case array.ListLike:
+		start, end := a.ValueOffsets(i)
+		values := a.ListValues()
+		items := make([]interface{}, 0, end-start)
+		for k := start; k < end; k++ {
+			items = append(items, jsonValue(values, int(k)))
+		}
+		return items
+	default:
+		return arr.GetOneForMarshal(i)
+	}
+}
+
+// jsonFloat formats a float as a JSON number with a decimal point or
+// exponent. NaN and infinities are returned as is and fail to marshal, as
+// JSON cannot represent them.
+func jsonFloat(v float64, bitSize int) interface{} {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return v
+	}
+	// Like encoding/json, use exponents only for very small or large values
+	format := byte('f')
+	if abs := math.Abs(v); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	text := strconv.FormatFloat(v, format, -1, bitSize)
+	if !strings.ContainsAny(text, ".e") {
+		text += ".0"
+	}
+	return json.Number(text)
+}
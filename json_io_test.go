@@ -1,8 +1,11 @@
 package gopherframe
 
 import (
+	"bytes"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/apache/arrow-go/v18/arrow"
@@ -158,3 +161,92 @@ func TestReadJSON_EmptyArray(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, int64(0), df.NumRows())
 }
+
+const nestedEventsNDJSON = `{"id": 1, "amount": 9.5, "user": {"name": "Alice", "address": {"city": "Oslo"}}, "tags": ["a", "b"]}
+{"id": 2, "amount": 10, "user": {"name": "Bob"}, "tags": []}
+{"id": 3, "amount": null, "user": null, "tags": null}
+`
+
+func TestReadNDJSON_NestedTypes(t *testing.T) {
+	df, err := ReadNDJSONFrom(strings.NewReader(nestedEventsNDJSON), JSONReadOptions{})
+	require.NoError(t, err)
+	defer df.Release()
+
+	schema := df.Record().Schema()
+	assert.Equal(t, []string{"amount", "id", "tags", "user"}, df.ColumnNames())
+	assert.Equal(t, arrow.PrimitiveTypes.Int64, schema.Field(1).Type, "integral numbers are int64")
+	assert.Equal(t, arrow.PrimitiveTypes.Float64, schema.Field(0).Type, "mixed numbers are float64")
+	assert.Equal(t, arrow.ListOf(arrow.BinaryTypes.String), schema.Field(2).Type)
+	assert.Equal(t, arrow.StructOf(
+		arrow.Field{Name: "address", Type: arrow.StructOf(
+			arrow.Field{Name: "city", Type: arrow.BinaryTypes.String, Nullable: true},
+		), Nullable: true},
+		arrow.Field{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+	), schema.Field(3).Type)
+
+	users := df.Record().Column(3).(*array.Struct)
+	assert.True(t, users.IsNull(2))
+	assert.Equal(t, "Bob", users.Field(1).(*array.String).Value(1))
+	tags := df.Record().Column(2).(*array.List)
+	start, end := tags.ValueOffsets(0)
+	assert.Equal(t, int64(2), end-start)
+	assert.True(t, tags.IsNull(2))
+}
+
+func TestWriteJSON_RoundTripsNestedTypes(t *testing.T) {
+	df, err := ReadNDJSONFrom(strings.NewReader(nestedEventsNDJSON), JSONReadOptions{})
+	require.NoError(t, err)
+	defer df.Release()
+
+	for _, write := range []func(*DataFrame, io.Writer) error{WriteJSONTo, WriteNDJSONTo} {
+		var buf bytes.Buffer
+		require.NoError(t, write(df, &buf))
+
+		read := ReadJSONFrom
+		if !strings.HasPrefix(buf.String(), "[") {
+			read = ReadNDJSONFrom
+		}
+		back, err := read(&buf, JSONReadOptions{})
+		require.NoError(t, err)
+		assert.True(t, df.Record().Schema().Equal(back.Record().Schema()), "schema changed: %s", back.Record().Schema())
+		assert.Equal(t, "Oslo", back.Record().Column(3).(*array.Struct).Field(0).(*array.Struct).Field(0).(*array.String).Value(0))
+		back.Release()
+	}
+}
+
+func TestReadJSON_FlattenAndMaxDepth(t *testing.T) {
+	df, err := ReadNDJSONFrom(strings.NewReader(nestedEventsNDJSON), JSONReadOptions{FlattenSeparator: "_"})
+	require.NoError(t, err)
+	defer df.Release()
+	assert.Equal(t, []string{"amount", "id", "tags", "user_address_city", "user_name"}, df.ColumnNames())
+
+	df2, err := ReadNDJSONFrom(strings.NewReader(nestedEventsNDJSON), JSONReadOptions{FlattenSeparator: ".", MaxDepth: 1})
+	require.NoError(t, err)
+	defer df2.Release()
+	assert.Equal(t, []string{"amount", "id", "tags", "user.address", "user.name"}, df2.ColumnNames())
+	addresses := df2.Record().Column(3).(*array.String)
+	assert.Equal(t, `{"city":"Oslo"}`, addresses.Value(0), "objects below MaxDepth are JSON text")
+
+	_, err = ReadNDJSONFrom(strings.NewReader(nestedEventsNDJSON), JSONReadOptions{MaxDepth: -1})
+	assert.Error(t, err)
+}
+
+func TestReadJSON_Schema(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		{Name: "day", Type: arrow.FixedWidthTypes.Date32, Nullable: true},
+		{Name: "missing", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+	data := `[{"id": 7, "day": "2024-03-01", "extra": true}]`
+
+	df, err := ReadJSONFrom(strings.NewReader(data), JSONReadOptions{Schema: schema})
+	require.NoError(t, err)
+	defer df.Release()
+	assert.True(t, schema.Equal(df.Record().Schema()))
+	assert.Equal(t, int32(7), df.Record().Column(0).(*array.Int32).Value(0))
+	assert.Equal(t, "2024-03-01", df.Record().Column(1).(*array.Date32).Value(0).FormattedString())
+	assert.True(t, df.Record().Column(2).IsNull(0))
+
+	_, err = ReadJSONFrom(strings.NewReader(`[{"id": "x"}]`), JSONReadOptions{Schema: schema})
+	assert.ErrorContains(t, err, "column id")
+}
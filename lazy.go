@@ -0,0 +1,308 @@
+package gopherframe
+
+import (
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/felixgeelhaar/GopherFrame/pkg/core"
+	"github.com/felixgeelhaar/GopherFrame/pkg/expr"
+)
+
+// LazyFrame is a DataFrame whose operations are recorded in a logical plan
+// instead of being executed immediately. Collect optimizes the plan and then
+// executes it, which avoids work that does not affect the result:
+//   - Filters are pushed below projections, computed columns, sorts and joins
+//   - Columns that no later operation reads are dropped at the scan
+//   - Computed columns that are never used are not evaluated
+//   - Subexpressions shared by several computed columns are evaluated once
+//
+// Like DataFrame, a LazyFrame carries errors through chained calls; they are
+// reported by Collect.
+//
+// Example:
+//
+//	result := df.Lazy().
+//	    Filter(Col("amount").Gt(Lit(100.0))).
+//	    WithColumn("net", Col("amount").Sub(Col("fee"))).
+//	    GroupBy("region").
+//	    Agg(Sum("net")).
+//	    Sort("net_sum", false).
+//	    Limit(10).
+//	    Collect()
+//	if result.Err() != nil {
+//	    log.Fatal(result.Err())
+//	}
+//	defer result.Release()
+type LazyFrame struct {
+	plan planNode
+	err  error
+}
+
+// WindowOptions describes the window of a LazyFrame.Window computation.
+type WindowOptions struct {
+	// PartitionBy lists the columns that split rows into independent windows.
+	PartitionBy []string
+	// OrderBy lists the columns that order rows within each partition.
+	OrderBy []string
+	// Descending orders rows by OrderBy in descending instead of ascending order.
+	Descending bool
+	// Rows is the size of rolling windows; 0 means the whole partition.
+	Rows int
+}
+
+// Lazy returns a LazyFrame that reads from this DataFrame.
+func (df *DataFrame) Lazy() *LazyFrame {
+	if df.err != nil {
+		return &LazyFrame{err: df.err}
+	}
+	return &LazyFrame{plan: &scanNode{df: df}}
+}
+
+// ScanParquet returns a LazyFrame that reads from a Parquet file. Only the
+// file's schema is read here; data is read by Collect. Selections and filters
+// in the plan are pushed into the scan, so columns that are not needed are
+// not read and row groups whose statistics rule out every row are skipped.
+//
+// Example:
+//
+//	result := ScanParquet("sales.parquet").
+//	    Filter(Col("amount").Gt(Lit(100.0))).
+//	    Select("region", "amount").
+//	    Collect()
+func ScanParquet(filename string) *LazyFrame {
+	schema, err := readParquetSchema(filename)
+	if err != nil {
+		return &LazyFrame{err: err}
+	}
+	return &LazyFrame{plan: &parquetScanNode{filename: filename, schema: schema}}
+}
+
+// then appends a plan node built from the current plan.
+func (lf *LazyFrame) then(build func(input planNode) planNode) *LazyFrame {
+	if lf.err != nil {
+		return &LazyFrame{err: lf.err}
+	}
+	return &LazyFrame{plan: build(lf.plan)}
+}
+
+// Filter keeps only the rows matching the predicate.
+func (lf *LazyFrame) Filter(predicate expr.Expr) *LazyFrame {
+	return lf.then(func(input planNode) planNode {
+		return &filterNode{input: input, predicate: predicate}
+	})
+}
+
+// Select keeps only the specified columns.
+func (lf *LazyFrame) Select(columnNames ...string) *LazyFrame {
+	return lf.then(func(input planNode) planNode {
+		return &projectNode{input: input, selected: columnNames}
+	})
+}
+
+// WithColumn adds or replaces a computed column.
+func (lf *LazyFrame) WithColumn(name string, expression expr.Expr) *LazyFrame {
+	return lf.then(func(input planNode) planNode {
+		return &withColumnNode{input: input, name: name, expr: expression}
+	})
+}
+
+// Sort orders rows by a single column.
+func (lf *LazyFrame) Sort(columnName string, ascending bool) *LazyFrame {
+	return lf.SortMultiple([]SortKey{By(columnName, ascending)})
+}
+
+// SortMultiple orders rows by several columns in the specified order.
+func (lf *LazyFrame) SortMultiple(sortKeys []SortKey) *LazyFrame {
+	return lf.then(func(input planNode) planNode {
+		return &sortNode{input: input, keys: sortKeys}
+	})
+}
+
+// Limit keeps at most the first n rows.
+func (lf *LazyFrame) Limit(n int64) *LazyFrame {
+	return lf.then(func(input planNode) planNode {
+		return &limitNode{input: input, n: n}
+	})
+}
+
+// Cast converts columns to new types, failing on values that cannot be converted.
+func (lf *LazyFrame) Cast(types map[string]arrow.DataType) *LazyFrame {
+	return lf.castColumns(types, expr.CastStrict)
+}
+
+// TryCast converts columns to new types, turning values that cannot be converted into nulls.
+func (lf *LazyFrame) TryCast(types map[string]arrow.DataType) *LazyFrame {
+	return lf.castColumns(types, expr.CastSafe)
+}
+
+func (lf *LazyFrame) castColumns(types map[string]arrow.DataType, mode expr.CastMode) *LazyFrame {
+	result := lf
+	for _, col := range sortedKeys(types) {
+		result = result.WithColumn(col, expr.NewCastExpr(expr.Col(col), types[col], mode))
+	}
+	return result
+}
+
+// FillNull replaces null values in the given columns, as DataFrame.FillNull does.
+func (lf *LazyFrame) FillNull(values map[string]interface{}) *LazyFrame {
+	columns := sortedKeys(values)
+	return lf.then(func(input planNode) planNode {
+		return &mapNode{
+			input:    input,
+			name:     "fill_null",
+			reads:    columns,
+			modifies: columns,
+			apply:    func(df *DataFrame) *DataFrame { return df.FillNull(values) },
+		}
+	})
+}
+
+// DropNulls removes rows with a null value in any of the given columns,
+// or in any column when none are given.
+func (lf *LazyFrame) DropNulls(columns ...string) *LazyFrame {
+	if len(columns) == 0 {
+		return lf.then(func(input planNode) planNode {
+			return &mapNode{
+				input:    input,
+				name:     "drop_nulls",
+				readsAll: true,
+				apply:    func(df *DataFrame) *DataFrame { return df.DropNulls() },
+			}
+		})
+	}
+
+	predicate := expr.Col(columns[0]).IsNotNull()
+	for _, col := range columns[1:] {
+		predicate = predicate.And(expr.Col(col).IsNotNull())
+	}
+	return lf.Filter(predicate)
+}
+
+// LazyGroupedFrame is a LazyFrame grouped by one or more columns.
+type LazyGroupedFrame struct {
+	lf      *LazyFrame
+	columns []string
+}
+
+// GroupBy groups rows by the specified columns; call Agg to aggregate them.
+func (lf *LazyFrame) GroupBy(columns ...string) *LazyGroupedFrame {
+	return &LazyGroupedFrame{lf: lf, columns: columns}
+}
+
+// Agg computes the aggregations for each group.
+func (lg *LazyGroupedFrame) Agg(aggregations ...Aggregation) *LazyFrame {
+	if lg.lf.err != nil {
+		return &LazyFrame{err: lg.lf.err}
+	}
+	if len(lg.columns) == 0 {
+		return &LazyFrame{err: fmt.Errorf("no columns specified for groupby")}
+	}
+	if len(aggregations) == 0 {
+		return &LazyFrame{err: fmt.Errorf("no aggregations specified")}
+	}
+	return lg.lf.then(func(input planNode) planNode {
+		return &aggregateNode{input: input, groupBy: lg.columns, aggregations: aggregations}
+	})
+}
+
+// InnerJoin joins with another LazyFrame, keeping rows with matching keys.
+func (lf *LazyFrame) InnerJoin(other *LazyFrame, leftKey, rightKey string) *LazyFrame {
+	return lf.join(other, core.InnerJoin, []string{leftKey}, []string{rightKey})
+}
+
+// LeftJoin joins with another LazyFrame, keeping all rows of this one.
+func (lf *LazyFrame) LeftJoin(other *LazyFrame, leftKey, rightKey string) *LazyFrame {
+	return lf.join(other, core.LeftJoin, []string{leftKey}, []string{rightKey})
+}
+
+// RightJoin joins with another LazyFrame, keeping all rows of the other one.
+func (lf *LazyFrame) RightJoin(other *LazyFrame, leftKey, rightKey string) *LazyFrame {
+	return lf.join(other, core.RightJoin, []string{leftKey}, []string{rightKey})
+}
+
+// FullOuterJoin joins with another LazyFrame, keeping all rows of both.
+func (lf *LazyFrame) FullOuterJoin(other *LazyFrame, leftKey, rightKey string) *LazyFrame {
+	return lf.join(other, core.FullOuterJoin, []string{leftKey}, []string{rightKey})
+}
+
+// InnerJoinMulti is InnerJoin on several key columns.
+func (lf *LazyFrame) InnerJoinMulti(other *LazyFrame, leftKeys, rightKeys []string) *LazyFrame {
+	return lf.join(other, core.InnerJoin, leftKeys, rightKeys)
+}
+
+// LeftJoinMulti is LeftJoin on several key columns.
+func (lf *LazyFrame) LeftJoinMulti(other *LazyFrame, leftKeys, rightKeys []string) *LazyFrame {
+	return lf.join(other, core.LeftJoin, leftKeys, rightKeys)
+}
+
+// RightJoinMulti is RightJoin on several key columns.
+func (lf *LazyFrame) RightJoinMulti(other *LazyFrame, leftKeys, rightKeys []string) *LazyFrame {
+	return lf.join(other, core.RightJoin, leftKeys, rightKeys)
+}
+
+// FullOuterJoinMulti is FullOuterJoin on several key columns.
+func (lf *LazyFrame) FullOuterJoinMulti(other *LazyFrame, leftKeys, rightKeys []string) *LazyFrame {
+	return lf.join(other, core.FullOuterJoin, leftKeys, rightKeys)
+}
+
+func (lf *LazyFrame) join(other *LazyFrame, joinType core.JoinType, leftKeys, rightKeys []string) *LazyFrame {
+	if lf.err != nil {
+		return &LazyFrame{err: lf.err}
+	}
+	if other == nil {
+		return &LazyFrame{err: fmt.Errorf("other LazyFrame cannot be nil")}
+	}
+	if other.err != nil {
+		return &LazyFrame{err: other.err}
+	}
+	if len(leftKeys) == 0 || len(leftKeys) != len(rightKeys) {
+		return &LazyFrame{err: fmt.Errorf("join requires the same non-zero number of left and right keys, got %d and %d", len(leftKeys), len(rightKeys))}
+	}
+	return &LazyFrame{plan: &joinNode{
+		left:      lf.plan,
+		right:     other.plan,
+		joinType:  joinType,
+		leftKeys:  leftKeys,
+		rightKeys: rightKeys,
+	}}
+}
+
+// Pivot transforms long-format data to wide-format, as DataFrame.Pivot does.
+func (lf *LazyFrame) Pivot(indexCols []string, pivotCol, valueCol string) *LazyFrame {
+	return lf.then(func(input planNode) planNode {
+		return &pivotNode{input: input, indexCols: indexCols, pivotCol: pivotCol, valueCol: valueCol}
+	})
+}
+
+// Unpivot transforms wide-format data to long-format, as DataFrame.Unpivot does.
+func (lf *LazyFrame) Unpivot(idCols, valueCols []string, variableName, valueName string) *LazyFrame {
+	return lf.then(func(input planNode) planNode {
+		return &unpivotNode{input: input, idCols: idCols, valueCols: valueCols, variableName: variableName, valueName: valueName}
+	})
+}
+
+// Window appends the results of window functions as new columns.
+//
+// Example:
+//
+//	ranked := df.Lazy().Window(
+//	    WindowOptions{PartitionBy: []string{"region"}, OrderBy: []string{"sales"}, Descending: true},
+//	    core.Rank().As("sales_rank"),
+//	)
+func (lf *LazyFrame) Window(options WindowOptions, funcs ...core.WindowFunc) *LazyFrame {
+	if len(funcs) == 0 {
+		return &LazyFrame{err: fmt.Errorf("at least one window function required")}
+	}
+	return lf.then(func(input planNode) planNode {
+		return &windowNode{input: input, options: options, funcs: funcs}
+	})
+}
+
+// Collect optimizes the plan, executes it and returns the resulting DataFrame.
+// The source DataFrames are not modified and must outlive the call.
+func (lf *LazyFrame) Collect() *DataFrame {
+	if lf.err != nil {
+		return &DataFrame{err: lf.err}
+	}
+	return executePlan(optimizePlan(lf.plan))
+}
@@ -0,0 +1,255 @@
+package gopherframe
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/felixgeelhaar/GopherFrame/pkg/core"
+)
+
+// Explain returns the logical plan before and after optimization, each as an
+// indented tree with one operator per line and inputs below their consumer.
+//
+// Example output:
+//
+//	== Logical Plan ==
+//	Select [net]
+//	  Filter (Col(customer_id) equal Lit(10))
+//	    WithColumn net = (Col(amount) subtract Col(fee))
+//	      Scan [order_id, customer_id, amount, fee]
+//
+//	== Optimized Plan ==
+//	Select [net]
+//	  WithColumn net = (Col(amount) subtract Col(fee))
+//	    Filter (Col(customer_id) equal Lit(10))
+//	      Scan [customer_id, amount, fee]
+func (lf *LazyFrame) Explain() string {
+	if lf.err != nil {
+		return fmt.Sprintf("Invalid plan: %v\n", lf.err)
+	}
+	return explainPlans(lf.plan, optimizePlan(lf.plan))
+}
+
+// ExplainAnalyze optimizes and executes the plan, and returns the optimized
+// plan annotated with each operator's output row count, elapsed time and
+// bytes allocated. Times and allocations cover the operator alone, not its
+// inputs. Allocations count the bytes an operator requests from the memory
+// allocator of its input DataFrames; memory that expression kernels allocate
+// on their own is not included. The query result is discarded.
+func (lf *LazyFrame) ExplainAnalyze() (string, error) {
+	if lf.err != nil {
+		return "", lf.err
+	}
+	return explainAnalyzePlan(lf.plan, optimizePlan(lf.plan))
+}
+
+// operatorStats holds runtime statistics for one plan node.
+type operatorStats struct {
+	rows      int64
+	elapsed   time.Duration
+	allocated uint64
+}
+
+func explainPlans(original, optimized planNode) string {
+	var sb strings.Builder
+	sb.WriteString("== Logical Plan ==\n")
+	writePlanTree(&sb, original, 0, nil)
+	sb.WriteString("\n== Optimized Plan ==\n")
+	writePlanTree(&sb, optimized, 0, nil)
+	return sb.String()
+}
+
+func explainAnalyzePlan(original, optimized planNode) (string, error) {
+	stats := make(map[planNode]*operatorStats)
+
+	start := time.Now()
+	result := executePlanObserved(optimized, func(node planNode, inputs []*DataFrame, run func([]*DataFrame) *DataFrame) *DataFrame {
+		// Run the operator over its inputs with an allocator that counts
+		// what the operator allocates through them
+		base := memory.DefaultAllocator
+		if len(inputs) > 0 {
+			base = inputs[0].coreDF.Allocator()
+			if inner, ok := base.(*countingAllocator); ok {
+				base = inner.Allocator
+			}
+		}
+		counter := &countingAllocator{Allocator: base}
+		counted := make([]*DataFrame, len(inputs))
+		for i, input := range inputs {
+			counted[i] = &DataFrame{coreDF: core.NewDataFrameWithAllocator(input.coreDF.Record(), counter)}
+		}
+		began := time.Now()
+
+		out := run(counted)
+
+		elapsed := time.Since(began)
+		for _, input := range counted {
+			if input != out {
+				input.Release()
+			}
+		}
+		stats[node] = &operatorStats{
+			rows:      out.NumRows(),
+			elapsed:   elapsed,
+			allocated: uint64(counter.allocated.Load()),
+		}
+		return out
+	})
+	total := time.Since(start)
+	defer result.Release()
+	if err := result.Err(); err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString("== Logical Plan ==\n")
+	writePlanTree(&sb, original, 0, nil)
+	sb.WriteString("\n== Optimized Plan (analyzed) ==\n")
+	writePlanTree(&sb, optimized, 0, stats)
+	fmt.Fprintf(&sb, "\nTotal: rows=%d, time=%s\n", result.NumRows(), total)
+	return sb.String(), nil
+}
+
+// countingAllocator counts the bytes allocated through an allocator.
+// Reallocations count the bytes they grow by.
+type countingAllocator struct {
+	memory.Allocator
+	allocated atomic.Int64
+}
+
+func (a *countingAllocator) Allocate(size int) []byte {
+	a.allocated.Add(int64(size))
+	return a.Allocator.Allocate(size)
+}
+
+func (a *countingAllocator) Reallocate(size int, b []byte) []byte {
+	if grown := size - len(b); grown > 0 {
+		a.allocated.Add(int64(grown))
+	}
+	return a.Allocator.Reallocate(size, b)
+}
+
+// writePlanTree writes node and its inputs, indenting two spaces per level.
+func writePlanTree(sb *strings.Builder, node planNode, depth int, stats map[planNode]*operatorStats) {
+	sb.WriteString(strings.Repeat("  ", depth))
+	sb.WriteString(node.describe())
+	if s, ok := stats[node]; ok {
+		fmt.Fprintf(sb, " (rows=%d, time=%s, allocated=%s)", s.rows, s.elapsed, formatBytes(s.allocated))
+	}
+	sb.WriteByte('\n')
+	for _, input := range node.inputs() {
+		writePlanTree(sb, input, depth+1, stats)
+	}
+}
+
+// formatBytes renders a byte count with a binary unit.
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// Node descriptions
+
+func (n *scanNode) describe() string {
+	cols, _ := n.columns()
+	return fmt.Sprintf("Scan [%s]", strings.Join(cols, ", "))
+}
+
+func (n *parquetScanNode) describe() string {
+	cols, _ := n.columns()
+	desc := fmt.Sprintf("ParquetScan %s [%s]", n.filename, strings.Join(cols, ", "))
+	if n.predicate != nil {
+		desc += " filter=" + n.predicate.String()
+	}
+	return desc
+}
+
+func (n *filterNode) describe() string {
+	return "Filter " + n.predicate.String()
+}
+
+func (n *projectNode) describe() string {
+	return fmt.Sprintf("Select [%s]", strings.Join(n.selected, ", "))
+}
+
+func (n *withColumnNode) describe() string {
+	return fmt.Sprintf("WithColumn %s = %s", n.name, n.expr.String())
+}
+
+func (n *sortNode) describe() string {
+	keys := make([]string, len(n.keys))
+	for i, key := range n.keys {
+		direction := "ASC"
+		if !key.Ascending {
+			direction = "DESC"
+		}
+		keys[i] = key.Column + " " + direction
+	}
+	return fmt.Sprintf("Sort [%s]", strings.Join(keys, ", "))
+}
+
+func (n *limitNode) describe() string {
+	return fmt.Sprintf("Limit %d", n.n)
+}
+
+func (n *aggregateNode) describe() string {
+	aggs := make([]string, len(n.aggregations))
+	for i, agg := range n.aggregations {
+		aggs[i] = agg.describe()
+	}
+	return fmt.Sprintf("Aggregate by [%s]: %s", strings.Join(n.groupBy, ", "), strings.Join(aggs, ", "))
+}
+
+func (n *joinNode) describe() string {
+	kind := map[core.JoinType]string{
+		core.InnerJoin:     "InnerJoin",
+		core.LeftJoin:      "LeftJoin",
+		core.RightJoin:     "RightJoin",
+		core.FullOuterJoin: "FullOuterJoin",
+	}[n.joinType]
+	return fmt.Sprintf("%s on [%s] = [%s]", kind, strings.Join(n.leftKeys, ", "), strings.Join(n.rightKeys, ", "))
+}
+
+func (n *pivotNode) describe() string {
+	return fmt.Sprintf("Pivot index=[%s] columns=%s values=%s", strings.Join(n.indexCols, ", "), n.pivotCol, n.valueCol)
+}
+
+func (n *unpivotNode) describe() string {
+	return fmt.Sprintf("Unpivot id=[%s] values=[%s] AS %s, %s",
+		strings.Join(n.idCols, ", "), strings.Join(n.valueCols, ", "), n.variableName, n.valueName)
+}
+
+func (n *windowNode) describe() string {
+	names := make([]string, len(n.funcs))
+	for i, fn := range n.funcs {
+		names[i] = fn.Name()
+	}
+	order := "ASC"
+	if n.options.Descending {
+		order = "DESC"
+	}
+	desc := fmt.Sprintf("Window [%s] partition=[%s] order=[%s] %s",
+		strings.Join(names, ", "), strings.Join(n.options.PartitionBy, ", "), strings.Join(n.options.OrderBy, ", "), order)
+	if n.options.Rows > 0 {
+		desc += fmt.Sprintf(" rows=%d", n.options.Rows)
+	}
+	return desc
+}
+
+func (n *mapNode) describe() string {
+	if n.readsAll {
+		return fmt.Sprintf("Map %s [*]", n.name)
+	}
+	return fmt.Sprintf("Map %s [%s]", n.name, strings.Join(n.reads, ", "))
+}
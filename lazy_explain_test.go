@@ -0,0 +1,117 @@
+package gopherframe
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazyFrame_Explain(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	orders, _ := createLazyOrdersDataFrames(pool)
+	defer orders.Release()
+
+	explained := orders.Lazy().
+		WithColumn("net", Col("amount").Sub(Col("fee"))).
+		Filter(Col("customer_id").Eq(Lit(int64(10)))).
+		Select("net").
+		Explain()
+
+	logical, optimized, found := strings.Cut(explained, "== Optimized Plan ==\n")
+	require.True(t, found)
+	assert.Equal(t, "== Logical Plan ==\n"+
+		"Select [net]\n"+
+		"  Filter (Col(customer_id) equal Lit(10))\n"+
+		"    WithColumn net = (Col(amount) subtract Col(fee))\n"+
+		"      Scan [amount, customer_id, fee, order_id]\n\n", logical)
+	assert.Equal(t, "Select [net]\n"+
+		"  WithColumn net = (Col(amount) subtract Col(fee))\n"+
+		"    Filter (Col(customer_id) equal Lit(10))\n"+
+		"      Scan [amount, customer_id, fee]\n", optimized)
+}
+
+func TestLazyFrame_ExplainJoinIndentsBothInputs(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	orders, customers := createLazyOrdersDataFrames(pool)
+	defer orders.Release()
+	defer customers.Release()
+
+	explained := orders.Lazy().
+		InnerJoin(customers.Lazy(), "customer_id", "id").
+		GroupBy("region").
+		Agg(Sum("amount")).
+		Explain()
+
+	assert.Contains(t, explained, "Aggregate by [region]: sum(amount) AS amount_sum\n  InnerJoin on [customer_id] = [id]\n")
+	assert.Equal(t, 4, strings.Count(explained, "\n    Scan ["), "both join inputs are one level below the join")
+}
+
+func TestLazyFrame_ExplainAnalyze(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	orders, _ := createLazyOrdersDataFrames(pool)
+	defer orders.Release()
+
+	report, err := orders.Lazy().
+		WithColumn("net", Col("amount").Sub(Col("fee"))).
+		Filter(Col("customer_id").Eq(Lit(int64(10)))).
+		ExplainAnalyze()
+	require.NoError(t, err)
+
+	assert.Contains(t, report, "== Optimized Plan (analyzed) ==")
+	assert.Contains(t, report, "Scan [amount, customer_id, fee, order_id] (rows=6, time=")
+	assert.Contains(t, report, "Filter (Col(customer_id) equal Lit(10)) (rows=3, time=")
+	assert.Contains(t, report, "WithColumn net = (Col(amount) subtract Col(fee)) (rows=3, time=")
+	assert.Contains(t, report, "allocated=")
+	assert.Contains(t, report, "Total: rows=3")
+
+	// Scans share their source's buffers; the filter copies the kept rows
+	_, analyzed, _ := strings.Cut(report, "(analyzed)")
+	for _, line := range strings.Split(analyzed, "\n") {
+		switch {
+		case strings.Contains(line, "Scan ["):
+			assert.Contains(t, line, "allocated=0 B")
+		case strings.Contains(line, "Filter ("):
+			assert.NotContains(t, line, "allocated=0 B")
+		}
+	}
+
+	_, err = orders.Lazy().Filter(Col("missing").Gt(Lit(1.0))).ExplainAnalyze()
+	assert.Error(t, err)
+
+	_, err = orders.Lazy().Limit(-1).ExplainAnalyze()
+	assert.Error(t, err)
+	assert.Contains(t, orders.Lazy().GroupBy().Agg(Sum("amount")).Explain(), "Invalid plan")
+}
+
+func TestQueryPlan_Explain(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	orders, _ := createLazyOrdersDataFrames(pool)
+	defer orders.Release()
+
+	plan := NewQueryPlan(orders).
+		Select("amount", "customer_id").
+		Filter(Col("amount").Gt(Lit(60.0)))
+
+	assert.Equal(t, "== Logical Plan ==\n"+
+		"Filter (Col(amount) greater Lit(60))\n"+
+		"  Select [amount, customer_id]\n"+
+		"    Scan [amount, customer_id, fee, order_id]\n\n"+
+		"== Optimized Plan ==\n"+
+		"Select [amount, customer_id]\n"+
+		"  Filter (Col(amount) greater Lit(60))\n"+
+		"    Scan [amount, customer_id, fee, order_id]\n", plan.Explain())
+
+	report, err := plan.ExplainAnalyze()
+	require.NoError(t, err)
+	assert.Contains(t, report, "Select [amount, customer_id] (rows=4, time=")
+	assert.Contains(t, report, "Total: rows=4")
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512 B", formatBytes(512))
+	assert.Equal(t, "1.5 KiB", formatBytes(1536))
+	assert.Equal(t, "2.0 MiB", formatBytes(2<<20))
+}
@@ -0,0 +1,636 @@
+package gopherframe
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/felixgeelhaar/GopherFrame/pkg/core"
+	"github.com/felixgeelhaar/GopherFrame/pkg/expr"
+)
+
+// optimizePlan rewrites a logical plan into an equivalent, cheaper one.
+// Passes run in order:
+//  1. Expression optimization (constant folding, identity elimination)
+//  2. Common subexpression elimination across chains of computed columns
+//  3. Predicate pushdown, including through joins
+//  4. Projection pruning
+func optimizePlan(plan planNode) planNode {
+	plan = optimizeExpressions(plan)
+	plan = eliminateCommonSubexpressions(plan)
+	plan = pushDownPredicates(plan)
+	return pruneColumns(plan, nil)
+}
+
+// transformPlan rewrites a plan bottom-up: inputs first, then the node itself.
+func transformPlan(node planNode, fn func(planNode) planNode) planNode {
+	if children := node.inputs(); len(children) > 0 {
+		newChildren := make([]planNode, len(children))
+		for i, child := range children {
+			newChildren[i] = transformPlan(child, fn)
+		}
+		node = node.withInputs(newChildren)
+	}
+	return fn(node)
+}
+
+// ====================
+// Expression Optimization
+// ====================
+
+func optimizeExpressions(plan planNode) planNode {
+	return transformPlan(plan, func(node planNode) planNode {
+		switch n := node.(type) {
+		case *filterNode:
+			return &filterNode{input: n.input, predicate: OptimizeWithSchema(n.predicate, planSchema(n.input))}
+		case *withColumnNode:
+			return &withColumnNode{input: n.input, name: n.name, expr: OptimizeWithSchema(n.expr, planSchema(n.input))}
+		}
+		return node
+	})
+}
+
+// planSchema returns the output schema of a plan when it follows from the
+// schemas of its scans, or nil when it is only known once the plan runs.
+func planSchema(node planNode) *arrow.Schema {
+	switch n := node.(type) {
+	case *scanNode:
+		if n.df.err != nil {
+			return nil
+		}
+		return projectSchema(n.df.Schema(), n.projection)
+	case *parquetScanNode:
+		return projectSchema(n.schema, n.projection)
+	case *filterNode, *sortNode, *limitNode:
+		return planSchema(node.inputs()[0])
+	case *projectNode:
+		if input := planSchema(n.input); input != nil {
+			return projectSchema(input, n.selected)
+		}
+	case *withColumnNode:
+		input := planSchema(n.input)
+		if input == nil {
+			return nil
+		}
+		empty := emptyDataFrame(input)
+		defer empty.Release()
+		dataType, ok := resolveType(n.expr, empty)
+		if !ok {
+			return nil
+		}
+		fields := append([]arrow.Field(nil), input.Fields()...)
+		field := arrow.Field{Name: n.name, Type: dataType, Nullable: true}
+		if indices := input.FieldIndices(n.name); len(indices) > 0 {
+			fields[indices[0]] = field
+		} else {
+			fields = append(fields, field)
+		}
+		return arrow.NewSchema(fields, nil)
+	}
+	return nil
+}
+
+// projectSchema keeps the listed fields of schema, in order, or returns nil
+// if one is missing. A nil projection keeps every field.
+func projectSchema(schema *arrow.Schema, projection []string) *arrow.Schema {
+	if projection == nil {
+		return schema
+	}
+	fields := make([]arrow.Field, len(projection))
+	for i, name := range projection {
+		indices := schema.FieldIndices(name)
+		if len(indices) == 0 {
+			return nil
+		}
+		fields[i] = schema.Field(indices[0])
+	}
+	return arrow.NewSchema(fields, nil)
+}
+
+// ====================
+// Common Subexpression Elimination
+// ====================
+
+// cseColumnPrefix names the temporary columns holding shared subexpressions.
+const cseColumnPrefix = "__cse_"
+
+// eliminateCommonSubexpressions finds chains of consecutive computed columns
+// and evaluates subexpressions they share only once, into temporary columns
+// that are dropped again at the end of the chain.
+func eliminateCommonSubexpressions(node planNode) planNode {
+	var chain []*withColumnNode
+	base := node
+	for {
+		wc, ok := base.(*withColumnNode)
+		if !ok {
+			break
+		}
+		chain = append(chain, wc)
+		base = wc.input
+	}
+
+	if len(chain) == 0 {
+		children := node.inputs()
+		if len(children) == 0 {
+			return node
+		}
+		newChildren := make([]planNode, len(children))
+		for i, child := range children {
+			newChildren[i] = eliminateCommonSubexpressions(child)
+		}
+		return node.withInputs(newChildren)
+	}
+
+	base = eliminateCommonSubexpressions(base)
+
+	// chain was collected top-down; evaluate it in execution order
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return rewriteChain(chain, base)
+}
+
+// rewriteChain rebuilds a chain of computed columns on top of base, sharing
+// repeated subexpressions through temporary columns.
+func rewriteChain(chain []*withColumnNode, base planNode) planNode {
+	rebuild := func(input planNode, exprs []expr.Expr) planNode {
+		for i, wc := range chain {
+			input = &withColumnNode{input: input, name: wc.name, expr: exprs[i]}
+		}
+		return input
+	}
+
+	exprs := make([]expr.Expr, len(chain))
+	produced := make([]string, len(chain))
+	for i, wc := range chain {
+		exprs[i] = wc.expr
+		produced[i] = wc.name
+	}
+
+	baseCols, ok := base.columns()
+	if !ok {
+		return rebuild(base, exprs)
+	}
+
+	// Count every shareable subexpression that only reads columns of the base
+	counts := make(map[string]int)
+	var order []string
+	subexprs := make(map[string]expr.Expr)
+	for _, e := range exprs {
+		expr.Walk(e, func(node expr.Expr) bool {
+			if len(node.Children()) == 0 || intersects(node.Columns(), produced) {
+				return true
+			}
+			key, ok := exprKey(node)
+			if !ok {
+				return true
+			}
+			if counts[key] == 0 {
+				order = append(order, key)
+				subexprs[key] = node
+			}
+			counts[key]++
+			return true
+		})
+	}
+
+	common := make(map[string]string)
+	input := base
+	for _, key := range order {
+		if counts[key] < 2 {
+			continue
+		}
+		name := fmt.Sprintf("%s%d", cseColumnPrefix, len(common))
+		for containsString(baseCols, name) {
+			name += "_"
+		}
+		common[key] = name
+		input = &withColumnNode{input: input, name: name, expr: subexprs[key]}
+	}
+	if len(common) == 0 {
+		return rebuild(base, exprs)
+	}
+
+	for i, e := range exprs {
+		exprs[i] = replaceSubexpressions(e, common)
+	}
+
+	// Drop the temporary columns again
+	outputCols := baseCols
+	for _, name := range produced {
+		outputCols = appendMissing(outputCols, name)
+	}
+	return &projectNode{input: rebuild(input, exprs), selected: outputCols}
+}
+
+// replaceSubexpressions replaces the outermost occurrences of shared
+// subexpressions with references to their temporary columns.
+func replaceSubexpressions(e expr.Expr, common map[string]string) expr.Expr {
+	if len(e.Children()) > 0 {
+		if key, ok := exprKey(e); ok {
+			if name, found := common[key]; found {
+				return expr.Col(name)
+			}
+		}
+	}
+
+	children := e.Children()
+	if len(children) == 0 {
+		return e
+	}
+	newChildren := make([]expr.Expr, len(children))
+	changed := false
+	for i, child := range children {
+		newChildren[i] = replaceSubexpressions(child, common)
+		changed = changed || newChildren[i] != child
+	}
+	if !changed {
+		return e
+	}
+	rebuilt, err := e.WithChildren(newChildren)
+	if err != nil {
+		return e
+	}
+	return rebuilt
+}
+
+// exprKey returns a structural key identifying an expression, or false when
+// the expression contains nodes whose behavior cannot be compared (such as
+// user-defined functions).
+func exprKey(e expr.Expr) (string, bool) {
+	switch v := e.(type) {
+	case *expr.ColumnExpr:
+		return "col:" + v.Name(), true
+	case *expr.LiteralExpr:
+		return fmt.Sprintf("lit:%T:%v", v.Value(), v.Value()), true
+	case *constantFoldedExpr:
+		return fmt.Sprintf("lit:%T:%v", v.value, v.value), true
+	case *expr.CastExpr:
+		key, ok := exprKey(v.Children()[0])
+		return fmt.Sprintf("%s<%s>(%s)", v.Op(), v.DataType(), key), ok
+	case *expr.BinaryExpr, *expr.UnaryExpr, *expr.TernaryExpr, *expr.CoalesceExpr, *expr.CaseExpr:
+		children := e.Children()
+		keys := make([]string, len(children))
+		for i, child := range children {
+			key, ok := exprKey(child)
+			if !ok {
+				return "", false
+			}
+			keys[i] = key
+		}
+		return fmt.Sprintf("%T:%s(%s)", e, e.Op(), strings.Join(keys, ",")), true
+	default:
+		return "", false
+	}
+}
+
+// ====================
+// Predicate Pushdown
+// ====================
+
+func pushDownPredicates(node planNode) planNode {
+	if children := node.inputs(); len(children) > 0 {
+		newChildren := make([]planNode, len(children))
+		for i, child := range children {
+			newChildren[i] = pushDownPredicates(child)
+		}
+		node = node.withInputs(newChildren)
+	}
+
+	filter, ok := node.(*filterNode)
+	if !ok {
+		return node
+	}
+	return pushFilter(filter.predicate, filter.input)
+}
+
+// pushFilter places a filter as close to the scans as the plan allows and
+// returns the rewritten plan.
+func pushFilter(predicate expr.Expr, node planNode) planNode {
+	columns := predicate.Columns()
+	pushThrough := func(input planNode) planNode {
+		return node.withInputs([]planNode{pushFilter(predicate, input)})
+	}
+
+	switch n := node.(type) {
+	case *filterNode, *projectNode, *sortNode:
+		// Row filters commute with other filters, projections and sorts
+		return pushThrough(n.inputs()[0])
+	case *withColumnNode:
+		if !expr.ReferencesColumn(predicate, n.name) {
+			return pushThrough(n.input)
+		}
+	case *mapNode:
+		if !intersects(columns, n.modifies) {
+			return pushThrough(n.input)
+		}
+	case *aggregateNode:
+		// Filtering on group keys removes whole groups either way
+		if isSubset(columns, n.groupBy) {
+			return pushThrough(n.input)
+		}
+	case *windowNode:
+		// Filtering on partition keys removes whole partitions either way
+		if isSubset(columns, n.options.PartitionBy) {
+			return pushThrough(n.input)
+		}
+	case *joinNode:
+		return pushFilterIntoJoin(predicate, n)
+	case *parquetScanNode:
+		// The scan skips row groups using statistics and filters the rest
+		pushed := *n
+		if n.predicate != nil {
+			pushed.predicate = n.predicate.And(predicate)
+		} else {
+			pushed.predicate = predicate
+		}
+		return &pushed
+	}
+	return &filterNode{input: node, predicate: predicate}
+}
+
+// pushFilterIntoJoin pushes each conjunct of a predicate into the join input
+// it reads from, when the join type preserves its meaning there: filters on
+// the left input are pushed for inner and left joins, filters on the right
+// input for inner and right joins. Other conjuncts stay above the join.
+func pushFilterIntoJoin(predicate expr.Expr, join *joinNode) planNode {
+	lineage, ok := join.lineage()
+	if !ok {
+		return &filterNode{input: join, predicate: predicate}
+	}
+	sources := make(map[string]joinColumn, len(lineage))
+	for _, col := range lineage {
+		sources[col.output] = col
+	}
+
+	canPushLeft := join.joinType == core.InnerJoin || join.joinType == core.LeftJoin
+	canPushRight := join.joinType == core.InnerJoin || join.joinType == core.RightJoin
+
+	left, right := join.left, join.right
+	var remaining []expr.Expr
+	for _, conjunct := range splitConjunction(predicate) {
+		side, renames, ok := conjunctSide(conjunct, sources)
+		switch {
+		case ok && !side && canPushLeft:
+			left = pushFilter(conjunct, left)
+		case ok && side && canPushRight:
+			rewritten, err := renameColumns(conjunct, renames)
+			if err != nil {
+				remaining = append(remaining, conjunct)
+				continue
+			}
+			right = pushFilter(rewritten, right)
+		default:
+			remaining = append(remaining, conjunct)
+		}
+	}
+
+	var result planNode = join.withInputs([]planNode{left, right})
+	if len(remaining) > 0 {
+		result = &filterNode{input: result, predicate: combineConjunction(remaining)}
+	}
+	return result
+}
+
+// conjunctSide reports which join input all columns of e come from
+// (false for left, true for right), and how to rename them in that input.
+func conjunctSide(e expr.Expr, sources map[string]joinColumn) (bool, map[string]string, bool) {
+	columns := e.Columns()
+	if len(columns) == 0 {
+		return false, nil, false
+	}
+
+	renames := make(map[string]string, len(columns))
+	var right bool
+	for i, col := range columns {
+		source, found := sources[col]
+		if !found || (i > 0 && source.right != right) {
+			return false, nil, false
+		}
+		right = source.right
+		renames[col] = source.source
+	}
+	return right, renames, true
+}
+
+// renameColumns rewrites column references according to renames.
+func renameColumns(e expr.Expr, renames map[string]string) (expr.Expr, error) {
+	return expr.Transform(e, func(node expr.Expr) (expr.Expr, error) {
+		if node.Op() == expr.OpColumn {
+			if name, ok := renames[node.Name()]; ok && name != node.Name() {
+				return expr.Col(name), nil
+			}
+		}
+		return node, nil
+	})
+}
+
+// splitConjunction splits a predicate on its top-level AND operators.
+func splitConjunction(e expr.Expr) []expr.Expr {
+	if e.Op() != "and" {
+		return []expr.Expr{e}
+	}
+	var conjuncts []expr.Expr
+	for _, child := range e.Children() {
+		conjuncts = append(conjuncts, splitConjunction(child)...)
+	}
+	return conjuncts
+}
+
+// combineConjunction joins predicates with AND.
+func combineConjunction(conjuncts []expr.Expr) expr.Expr {
+	result := conjuncts[0]
+	for _, conjunct := range conjuncts[1:] {
+		result = result.And(conjunct)
+	}
+	return result
+}
+
+// ====================
+// Projection Pruning
+// ====================
+
+// pruneColumns removes columns and computed columns that no later operation
+// needs. required lists the columns the parent reads; nil means all of them.
+func pruneColumns(node planNode, required []string) planNode {
+	switch n := node.(type) {
+	case *scanNode:
+		if required == nil {
+			return n
+		}
+		all := n.df.ColumnNames()
+		projection := make([]string, 0, len(required))
+		for _, col := range all {
+			if containsString(required, col) {
+				projection = append(projection, col)
+			}
+		}
+		if len(projection) == len(all) {
+			return n
+		}
+		return &scanNode{df: n.df, projection: projection}
+
+	case *parquetScanNode:
+		if required == nil {
+			return n
+		}
+		all, _ := n.columns()
+		projection := make([]string, 0, len(required))
+		for _, col := range all {
+			if containsString(required, col) {
+				projection = append(projection, col)
+			}
+		}
+		if len(projection) == len(all) {
+			return n
+		}
+		// The scan reads the columns of its own predicate itself
+		pruned := *n
+		pruned.projection = projection
+		return &pruned
+
+	case *filterNode:
+		return n.withInputs([]planNode{pruneColumns(n.input, union(required, n.predicate.Columns()))})
+
+	case *projectNode:
+		selected := n.selected
+		if required != nil {
+			selected = make([]string, 0, len(n.selected))
+			for _, col := range n.selected {
+				if containsString(required, col) {
+					selected = append(selected, col)
+				}
+			}
+		}
+		return &projectNode{input: pruneColumns(n.input, selected), selected: selected}
+
+	case *withColumnNode:
+		if required != nil && !containsString(required, n.name) {
+			// Nothing reads the computed column
+			return pruneColumns(n.input, required)
+		}
+		var inputRequired []string
+		if required != nil {
+			inputRequired = union(remove(required, n.name), n.expr.Columns())
+		}
+		return n.withInputs([]planNode{pruneColumns(n.input, inputRequired)})
+
+	case *sortNode:
+		keys := make([]string, len(n.keys))
+		for i, key := range n.keys {
+			keys[i] = key.Column
+		}
+		return n.withInputs([]planNode{pruneColumns(n.input, union(required, keys))})
+
+	case *limitNode:
+		return n.withInputs([]planNode{pruneColumns(n.input, required)})
+
+	case *mapNode:
+		if n.readsAll {
+			required = nil
+		}
+		return n.withInputs([]planNode{pruneColumns(n.input, union(required, n.reads))})
+
+	case *aggregateNode:
+		inputRequired := append([]string(nil), n.groupBy...)
+		for _, agg := range n.aggregations {
+			inputRequired = union(inputRequired, agg.columns())
+		}
+		return n.withInputs([]planNode{pruneColumns(n.input, inputRequired)})
+
+	case *joinNode:
+		return pruneJoinColumns(n, required)
+
+	case *pivotNode:
+		inputRequired := union(n.indexCols, []string{n.pivotCol, n.valueCol})
+		return n.withInputs([]planNode{pruneColumns(n.input, inputRequired)})
+
+	case *unpivotNode:
+		return n.withInputs([]planNode{pruneColumns(n.input, union(n.idCols, n.valueCols))})
+
+	default:
+		// Window functions do not expose the columns they read
+		children := node.inputs()
+		newChildren := make([]planNode, len(children))
+		for i, child := range children {
+			newChildren[i] = pruneColumns(child, nil)
+		}
+		return node.withInputs(newChildren)
+	}
+}
+
+// pruneJoinColumns splits the columns required from a join between its inputs.
+// A left column whose name clashes with a required right column is kept so the
+// right column keeps its "right_" prefix.
+func pruneJoinColumns(join *joinNode, required []string) planNode {
+	lineage, ok := join.lineage()
+	if required == nil || !ok {
+		return join.withInputs([]planNode{pruneColumns(join.left, nil), pruneColumns(join.right, nil)})
+	}
+
+	leftRequired := append([]string(nil), join.leftKeys...)
+	rightRequired := append([]string(nil), join.rightKeys...)
+	for _, col := range lineage {
+		if !containsString(required, col.output) {
+			continue
+		}
+		if col.right {
+			rightRequired = union(rightRequired, []string{col.source})
+			if col.output != col.source {
+				leftRequired = union(leftRequired, []string{col.source})
+			}
+		} else {
+			leftRequired = union(leftRequired, []string{col.source})
+		}
+	}
+	return join.withInputs([]planNode{
+		pruneColumns(join.left, leftRequired),
+		pruneColumns(join.right, rightRequired),
+	})
+}
+
+// union returns a followed by the elements of b not in a. A nil a means
+// "all columns" and stays nil.
+func union(a, b []string) []string {
+	if a == nil {
+		return nil
+	}
+	result := append([]string(nil), a...)
+	for _, s := range b {
+		if !containsString(result, s) {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// remove returns values without s.
+func remove(values []string, s string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != s {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// intersects reports whether a and b share an element.
+func intersects(a, b []string) bool {
+	for _, s := range a {
+		if containsString(b, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSubset reports whether every element of a is in b.
+func isSubset(a, b []string) bool {
+	for _, s := range a {
+		if !containsString(b, s) {
+			return false
+		}
+	}
+	return true
+}
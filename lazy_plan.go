@@ -0,0 +1,466 @@
+package gopherframe
+
+import (
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/felixgeelhaar/GopherFrame/pkg/core"
+	"github.com/felixgeelhaar/GopherFrame/pkg/expr"
+	"github.com/felixgeelhaar/GopherFrame/pkg/storage"
+)
+
+// planNode is a node of a LazyFrame's logical plan. Nodes are immutable;
+// optimizer passes build new nodes instead of modifying existing ones.
+type planNode interface {
+	// inputs returns the nodes this node reads from.
+	inputs() []planNode
+
+	// withInputs returns a copy of the node reading from new inputs.
+	withInputs(inputs []planNode) planNode
+
+	// columns returns the output column names, or false when they are only
+	// known once the data has been read (for example, after a Pivot).
+	columns() ([]string, bool)
+
+	// execute runs the operation over its materialized inputs.
+	execute(inputs []*DataFrame) *DataFrame
+
+	// describe returns a one-line description of the operation for Explain.
+	describe() string
+}
+
+// scanNode reads an in-memory DataFrame, optionally keeping only some columns.
+type scanNode struct {
+	df         *DataFrame
+	projection []string // nil reads every column
+}
+
+func (n *scanNode) inputs() []planNode                    { return nil }
+func (n *scanNode) withInputs(inputs []planNode) planNode { return n }
+
+func (n *scanNode) columns() ([]string, bool) {
+	if n.projection != nil {
+		return n.projection, true
+	}
+	return n.df.ColumnNames(), true
+}
+
+func (n *scanNode) execute(inputs []*DataFrame) *DataFrame {
+	if n.df.err != nil {
+		return &DataFrame{err: n.df.err}
+	}
+	if n.projection != nil {
+		return n.df.Select(n.projection...)
+	}
+	// Wrap the record so the result can be released independently of the source
+	return NewDataFrame(n.df.Record())
+}
+
+// parquetScanNode reads a Parquet file. The optimizer pushes projections and
+// filters into it, so unused columns and row groups are never read.
+type parquetScanNode struct {
+	filename   string
+	schema     *arrow.Schema
+	projection []string  // nil reads every column
+	predicate  expr.Expr // nil reads every row
+}
+
+func (n *parquetScanNode) inputs() []planNode                    { return nil }
+func (n *parquetScanNode) withInputs(inputs []planNode) planNode { return n }
+
+func (n *parquetScanNode) columns() ([]string, bool) {
+	if n.projection != nil {
+		return n.projection, true
+	}
+	names := make([]string, n.schema.NumFields())
+	for i, field := range n.schema.Fields() {
+		names[i] = field.Name
+	}
+	return names, true
+}
+
+func (n *parquetScanNode) execute(inputs []*DataFrame) *DataFrame {
+	opts := storage.ReadOptions{Columns: n.projection}
+	if n.predicate != nil {
+		opts.Filter = n.predicate
+	}
+	df, err := ReadParquetWithOptions(n.filename, opts)
+	if err != nil {
+		return &DataFrame{err: err}
+	}
+	return df
+}
+
+// filterNode keeps the rows matching a predicate.
+type filterNode struct {
+	input     planNode
+	predicate expr.Expr
+}
+
+func (n *filterNode) inputs() []planNode { return []planNode{n.input} }
+func (n *filterNode) withInputs(inputs []planNode) planNode {
+	return &filterNode{input: inputs[0], predicate: n.predicate}
+}
+func (n *filterNode) columns() ([]string, bool) { return n.input.columns() }
+func (n *filterNode) execute(inputs []*DataFrame) *DataFrame {
+	return inputs[0].Filter(n.predicate)
+}
+
+// projectNode keeps the listed columns, in order.
+type projectNode struct {
+	input    planNode
+	selected []string
+}
+
+func (n *projectNode) inputs() []planNode { return []planNode{n.input} }
+func (n *projectNode) withInputs(inputs []planNode) planNode {
+	return &projectNode{input: inputs[0], selected: n.selected}
+}
+func (n *projectNode) columns() ([]string, bool) { return n.selected, true }
+func (n *projectNode) execute(inputs []*DataFrame) *DataFrame {
+	return inputs[0].Select(n.selected...)
+}
+
+// withColumnNode adds or replaces a computed column.
+type withColumnNode struct {
+	input planNode
+	name  string
+	expr  expr.Expr
+}
+
+func (n *withColumnNode) inputs() []planNode { return []planNode{n.input} }
+func (n *withColumnNode) withInputs(inputs []planNode) planNode {
+	return &withColumnNode{input: inputs[0], name: n.name, expr: n.expr}
+}
+func (n *withColumnNode) columns() ([]string, bool) {
+	cols, ok := n.input.columns()
+	if !ok {
+		return nil, false
+	}
+	return appendMissing(cols, n.name), true
+}
+func (n *withColumnNode) execute(inputs []*DataFrame) *DataFrame {
+	return inputs[0].WithColumn(n.name, n.expr)
+}
+
+// sortNode orders rows by one or more keys.
+type sortNode struct {
+	input planNode
+	keys  []SortKey
+}
+
+func (n *sortNode) inputs() []planNode { return []planNode{n.input} }
+func (n *sortNode) withInputs(inputs []planNode) planNode {
+	return &sortNode{input: inputs[0], keys: n.keys}
+}
+func (n *sortNode) columns() ([]string, bool) { return n.input.columns() }
+func (n *sortNode) execute(inputs []*DataFrame) *DataFrame {
+	return inputs[0].SortMultiple(n.keys)
+}
+
+// limitNode keeps the first n rows.
+type limitNode struct {
+	input planNode
+	n     int64
+}
+
+func (n *limitNode) inputs() []planNode { return []planNode{n.input} }
+func (n *limitNode) withInputs(inputs []planNode) planNode {
+	return &limitNode{input: inputs[0], n: n.n}
+}
+func (n *limitNode) columns() ([]string, bool) { return n.input.columns() }
+func (n *limitNode) execute(inputs []*DataFrame) *DataFrame {
+	return inputs[0].Limit(n.n)
+}
+
+// aggregateNode groups rows and computes aggregations per group.
+type aggregateNode struct {
+	input        planNode
+	groupBy      []string
+	aggregations []Aggregation
+}
+
+func (n *aggregateNode) inputs() []planNode { return []planNode{n.input} }
+func (n *aggregateNode) withInputs(inputs []planNode) planNode {
+	return &aggregateNode{input: inputs[0], groupBy: n.groupBy, aggregations: n.aggregations}
+}
+func (n *aggregateNode) columns() ([]string, bool) {
+	cols := append([]string(nil), n.groupBy...)
+	for _, agg := range n.aggregations {
+		cols = append(cols, agg.Name())
+	}
+	return cols, true
+}
+func (n *aggregateNode) execute(inputs []*DataFrame) *DataFrame {
+	return inputs[0].GroupBy(n.groupBy...).Agg(n.aggregations...)
+}
+
+// joinNode combines two inputs on equal key columns.
+type joinNode struct {
+	left      planNode
+	right     planNode
+	joinType  core.JoinType
+	leftKeys  []string
+	rightKeys []string
+}
+
+func (n *joinNode) inputs() []planNode { return []planNode{n.left, n.right} }
+func (n *joinNode) withInputs(inputs []planNode) planNode {
+	return &joinNode{left: inputs[0], right: inputs[1], joinType: n.joinType, leftKeys: n.leftKeys, rightKeys: n.rightKeys}
+}
+
+func (n *joinNode) columns() ([]string, bool) {
+	lineage, ok := n.lineage()
+	if !ok {
+		return nil, false
+	}
+	cols := make([]string, len(lineage))
+	for i, source := range lineage {
+		cols[i] = source.output
+	}
+	return cols, true
+}
+
+func (n *joinNode) execute(inputs []*DataFrame) *DataFrame {
+	left, right := inputs[0], inputs[1]
+	if len(n.leftKeys) == 1 && len(n.rightKeys) == 1 {
+		switch n.joinType {
+		case core.LeftJoin:
+			return left.LeftJoin(right, n.leftKeys[0], n.rightKeys[0])
+		case core.RightJoin:
+			return left.RightJoin(right, n.leftKeys[0], n.rightKeys[0])
+		case core.FullOuterJoin:
+			return left.FullOuterJoin(right, n.leftKeys[0], n.rightKeys[0])
+		default:
+			return left.InnerJoin(right, n.leftKeys[0], n.rightKeys[0])
+		}
+	}
+
+	switch n.joinType {
+	case core.LeftJoin:
+		return left.LeftJoinMulti(right, n.leftKeys, n.rightKeys)
+	case core.RightJoin:
+		return left.RightJoinMulti(right, n.leftKeys, n.rightKeys)
+	case core.FullOuterJoin:
+		return left.FullOuterJoinMulti(right, n.leftKeys, n.rightKeys)
+	default:
+		return left.InnerJoinMulti(right, n.leftKeys, n.rightKeys)
+	}
+}
+
+// joinColumn records where a join output column comes from.
+type joinColumn struct {
+	output string
+	source string
+	right  bool
+}
+
+// lineage maps each output column of the join to its input column, following
+// the naming rules of the join kernels: all left columns, then right columns
+// except the right keys, prefixed with "right_" when the name is taken.
+func (n *joinNode) lineage() ([]joinColumn, bool) {
+	leftCols, ok := n.left.columns()
+	if !ok {
+		return nil, false
+	}
+	rightCols, ok := n.right.columns()
+	if !ok {
+		return nil, false
+	}
+
+	taken := make(map[string]bool, len(leftCols)+len(rightCols))
+	lineage := make([]joinColumn, 0, len(leftCols)+len(rightCols))
+	for _, col := range leftCols {
+		lineage = append(lineage, joinColumn{output: col, source: col})
+		taken[col] = true
+	}
+	for _, col := range rightCols {
+		if containsString(n.rightKeys, col) {
+			continue
+		}
+		name := col
+		if taken[name] {
+			name = "right_" + name
+		}
+		lineage = append(lineage, joinColumn{output: name, source: col, right: true})
+		taken[name] = true
+	}
+	return lineage, true
+}
+
+// pivotNode turns the distinct values of a column into new columns.
+type pivotNode struct {
+	input     planNode
+	indexCols []string
+	pivotCol  string
+	valueCol  string
+}
+
+func (n *pivotNode) inputs() []planNode { return []planNode{n.input} }
+func (n *pivotNode) withInputs(inputs []planNode) planNode {
+	return &pivotNode{input: inputs[0], indexCols: n.indexCols, pivotCol: n.pivotCol, valueCol: n.valueCol}
+}
+func (n *pivotNode) columns() ([]string, bool) { return nil, false }
+func (n *pivotNode) execute(inputs []*DataFrame) *DataFrame {
+	return inputs[0].Pivot(n.indexCols, n.pivotCol, n.valueCol)
+}
+
+// unpivotNode turns value columns into variable/value rows.
+type unpivotNode struct {
+	input        planNode
+	idCols       []string
+	valueCols    []string
+	variableName string
+	valueName    string
+}
+
+func (n *unpivotNode) inputs() []planNode { return []planNode{n.input} }
+func (n *unpivotNode) withInputs(inputs []planNode) planNode {
+	return &unpivotNode{input: inputs[0], idCols: n.idCols, valueCols: n.valueCols, variableName: n.variableName, valueName: n.valueName}
+}
+func (n *unpivotNode) columns() ([]string, bool) {
+	cols := append([]string(nil), n.idCols...)
+	return append(cols, n.variableName, n.valueName), true
+}
+func (n *unpivotNode) execute(inputs []*DataFrame) *DataFrame {
+	return inputs[0].Unpivot(n.idCols, n.valueCols, n.variableName, n.valueName)
+}
+
+// windowNode appends window function results as new columns.
+type windowNode struct {
+	input   planNode
+	options WindowOptions
+	funcs   []core.WindowFunc
+}
+
+func (n *windowNode) inputs() []planNode { return []planNode{n.input} }
+func (n *windowNode) withInputs(inputs []planNode) planNode {
+	return &windowNode{input: inputs[0], options: n.options, funcs: n.funcs}
+}
+func (n *windowNode) columns() ([]string, bool) {
+	cols, ok := n.input.columns()
+	if !ok {
+		return nil, false
+	}
+	cols = append([]string(nil), cols...)
+	for _, fn := range n.funcs {
+		cols = append(cols, fn.Name())
+	}
+	return cols, true
+}
+
+func (n *windowNode) execute(inputs []*DataFrame) *DataFrame {
+	input := inputs[0]
+	if input.err != nil {
+		return &DataFrame{err: input.err}
+	}
+
+	spec := input.coreDF.Window()
+	if len(n.options.PartitionBy) > 0 {
+		spec = spec.PartitionBy(n.options.PartitionBy...)
+	}
+	if len(n.options.OrderBy) > 0 {
+		if n.options.Descending {
+			spec = spec.OrderByDesc(n.options.OrderBy...)
+		} else {
+			spec = spec.OrderBy(n.options.OrderBy...)
+		}
+	}
+	if n.options.Rows > 0 {
+		spec = spec.Rows(n.options.Rows)
+	}
+
+	result, err := spec.Over(n.funcs...)
+	if err != nil {
+		return &DataFrame{err: err}
+	}
+	return &DataFrame{coreDF: result}
+}
+
+// mapNode applies a row-wise DataFrame operation that keeps the column set,
+// such as FillNull or DropNulls.
+type mapNode struct {
+	input    planNode
+	name     string
+	reads    []string // columns the operation reads; nil with readsAll
+	readsAll bool
+	modifies []string // columns whose values may change
+	apply    func(df *DataFrame) *DataFrame
+}
+
+func (n *mapNode) inputs() []planNode { return []planNode{n.input} }
+func (n *mapNode) withInputs(inputs []planNode) planNode {
+	copied := *n
+	copied.input = inputs[0]
+	return &copied
+}
+func (n *mapNode) columns() ([]string, bool) { return n.input.columns() }
+func (n *mapNode) execute(inputs []*DataFrame) *DataFrame {
+	return n.apply(inputs[0])
+}
+
+// executePlan materializes a plan bottom-up. Intermediate results are
+// released as soon as the node that consumes them has run.
+func executePlan(node planNode) *DataFrame {
+	return executePlanObserved(node, nil)
+}
+
+// executePlanObserved is executePlan with a callback run around each node,
+// used by ExplainAnalyze to collect statistics. observe receives the node, its
+// inputs and a function executing the node over inputs, and must return that
+// function's result. It may pass other DataFrames holding the same data as
+// the inputs, which it then owns.
+func executePlanObserved(node planNode, observe func(node planNode, inputs []*DataFrame, run func([]*DataFrame) *DataFrame) *DataFrame) *DataFrame {
+	children := node.inputs()
+	inputs := make([]*DataFrame, len(children))
+	defer func() {
+		for _, input := range inputs {
+			if input != nil {
+				input.Release()
+			}
+		}
+	}()
+
+	for i, child := range children {
+		inputs[i] = executePlanObserved(child, observe)
+		if err := inputs[i].Err(); err != nil {
+			return &DataFrame{err: err}
+		}
+	}
+
+	var result *DataFrame
+	if observe != nil {
+		result = observe(node, inputs, node.execute)
+	} else {
+		result = node.execute(inputs)
+	}
+	if result == nil {
+		return &DataFrame{err: fmt.Errorf("plan node produced no result")}
+	}
+	// Some operations return their input unchanged; hand ownership to the caller
+	for i, input := range inputs {
+		if input == result {
+			inputs[i] = nil
+		}
+	}
+	return result
+}
+
+// appendMissing returns cols with name appended unless it is already present.
+func appendMissing(cols []string, name string) []string {
+	if containsString(cols, name) {
+		return cols
+	}
+	result := make([]string, len(cols), len(cols)+1)
+	copy(result, cols)
+	return append(result, name)
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,321 @@
+package gopherframe
+
+import (
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/felixgeelhaar/GopherFrame/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createLazyOrdersDataFrames(pool memory.Allocator) (*DataFrame, *DataFrame) {
+	orders := createTestDataFrame(pool, map[string]interface{}{
+		"order_id":    []int64{1, 2, 3, 4, 5, 6},
+		"customer_id": []int64{10, 20, 10, 30, 20, 10},
+		"amount":      []float64{50, 150, 200, 75, 300, 25},
+		"fee":         []float64{5, 10, 10, 5, 20, 5},
+	})
+	customers := createTestDataFrame(pool, map[string]interface{}{
+		"id":     []int64{10, 20, 30},
+		"region": []string{"north", "south", "north"},
+		"amount": []float64{1, 2, 3}, // clashes with orders.amount
+	})
+	return orders, customers
+}
+
+// findPlanNodes returns all nodes of type T in a plan, top-down.
+func findPlanNodes[T planNode](plan planNode) []T {
+	var found []T
+	if n, ok := plan.(T); ok {
+		found = append(found, n)
+	}
+	for _, child := range plan.inputs() {
+		found = append(found, findPlanNodes[T](child)...)
+	}
+	return found
+}
+
+func TestLazyFrame_MatchesEagerPipeline(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	orders, customers := createLazyOrdersDataFrames(pool)
+	defer orders.Release()
+	defer customers.Release()
+
+	eager := orders.
+		WithColumn("net", Col("amount").Sub(Col("fee"))).
+		Filter(Col("amount").Gt(Lit(60.0))).
+		InnerJoin(customers, "customer_id", "id").
+		GroupBy("region").
+		Agg(Sum("net")).
+		Sort("net_sum", false)
+	require.NoError(t, eager.Err())
+	defer eager.Release()
+
+	lazy := orders.Lazy().
+		WithColumn("net", Col("amount").Sub(Col("fee"))).
+		Filter(Col("amount").Gt(Lit(60.0))).
+		InnerJoin(customers.Lazy(), "customer_id", "id").
+		GroupBy("region").
+		Agg(Sum("net")).
+		Sort("net_sum", false).
+		Collect()
+	require.NoError(t, lazy.Err())
+	defer lazy.Release()
+
+	assert.Equal(t, eager.ColumnNames(), lazy.ColumnNames())
+	assert.Equal(t, eager.NumRows(), lazy.NumRows())
+	for i := 0; i < int(eager.NumRows()); i++ {
+		assert.Equal(t, eager.Record().Column(0).ValueStr(i), lazy.Record().Column(0).ValueStr(i))
+	}
+	assert.Equal(t, eager.Record().Column(1).(*array.Float64).Float64Values(), lazy.Record().Column(1).(*array.Float64).Float64Values())
+}
+
+func TestLazyFrame_DoesNotExecuteUntilCollect(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	orders, _ := createLazyOrdersDataFrames(pool)
+	defer orders.Release()
+
+	// Referencing a missing column only fails at Collect
+	lf := orders.Lazy().Filter(Col("missing").Gt(Lit(1.0)))
+	require.NotNil(t, lf)
+
+	result := lf.Collect()
+	require.Error(t, result.Err())
+	assert.Contains(t, result.Err().Error(), "missing")
+}
+
+func TestLazyFrame_PredicatePushdownThroughJoin(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	orders, customers := createLazyOrdersDataFrames(pool)
+	defer orders.Release()
+	defer customers.Release()
+
+	lf := orders.Lazy().
+		InnerJoin(customers.Lazy(), "customer_id", "id").
+		Filter(Col("amount").Gt(Lit(60.0)).
+			And(Col("region").Eq(Lit("north"))).
+			And(Col("right_amount").Lt(Lit(10.0))))
+
+	plan := optimizePlan(lf.plan)
+	join, ok := plan.(*joinNode)
+	require.True(t, ok, "all conjuncts should be pushed below the join")
+
+	leftFilters := findPlanNodes[*filterNode](join.left)
+	require.Len(t, leftFilters, 1)
+	assert.Equal(t, []string{"amount"}, leftFilters[0].predicate.Columns())
+
+	// right_amount is renamed back to the right input's column name
+	rightFilters := findPlanNodes[*filterNode](join.right)
+	require.Len(t, rightFilters, 2)
+	var rightColumns []string
+	for _, f := range rightFilters {
+		rightColumns = append(rightColumns, f.predicate.Columns()...)
+	}
+	assert.ElementsMatch(t, []string{"region", "amount"}, rightColumns)
+
+	result := lf.Collect()
+	require.NoError(t, result.Err())
+	defer result.Release()
+
+	// Orders 3 (200, customer 10) qualifies; order 4 (75, customer 30) too
+	assert.Equal(t, int64(2), result.NumRows())
+}
+
+func TestLazyFrame_PushdownRespectsOuterJoins(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	orders, customers := createLazyOrdersDataFrames(pool)
+	defer orders.Release()
+	defer customers.Release()
+
+	lf := orders.Lazy().
+		LeftJoin(customers.Lazy(), "customer_id", "id").
+		Filter(Col("region").Eq(Lit("north")).And(Col("fee").Gt(Lit(5.0))))
+
+	plan := optimizePlan(lf.plan)
+	top, ok := plan.(*filterNode)
+	require.True(t, ok, "filter on the right side of a left join must stay above it")
+	assert.Equal(t, []string{"region"}, top.predicate.Columns())
+
+	join := findPlanNodes[*joinNode](plan)[0]
+	assert.Len(t, findPlanNodes[*filterNode](join.left), 1, "left-side filter is pushed")
+	assert.Empty(t, findPlanNodes[*filterNode](join.right))
+}
+
+func TestLazyFrame_ProjectionPruning(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	orders, _ := createLazyOrdersDataFrames(pool)
+	defer orders.Release()
+
+	lf := orders.Lazy().
+		WithColumn("unused", Col("fee").Mul(Lit(2.0))).
+		WithColumn("net", Col("amount").Sub(Col("fee"))).
+		Filter(Col("customer_id").Eq(Lit(int64(10)))).
+		Select("net")
+
+	plan := optimizePlan(lf.plan)
+
+	computed := findPlanNodes[*withColumnNode](plan)
+	require.Len(t, computed, 1, "unused computed column is not evaluated")
+	assert.Equal(t, "net", computed[0].name)
+
+	scans := findPlanNodes[*scanNode](plan)
+	require.Len(t, scans, 1)
+	assert.Equal(t, []string{"amount", "customer_id", "fee"}, scans[0].projection)
+
+	result := lf.Collect()
+	require.NoError(t, result.Err())
+	defer result.Release()
+
+	assert.Equal(t, []string{"net"}, result.ColumnNames())
+	assert.Equal(t, []float64{45, 190, 20}, result.Record().Column(0).(*array.Float64).Float64Values())
+}
+
+func TestLazyFrame_PruningKeepsExpressionAggregationInputs(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	orders, _ := createLazyOrdersDataFrames(pool)
+	defer orders.Release()
+
+	lf := orders.Lazy().
+		GroupBy("customer_id").
+		Agg(SumExpr(Col("amount").Mul(Lit(2.0))).Filter(Col("order_id").Gt(Lit(int64(1)))).As("doubled"))
+
+	scans := findPlanNodes[*scanNode](optimizePlan(lf.plan))
+	require.Len(t, scans, 1)
+	assert.Equal(t, []string{"amount", "customer_id", "order_id"}, scans[0].projection)
+	assert.Contains(t, lf.Explain(), "sum((Col(amount) multiply Lit(2))) FILTER (WHERE (Col(order_id) greater Lit(1))) AS doubled")
+
+	result := lf.Collect()
+	require.NoError(t, result.Err())
+	defer result.Release()
+	assert.Equal(t, []float64{450, 900, 150}, result.Record().Column(1).(*array.Float64).Float64Values())
+}
+
+func TestLazyFrame_PruningKeepsJoinColumnNames(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	orders, customers := createLazyOrdersDataFrames(pool)
+	defer orders.Release()
+	defer customers.Release()
+
+	// right_amount only exists because the left input also has "amount"
+	result := orders.Lazy().
+		InnerJoin(customers.Lazy(), "customer_id", "id").
+		Select("order_id", "right_amount").
+		Collect()
+	require.NoError(t, result.Err())
+	defer result.Release()
+
+	assert.Equal(t, []string{"order_id", "right_amount"}, result.ColumnNames())
+	assert.Equal(t, int64(6), result.NumRows())
+}
+
+func TestLazyFrame_CommonSubexpressionElimination(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	orders, _ := createLazyOrdersDataFrames(pool)
+	defer orders.Release()
+
+	net := Col("amount").Sub(Col("fee"))
+	lf := orders.Lazy().
+		WithColumn("net", net).
+		WithColumn("net_doubled", net.Mul(Lit(2.0))).
+		WithColumn("net_tax", Col("amount").Sub(Col("fee")).Mul(Lit(0.2)))
+
+	plan := optimizePlan(lf.plan)
+	evaluations := 0
+	for _, wc := range findPlanNodes[*withColumnNode](plan) {
+		if wc.expr.Op() == "subtract" {
+			evaluations++
+		}
+	}
+	assert.Equal(t, 1, evaluations, "amount - fee is evaluated once")
+
+	result := lf.Collect()
+	require.NoError(t, result.Err())
+	defer result.Release()
+
+	assert.Equal(t, []string{"amount", "customer_id", "fee", "order_id", "net", "net_doubled", "net_tax"}, result.ColumnNames())
+	record := result.Record()
+	assert.Equal(t, 45.0, record.Column(4).(*array.Float64).Value(0))
+	assert.Equal(t, 90.0, record.Column(5).(*array.Float64).Value(0))
+	assert.InDelta(t, 9.0, record.Column(6).(*array.Float64).Value(0), 1e-9)
+}
+
+func TestLazyFrame_PivotWindowAndLimit(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	orders, customers := createLazyOrdersDataFrames(pool)
+	defer orders.Release()
+	defer customers.Release()
+
+	ranked := orders.Lazy().
+		Window(WindowOptions{PartitionBy: []string{"customer_id"}, OrderBy: []string{"amount"}, Descending: true},
+			core.RowNumber().As("rank")).
+		Filter(Col("customer_id").Eq(Lit(int64(10)))).
+		Sort("rank", true).
+		Limit(2).
+		Collect()
+	require.NoError(t, ranked.Err())
+	defer ranked.Release()
+
+	assert.Equal(t, int64(2), ranked.NumRows())
+	amounts := ranked.Record().Column(0).(*array.Float64)
+	assert.Equal(t, []float64{200, 50}, amounts.Float64Values())
+
+	pivoted := orders.Lazy().
+		InnerJoin(customers.Lazy(), "customer_id", "id").
+		Pivot([]string{"customer_id"}, "region", "fee").
+		Collect()
+	require.NoError(t, pivoted.Err())
+	defer pivoted.Release()
+	assert.True(t, pivoted.HasColumn("north"))
+	assert.True(t, pivoted.HasColumn("south"))
+}
+
+func TestLazyFrame_CastFillAndDropNulls(t *testing.T) {
+	df := createNullableSampleDataFrame()
+	defer df.Release()
+
+	result := df.Lazy().
+		DropNulls("city").
+		FillNull(map[string]interface{}{"age": 0}).
+		Cast(map[string]arrow.DataType{"age": arrow.PrimitiveTypes.Float64}).
+		Collect()
+	require.NoError(t, result.Err())
+	defer result.Release()
+
+	assert.Equal(t, int64(2), result.NumRows())
+	assert.Equal(t, []float64{30, 0}, result.Record().Column(0).(*array.Float64).Float64Values())
+}
+
+func TestLazyFrame_Errors(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	orders, _ := createLazyOrdersDataFrames(pool)
+	defer orders.Release()
+
+	assert.Error(t, orders.Lazy().GroupBy().Agg(Sum("amount")).Collect().Err())
+	assert.Error(t, orders.Lazy().GroupBy("customer_id").Agg().Collect().Err())
+	assert.Error(t, orders.Lazy().InnerJoin(nil, "a", "b").Collect().Err())
+	assert.Error(t, orders.Lazy().Window(WindowOptions{}).Collect().Err())
+	assert.Error(t, orders.Lazy().Limit(-1).Collect().Err())
+
+	// Errors propagate through later operations
+	failed := orders.Lazy().GroupBy().Agg(Sum("amount")).Filter(Col("x").Gt(Lit(1.0))).Select("x")
+	assert.Error(t, failed.Collect().Err())
+}
+
+func TestDataFrame_Limit(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	orders, _ := createLazyOrdersDataFrames(pool)
+	defer orders.Release()
+
+	head := orders.Limit(4)
+	require.NoError(t, head.Err())
+	defer head.Release()
+	assert.Equal(t, int64(4), head.NumRows())
+
+	all := orders.Limit(100)
+	require.NoError(t, all.Err())
+	defer all.Release()
+	assert.Equal(t, int64(6), all.NumRows())
+}
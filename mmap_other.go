@@ -0,0 +1,15 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package gopherframe
+
+import "os"
+
+// mmapFile reads the whole file on platforms without mmap support, so that
+// callers work unchanged, at the cost of holding the file in memory.
+func mmapFile(filename string) ([]byte, func() error, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() error { return nil }, nil
+}
@@ -0,0 +1,340 @@
+package gopherframe
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// BadRecordPolicy decides what chunked and streaming NDJSON readers do with
+// lines that are not valid JSON objects or whose values do not fit the
+// column types.
+type BadRecordPolicy int
+
+const (
+	// BadRecordFail stops reading with an error giving the line number.
+	BadRecordFail BadRecordPolicy = iota
+	// BadRecordSkip drops bad lines and continues.
+	BadRecordSkip
+	// BadRecordCollect drops bad lines and keeps them, with their line
+	// number and error, in the reader's rejects DataFrame.
+	BadRecordCollect
+)
+
+// String returns the name of the policy.
+func (p BadRecordPolicy) String() string {
+	switch p {
+	case BadRecordFail:
+		return "fail"
+	case BadRecordSkip:
+		return "skip"
+	case BadRecordCollect:
+		return "collect"
+	default:
+		return fmt.Sprintf("BadRecordPolicy(%d)", int(p))
+	}
+}
+
+// NDJSONReadOptions configures the chunked and streaming NDJSON readers.
+type NDJSONReadOptions struct {
+	JSONReadOptions
+
+	// BadRecords is the policy for lines that cannot be read. The zero
+	// value fails on the first bad line.
+	BadRecords BadRecordPolicy
+}
+
+// rejectsSchema is the schema of the DataFrame of rejected NDJSON lines.
+var rejectsSchema = arrow.NewSchema([]arrow.Field{
+	{Name: "line_number", Type: arrow.PrimitiveTypes.Int64},
+	{Name: "line", Type: arrow.BinaryTypes.String},
+	{Name: "error", Type: arrow.BinaryTypes.String},
+}, nil)
+
+// ReadNDJSONChunked reads a newline-delimited JSON file in chunks of
+// chunkSize rows, failing on the first malformed line.
+// Returns an iterator that yields DataFrames one chunk at a time.
+func ReadNDJSONChunked(filename string, chunkSize int) (*DataFrameIterator, error) {
+	return ReadNDJSONChunkedWithOptions(filename, chunkSize, NDJSONReadOptions{})
+}
+
+// ReadNDJSONChunkedWithOptions is ReadNDJSONChunked with the objects mapped
+// to columns as described by opts. Unless opts.Schema is set, column types
+// are inferred once from the first chunk and enforced on later chunks; keys
+// that first appear in later chunks are ignored. Lines that are not valid
+// JSON objects, or whose values do not fit their column, are handled by
+// opts.BadRecords. Compressed files are decompressed as they are read.
+//
+// Example:
+//
+//	it, err := ReadNDJSONChunkedWithOptions("export.ndjson.gz", 100_000, NDJSONReadOptions{
+//	    BadRecords: BadRecordCollect,
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	defer it.Close()
+//	err = it.ForEachChunk(process)
+//	rejects := it.Rejects() // line_number, line, error
+func ReadNDJSONChunkedWithOptions(filename string, chunkSize int, opts NDJSONReadOptions) (*DataFrameIterator, error) {
+	if err := validateFilePath(filename); err != nil {
+		return nil, err
+	}
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("chunk size must be positive")
+	}
+	if opts.MaxDepth < 0 {
+		return nil, fmt.Errorf("max depth must not be negative, got %d", opts.MaxDepth)
+	}
+	if opts.BadRecords < BadRecordFail || opts.BadRecords > BadRecordCollect {
+		return nil, fmt.Errorf("unknown bad record policy: %s", opts.BadRecords)
+	}
+
+	source := &ndjsonChunkSource{filename: filename, chunkSize: chunkSize, opts: opts}
+	if err := source.open(); err != nil {
+		return nil, err
+	}
+	return &DataFrameIterator{source: source}, nil
+}
+
+// ndjsonReader reads the non-blank lines of newline-delimited JSON. Unlike
+// bufio.Scanner it has no limit on the line length.
+type ndjsonReader struct {
+	reader *bufio.Reader
+	line   int
+}
+
+func newNDJSONReader(r io.Reader) *ndjsonReader {
+	return &ndjsonReader{reader: bufio.NewReader(r)}
+}
+
+// next returns the next non-blank line without its line ending and its
+// 1-based line number. It returns io.EOF at the end of the data.
+func (r *ndjsonReader) next() ([]byte, int, error) {
+	for {
+		data, err := r.reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, 0, fmt.Errorf("error reading NDJSON data: %w", err)
+		}
+		if len(data) == 0 {
+			return nil, 0, io.EOF
+		}
+		r.line++
+		if len(bytes.TrimSpace(data)) == 0 {
+			continue
+		}
+		return bytes.TrimRight(data, "\r\n"), r.line, nil
+	}
+}
+
+// ndjsonRejects collects rejected lines. It is safe for concurrent use, so a
+// StreamingReader can report rejects while its producer is running.
+type ndjsonRejects struct {
+	mu      sync.Mutex
+	numbers []int64
+	lines   []string
+	errs    []string
+}
+
+func (r *ndjsonRejects) add(line string, number int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.numbers = append(r.numbers, int64(number))
+	r.lines = append(r.lines, line)
+	r.errs = append(r.errs, err.Error())
+}
+
+func (r *ndjsonRejects) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.numbers, r.lines, r.errs = nil, nil, nil
+}
+
+// dataFrame returns the rejected lines collected so far.
+func (r *ndjsonRejects) dataFrame() *DataFrame {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return newRejectsDataFrame(r.numbers, r.lines, r.errs)
+}
+
+// newRejectsDataFrame builds a DataFrame with the rejects schema.
+func newRejectsDataFrame(numbers []int64, lines, errs []string) *DataFrame {
+	pool := memory.NewGoAllocator()
+	numberBuilder := array.NewInt64Builder(pool)
+	defer numberBuilder.Release()
+	numberBuilder.AppendValues(numbers, nil)
+	lineBuilder := array.NewStringBuilder(pool)
+	defer lineBuilder.Release()
+	lineBuilder.AppendValues(lines, nil)
+	errBuilder := array.NewStringBuilder(pool)
+	defer errBuilder.Release()
+	errBuilder.AppendValues(errs, nil)
+
+	columns := []arrow.Array{numberBuilder.NewArray(), lineBuilder.NewArray(), errBuilder.NewArray()}
+	defer func() {
+		for _, col := range columns {
+			col.Release()
+		}
+	}()
+	record := array.NewRecord(rejectsSchema, columns, int64(len(numbers)))
+	defer record.Release()
+	return NewDataFrame(record)
+}
+
+// ndjsonChunkSource reads chunks of rows from an open NDJSON file.
+type ndjsonChunkSource struct {
+	filename  string
+	chunkSize int
+	opts      NDJSONReadOptions
+
+	file    io.ReadCloser
+	lines   *ndjsonReader
+	schema  *arrow.Schema
+	rejects ndjsonRejects
+}
+
+func (s *ndjsonChunkSource) open() error {
+	f, err := openCompressed(s.filename)
+	if err != nil {
+		return fmt.Errorf("failed to open NDJSON file: %w", err)
+	}
+	s.file, s.lines = f, newNDJSONReader(f)
+	return nil
+}
+
+// reject applies the bad record policy to a line that cannot be read. It
+// returns the error to stop with under BadRecordFail.
+func (s *ndjsonChunkSource) reject(line []byte, number int, err error) error {
+	switch s.opts.BadRecords {
+	case BadRecordSkip:
+		return nil
+	case BadRecordCollect:
+		s.rejects.add(string(line), number, err)
+		return nil
+	default:
+		return fmt.Errorf("failed to read NDJSON at line %d: %w", number, err)
+	}
+}
+
+// readLines parses up to chunkSize objects, applying the bad record policy
+// to lines that are not valid JSON objects.
+func (s *ndjsonChunkSource) readLines() (records []map[string]interface{}, lines [][]byte, numbers []int, err error) {
+	for len(records) < s.chunkSize {
+		line, number, err := s.lines.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		var obj map[string]interface{}
+		if err := unmarshalJSONObject(line, &obj); err != nil {
+			if err := s.reject(line, number, err); err != nil {
+				return nil, nil, nil, err
+			}
+			continue
+		}
+		records = append(records, obj)
+		lines = append(lines, line)
+		numbers = append(numbers, number)
+	}
+	return records, lines, numbers, nil
+}
+
+func (s *ndjsonChunkSource) next() (*DataFrame, error) {
+	for s.lines != nil {
+		records, lines, numbers, err := s.readLines()
+		if err != nil {
+			return nil, err
+		}
+		if len(records) == 0 {
+			return nil, io.EOF
+		}
+		if records, err = shapeJSONRecords(records, s.opts.JSONReadOptions); err != nil {
+			return nil, err
+		}
+		if s.schema == nil {
+			s.schema = s.opts.Schema
+			if s.schema == nil {
+				s.schema = inferJSONSchema(records, s.opts.FlattenSeparator)
+			}
+		}
+
+		// Drop records whose values do not fit the schema, one at a time
+		for len(records) > 0 {
+			record, err := buildJSONRecord(s.schema, records)
+			var rowErr *jsonRowError
+			if errors.As(err, &rowErr) {
+				i := rowErr.row
+				if err := s.reject(lines[i], numbers[i], fmt.Errorf("column %s: %w", rowErr.column, rowErr.err)); err != nil {
+					return nil, err
+				}
+				records = slices.Delete(records, i, i+1)
+				lines = slices.Delete(lines, i, i+1)
+				numbers = slices.Delete(numbers, i, i+1)
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			df := NewDataFrame(record)
+			record.Release()
+			return df, nil
+		}
+		// Every line of the chunk was rejected
+	}
+	return nil, io.EOF
+}
+
+// reset reopens the file and clears the rejects. The schema resolved from
+// the first chunk is kept.
+func (s *ndjsonChunkSource) reset() error {
+	_ = s.close()
+	s.rejects.reset()
+	return s.open()
+}
+
+func (s *ndjsonChunkSource) count() int {
+	f, err := openCompressed(s.filename)
+	if err != nil {
+		return 0
+	}
+	defer func() { _ = f.Close() }()
+
+	lines := newNDJSONReader(f)
+	rows := 0
+	for {
+		line, _, err := lines.next()
+		if err != nil {
+			break
+		}
+		var obj map[string]interface{}
+		if unmarshalJSONObject(line, &obj) == nil {
+			rows++
+		}
+	}
+	return (rows + s.chunkSize - 1) / s.chunkSize
+}
+
+func (s *ndjsonChunkSource) close() error {
+	s.lines = nil
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// rejectedRows implements rejectSource.
+func (s *ndjsonChunkSource) rejectedRows() *DataFrame {
+	return s.rejects.dataFrame()
+}
@@ -0,0 +1,147 @@
+package gopherframe
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// corruptLogNDJSON has a malformed line (4), a blank line (5) and a value
+// that does not fit the int64 column inferred from the first chunk (7).
+const corruptLogNDJSON = `{"id": 1, "level": "info"}
+{"id": 2, "level": "warn"}
+{"id": 3, "level": "info"}
+{"id": 4, "level": 
+` + `
+{"id": 5, "level": "error"}
+{"id": "six", "level": "info"}
+{"id": 7, "level": "info"}
+`
+
+func writeTestNDJSON(t *testing.T, name, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(data), 0600))
+	return path
+}
+
+func collectIDs(t *testing.T, it *DataFrameIterator) []int64 {
+	t.Helper()
+	var ids []int64
+	require.NoError(t, it.ForEachChunk(func(chunk *DataFrame) error {
+		defer chunk.Release()
+		ids = append(ids, chunk.Record().Column(0).(*array.Int64).Int64Values()...)
+		return nil
+	}))
+	return ids
+}
+
+func TestReadNDJSONChunked_BadRecordPolicies(t *testing.T) {
+	path := writeTestNDJSON(t, "log.ndjson", corruptLogNDJSON)
+
+	t.Run("fail", func(t *testing.T) {
+		it, err := ReadNDJSONChunked(path, 2)
+		require.NoError(t, err)
+		defer func() { _ = it.Close() }()
+
+		err = it.ForEachChunk(func(chunk *DataFrame) error {
+			chunk.Release()
+			return nil
+		})
+		assert.ErrorContains(t, err, "line 4")
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		it, err := ReadNDJSONChunkedWithOptions(path, 2, NDJSONReadOptions{BadRecords: BadRecordSkip})
+		require.NoError(t, err)
+		defer func() { _ = it.Close() }()
+
+		assert.Equal(t, []int64{1, 2, 3, 5, 7}, collectIDs(t, it))
+		rejects := it.Rejects()
+		defer rejects.Release()
+		assert.Equal(t, int64(0), rejects.NumRows())
+	})
+
+	t.Run("collect", func(t *testing.T) {
+		it, err := ReadNDJSONChunkedWithOptions(path, 2, NDJSONReadOptions{BadRecords: BadRecordCollect})
+		require.NoError(t, err)
+		defer func() { _ = it.Close() }()
+
+		assert.Equal(t, []int64{1, 2, 3, 5, 7}, collectIDs(t, it))
+		rejects := it.Rejects()
+		defer rejects.Release()
+		assert.Equal(t, []string{"line_number", "line", "error"}, rejects.ColumnNames())
+		record := rejects.Record()
+		assert.Equal(t, []int64{4, 7}, record.Column(0).(*array.Int64).Int64Values())
+		assert.Equal(t, `{"id": 4, "level": `, record.Column(1).(*array.String).Value(0))
+		assert.Equal(t, `{"id": "six", "level": "info"}`, record.Column(1).(*array.String).Value(1))
+		assert.Contains(t, record.Column(2).(*array.String).Value(1), "column id")
+
+		// Reading again starts a new set of rejects
+		collectIDs(t, it)
+		again := it.Rejects()
+		defer again.Release()
+		assert.Equal(t, int64(2), again.NumRows())
+	})
+}
+
+func TestReadNDJSONChunked_SchemaAndCompression(t *testing.T) {
+	df, err := ReadNDJSONFrom(strings.NewReader(nestedEventsNDJSON), JSONReadOptions{})
+	require.NoError(t, err)
+	defer df.Release()
+	path := filepath.Join(t.TempDir(), "events.ndjson.gz")
+	require.NoError(t, WriteNDJSON(df, path))
+
+	it, err := ReadNDJSONChunked(path, 2)
+	require.NoError(t, err)
+	defer func() { _ = it.Close() }()
+	result, err := it.Collect()
+	require.NoError(t, err)
+	defer result.Release()
+	assert.True(t, df.Record().Schema().Equal(result.Record().Schema()))
+	assert.Equal(t, int64(3), result.NumRows())
+}
+
+func TestReadNDJSONChunked_InvalidArguments(t *testing.T) {
+	path := writeTestNDJSON(t, "log.ndjson", corruptLogNDJSON)
+
+	_, err := ReadNDJSONChunked(path, 0)
+	assert.Error(t, err)
+	_, err = ReadNDJSONChunkedWithOptions(path, 2, NDJSONReadOptions{BadRecords: BadRecordPolicy(9)})
+	assert.ErrorContains(t, err, "BadRecordPolicy(9)")
+	_, err = ReadNDJSONChunked(filepath.Join(t.TempDir(), "missing.ndjson"), 2)
+	assert.Error(t, err)
+}
+
+func TestReadNDJSONStreaming_CollectsRejects(t *testing.T) {
+	path := writeTestNDJSON(t, "log.ndjson", corruptLogNDJSON)
+
+	sr, err := ReadNDJSONStreamingWithOptions(path, 2, 1, NDJSONReadOptions{BadRecords: BadRecordCollect})
+	require.NoError(t, err)
+
+	var rows int64
+	for chunk := range sr.Chunks() {
+		rows += chunk.NumRows()
+		chunk.Release()
+	}
+	require.NoError(t, sr.Err())
+	assert.Equal(t, int64(5), rows)
+
+	rejects := sr.Rejects()
+	defer rejects.Release()
+	assert.Equal(t, int64(2), rejects.NumRows())
+}
+
+func TestReadNDJSON_LongLines(t *testing.T) {
+	// Longer than bufio.Scanner's default 64 KiB token limit
+	long := strings.Repeat("x", 100_000)
+	df, err := ReadNDJSONFrom(strings.NewReader(`{"text": "`+long+`"}`+"\n"), JSONReadOptions{})
+	require.NoError(t, err)
+	defer df.Release()
+	assert.Equal(t, long, df.Record().Column(0).(*array.String).Value(0))
+}
@@ -0,0 +1,135 @@
+package gopherframe
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/compute"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/arrow/scalar"
+	"github.com/felixgeelhaar/GopherFrame/pkg/expr"
+)
+
+// FillNull returns a new DataFrame where null values in the given columns are
+// replaced with the corresponding fill value. Fill values are converted to the
+// column's type; a value that cannot be represented in that type is an error.
+//
+// Example:
+//
+//	filled := df.FillNull(map[string]interface{}{"age": 0, "city": "unknown"})
+func (df *DataFrame) FillNull(values map[string]interface{}) *DataFrame {
+	return replaceColumns(df, values, func(field arrow.Field, value interface{}) (expr.Expr, error) {
+		fillValue, err := convertFillValue(value, field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fill value for column %s: %w", field.Name, err)
+		}
+		return expr.Col(field.Name).FillNull(expr.Lit(fillValue)), nil
+	})
+}
+
+// DropNulls returns a new DataFrame without the rows that contain a null value
+// in any of the given columns. With no columns, all columns are checked.
+func (df *DataFrame) DropNulls(columns ...string) *DataFrame {
+	if df.err != nil {
+		return &DataFrame{err: df.err}
+	}
+	if len(columns) == 0 {
+		columns = df.ColumnNames()
+	}
+	if len(columns) == 0 {
+		return NewDataFrame(df.Record())
+	}
+
+	predicate := expr.Col(columns[0]).IsNotNull()
+	for _, col := range columns[1:] {
+		predicate = predicate.And(expr.Col(col).IsNotNull())
+	}
+	return df.Filter(predicate)
+}
+
+// convertFillValue converts a Go fill value to a scalar of dataType. Numbers
+// fill numeric columns, strings fill string columns and time.Time values fill
+// timestamp and date columns; a value the column type cannot represent
+// exactly is an error.
+func convertFillValue(value interface{}, dataType arrow.DataType) (scalar.Scalar, error) {
+	var sc scalar.Scalar
+	if t, ok := value.(time.Time); ok {
+		switch dt := dataType.(type) {
+		case *arrow.TimestampType:
+			ts, err := arrow.TimestampFromTime(t, dt.Unit)
+			if err != nil {
+				return nil, err
+			}
+			return scalar.NewTimestampScalar(ts, dt), nil
+		case *arrow.Date32Type:
+			return scalar.NewDate32Scalar(arrow.Date32FromTime(t)), nil
+		case *arrow.Date64Type:
+			return scalar.NewDate64Scalar(arrow.Date64FromTime(t)), nil
+		}
+	} else if value != nil {
+		sc = makeFillScalar(value)
+	}
+	if sc == nil || !fillCompatible(sc.DataType(), dataType) {
+		return nil, fmt.Errorf("cannot use %T as %s", value, dataType)
+	}
+	if arrow.TypeEqual(sc.DataType(), dataType) {
+		return sc, nil
+	}
+
+	pool := memory.NewGoAllocator()
+	arr, err := scalar.MakeArrayFromScalar(sc, 1, pool)
+	if err != nil {
+		return nil, err
+	}
+	defer arr.Release()
+	converted, err := compute.CastArray(context.Background(), arr, compute.SafeCastOptions(dataType))
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert %v to %s: %w", value, dataType, err)
+	}
+	defer converted.Release()
+
+	// Safe casts still round floats into decimals, so check that the value
+	// survives the round trip. Floating point columns take the nearest value.
+	if !arrow.IsFloating(dataType.ID()) {
+		back, err := compute.CastArray(context.Background(), converted, compute.SafeCastOptions(sc.DataType()))
+		if err != nil {
+			return nil, fmt.Errorf("%v cannot be represented as %s", value, dataType)
+		}
+		defer back.Release()
+		if !array.Equal(back, arr) {
+			return nil, fmt.Errorf("%v cannot be represented as %s", value, dataType)
+		}
+	}
+	return scalar.GetScalar(converted, 0)
+}
+
+// makeFillScalar returns the Arrow scalar of a Go value, or nil for Go types
+// that have none.
+func makeFillScalar(value interface{}) (sc scalar.Scalar) {
+	defer func() {
+		if recover() != nil {
+			sc = nil
+		}
+	}()
+	return scalar.MakeScalar(value)
+}
+
+// fillCompatible reports whether a fill value of type from may fill a column
+// of type to: numbers fill numeric columns and strings fill string columns.
+func fillCompatible(from, to arrow.DataType) bool {
+	numeric := func(dt arrow.DataType) bool {
+		id := dt.ID()
+		return arrow.IsInteger(id) || arrow.IsFloating(id) || arrow.IsDecimal(id)
+	}
+	switch {
+	case numeric(from):
+		return numeric(to)
+	case from.ID() == arrow.STRING:
+		return to.ID() == arrow.STRING || to.ID() == arrow.LARGE_STRING
+	default:
+		return arrow.TypeEqual(from, to)
+	}
+}
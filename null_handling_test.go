@@ -0,0 +1,177 @@
+package gopherframe
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createNullableSampleDataFrame() *DataFrame {
+	pool := memory.NewGoAllocator()
+
+	ageBuilder := array.NewInt64Builder(pool)
+	defer ageBuilder.Release()
+	ageBuilder.AppendValues([]int64{30, 0, 25, 0}, []bool{true, false, true, false})
+	ageArray := ageBuilder.NewArray()
+	defer ageArray.Release()
+
+	cityBuilder := array.NewStringBuilder(pool)
+	defer cityBuilder.Release()
+	cityBuilder.AppendValues([]string{"Berlin", "Paris", "", ""}, []bool{true, true, false, false})
+	cityArray := cityBuilder.NewArray()
+	defer cityArray.Release()
+
+	scoreBuilder := array.NewFloat64Builder(pool)
+	defer scoreBuilder.Release()
+	scoreBuilder.AppendValues([]float64{1.5, 2.5, 3.5, 0}, []bool{true, true, true, false})
+	scoreArray := scoreBuilder.NewArray()
+	defer scoreArray.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "age", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "city", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "score", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	}, nil)
+	record := array.NewRecord(schema, []arrow.Array{ageArray, cityArray, scoreArray}, 4)
+	defer record.Release()
+
+	return NewDataFrame(record)
+}
+
+func TestDataFrame_FillNull(t *testing.T) {
+	df := createNullableSampleDataFrame()
+	defer df.Release()
+
+	filled := df.FillNull(map[string]interface{}{"age": 0, "city": "unknown", "score": 0})
+	require.NoError(t, filled.Err())
+	defer filled.Release()
+
+	assert.True(t, filled.IsComplete())
+
+	record := filled.Record()
+	// Column types are preserved
+	assert.Equal(t, arrow.INT64, record.Column(0).DataType().ID())
+	assert.Equal(t, arrow.FLOAT64, record.Column(2).DataType().ID())
+
+	assert.Equal(t, []int64{30, 0, 25, 0}, record.Column(0).(*array.Int64).Int64Values())
+	cities := record.Column(1).(*array.String)
+	assert.Equal(t, "Paris", cities.Value(1))
+	assert.Equal(t, "unknown", cities.Value(2))
+	assert.Equal(t, 0.0, record.Column(2).(*array.Float64).Value(3))
+}
+
+func TestDataFrame_FillNull_Errors(t *testing.T) {
+	df := createNullableSampleDataFrame()
+	defer df.Release()
+
+	result := df.FillNull(map[string]interface{}{"missing": 1})
+	assert.Error(t, result.Err())
+
+	result = df.FillNull(map[string]interface{}{"age": "not a number"})
+	assert.Error(t, result.Err())
+
+	result = df.FillNull(map[string]interface{}{"age": 1.5})
+	assert.Error(t, result.Err())
+}
+
+func TestDataFrame_FillNull_OtherTypes(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	valid := []bool{true, false}
+
+	i32Builder := array.NewInt32Builder(pool)
+	defer i32Builder.Release()
+	i32Builder.AppendValues([]int32{7, 0}, valid)
+	i32Array := i32Builder.NewArray()
+	defer i32Array.Release()
+
+	f32Builder := array.NewFloat32Builder(pool)
+	defer f32Builder.Release()
+	f32Builder.AppendValues([]float32{1.5, 0}, valid)
+	f32Array := f32Builder.NewArray()
+	defer f32Array.Release()
+
+	dateBuilder := array.NewDate32Builder(pool)
+	defer dateBuilder.Release()
+	dateBuilder.AppendValues([]arrow.Date32{19000, 0}, valid)
+	dateArray := dateBuilder.NewArray()
+	defer dateArray.Release()
+
+	decType := &arrow.Decimal128Type{Precision: 10, Scale: 2}
+	decBuilder := array.NewDecimal128Builder(pool, decType)
+	defer decBuilder.Release()
+	decBuilder.AppendValues([]decimal128.Num{decimal128.FromI64(1234), {}}, valid)
+	decArray := decBuilder.NewArray()
+	defer decArray.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "i32", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		{Name: "f32", Type: arrow.PrimitiveTypes.Float32, Nullable: true},
+		{Name: "date", Type: arrow.FixedWidthTypes.Date32, Nullable: true},
+		{Name: "dec", Type: decType, Nullable: true},
+	}, nil)
+	record := array.NewRecord(schema, []arrow.Array{i32Array, f32Array, dateArray, decArray}, 2)
+	defer record.Release()
+	df := NewDataFrame(record)
+	defer df.Release()
+
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	filled := df.FillNull(map[string]interface{}{"i32": -1, "f32": 0.25, "date": day, "dec": 2.5})
+	require.NoError(t, filled.Err())
+	defer filled.Release()
+
+	result := filled.Record()
+	// Column types are preserved
+	for i, field := range result.Schema().Fields() {
+		assert.True(t, arrow.TypeEqual(schema.Field(i).Type, field.Type), field.Name)
+	}
+	assert.Equal(t, []int32{7, -1}, result.Column(0).(*array.Int32).Int32Values())
+	assert.Equal(t, []float32{1.5, 0.25}, result.Column(1).(*array.Float32).Float32Values())
+	assert.Equal(t, arrow.Date32FromTime(day), result.Column(2).(*array.Date32).Value(1))
+	assert.Equal(t, decimal128.FromI64(250), result.Column(3).(*array.Decimal128).Value(1))
+
+	assert.Error(t, df.FillNull(map[string]interface{}{"i32": int64(1) << 40}).Err(), "overflow")
+	assert.Error(t, df.FillNull(map[string]interface{}{"dec": 0.125}).Err(), "lost precision")
+	assert.Error(t, df.FillNull(map[string]interface{}{"date": 1}).Err())
+}
+
+func TestDataFrame_FillNull_NoColumns(t *testing.T) {
+	df := createNullableSampleDataFrame()
+	defer df.Release()
+
+	// The result is a separate DataFrame that can be released on its own
+	result := df.FillNull(map[string]interface{}{})
+	require.NoError(t, result.Err())
+	assert.NotSame(t, df, result)
+	result.Release()
+	assert.Equal(t, int64(4), df.NumRows())
+}
+
+func TestDataFrame_DropNulls(t *testing.T) {
+	df := createNullableSampleDataFrame()
+	defer df.Release()
+
+	t.Run("all columns", func(t *testing.T) {
+		result := df.DropNulls()
+		require.NoError(t, result.Err())
+		defer result.Release()
+		assert.Equal(t, int64(1), result.NumRows())
+	})
+
+	t.Run("subset of columns", func(t *testing.T) {
+		result := df.DropNulls("city")
+		require.NoError(t, result.Err())
+		defer result.Release()
+		assert.Equal(t, int64(2), result.NumRows())
+	})
+
+	t.Run("missing column", func(t *testing.T) {
+		result := df.DropNulls("missing")
+		assert.Error(t, result.Err())
+	})
+}
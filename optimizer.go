@@ -14,33 +14,188 @@ import (
 // Optimize applies optimization passes to an expression tree.
 // Currently implements:
 // - Constant folding: pre-evaluates expressions on literal values
-// - Identity elimination: removes no-op operations (x + 0, x * 1)
+//
+// Use OptimizeWithSchema to also remove no-op arithmetic, which needs the
+// column types.
 func Optimize(e expr.Expr) expr.Expr {
-	return optimizeExpr(e)
+	return optimizeExpr(e, nil)
 }
 
-func optimizeExpr(e expr.Expr) expr.Expr {
-	switch v := e.(type) {
-	case *constantFoldedExpr:
-		return v // Already optimized
-	default:
-		// Try constant folding for binary expressions on two literals
-		folded := tryConstantFold(e)
-		if folded != nil {
-			return folded
+// OptimizeWithSchema optimizes an expression over columns of the given schema.
+// Besides constant folding it removes no-op operations (x + 0, x * 1) when the
+// literal has the type of x, so the result keeps the same type and the same
+// errors as the original expression.
+func OptimizeWithSchema(e expr.Expr, schema *arrow.Schema) expr.Expr {
+	return optimizeExpr(e, schema)
+}
+
+func optimizeExpr(e expr.Expr, schema *arrow.Schema) expr.Expr {
+	var empty *core.DataFrame
+	if schema != nil {
+		empty = emptyDataFrame(schema)
+		defer empty.Release()
+	}
+
+	optimized, err := expr.Transform(e, func(node expr.Expr) (expr.Expr, error) {
+		if folded := tryConstantFold(node); folded != nil {
+			return folded, nil
 		}
+		if empty != nil {
+			if simplified := tryEliminateIdentity(node, empty); simplified != nil {
+				return simplified, nil
+			}
+		}
+		return node, nil
+	})
+	if err != nil {
+		// Optimization is best effort; fall back to the original expression
 		return e
 	}
+	return optimized
 }
 
 // tryConstantFold attempts to evaluate an expression at optimization time
-// if all inputs are literals.
+// if all inputs are literals. Expressions that fail to evaluate are left in
+// place so the error surfaces when the query runs.
 func tryConstantFold(e expr.Expr) expr.Expr {
-	s := e.String()
-	// Detect patterns like "Lit(x) op Lit(y)" for constant folding
-	// This is a simple heuristic; a full implementation would walk the AST
-	_ = s
-	return nil
+	children := e.Children()
+	if len(children) == 0 {
+		return nil
+	}
+	for _, child := range children {
+		if child.Op() != expr.OpLiteral {
+			return nil
+		}
+	}
+
+	// Evaluate against a single-row frame with no columns
+	schema := arrow.NewSchema(nil, nil)
+	record := array.NewRecord(schema, nil, 1)
+	df := core.NewDataFrame(record)
+	record.Release()
+	defer df.Release()
+
+	result, err := e.Evaluate(df)
+	if err != nil {
+		return nil
+	}
+	defer result.Release()
+
+	if result.Len() != 1 || result.IsNull(0) {
+		return nil
+	}
+	switch arr := result.(type) {
+	case *array.Float64:
+		return FoldedLit(arr.Value(0))
+	case *array.Int64:
+		return FoldedLit(arr.Value(0))
+	case *array.String:
+		return FoldedLit(arr.Value(0))
+	case *array.Boolean:
+		return FoldedLit(arr.Value(0))
+	default:
+		return nil
+	}
+}
+
+// tryEliminateIdentity removes arithmetic that cannot change its operand:
+// x + 0, 0 + x, x - 0, x * 1, 1 * x and x / 1. The operation is only removed
+// when the literal, the operand and the result all have the same type over
+// the columns of empty, since otherwise it converts or rejects its operand.
+func tryEliminateIdentity(e expr.Expr, empty *core.DataFrame) expr.Expr {
+	children := e.Children()
+	if len(children) != 2 {
+		return nil
+	}
+	left, right := children[0], children[1]
+
+	var operand, literal expr.Expr
+	switch e.Op() {
+	case "add":
+		if isNumericConstant(right, 0) {
+			operand, literal = left, right
+		} else if isNumericConstant(left, 0) {
+			operand, literal = right, left
+		}
+	case "subtract":
+		if isNumericConstant(right, 0) {
+			operand, literal = left, right
+		}
+	case "multiply":
+		if isNumericConstant(right, 1) {
+			operand, literal = left, right
+		} else if isNumericConstant(left, 1) {
+			operand, literal = right, left
+		}
+	case "divide":
+		if isNumericConstant(right, 1) {
+			operand, literal = left, right
+		}
+	}
+	if operand == nil {
+		return nil
+	}
+
+	operandType, ok := resolveType(operand, empty)
+	if !ok {
+		return nil
+	}
+	for _, other := range []expr.Expr{literal, e} {
+		if dt, ok := resolveType(other, empty); !ok || !arrow.TypeEqual(dt, operandType) {
+			return nil
+		}
+	}
+	return operand
+}
+
+// resolveType returns the type e evaluates to over the columns of empty, a
+// DataFrame without rows, or false if e cannot be evaluated.
+func resolveType(e expr.Expr, empty *core.DataFrame) (arrow.DataType, bool) {
+	result, err := e.Evaluate(empty)
+	if err != nil {
+		return nil, false
+	}
+	defer result.Release()
+	return result.DataType(), true
+}
+
+// emptyDataFrame returns a DataFrame with the given schema and no rows.
+func emptyDataFrame(schema *arrow.Schema) *core.DataFrame {
+	pool := memory.NewGoAllocator()
+	columns := make([]arrow.Array, schema.NumFields())
+	for i, field := range schema.Fields() {
+		columns[i] = array.MakeArrayOfNull(pool, field.Type, 0)
+	}
+	record := array.NewRecord(schema, columns, 0)
+	for _, col := range columns {
+		col.Release()
+	}
+	defer record.Release()
+	return core.NewDataFrame(record)
+}
+
+// isNumericConstant reports whether e is a literal equal to the given number.
+func isNumericConstant(e expr.Expr, want float64) bool {
+	var value interface{}
+	switch c := e.(type) {
+	case *expr.LiteralExpr:
+		value = c.Value()
+	case *constantFoldedExpr:
+		value = c.value
+	default:
+		return false
+	}
+
+	switch v := value.(type) {
+	case int:
+		return float64(v) == want
+	case int64:
+		return float64(v) == want
+	case float64:
+		return v == want
+	default:
+		return false
+	}
 }
 
 // constantFoldedExpr wraps a pre-computed constant value as an Expr.
@@ -95,8 +250,21 @@ func (c *constantFoldedExpr) Evaluate(df *core.DataFrame) (arrow.Array, error) {
 	}
 }
 
-func (c *constantFoldedExpr) Name() string              { return c.name }
-func (c *constantFoldedExpr) String() string            { return c.name }
+func (c *constantFoldedExpr) EvaluateRecord(record arrow.Record) (arrow.Array, error) {
+	return expr.EvaluateRecord(c, record)
+}
+
+func (c *constantFoldedExpr) Name() string          { return c.name }
+func (c *constantFoldedExpr) String() string        { return c.name }
+func (c *constantFoldedExpr) Children() []expr.Expr { return nil }
+func (c *constantFoldedExpr) Op() string            { return expr.OpLiteral }
+func (c *constantFoldedExpr) Columns() []string     { return nil }
+func (c *constantFoldedExpr) WithChildren(children []expr.Expr) (expr.Expr, error) {
+	if len(children) != 0 {
+		return nil, fmt.Errorf("constant expression has no children, got %d", len(children))
+	}
+	return c, nil
+}
 func (c *constantFoldedExpr) Add(o expr.Expr) expr.Expr { return expr.NewBinaryExpr(c, o, "add") }
 func (c *constantFoldedExpr) Sub(o expr.Expr) expr.Expr { return expr.NewBinaryExpr(c, o, "sub") }
 func (c *constantFoldedExpr) Mul(o expr.Expr) expr.Expr { return expr.NewBinaryExpr(c, o, "mul") }
@@ -104,6 +272,28 @@ func (c *constantFoldedExpr) Div(o expr.Expr) expr.Expr { return expr.NewBinaryE
 func (c *constantFoldedExpr) Gt(o expr.Expr) expr.Expr  { return expr.NewBinaryExpr(c, o, "gt") }
 func (c *constantFoldedExpr) Lt(o expr.Expr) expr.Expr  { return expr.NewBinaryExpr(c, o, "lt") }
 func (c *constantFoldedExpr) Eq(o expr.Expr) expr.Expr  { return expr.NewBinaryExpr(c, o, "eq") }
+func (c *constantFoldedExpr) Ne(o expr.Expr) expr.Expr  { return expr.NewBinaryExpr(c, o, "not_equal") }
+func (c *constantFoldedExpr) Ge(o expr.Expr) expr.Expr {
+	return expr.NewBinaryExpr(c, o, "greater_equal")
+}
+func (c *constantFoldedExpr) Le(o expr.Expr) expr.Expr  { return expr.NewBinaryExpr(c, o, "less_equal") }
+func (c *constantFoldedExpr) And(o expr.Expr) expr.Expr { return expr.NewBinaryExpr(c, o, "and") }
+func (c *constantFoldedExpr) Or(o expr.Expr) expr.Expr  { return expr.NewBinaryExpr(c, o, "or") }
+func (c *constantFoldedExpr) Not() expr.Expr            { return expr.NewUnaryExpr(c, "not") }
+func (c *constantFoldedExpr) IsNull() expr.Expr         { return expr.NewUnaryExpr(c, "is_null") }
+func (c *constantFoldedExpr) IsNotNull() expr.Expr      { return expr.NewUnaryExpr(c, "is_not_null") }
+func (c *constantFoldedExpr) FillNull(v expr.Expr) expr.Expr {
+	return expr.Coalesce(c, v)
+}
+func (c *constantFoldedExpr) NullIf(v expr.Expr) expr.Expr {
+	return expr.NewBinaryExpr(c, v, "null_if")
+}
+func (c *constantFoldedExpr) Cast(dt arrow.DataType) expr.Expr {
+	return expr.NewCastExpr(c, dt, expr.CastStrict)
+}
+func (c *constantFoldedExpr) TryCast(dt arrow.DataType) expr.Expr {
+	return expr.NewCastExpr(c, dt, expr.CastSafe)
+}
 func (c *constantFoldedExpr) Contains(s expr.Expr) expr.Expr {
 	return expr.NewBinaryExpr(c, s, "contains")
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/felixgeelhaar/GopherFrame/pkg/expr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -90,3 +91,116 @@ func TestFoldedLit_AllTypes(t *testing.T) {
 	r2 := df.WithColumn("bool_const", FoldedLit(true))
 	require.NoError(t, r2.Err())
 }
+
+func TestOptimize_ConstantFolding(t *testing.T) {
+	e := Col("x").Gt(Lit(1.0).Add(Lit(2.0)))
+	optimized := Optimize(e)
+
+	right := optimized.Children()[1]
+	assert.Equal(t, "const(3)", right.Name())
+	assert.Equal(t, []string{"x"}, optimized.Columns())
+
+	// Fully constant expressions fold to a single literal
+	assert.Equal(t, "const(true)", Optimize(Lit(int64(2)).Gt(Lit(int64(1)))).Name())
+}
+
+func TestOptimize_IdentityElimination(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "x", Type: arrow.PrimitiveTypes.Float64},
+		{Name: "n", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+
+	e := Col("x").Add(Lit(0.0)).Mul(Lit(1.0))
+	assert.Equal(t, "Col(x)", OptimizeWithSchema(e, schema).String())
+
+	// x - 0 folds away, but 0 - x does not
+	assert.Equal(t, "Col(n)", OptimizeWithSchema(Col("n").Sub(Lit(int64(0))), schema).String())
+	assert.Equal(t, "subtract", OptimizeWithSchema(Lit(int64(0)).Sub(Col("n")), schema).Op())
+
+	// Literals of another type convert the operand, so they stay
+	assert.Equal(t, "add", OptimizeWithSchema(Col("n").Add(Lit(0.0)), schema).Op())
+	assert.Equal(t, "multiply", OptimizeWithSchema(Col("x").Mul(Lit(int64(1))), schema).Op())
+
+	// Without a schema the types are unknown
+	assert.Equal(t, "add", Optimize(Col("x").Add(Lit(0.0))).Op())
+}
+
+func TestOptimize_IdentityEliminationPreservesTypesAndErrors(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	i32b := array.NewInt32Builder(pool)
+	defer i32b.Release()
+	i32b.AppendValues([]int32{1, 2, 3}, nil)
+	i32 := i32b.NewArray()
+	defer i32.Release()
+
+	nb := array.NewInt64Builder(pool)
+	defer nb.Release()
+	nb.AppendValues([]int64{4, 5, 6}, nil)
+	n := nb.NewArray()
+	defer n.Release()
+
+	sb := array.NewStringBuilder(pool)
+	defer sb.Release()
+	sb.AppendValues([]string{"a", "b", "c"}, nil)
+	str := sb.NewArray()
+	defer str.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "i32", Type: arrow.PrimitiveTypes.Int32},
+		{Name: "n", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "s", Type: arrow.BinaryTypes.String},
+	}, nil)
+	record := array.NewRecord(schema, []arrow.Array{i32, n, str}, 3)
+	defer record.Release()
+	df := NewDataFrame(record)
+	defer df.Release()
+
+	for _, e := range []expr.Expr{
+		Col("i32").Add(Lit(int64(0))),
+		Col("i32").Mul(Lit(int64(1))),
+		Col("n").Add(Lit(0.0)),
+		Col("n").Div(Lit(int64(1))),
+		Lit(int64(1)).Mul(Col("n")),
+		Col("s").Add(Lit(int64(0))),
+		Col("s").Mul(Lit(1.0)),
+	} {
+		t.Run(e.String(), func(t *testing.T) {
+			plain := df.WithColumn("out", e)
+			optimized := df.WithColumn("out", OptimizeWithSchema(e, schema))
+			defer plain.Release()
+			defer optimized.Release()
+
+			if plain.Err() != nil {
+				assert.Error(t, optimized.Err())
+				return
+			}
+			require.NoError(t, optimized.Err())
+			assert.True(t, arrow.TypeEqual(
+				plain.Schema().Field(3).Type, optimized.Schema().Field(3).Type))
+		})
+	}
+}
+
+func TestOptimize_PreservesResults(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "x", Type: arrow.PrimitiveTypes.Float64},
+	}, nil)
+	xb := array.NewFloat64Builder(pool)
+	xb.AppendValues([]float64{1, 2, 3, 4}, nil)
+	xArr := xb.NewArray()
+	defer xArr.Release()
+	xb.Release()
+
+	record := array.NewRecord(schema, []arrow.Array{xArr}, 4)
+	defer record.Release()
+	df := NewDataFrame(record)
+
+	predicate := Col("x").Mul(Lit(1.0)).Gt(Lit(1.0).Add(Lit(1.0)))
+	plain := df.Filter(predicate)
+	optimized := df.Filter(Optimize(predicate))
+	require.NoError(t, plain.Err())
+	require.NoError(t, optimized.Err())
+	assert.Equal(t, plain.NumRows(), optimized.NumRows())
+	assert.Equal(t, int64(2), optimized.NumRows())
+}
@@ -0,0 +1,532 @@
+package gopherframe
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/metadata"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/felixgeelhaar/GopherFrame/pkg/expr"
+	"github.com/felixgeelhaar/GopherFrame/pkg/storage"
+)
+
+// ReadParquetWithOptions reads a DataFrame from a Parquet file, reading only
+// what the options require:
+//   - Columns limits the result to those columns; other columns are not decoded
+//   - Filter keeps only matching rows; it must be an expr.Expr. Row groups whose
+//     column statistics (min, max, null count) show that no row can match are
+//     skipped without being read
+//   - Limit caps the number of rows returned
+//
+// Example:
+//
+//	df, err := ReadParquetWithOptions("sales.parquet", storage.ReadOptions{
+//	    Columns: []string{"region", "amount"},
+//	    Filter:  Col("amount").Gt(Lit(100.0)),
+//	})
+func ReadParquetWithOptions(filename string, opts storage.ReadOptions) (*DataFrame, error) {
+	if err := validateFilePath(filename); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Parquet file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	return readParquet(f, opts)
+}
+
+// ReadParquetFrom reads a DataFrame from Parquet data of the given size,
+// applying opts like ReadParquetWithOptions. Parquet keeps its metadata in a
+// footer at the end of the data, so r must support random access; *os.File,
+// *bytes.Reader and range-request readers for object storage all do. r is
+// not closed.
+func ReadParquetFrom(r io.ReaderAt, size int64, opts storage.ReadOptions) (*DataFrame, error) {
+	return readParquet(io.NewSectionReader(r, 0, size), opts)
+}
+
+// ParquetWriteOptions configures compression, row group size, encodings,
+// statistics and footer metadata of written Parquet files.
+type ParquetWriteOptions = storage.ParquetWriteOptions
+
+// WriteParquetWithOptions writes a DataFrame to a Parquet file encoded as
+// described by opts. Without a RowGroupSize the DataFrame is written as a
+// single row group.
+//
+// Example:
+//
+//	err := WriteParquetWithOptions(df, "sales.parquet", ParquetWriteOptions{
+//	    Compression:      "zstd",
+//	    CompressionLevel: 3,
+//	    RowGroupSize:     100_000,
+//	    Dictionary:       map[string]bool{"id": false},
+//	    Metadata:         map[string]string{"source": "daily-export"},
+//	})
+func WriteParquetWithOptions(df *DataFrame, filename string, opts ParquetWriteOptions) error {
+	if df.Err() != nil {
+		return fmt.Errorf("DataFrame has error: %w", df.Err())
+	}
+
+	if err := validateFilePath(filename); err != nil {
+		return err
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	return writeParquet(df, f, opts)
+}
+
+// WriteParquetTo writes a DataFrame to w as Parquet encoded as described by
+// opts. Parquet is written sequentially, so w needs no seeking; it is not
+// closed.
+func WriteParquetTo(df *DataFrame, w io.Writer, opts ParquetWriteOptions) error {
+	if df.Err() != nil {
+		return fmt.Errorf("DataFrame has error: %w", df.Err())
+	}
+
+	// Hide any Close method, which the Parquet writer would call
+	return writeParquet(df, struct{ io.Writer }{w}, opts)
+}
+
+// writeParquet encodes a DataFrame as Parquet to w and writes the footer.
+// Closing the Parquet writer closes w if it is an io.Closer.
+func writeParquet(df *DataFrame, w io.Writer, opts ParquetWriteOptions) error {
+	record := df.coreDF.Record()
+	writer, err := storage.NewParquetWriter(record.Schema(), w, memory.DefaultAllocator, opts)
+	if err != nil {
+		return err
+	}
+
+	table := array.NewTableFromRecords(record.Schema(), []arrow.Record{record})
+	defer table.Release()
+
+	// WriteTable starts a row group every chunkSize rows
+	chunkSize := opts.RowGroupSize
+	if chunkSize <= 0 {
+		chunkSize = max(table.NumRows(), 1)
+	}
+	if err := writer.WriteTable(table, chunkSize); err != nil {
+		_ = writer.Close()
+		return fmt.Errorf("failed to write table: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to close Parquet writer: %w", err)
+	}
+	return nil
+}
+
+// readParquet reads a DataFrame from Parquet data, applying the projection,
+// predicate and limit in opts.
+func readParquet(r parquet.ReaderAtSeeker, opts storage.ReadOptions) (*DataFrame, error) {
+	predicate, err := predicateExpr(opts.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := memory.DefaultAllocator
+	parquetReader, err := file.NewParquetReader(r, file.WithReadProps(parquet.NewReaderProperties(pool)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Parquet reader: %w", err)
+	}
+	defer func() { _ = parquetReader.Close() }()
+
+	arrowReader, err := pqarrow.NewFileReader(parquetReader, pqarrow.ArrowReadProperties{}, pool)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Arrow reader: %w", err)
+	}
+	schema, err := arrowReader.Schema()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Parquet schema: %w", err)
+	}
+
+	// Read the requested columns plus the ones the predicate needs
+	readColumns := opts.Columns
+	if len(readColumns) > 0 {
+		for _, col := range readColumns {
+			if !schema.HasField(col) {
+				return nil, fmt.Errorf("column not found: %s", col)
+			}
+		}
+		if predicate != nil {
+			readColumns = union(readColumns, predicate.Columns())
+		}
+	}
+	leaves := parquetLeafIndices(parquetReader.MetaData(), readColumns)
+
+	rowGroups := selectRowGroups(parquetReader.MetaData(), schema, predicate)
+
+	table, err := arrowReader.ReadRowGroups(context.Background(), leaves, rowGroups)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read table: %w", err)
+	}
+	defer table.Release()
+
+	record, err := tableToRecord(table, pool)
+	if err != nil {
+		return nil, err
+	}
+	df := NewDataFrame(record)
+	record.Release()
+
+	if predicate != nil {
+		filtered := df.Filter(predicate)
+		df.Release()
+		if filtered.Err() != nil {
+			return nil, fmt.Errorf("failed to apply filter %s: %w", predicate, filtered.Err())
+		}
+		df = filtered
+	}
+	if len(opts.Columns) > 0 {
+		selected := df.Select(opts.Columns...)
+		df.Release()
+		if selected.Err() != nil {
+			return nil, selected.Err()
+		}
+		df = selected
+	}
+	if opts.Limit > 0 && df.NumRows() > opts.Limit {
+		limited := df.Limit(opts.Limit)
+		df.Release()
+		df = limited
+	}
+	return df, nil
+}
+
+// readParquetSchema reads the Arrow schema of a Parquet file from its footer.
+func readParquetSchema(filename string) (*arrow.Schema, error) {
+	if err := validateFilePath(filename); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Parquet file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	parquetReader, err := file.NewParquetReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Parquet reader: %w", err)
+	}
+	defer func() { _ = parquetReader.Close() }()
+
+	arrowReader, err := pqarrow.NewFileReader(parquetReader, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Arrow reader: %w", err)
+	}
+	schema, err := arrowReader.Schema()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Parquet schema: %w", err)
+	}
+	return schema, nil
+}
+
+// predicateExpr returns the expression behind a storage predicate.
+func predicateExpr(predicate storage.Predicate) (expr.Expr, error) {
+	if predicate == nil {
+		return nil, nil
+	}
+	e, ok := predicate.(expr.Expr)
+	if !ok {
+		return nil, fmt.Errorf("filter must be an expr.Expr, got %T", predicate)
+	}
+	return e, nil
+}
+
+// tableToRecord combines the chunks of a table into a single record.
+func tableToRecord(table arrow.Table, pool memory.Allocator) (arrow.Record, error) {
+	columns := make([]arrow.Array, table.NumCols())
+	defer func() {
+		for _, col := range columns {
+			if col != nil {
+				col.Release()
+			}
+		}
+	}()
+
+	for i := range columns {
+		chunks := table.Column(i).Data().Chunks()
+		switch len(chunks) {
+		case 0:
+			columns[i] = array.MakeArrayOfNull(pool, table.Schema().Field(i).Type, 0)
+		case 1:
+			chunks[0].Retain()
+			columns[i] = chunks[0]
+		default:
+			combined, err := array.Concatenate(chunks, pool)
+			if err != nil {
+				return nil, fmt.Errorf("failed to combine column %s: %w", table.Schema().Field(i).Name, err)
+			}
+			columns[i] = combined
+		}
+	}
+	return array.NewRecord(table.Schema(), columns, table.NumRows()), nil
+}
+
+// parquetLeafIndices returns the Parquet leaf columns that store the given
+// top-level columns, or all leaves when columns is empty. Nested columns
+// have one leaf per primitive field.
+func parquetLeafIndices(meta *metadata.FileMetaData, columns []string) []int {
+	leaves := []int{}
+	for i := 0; i < meta.Schema.NumColumns(); i++ {
+		if len(columns) == 0 || containsString(columns, meta.Schema.Column(i).ColumnPath()[0]) {
+			leaves = append(leaves, i)
+		}
+	}
+	return leaves
+}
+
+// selectRowGroups returns the row groups that may contain rows matching the
+// predicate according to their column statistics.
+func selectRowGroups(meta *metadata.FileMetaData, schema *arrow.Schema, predicate expr.Expr) []int {
+	rowGroups := make([]int, 0, len(meta.RowGroups))
+	if predicate == nil {
+		for i := 0; i < len(meta.RowGroups); i++ {
+			rowGroups = append(rowGroups, i)
+		}
+		return rowGroups
+	}
+
+	// Statistics are only usable for top-level primitive columns
+	leaves := make(map[string]int)
+	for _, col := range predicate.Columns() {
+		idx := meta.Schema.ColumnIndexByName(col)
+		fields := schema.FieldIndices(col)
+		if idx >= 0 && len(fields) == 1 && statisticsComparable(schema.Field(fields[0]).Type) {
+			leaves[col] = idx
+		}
+	}
+
+	for i := 0; i < len(meta.RowGroups); i++ {
+		rowGroup := meta.RowGroup(i)
+		stats := make(map[string]columnStatistics, len(leaves))
+		for col, idx := range leaves {
+			if s, ok := readColumnStatistics(rowGroup, idx); ok {
+				stats[col] = s
+			}
+		}
+		if statisticsMayMatch(predicate, stats) {
+			rowGroups = append(rowGroups, i)
+		}
+	}
+	return rowGroups
+}
+
+// statisticsComparable reports whether Parquet statistics of a column of this
+// type order values the same way as the expression literals compared to it.
+func statisticsComparable(dataType arrow.DataType) bool {
+	switch dataType.ID() {
+	case arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64,
+		arrow.FLOAT32, arrow.FLOAT64, arrow.STRING:
+		return true
+	}
+	return false
+}
+
+// columnStatistics summarizes the values of one column in a row group.
+type columnStatistics struct {
+	numRows  int64
+	nulls    int64 // -1 when unknown
+	min, max interface{}
+	hasRange bool
+	// floating is set for floating point columns, whose range leaves out
+	// NaN values
+	floating bool
+}
+
+// readColumnStatistics reads the statistics of one column chunk.
+func readColumnStatistics(rowGroup *metadata.RowGroupMetaData, leaf int) (columnStatistics, bool) {
+	chunk, err := rowGroup.ColumnChunk(leaf)
+	if err != nil {
+		return columnStatistics{}, false
+	}
+	if set, err := chunk.StatsSet(); err != nil || !set {
+		return columnStatistics{}, false
+	}
+	typed, err := chunk.Statistics()
+	if err != nil || typed == nil {
+		return columnStatistics{}, false
+	}
+
+	result := columnStatistics{numRows: rowGroup.NumRows(), nulls: -1}
+	if typed.HasNullCount() {
+		result.nulls = typed.NullCount()
+	}
+	if !typed.HasMinMax() {
+		return result, true
+	}
+	result.hasRange = true
+	switch s := typed.(type) {
+	case *metadata.Int32Statistics:
+		result.min, result.max = int64(s.Min()), int64(s.Max())
+	case *metadata.Int64Statistics:
+		result.min, result.max = s.Min(), s.Max()
+	case *metadata.Float32Statistics:
+		result.min, result.max = float64(s.Min()), float64(s.Max())
+		result.floating = true
+	case *metadata.Float64Statistics:
+		result.min, result.max = s.Min(), s.Max()
+		result.floating = true
+	case *metadata.ByteArrayStatistics:
+		result.min, result.max = string(s.Min()), string(s.Max())
+	default:
+		result.hasRange = false
+	}
+	return result, true
+}
+
+// statisticsMayMatch reports whether any row described by the statistics may
+// satisfy the predicate. It returns true whenever the statistics cannot rule
+// rows out, so a false result is always safe to act on.
+func statisticsMayMatch(predicate expr.Expr, stats map[string]columnStatistics) bool {
+	children := predicate.Children()
+	switch predicate.Op() {
+	case "and":
+		return statisticsMayMatch(children[0], stats) && statisticsMayMatch(children[1], stats)
+	case "or":
+		return statisticsMayMatch(children[0], stats) || statisticsMayMatch(children[1], stats)
+	case "is_null", "is_not_null":
+		s, ok := columnStatisticsFor(children[0], stats)
+		if !ok || s.nulls < 0 {
+			return true
+		}
+		if predicate.Op() == "is_null" {
+			return s.nulls > 0
+		}
+		return s.nulls < s.numRows
+	case "equal", "not_equal", "greater", "greater_equal", "less", "less_equal":
+		return comparisonMayMatch(predicate.Op(), children[0], children[1], stats)
+	}
+	return true
+}
+
+// comparisonMayMatch checks a column-to-literal comparison against the
+// column's value range.
+func comparisonMayMatch(op string, left, right expr.Expr, stats map[string]columnStatistics) bool {
+	s, ok := columnStatisticsFor(left, stats)
+	value, isLiteral := literalValue(right)
+	if !ok || !isLiteral {
+		// Try the mirrored comparison, e.g. 5 < x as x > 5
+		s, ok = columnStatisticsFor(right, stats)
+		value, isLiteral = literalValue(left)
+		if !ok || !isLiteral {
+			return true
+		}
+		op = mirroredComparisons[op]
+	}
+
+	// Comparisons with null are never true
+	if s.nulls == s.numRows {
+		return false
+	}
+	if !s.hasRange {
+		return true
+	}
+	minCmp, ok1 := compareStatisticValues(s.min, value)
+	maxCmp, ok2 := compareStatisticValues(s.max, value)
+	if !ok1 || !ok2 {
+		return true
+	}
+
+	switch op {
+	case "equal":
+		return minCmp <= 0 && maxCmp >= 0
+	case "not_equal":
+		// NaN differs from every value, but is not part of the range
+		return minCmp != 0 || maxCmp != 0 || s.floating
+	case "greater":
+		return maxCmp > 0
+	case "greater_equal":
+		return maxCmp >= 0
+	case "less":
+		return minCmp < 0
+	case "less_equal":
+		return minCmp <= 0
+	}
+	return true
+}
+
+var mirroredComparisons = map[string]string{
+	"equal":         "equal",
+	"not_equal":     "not_equal",
+	"greater":       "less",
+	"greater_equal": "less_equal",
+	"less":          "greater",
+	"less_equal":    "greater_equal",
+}
+
+// columnStatisticsFor returns the statistics of a column reference.
+func columnStatisticsFor(e expr.Expr, stats map[string]columnStatistics) (columnStatistics, bool) {
+	if e.Op() != expr.OpColumn {
+		return columnStatistics{}, false
+	}
+	s, ok := stats[e.Columns()[0]]
+	return s, ok
+}
+
+// literalValue returns a literal's value as int64, float64 or string.
+func literalValue(e expr.Expr) (interface{}, bool) {
+	lit, ok := e.(*expr.LiteralExpr)
+	if !ok {
+		return nil, false
+	}
+	switch v := lit.Value().(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		return v, true
+	}
+	return nil, false
+}
+
+// compareStatisticValues compares two int64, float64 or string values.
+func compareStatisticValues(a, b interface{}) (int, bool) {
+	switch x := a.(type) {
+	case int64:
+		switch y := b.(type) {
+		case int64:
+			return cmp.Compare(x, y), true
+		case float64:
+			return cmp.Compare(float64(x), y), true
+		}
+	case float64:
+		switch y := b.(type) {
+		case int64:
+			return cmp.Compare(x, float64(y)), true
+		case float64:
+			return cmp.Compare(x, y), true
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), true
+		}
+	}
+	return 0, false
+}
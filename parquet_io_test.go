@@ -0,0 +1,270 @@
+package gopherframe
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/felixgeelhaar/GopherFrame/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeRowGroupedParquet writes 9 rows in 3 row groups of 3 rows:
+// id 1-9, region a/b/c per group, and a score that is null in the last group.
+func writeRowGroupedParquet(t *testing.T) string {
+	t.Helper()
+	pool := memory.NewGoAllocator()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "region", Type: arrow.BinaryTypes.String},
+		{Name: "score", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	}, nil)
+
+	builder := array.NewRecordBuilder(pool, schema)
+	defer builder.Release()
+	builder.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2, 3, 4, 5, 6, 7, 8, 9}, nil)
+	builder.Field(1).(*array.StringBuilder).AppendValues([]string{"a", "a", "a", "b", "b", "b", "c", "c", "c"}, nil)
+	builder.Field(2).(*array.Float64Builder).AppendValues(
+		[]float64{0.5, 1.5, 2.5, 3.5, 4.5, 5.5, 0, 0, 0},
+		[]bool{true, true, true, true, true, true, false, false, false})
+	record := builder.NewRecord()
+	defer record.Release()
+
+	filename := filepath.Join(t.TempDir(), "grouped.parquet")
+	f, err := os.Create(filename)
+	require.NoError(t, err)
+	writer, err := pqarrow.NewFileWriter(schema, f,
+		parquet.NewWriterProperties(parquet.WithMaxRowGroupLength(3)), pqarrow.DefaultWriterProps())
+	require.NoError(t, err)
+	require.NoError(t, writer.Write(record))
+	require.NoError(t, writer.Close())
+	return filename
+}
+
+func readRowGroupsFor(t *testing.T, filename string, predicate storage.Predicate) []int {
+	t.Helper()
+	reader, err := file.OpenParquetFile(filename, false)
+	require.NoError(t, err)
+	defer func() { _ = reader.Close() }()
+	arrowReader, err := pqarrow.NewFileReader(reader, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	require.NoError(t, err)
+	schema, err := arrowReader.Schema()
+	require.NoError(t, err)
+	e, err := predicateExpr(predicate)
+	require.NoError(t, err)
+	return selectRowGroups(reader.MetaData(), schema, e)
+}
+
+func TestReadParquetWithOptions_Projection(t *testing.T) {
+	filename := writeRowGroupedParquet(t)
+
+	df, err := ReadParquetWithOptions(filename, storage.ReadOptions{Columns: []string{"score", "id"}})
+	require.NoError(t, err)
+	defer df.Release()
+
+	assert.Equal(t, []string{"score", "id"}, df.ColumnNames())
+	assert.Equal(t, int64(9), df.NumRows())
+
+	_, err = ReadParquetWithOptions(filename, storage.ReadOptions{Columns: []string{"missing"}})
+	assert.Error(t, err)
+}
+
+func TestReadParquetWithOptions_SkipsRowGroupsByStatistics(t *testing.T) {
+	filename := writeRowGroupedParquet(t)
+
+	tests := []struct {
+		name      string
+		predicate storage.Predicate
+		want      []int
+	}{
+		{"range on int", Col("id").Gt(Lit(int64(4))), []int{1, 2}},
+		{"mirrored literal", Lit(int64(4)).Gt(Col("id")), []int{0}},
+		{"string equality", Col("region").Eq(Lit("b")), []int{1}},
+		{"float vs int literal", Col("score").Ge(Lit(int64(3))), []int{1}},
+		{"or", Col("id").Eq(Lit(int64(1))).Or(Col("id").Eq(Lit(int64(9)))), []int{0, 2}},
+		{"and", Col("id").Gt(Lit(int64(2))).And(Col("region").Eq(Lit("a"))), []int{0}},
+		{"is null", Col("score").IsNull(), []int{2}},
+		{"is not null", Col("score").IsNotNull(), []int{0, 1}},
+		{"no match", Col("id").Gt(Lit(int64(100))), []int{}},
+		{"not prunable", Col("id").Add(Lit(int64(1))).Gt(Lit(int64(100))), []int{0, 1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, readRowGroupsFor(t, filename, tt.predicate))
+		})
+	}
+}
+
+func TestReadParquetWithOptions_NotEqualKeepsNaN(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "x", Type: arrow.PrimitiveTypes.Float64},
+	}, nil)
+	builder := array.NewRecordBuilder(pool, schema)
+	defer builder.Release()
+	builder.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 1, 2, 2}, nil)
+	builder.Field(1).(*array.Float64Builder).AppendValues([]float64{5, math.NaN(), 6, 7}, nil)
+	record := builder.NewRecord()
+	defer record.Release()
+
+	filename := filepath.Join(t.TempDir(), "nan.parquet")
+	f, err := os.Create(filename)
+	require.NoError(t, err)
+	writer, err := pqarrow.NewFileWriter(schema, f,
+		parquet.NewWriterProperties(parquet.WithMaxRowGroupLength(2)), pqarrow.DefaultWriterProps())
+	require.NoError(t, err)
+	require.NoError(t, writer.Write(record))
+	require.NoError(t, writer.Close())
+
+	// The statistics of the first row group leave out NaN, so its range is
+	// only 5, but NaN != 5 is true
+	assert.Equal(t, []int{0, 1}, readRowGroupsFor(t, filename, Col("x").Ne(Lit(5.0))))
+	assert.Equal(t, []int{1}, readRowGroupsFor(t, filename, Col("id").Ne(Lit(int64(1)))))
+
+	df, err := ReadParquetWithOptions(filename, storage.ReadOptions{Filter: Col("x").Ne(Lit(5.0))})
+	require.NoError(t, err)
+	defer df.Release()
+	assert.Equal(t, []int64{1, 2, 2}, df.Record().Column(0).(*array.Int64).Int64Values())
+}
+
+func TestReadParquetWithOptions_Filter(t *testing.T) {
+	filename := writeRowGroupedParquet(t)
+
+	df, err := ReadParquetWithOptions(filename, storage.ReadOptions{
+		Columns: []string{"region"},
+		Filter:  Col("id").Gt(Lit(int64(4))).And(Col("id").Lt(Lit(int64(8)))),
+	})
+	require.NoError(t, err)
+	defer df.Release()
+
+	assert.Equal(t, []string{"region"}, df.ColumnNames(), "predicate-only columns are dropped")
+	require.Equal(t, int64(3), df.NumRows())
+	regions := df.Record().Column(0).(*array.String)
+	assert.Equal(t, []string{"b", "b", "c"}, []string{regions.Value(0), regions.Value(1), regions.Value(2)})
+
+	empty, err := ReadParquetWithOptions(filename, storage.ReadOptions{Filter: Col("id").Gt(Lit(int64(100)))})
+	require.NoError(t, err)
+	defer empty.Release()
+	assert.Equal(t, int64(0), empty.NumRows())
+	assert.Equal(t, []string{"id", "region", "score"}, empty.ColumnNames())
+
+	limited, err := ReadParquetWithOptions(filename, storage.ReadOptions{Filter: Col("id").Gt(Lit(int64(2))), Limit: 2})
+	require.NoError(t, err)
+	defer limited.Release()
+	assert.Equal(t, []int64{3, 4}, limited.Record().Column(0).(*array.Int64).Int64Values())
+}
+
+func TestReadParquet_ReadsAllRowGroups(t *testing.T) {
+	filename := writeRowGroupedParquet(t)
+
+	df, err := ReadParquet(filename)
+	require.NoError(t, err)
+	defer df.Release()
+
+	assert.Equal(t, int64(9), df.NumRows())
+	assert.Equal(t, 3, df.Record().Column(2).NullN())
+}
+
+func TestScanParquet_PushesSelectAndFilterIntoScan(t *testing.T) {
+	filename := writeRowGroupedParquet(t)
+
+	lf := ScanParquet(filename).
+		Filter(Col("region").Eq(Lit("b"))).
+		WithColumn("double", Col("score").Mul(Lit(2.0))).
+		Select("id", "double")
+
+	plan := optimizePlan(lf.plan)
+	assert.Empty(t, findPlanNodes[*filterNode](plan), "filter is evaluated by the scan")
+	scans := findPlanNodes[*parquetScanNode](plan)
+	require.Len(t, scans, 1)
+	assert.Equal(t, []string{"id", "score"}, scans[0].projection)
+	require.NotNil(t, scans[0].predicate)
+	assert.Equal(t, []string{"region"}, scans[0].predicate.Columns())
+	assert.Contains(t, lf.Explain(), "ParquetScan "+filename+" [id, score] filter=")
+
+	result := lf.Collect()
+	require.NoError(t, result.Err())
+	defer result.Release()
+
+	assert.Equal(t, []string{"id", "double"}, result.ColumnNames())
+	assert.Equal(t, []int64{4, 5, 6}, result.Record().Column(0).(*array.Int64).Int64Values())
+	assert.Equal(t, []float64{7, 9, 11}, result.Record().Column(1).(*array.Float64).Float64Values())
+
+	assert.Error(t, ScanParquet(filepath.Join(t.TempDir(), "missing.parquet")).Collect().Err())
+}
+
+func TestWriteParquetWithOptions(t *testing.T) {
+	df, err := ReadParquet(writeRowGroupedParquet(t))
+	require.NoError(t, err)
+	defer df.Release()
+
+	filename := filepath.Join(t.TempDir(), "options.parquet")
+	err = WriteParquetWithOptions(df, filename, ParquetWriteOptions{
+		Compression:       "zstd",
+		CompressionLevel:  3,
+		RowGroupSize:      4,
+		Dictionary:        map[string]bool{"id": false},
+		DisableStatistics: true,
+		Metadata:          map[string]string{"source": "test"},
+	})
+	require.NoError(t, err)
+
+	reader, err := file.OpenParquetFile(filename, false)
+	require.NoError(t, err)
+	defer func() { _ = reader.Close() }()
+	meta := reader.MetaData()
+
+	assert.Equal(t, 3, len(meta.RowGroups), "9 rows in groups of at most 4")
+	rowGroup := meta.RowGroup(0)
+	for i := 0; i < rowGroup.NumColumns(); i++ {
+		column, err := rowGroup.ColumnChunk(i)
+		require.NoError(t, err)
+		assert.Equal(t, compress.Codecs.Zstd, column.Compression())
+		statsSet, err := column.StatsSet()
+		require.NoError(t, err)
+		assert.False(t, statsSet)
+		assert.Equal(t, column.PathInSchema().String() != "id", column.HasDictionaryPage(), column.PathInSchema().String())
+	}
+	source := meta.KeyValueMetadata().FindValue("source")
+	require.NotNil(t, source)
+	assert.Equal(t, "test", *source)
+
+	roundTrip, err := ReadParquet(filename)
+	require.NoError(t, err)
+	defer roundTrip.Release()
+	assert.Equal(t, df.NumRows(), roundTrip.NumRows())
+	assert.Equal(t, df.ColumnNames(), roundTrip.ColumnNames())
+
+	err = WriteParquetWithOptions(df, filepath.Join(t.TempDir(), "bad.parquet"), ParquetWriteOptions{Compression: "lzo"})
+	assert.ErrorIs(t, err, storage.ErrUnsupportedOperation)
+}
+
+func TestNewParquetStreamWriterWithOptions(t *testing.T) {
+	df, err := ReadParquet(writeRowGroupedParquet(t))
+	require.NoError(t, err)
+	defer df.Release()
+
+	filename := filepath.Join(t.TempDir(), "stream.parquet")
+	w, err := NewParquetStreamWriterWithOptions(filename, df.Schema(), ParquetWriteOptions{Compression: "snappy", RowGroupSize: 5})
+	require.NoError(t, err)
+	require.NoError(t, w.Write(df))
+	require.NoError(t, w.Close())
+
+	reader, err := file.OpenParquetFile(filename, false)
+	require.NoError(t, err)
+	defer func() { _ = reader.Close() }()
+	assert.Equal(t, 2, len(reader.MetaData().RowGroups))
+	column, err := reader.MetaData().RowGroup(0).ColumnChunk(0)
+	require.NoError(t, err)
+	assert.Equal(t, compress.Codecs.Snappy, column.Compression())
+}
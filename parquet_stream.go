@@ -0,0 +1,301 @@
+package gopherframe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/felixgeelhaar/GopherFrame/pkg/core"
+	"github.com/felixgeelhaar/GopherFrame/pkg/storage"
+)
+
+// ReadParquetStreaming reads a Parquet file as a stream of DataFrames of at
+// most batchSize rows. Row groups are decoded one batch at a time and a batch
+// never spans two row groups, so memory use is bounded by the batch size
+// rather than the file size. Each chunk must be released by the consumer.
+//
+// Example:
+//
+//	sr, err := ReadParquetStreaming("events.parquet", 100_000)
+//	if err != nil {
+//	    return err
+//	}
+//	for chunk := range sr.Chunks() {
+//	    process(chunk)
+//	    chunk.Release()
+//	}
+//	if err := sr.Err(); err != nil {
+//	    return err
+//	}
+func ReadParquetStreaming(filename string, batchSize int) (*StreamingReader, error) {
+	return ReadParquetStreamingWithAllocator(filename, batchSize, memory.DefaultAllocator)
+}
+
+// ReadParquetStreamingWithAllocator is ReadParquetStreaming with batches
+// allocated from pool. With a core.LimitedAllocator, each batch is checked
+// against the limit before it is decoded; a batch that does not fit stops the
+// stream with a *core.ErrMemoryLimitExceeded error.
+func ReadParquetStreamingWithAllocator(filename string, batchSize int, pool memory.Allocator) (*StreamingReader, error) {
+	if err := validateFilePath(filename); err != nil {
+		return nil, err
+	}
+	if batchSize <= 0 {
+		return nil, fmt.Errorf("batch size must be positive")
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Parquet file: %w", err)
+	}
+	parquetReader, err := file.NewParquetReader(f, file.WithReadProps(parquet.NewReaderProperties(pool)))
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("failed to create Parquet reader: %w", err)
+	}
+	arrowReader, err := pqarrow.NewFileReader(parquetReader, pqarrow.ArrowReadProperties{BatchSize: int64(batchSize)}, pool)
+	if err != nil {
+		_ = parquetReader.Close()
+		_ = f.Close()
+		return nil, fmt.Errorf("failed to create Arrow reader: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sr := &StreamingReader{
+		chunkCh:  make(chan *DataFrame, 1),
+		errCh:    make(chan error, 1),
+		cancelFn: cancel,
+	}
+
+	go func() {
+		defer close(sr.chunkCh)
+		defer func() {
+			_ = parquetReader.Close()
+			_ = f.Close()
+		}()
+
+		meta := parquetReader.MetaData()
+		for rg := 0; rg < parquetReader.NumRowGroups(); rg++ {
+			rowGroup := meta.RowGroup(rg)
+			estimate := estimateBatchBytes(rowGroup.TotalByteSize(), rowGroup.NumRows(), batchSize)
+			if err := streamParquetRowGroup(ctx, arrowReader, rg, pool, estimate, sr.chunkCh); err != nil {
+				sr.errCh <- err
+				return
+			}
+			if ctx.Err() != nil {
+				return
+			}
+		}
+	}()
+
+	return sr, nil
+}
+
+// streamParquetRowGroup sends the batches of one row group to out.
+func streamParquetRowGroup(ctx context.Context, arrowReader *pqarrow.FileReader, rowGroup int, pool memory.Allocator, estimate int64, out chan<- *DataFrame) error {
+	recordReader, err := arrowReader.GetRecordReader(ctx, nil, []int{rowGroup})
+	if err != nil {
+		return fmt.Errorf("failed to read row group %d: %w", rowGroup, err)
+	}
+	defer recordReader.Release()
+
+	for {
+		if err := reserveMemory(pool, estimate); err != nil {
+			return fmt.Errorf("failed to read row group %d: %w", rowGroup, err)
+		}
+		var ok bool
+		err := guardAllocation(pool, func() error {
+			ok = recordReader.Next()
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to read row group %d: %w", rowGroup, err)
+		}
+		if !ok {
+			if err := recordReader.Err(); err != nil && !errors.Is(err, io.EOF) {
+				return fmt.Errorf("failed to read row group %d: %w", rowGroup, err)
+			}
+			return nil
+		}
+
+		// NewDataFrame retains the record beyond the reader's next batch
+		df := NewDataFrame(recordReader.Record())
+		select {
+		case <-ctx.Done():
+			df.Release()
+			return nil
+		case out <- df:
+		}
+	}
+}
+
+// ParquetStreamWriter writes DataFrames to a Parquet file one at a time, so a
+// file can be produced from data that never fits in memory at once. Every
+// DataFrame passed to Write becomes one or more row groups. Close must be
+// called to write the file footer.
+//
+// Example:
+//
+//	w, err := NewParquetStreamWriter("out.parquet", schema)
+//	if err != nil {
+//	    return err
+//	}
+//	for chunk := range sr.Chunks() {
+//	    if err := w.Write(chunk); err != nil {
+//	        _ = w.Close()
+//	        return err
+//	    }
+//	    chunk.Release()
+//	}
+//	return w.Close()
+type ParquetStreamWriter struct {
+	writer *pqarrow.FileWriter
+	schema *arrow.Schema
+	pool   memory.Allocator
+	rows   int64
+	closed bool
+}
+
+// NewParquetStreamWriter creates a Parquet file for DataFrames with the given schema.
+func NewParquetStreamWriter(filename string, schema *arrow.Schema) (*ParquetStreamWriter, error) {
+	return NewParquetStreamWriterWithAllocator(filename, schema, memory.DefaultAllocator)
+}
+
+// NewParquetStreamWriterWithAllocator is NewParquetStreamWriter with encoding
+// buffers allocated from pool. With a core.LimitedAllocator, Write checks each
+// DataFrame against the limit before encoding it.
+func NewParquetStreamWriterWithAllocator(filename string, schema *arrow.Schema, pool memory.Allocator) (*ParquetStreamWriter, error) {
+	return newParquetStreamWriter(filename, schema, pool, ParquetWriteOptions{})
+}
+
+// NewParquetStreamWriterWithOptions is NewParquetStreamWriter with the file
+// encoded as described by opts. A DataFrame with more than opts.RowGroupSize
+// rows is split into several row groups.
+func NewParquetStreamWriterWithOptions(filename string, schema *arrow.Schema, opts ParquetWriteOptions) (*ParquetStreamWriter, error) {
+	return newParquetStreamWriter(filename, schema, memory.DefaultAllocator, opts)
+}
+
+func newParquetStreamWriter(filename string, schema *arrow.Schema, pool memory.Allocator, opts ParquetWriteOptions) (*ParquetStreamWriter, error) {
+	if err := validateFilePath(filename); err != nil {
+		return nil, err
+	}
+	if schema == nil {
+		return nil, fmt.Errorf("schema cannot be nil")
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output file: %w", err)
+	}
+	writer, err := storage.NewParquetWriter(schema, f, pool, opts)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	return &ParquetStreamWriter{writer: writer, schema: schema, pool: pool}, nil
+}
+
+// Write appends the rows of df to the file. df must have the writer's schema.
+func (w *ParquetStreamWriter) Write(df *DataFrame) error {
+	if w.closed {
+		return fmt.Errorf("parquet stream writer is closed")
+	}
+	if df.Err() != nil {
+		return fmt.Errorf("DataFrame has error: %w", df.Err())
+	}
+	if !df.Schema().Equal(w.schema) {
+		return fmt.Errorf("schema mismatch: writer expects %s, got %s", w.schema, df.Schema())
+	}
+
+	record := df.Record()
+	if err := reserveMemory(w.pool, recordBytes(record)); err != nil {
+		return fmt.Errorf("failed to write DataFrame: %w", err)
+	}
+	err := guardAllocation(w.pool, func() error {
+		return w.writer.Write(record)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write DataFrame: %w", err)
+	}
+	w.rows += record.NumRows()
+	return nil
+}
+
+// RowsWritten returns the number of rows written so far.
+func (w *ParquetStreamWriter) RowsWritten() int64 {
+	return w.rows
+}
+
+// Close writes the file footer and closes the file. It is safe to call more than once.
+func (w *ParquetStreamWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	// Closing the Parquet writer also closes the file
+	if err := w.writer.Close(); err != nil {
+		return fmt.Errorf("failed to close Parquet writer: %w", err)
+	}
+	return nil
+}
+
+// estimateBatchBytes estimates the decoded size of batchSize rows of a row group.
+func estimateBatchBytes(rowGroupBytes, rowGroupRows int64, batchSize int) int64 {
+	if rowGroupRows <= 0 {
+		return 0
+	}
+	rows := min(int64(batchSize), rowGroupRows)
+	return (rowGroupBytes*rows + rowGroupRows - 1) / rowGroupRows
+}
+
+// recordBytes returns the size of the buffers backing a record.
+func recordBytes(record arrow.Record) int64 {
+	var total int64
+	for _, col := range record.Columns() {
+		for _, buf := range col.Data().Buffers() {
+			if buf != nil {
+				total += int64(buf.Len())
+			}
+		}
+	}
+	return total
+}
+
+// reserveMemory checks that size bytes can be allocated from a
+// core.LimitedAllocator. Other allocators are not checked.
+func reserveMemory(pool memory.Allocator, size int64) error {
+	if limited, ok := pool.(*core.LimitedAllocator); ok {
+		return limited.CheckCanAllocate(size)
+	}
+	return nil
+}
+
+// guardAllocation runs fn and reports a refused allocation as an error.
+// core.LimitedAllocator refuses allocations over its limit by returning nil,
+// which makes Arrow builders panic. A panic is only reported as
+// core.ErrMemoryLimitExceeded when the allocator refused an allocation while
+// fn ran; other panics, and panics with any other allocator, propagate.
+func guardAllocation(pool memory.Allocator, fn func() error) (err error) {
+	limited, ok := pool.(*core.LimitedAllocator)
+	if !ok {
+		return fn()
+	}
+	refusals := limited.Refusals()
+	defer func() {
+		if r := recover(); r != nil {
+			if limited.Refusals() == refusals {
+				panic(r)
+			}
+			err = &core.ErrMemoryLimitExceeded{Limit: limited.Limit(), Current: limited.AllocatedBytes()}
+		}
+	}()
+	return fn()
+}
@@ -0,0 +1,143 @@
+package gopherframe
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/felixgeelhaar/GopherFrame/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadParquetStreaming_BatchesPerRowGroup(t *testing.T) {
+	filename := writeRowGroupedParquet(t)
+
+	sr, err := ReadParquetStreaming(filename, 2)
+	require.NoError(t, err)
+
+	var sizes []int64
+	var ids []int64
+	for chunk := range sr.Chunks() {
+		sizes = append(sizes, chunk.NumRows())
+		ids = append(ids, chunk.Record().Column(0).(*array.Int64).Int64Values()...)
+		chunk.Release()
+	}
+	require.NoError(t, sr.Err())
+
+	// Row groups hold 3 rows, and batches never span row groups
+	assert.Equal(t, []int64{2, 1, 2, 1, 2, 1}, sizes)
+	assert.Equal(t, []int64{1, 2, 3, 4, 5, 6, 7, 8, 9}, ids)
+}
+
+func TestReadParquetStreaming_Errors(t *testing.T) {
+	filename := writeRowGroupedParquet(t)
+
+	_, err := ReadParquetStreaming(filename, 0)
+	assert.Error(t, err)
+	_, err = ReadParquetStreaming(filepath.Join(t.TempDir(), "missing.parquet"), 10)
+	assert.Error(t, err)
+
+	// A batch that does not fit the allocator's limit stops the stream
+	limited := core.NewLimitedAllocator(memory.NewGoAllocator(), 16)
+	sr, err := ReadParquetStreamingWithAllocator(filename, 3, limited)
+	require.NoError(t, err)
+	for chunk := range sr.Chunks() {
+		chunk.Release()
+	}
+	var limitErr *core.ErrMemoryLimitExceeded
+	assert.True(t, errors.As(sr.Err(), &limitErr), "got %v", sr.Err())
+}
+
+func TestReadParquetStreaming_Cancel(t *testing.T) {
+	filename := writeRowGroupedParquet(t)
+
+	sr, err := ReadParquetStreaming(filename, 1)
+	require.NoError(t, err)
+
+	first := <-sr.Chunks()
+	require.NotNil(t, first)
+	first.Release()
+	sr.Cancel()
+
+	// The producer stops; drain whatever was already buffered
+	for chunk := range sr.Chunks() {
+		chunk.Release()
+	}
+	assert.NoError(t, sr.Err())
+}
+
+func TestParquetStreamWriter(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	first := createTestDataFrame(pool, map[string]interface{}{
+		"id":    []int64{1, 2, 3},
+		"score": []float64{1.5, 2.5, 3.5},
+	})
+	defer first.Release()
+	second := createTestDataFrame(pool, map[string]interface{}{
+		"id":    []int64{4, 5},
+		"score": []float64{4.5, 5.5},
+	})
+	defer second.Release()
+
+	filename := filepath.Join(t.TempDir(), "stream.parquet")
+	w, err := NewParquetStreamWriter(filename, first.Schema())
+	require.NoError(t, err)
+	require.NoError(t, w.Write(first))
+	require.NoError(t, w.Write(second))
+	assert.Equal(t, int64(5), w.RowsWritten())
+
+	mismatched := createTestDataFrame(pool, map[string]interface{}{"id": []int64{6}})
+	defer mismatched.Release()
+	assert.Error(t, w.Write(mismatched))
+
+	require.NoError(t, w.Close())
+	require.NoError(t, w.Close())
+	assert.Error(t, w.Write(first), "writing after Close fails")
+
+	// Each DataFrame is its own row group
+	reader, err := file.OpenParquetFile(filename, false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, reader.NumRowGroups())
+	require.NoError(t, reader.Close())
+
+	df, err := ReadParquet(filename)
+	require.NoError(t, err)
+	defer df.Release()
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, df.Record().Column(0).(*array.Int64).Int64Values())
+}
+
+func TestParquetStreamWriter_LimitedAllocator(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	df := createTestDataFrame(pool, map[string]interface{}{"id": []int64{1, 2, 3, 4}})
+	defer df.Release()
+
+	limited := core.NewLimitedAllocator(memory.NewGoAllocator(), 8)
+	w, err := NewParquetStreamWriterWithAllocator(filepath.Join(t.TempDir(), "limited.parquet"), df.Schema(), limited)
+	require.NoError(t, err)
+	defer func() { _ = w.Close() }()
+
+	var limitErr *core.ErrMemoryLimitExceeded
+	assert.True(t, errors.As(w.Write(df), &limitErr))
+	assert.Equal(t, int64(0), w.RowsWritten())
+}
+
+func TestGuardAllocation_OtherPanicsPropagate(t *testing.T) {
+	limited := core.NewLimitedAllocator(memory.NewGoAllocator(), 1024)
+
+	assert.PanicsWithValue(t, "boom", func() {
+		_ = guardAllocation(limited, func() error { panic("boom") })
+	})
+
+	err := guardAllocation(limited, func() error {
+		builder := array.NewInt64Builder(limited)
+		defer builder.Release()
+		builder.AppendValues(make([]int64, 1024), nil)
+		return nil
+	})
+	var limitErr *core.ErrMemoryLimitExceeded
+	assert.True(t, errors.As(err, &limitErr))
+}
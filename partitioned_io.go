@@ -1,64 +1,206 @@
 package gopherframe
 
 import (
+	"context"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/compute"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/arrow/scalar"
 )
 
+// PartitionFormat is the file format of the data files of a partitioned
+// dataset.
+type PartitionFormat int
+
+const (
+	// PartitionFormatCSV writes CSV files with a header.
+	PartitionFormatCSV PartitionFormat = iota
+	// PartitionFormatParquet writes Parquet files.
+	PartitionFormatParquet
+	// PartitionFormatArrowIPC writes Arrow IPC files.
+	PartitionFormatArrowIPC
+)
+
+// String returns the name of the format.
+func (f PartitionFormat) String() string {
+	switch f {
+	case PartitionFormatCSV:
+		return "csv"
+	case PartitionFormatParquet:
+		return "parquet"
+	case PartitionFormatArrowIPC:
+		return "arrow-ipc"
+	default:
+		return fmt.Sprintf("PartitionFormat(%d)", int(f))
+	}
+}
+
+// extension returns the file extension of data files in the format.
+func (f PartitionFormat) extension() string {
+	switch f {
+	case PartitionFormatParquet:
+		return ".parquet"
+	case PartitionFormatArrowIPC:
+		return ".arrow"
+	default:
+		return ".csv"
+	}
+}
+
+// PartitionWriteMode decides what happens to the existing data files of the
+// partitions that a write adds rows to.
+type PartitionWriteMode int
+
+const (
+	// PartitionOverwrite replaces the data files of every partition that is
+	// written. Partitions without rows in the DataFrame are left untouched.
+	PartitionOverwrite PartitionWriteMode = iota
+	// PartitionAppend adds new data files next to the existing ones.
+	PartitionAppend
+)
+
+// String returns the name of the mode.
+func (m PartitionWriteMode) String() string {
+	switch m {
+	case PartitionOverwrite:
+		return "overwrite"
+	case PartitionAppend:
+		return "append"
+	default:
+		return fmt.Sprintf("PartitionWriteMode(%d)", int(m))
+	}
+}
+
+// PartitionWriteOptions configures WritePartitionedWithOptions.
+type PartitionWriteOptions struct {
+	// Format is the format of the data files. The zero value writes CSV.
+	Format PartitionFormat
+	// MaxRowsPerFile splits partitions with more rows into several files.
+	// 0 writes one file per partition.
+	MaxRowsPerFile int
+	// Mode decides whether existing data files of the written partitions
+	// are replaced or kept. The zero value replaces them.
+	Mode PartitionWriteMode
+}
+
+// PartitionReadOptions configures ReadPartitionedWithOptions.
+type PartitionReadOptions struct {
+	// PartitionTypes sets the types of partition columns. Columns that are
+	// not listed are int64 if every value is an integer, date32 if every
+	// value is a date like 2006-01-02, and string otherwise.
+	PartitionTypes map[string]arrow.DataType
+}
+
+// hiveDefaultPartition is the directory value of null partition values.
+const hiveDefaultPartition = "__HIVE_DEFAULT_PARTITION__"
+
 // WritePartitioned writes a DataFrame to partitioned CSV files organized by partition columns.
-// Creates a directory structure like: basePath/col1=val1/col2=val2/data.csv
+// Creates a directory structure like: basePath/col1=val1/col2=val2/part-00000.csv
 func WritePartitioned(df *DataFrame, basePath string, partitionCols []string) error {
+	return WritePartitionedWithOptions(df, basePath, partitionCols, PartitionWriteOptions{})
+}
+
+// WritePartitionedWithOptions writes a DataFrame as a Hive-style partitioned
+// dataset: the rows of each combination of partition values are written to
+// basePath/col1=val1/col2=val2/ as files named part-00000 and up, in the
+// format given by opts, without the partition columns. Partition names and
+// values are URL-escaped, so values may contain '/', '=' and other
+// characters that are not allowed in paths, and null values are written as
+// __HIVE_DEFAULT_PARTITION__.
+//
+// Example:
+//
+//	err := WritePartitionedWithOptions(events, "events", []string{"date", "country"}, PartitionWriteOptions{
+//	    Format:         PartitionFormatParquet,
+//	    MaxRowsPerFile: 1_000_000,
+//	    Mode:           PartitionAppend,
+//	})
+func WritePartitionedWithOptions(df *DataFrame, basePath string, partitionCols []string, opts PartitionWriteOptions) error {
 	if df.err != nil {
 		return df.err
 	}
 	if err := validateFilePath(basePath); err != nil {
 		return err
 	}
-	for _, col := range partitionCols {
-		if !df.HasColumn(col) {
-			return fmt.Errorf("partition column not found: %s", col)
-		}
+	if opts.Format < PartitionFormatCSV || opts.Format > PartitionFormatArrowIPC {
+		return fmt.Errorf("unknown partition format: %s", opts.Format)
+	}
+	if opts.Mode < PartitionOverwrite || opts.Mode > PartitionAppend {
+		return fmt.Errorf("unknown partition write mode: %s", opts.Mode)
+	}
+	if opts.MaxRowsPerFile < 0 {
+		return fmt.Errorf("max rows per file cannot be negative")
 	}
 
 	record := df.coreDF.Record()
 	schema := record.Schema()
-	numRows := int(record.NumRows())
-
-	// Group rows by partition key
-	type partKey string
-	partitions := make(map[partKey][]int)
-	partPaths := make(map[partKey]string)
+	partitionArrs := make([]arrow.Array, len(partitionCols))
+	for i, col := range partitionCols {
+		idx := findColIdx(schema, col)
+		if idx < 0 {
+			return fmt.Errorf("partition column not found: %s", col)
+		}
+		partitionArrs[i] = record.Column(idx)
+	}
+	var dataCols []string
+	for _, field := range schema.Fields() {
+		if !slices.Contains(partitionCols, field.Name) {
+			dataCols = append(dataCols, field.Name)
+		}
+	}
+	if len(dataCols) == 0 {
+		return fmt.Errorf("at least one column must not be a partition column")
+	}
 
-	for i := 0; i < numRows; i++ {
-		var parts []string
-		for _, col := range partitionCols {
-			idx := findColIdx(schema, col)
-			arr := record.Column(idx)
-			val := getStringValue(arr, i)
-			parts = append(parts, fmt.Sprintf("%s=%s", col, val))
+	// Group rows by partition directory
+	partitions := make(map[string][]int)
+	for i := 0; i < int(record.NumRows()); i++ {
+		segments := make([]string, len(partitionCols))
+		for j, col := range partitionCols {
+			value := hiveDefaultPartition
+			if partitionArrs[j].IsValid(i) {
+				value = escapePartitionValue(partitionArrs[j].ValueStr(i))
+			}
+			segments[j] = escapePartitionValue(col) + "=" + value
 		}
-		key := partKey(strings.Join(parts, "/"))
+		key := strings.Join(segments, "/")
 		partitions[key] = append(partitions[key], i)
-		partPaths[key] = filepath.Join(basePath, string(key))
 	}
 
-	// Write each partition
-	for key, rowIndices := range partitions {
-		dirPath := partPaths[key]
+	data := df.Select(dataCols...)
+	if data.err != nil {
+		return data.err
+	}
+	defer data.Release()
+	dataRecord := data.coreDF.Record()
+
+	for _, key := range sortedKeys(partitions) {
+		dirPath := filepath.Join(basePath, filepath.FromSlash(key))
 		if err := os.MkdirAll(dirPath, 0750); err != nil {
 			return fmt.Errorf("failed to create partition directory: %w", err)
 		}
-
-		// Build a sub-DataFrame from the selected rows (excluding partition columns)
-		partDF := df.selectRows(rowIndices, partitionCols)
-		if partDF.err != nil {
-			return partDF.err
+		part, err := preparePartitionDir(dirPath, opts.Mode)
+		if err != nil {
+			return err
 		}
 
-		filePath := filepath.Join(dirPath, "data.csv")
-		if err := WriteCSV(partDF, filePath); err != nil {
+		rows, err := takeRows(dataRecord, partitions[key])
+		if err != nil {
+			return fmt.Errorf("failed to select rows of partition %s: %w", key, err)
+		}
+		err = writePartitionFiles(rows, dirPath, part, opts)
+		rows.Release()
+		if err != nil {
 			return fmt.Errorf("failed to write partition %s: %w", key, err)
 		}
 	}
@@ -66,92 +208,342 @@ func WritePartitioned(df *DataFrame, basePath string, partitionCols []string) er
 	return nil
 }
 
+// preparePartitionDir removes the data files of a partition directory in
+// overwrite mode, and returns the number of the first new part file.
+func preparePartitionDir(dirPath string, mode PartitionWriteMode) (int, error) {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list partition directory: %w", err)
+	}
+	next := 0
+	for _, entry := range entries {
+		if entry.IsDir() || !isPartitionDataFile(entry.Name()) {
+			continue
+		}
+		if mode == PartitionOverwrite {
+			if err := os.Remove(filepath.Join(dirPath, entry.Name())); err != nil {
+				return 0, fmt.Errorf("failed to remove %s: %w", entry.Name(), err)
+			}
+			continue
+		}
+		var n int
+		if _, err := fmt.Sscanf(entry.Name(), "part-%d.", &n); err == nil && n >= next {
+			next = n + 1
+		}
+	}
+	return next, nil
+}
+
+// writePartitionFiles writes the rows of one partition to files numbered
+// from part, with at most opts.MaxRowsPerFile rows each.
+func writePartitionFiles(rows arrow.Record, dirPath string, part int, opts PartitionWriteOptions) error {
+	numRows := rows.NumRows()
+	size := numRows
+	if opts.MaxRowsPerFile > 0 {
+		size = int64(opts.MaxRowsPerFile)
+	}
+	for start := int64(0); start < numRows; start += size {
+		slice := rows.NewSlice(start, min(start+size, numRows))
+		df := NewDataFrame(slice)
+		slice.Release()
+
+		filePath := filepath.Join(dirPath, fmt.Sprintf("part-%05d%s", part, opts.Format.extension()))
+		var err error
+		switch opts.Format {
+		case PartitionFormatParquet:
+			err = WriteParquet(df, filePath)
+		case PartitionFormatArrowIPC:
+			err = WriteArrowIPC(df, filePath)
+		default:
+			err = WriteCSV(df, filePath)
+		}
+		df.Release()
+		if err != nil {
+			return err
+		}
+		part++
+	}
+	return nil
+}
+
+// takeRows returns a record of the rows of record at indices.
+func takeRows(record arrow.Record, indices []int) (arrow.Record, error) {
+	pool := memory.NewGoAllocator()
+	builder := array.NewInt64Builder(pool)
+	defer builder.Release()
+	for _, i := range indices {
+		builder.Append(int64(i))
+	}
+	indexArr := builder.NewArray()
+	defer indexArr.Release()
+
+	columns := make([]arrow.Array, record.NumCols())
+	defer func() {
+		for _, col := range columns {
+			if col != nil {
+				col.Release()
+			}
+		}
+	}()
+	for i, col := range record.Columns() {
+		taken, err := compute.TakeArray(context.Background(), col, indexArr)
+		if err != nil {
+			return nil, err
+		}
+		columns[i] = taken
+	}
+	return array.NewRecord(record.Schema(), columns, int64(len(indices))), nil
+}
+
+// escapePartitionValue escapes the characters that Hive escapes in
+// partition directory names, which includes every character that is not
+// allowed in paths on common file systems.
+func escapePartitionValue(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c < 0x20 || c == 0x7F || strings.IndexByte("\"#%'*/:=?\\{[]^<>|", c) >= 0 {
+			fmt.Fprintf(&b, "%%%02X", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
 // ReadPartitioned reads a partitioned dataset from a directory structure.
-// Expects Hive-style partitioning: basePath/col=val/data.csv
+// Expects Hive-style partitioning: basePath/col=val/part-00000.csv
 // Returns a single DataFrame with partition columns added.
 func ReadPartitioned(basePath string) (*DataFrame, error) {
+	return ReadPartitionedWithOptions(basePath, PartitionReadOptions{})
+}
+
+// ReadPartitionedWithOptions reads a Hive-style partitioned dataset into one
+// DataFrame. Data files are read by extension as CSV (.csv, optionally
+// compressed), Parquet (.parquet) or Arrow IPC (.arrow, .ipc, .feather);
+// other files and files whose names start with '.' or '_' are ignored. The
+// partition columns are added after the data columns, with their values
+// unescaped and typed as described by PartitionReadOptions.
+//
+// Example:
+//
+//	events, err := ReadPartitionedWithOptions("events", PartitionReadOptions{
+//	    PartitionTypes: map[string]arrow.DataType{"country": arrow.BinaryTypes.String},
+//	})
+func ReadPartitionedWithOptions(basePath string, opts PartitionReadOptions) (*DataFrame, error) {
 	if err := validateFilePath(basePath); err != nil {
 		return nil, err
 	}
 
-	var allDFs []*DataFrame
+	files, columns, err := listPartitionFiles(basePath)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no data files found in %s", basePath)
+	}
+	return readPartitionFiles(files, columns, partitionTypes(files, columns, opts))
+}
+
+// partitionFile is a data file of a partitioned dataset with the raw
+// partition values of its directory, by column.
+type partitionFile struct {
+	path   string
+	values map[string]string
+}
+
+// value returns the partition value of column, and false for null.
+func (f partitionFile) value(column string) (string, bool) {
+	v, ok := f.values[column]
+	return v, ok && v != hiveDefaultPartition
+}
+
+// partitionArray returns an array of length rows holding the partition
+// value of column as type dt.
+func (f partitionFile) partitionArray(column string, dt arrow.DataType, rows int, pool memory.Allocator) (arrow.Array, error) {
+	sc := scalar.MakeNullScalar(dt)
+	if value, ok := f.value(column); ok {
+		var err error
+		if sc, err = scalar.ParseScalar(dt, value); err != nil {
+			return nil, fmt.Errorf("partition value %q of column %s is not a valid %s: %w", value, column, dt, err)
+		}
+	}
+	arr, err := scalar.MakeArrayFromScalar(sc, rows, pool)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build partition column %s: %w", column, err)
+	}
+	return arr, nil
+}
 
-	err := filepath.Walk(basePath, func(path string, info os.FileInfo, err error) error {
+// listPartitionFiles walks a partitioned dataset and returns its data files
+// and the names of its partition columns in directory order.
+func listPartitionFiles(basePath string) ([]partitionFile, []string, error) {
+	var files []partitionFile
+	var columns []string
+	err := filepath.WalkDir(basePath, func(path string, entry os.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
-		if info.IsDir() || !strings.HasSuffix(info.Name(), ".csv") {
+		if entry.IsDir() || !isPartitionDataFile(entry.Name()) {
 			return nil
 		}
 
-		// Read the CSV
-		df, err := ReadCSV(path)
+		relPath, err := filepath.Rel(basePath, filepath.Dir(path))
 		if err != nil {
-			return fmt.Errorf("failed to read %s: %w", path, err)
+			return err
 		}
-
-		// Extract partition columns from path
-		relPath, _ := filepath.Rel(basePath, filepath.Dir(path))
-		parts := strings.Split(relPath, string(filepath.Separator))
-
-		for _, part := range parts {
-			if strings.Contains(part, "=") {
-				kv := strings.SplitN(part, "=", 2)
-				if len(kv) == 2 {
-					// Add partition column to DataFrame
-					df = df.WithColumn(kv[0], Lit(kv[1]))
+		file := partitionFile{path: path, values: make(map[string]string)}
+		for _, segment := range strings.Split(filepath.ToSlash(relPath), "/") {
+			rawName, rawValue, found := strings.Cut(segment, "=")
+			if !found {
+				continue
+			}
+			name, err := url.PathUnescape(rawName)
+			if err != nil {
+				return fmt.Errorf("invalid partition directory %s: %w", segment, err)
+			}
+			value := rawValue
+			if value != hiveDefaultPartition {
+				if value, err = url.PathUnescape(rawValue); err != nil {
+					return fmt.Errorf("invalid partition directory %s: %w", segment, err)
 				}
 			}
+			file.values[name] = value
+			if !slices.Contains(columns, name) {
+				columns = append(columns, name)
+			}
 		}
-
-		allDFs = append(allDFs, df)
+		files = append(files, file)
 		return nil
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, fmt.Errorf("failed to list partitioned dataset: %w", err)
 	}
+	return files, columns, nil
+}
 
-	if len(allDFs) == 0 {
-		return nil, fmt.Errorf("no CSV files found in %s", basePath)
+// isPartitionDataFile reports whether a file of a partitioned dataset holds
+// data in a format that can be read.
+func isPartitionDataFile(name string) bool {
+	if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+		return false
 	}
-
-	// For simplicity, return first partition (full concat would need union logic)
-	if len(allDFs) == 1 {
-		return allDFs[0], nil
+	if compressionFromExtension(name) != "" {
+		return strings.EqualFold(filepath.Ext(strings.TrimSuffix(name, filepath.Ext(name))), ".csv")
+	}
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv", ".parquet", ".arrow", ".ipc", ".feather":
+		return true
+	default:
+		return false
 	}
-
-	// Simple concatenation by collecting into iterator and collecting
-	it := &DataFrameIterator{chunks: allDFs}
-	return it.Collect()
 }
 
-// selectRows creates a new DataFrame with only the specified rows, excluding certain columns.
-func (df *DataFrame) selectRows(indices []int, excludeCols []string) *DataFrame {
-	if df.err != nil {
-		return &DataFrame{err: df.err}
+// partitionTypes returns the type of each partition column, from opts or
+// inferred from the values of files.
+func partitionTypes(files []partitionFile, columns []string, opts PartitionReadOptions) map[string]arrow.DataType {
+	types := make(map[string]arrow.DataType, len(columns))
+	for _, column := range columns {
+		if dt, ok := opts.PartitionTypes[column]; ok {
+			types[column] = dt
+			continue
+		}
+		isInt, isDate := true, true
+		for _, file := range files {
+			value, ok := file.value(column)
+			if !ok {
+				continue
+			}
+			if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+				isInt = false
+			}
+			if _, err := time.Parse("2006-01-02", value); err != nil {
+				isDate = false
+			}
+		}
+		switch {
+		case isInt:
+			types[column] = arrow.PrimitiveTypes.Int64
+		case isDate:
+			types[column] = arrow.FixedWidthTypes.Date32
+		default:
+			types[column] = arrow.BinaryTypes.String
+		}
 	}
+	return types
+}
 
-	record := df.coreDF.Record()
-	schema := record.Schema()
+// readPartitionFiles reads data files and adds their partition columns.
+func readPartitionFiles(files []partitionFile, columns []string, types map[string]arrow.DataType) (*DataFrame, error) {
+	dfs := make([]*DataFrame, 0, len(files))
+	defer func() {
+		for _, df := range dfs {
+			df.Release()
+		}
+	}()
+	for _, file := range files {
+		df, err := readPartitionFile(file, columns, types)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file.path, err)
+		}
+		dfs = append(dfs, df)
+	}
+	if len(dfs) == 1 {
+		df := dfs[0]
+		dfs = nil
+		return df, nil
+	}
+	return concatDataFrames(dfs)
+}
 
-	excludeSet := make(map[string]bool)
-	for _, col := range excludeCols {
-		excludeSet[col] = true
+// readPartitionFile reads one data file and adds its partition values as
+// constant columns of their types.
+func readPartitionFile(file partitionFile, columns []string, types map[string]arrow.DataType) (*DataFrame, error) {
+	var read func(string) (*DataFrame, error)
+	switch strings.ToLower(filepath.Ext(file.path)) {
+	case ".parquet":
+		read = ReadParquet
+	case ".arrow", ".ipc", ".feather":
+		read = ReadArrowIPC
+	default:
+		read = ReadCSV
 	}
+	df, err := read(file.path)
+	if err != nil {
+		return nil, err
+	}
+	defer df.Release()
 
-	// Determine which columns to keep
-	var keepFields []string
-	for _, f := range schema.Fields() {
-		if !excludeSet[f.Name] {
-			keepFields = append(keepFields, f.Name)
+	record := df.coreDF.Record()
+	numRows := int(record.NumRows())
+	var fields []arrow.Field
+	var arrays []arrow.Array
+	for i, field := range record.Schema().Fields() {
+		// Partition values take precedence over data columns of the same name
+		if !slices.Contains(columns, field.Name) {
+			fields = append(fields, field)
+			arrays = append(arrays, record.Column(i))
 		}
 	}
 
-	// Select those columns first, then select rows via a new DataFrame
-	selected := df.Select(keepFields...)
-	if selected.err != nil {
-		return selected
+	pool := memory.NewGoAllocator()
+	var partitionArrs []arrow.Array
+	defer func() {
+		for _, arr := range partitionArrs {
+			arr.Release()
+		}
+	}()
+	for _, column := range columns {
+		arr, err := file.partitionArray(column, types[column], numRows, pool)
+		if err != nil {
+			return nil, err
+		}
+		partitionArrs = append(partitionArrs, arr)
+		fields = append(fields, arrow.Field{Name: column, Type: arr.DataType(), Nullable: true})
+		arrays = append(arrays, arr)
 	}
 
-	return selected
+	result := array.NewRecord(arrow.NewSchema(fields, nil), arrays, int64(numRows))
+	defer result.Release()
+	return NewDataFrame(result), nil
 }
@@ -0,0 +1,235 @@
+package gopherframe
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/felixgeelhaar/GopherFrame/pkg/expr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// partitionedTestDataFrame returns six events on two days in three
+// countries, one of which needs escaping and one of which is null.
+func partitionedTestDataFrame(t *testing.T) *DataFrame {
+	t.Helper()
+	pool := memory.NewGoAllocator()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "amount", Type: arrow.PrimitiveTypes.Float64},
+		{Name: "day", Type: arrow.FixedWidthTypes.Date32},
+		{Name: "country", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+	builder := array.NewRecordBuilder(pool, schema)
+	defer builder.Release()
+
+	day1 := arrow.Date32FromTime(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))
+	day2 := arrow.Date32FromTime(time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC))
+	builder.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2, 3, 4, 5, 6}, nil)
+	builder.Field(1).(*array.Float64Builder).AppendValues([]float64{10.5, 20.5, 30.5, 40.5, 50.5, 60.5}, nil)
+	builder.Field(2).(*array.Date32Builder).AppendValues([]arrow.Date32{day1, day1, day1, day2, day2, day2}, nil)
+	builder.Field(3).(*array.StringBuilder).AppendValues(
+		[]string{"US", "a/b=c", "US", "", "US", "a/b=c"}, []bool{true, true, true, false, true, true})
+
+	record := builder.NewRecord()
+	defer record.Release()
+	return NewDataFrame(record)
+}
+
+func TestWritePartitionedWithOptions_RoundTrip(t *testing.T) {
+	formats := []PartitionFormat{PartitionFormatCSV, PartitionFormatParquet, PartitionFormatArrowIPC}
+	for _, format := range formats {
+		t.Run(format.String(), func(t *testing.T) {
+			df := partitionedTestDataFrame(t)
+			defer df.Release()
+			dir := t.TempDir()
+
+			err := WritePartitionedWithOptions(df, dir, []string{"day", "country"}, PartitionWriteOptions{Format: format})
+			require.NoError(t, err)
+
+			// Values are escaped in directory names and nulls use the Hive default
+			assert.DirExists(t, filepath.Join(dir, "day=2024-01-15", "country=a%2Fb%3Dc"))
+			assert.DirExists(t, filepath.Join(dir, "day=2024-01-16", "country=__HIVE_DEFAULT_PARTITION__"))
+			assert.FileExists(t, filepath.Join(dir, "day=2024-01-15", "country=US", "part-00000"+format.extension()))
+
+			result, err := ReadPartitioned(dir)
+			require.NoError(t, err)
+			defer result.Release()
+			assert.Equal(t, []string{"id", "amount", "day", "country"}, result.ColumnNames())
+
+			record := result.Record()
+			assert.Equal(t, arrow.FixedWidthTypes.Date32, record.Schema().Field(2).Type)
+			require.Equal(t, int64(6), record.NumRows())
+			ids := record.Column(0).(*array.Int64)
+			amounts := record.Column(1).(*array.Float64)
+			days := record.Column(2).(*array.Date32)
+			countries := record.Column(3).(*array.String)
+			for i := 0; i < ids.Len(); i++ {
+				id := ids.Value(i)
+				assert.Equal(t, float64(id*10)+0.5, amounts.Value(i))
+				if id <= 3 {
+					assert.Equal(t, "2024-01-15", days.ValueStr(i))
+				} else {
+					assert.Equal(t, "2024-01-16", days.ValueStr(i))
+				}
+				switch id {
+				case 2, 6:
+					assert.Equal(t, "a/b=c", countries.Value(i))
+				case 4:
+					assert.True(t, countries.IsNull(i))
+				default:
+					assert.Equal(t, "US", countries.Value(i))
+				}
+			}
+		})
+	}
+}
+
+func TestWritePartitionedWithOptions_FilesAndModes(t *testing.T) {
+	df := partitionedTestDataFrame(t)
+	defer df.Release()
+	dir := t.TempDir()
+	opts := PartitionWriteOptions{Format: PartitionFormatParquet, MaxRowsPerFile: 2}
+
+	partFiles := func(partition string) []string {
+		matches, err := filepath.Glob(filepath.Join(dir, partition, "part-*.parquet"))
+		require.NoError(t, err)
+		for i, match := range matches {
+			matches[i] = filepath.Base(match)
+		}
+		return matches
+	}
+
+	require.NoError(t, WritePartitionedWithOptions(df, dir, []string{"day"}, opts))
+	assert.Equal(t, []string{"part-00000.parquet", "part-00001.parquet"}, partFiles("day=2024-01-15"))
+
+	// Appending adds files after the existing ones
+	opts.Mode = PartitionAppend
+	require.NoError(t, WritePartitionedWithOptions(df, dir, []string{"day"}, opts))
+	assert.Len(t, partFiles("day=2024-01-15"), 4)
+	assert.Contains(t, partFiles("day=2024-01-15"), "part-00003.parquet")
+	result, err := ReadPartitioned(dir)
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), result.NumRows())
+	result.Release()
+
+	// Overwriting replaces only the partitions that are written
+	slice := df.Record().NewSlice(0, 3)
+	firstDay := NewDataFrame(slice)
+	slice.Release()
+	defer firstDay.Release()
+	opts.Mode = PartitionOverwrite
+	opts.MaxRowsPerFile = 0
+	require.NoError(t, WritePartitionedWithOptions(firstDay, dir, []string{"day"}, opts))
+	assert.Equal(t, []string{"part-00000.parquet"}, partFiles("day=2024-01-15"))
+	assert.Len(t, partFiles("day=2024-01-16"), 4)
+	result, err = ReadPartitioned(dir)
+	require.NoError(t, err)
+	assert.Equal(t, int64(9), result.NumRows())
+	result.Release()
+}
+
+func TestReadPartitionedWithOptions_PartitionTypes(t *testing.T) {
+	dir := t.TempDir()
+	for _, partition := range []string{"year=2023/zip=01234", "year=2024/zip=90210"} {
+		path := filepath.Join(dir, filepath.FromSlash(partition))
+		require.NoError(t, os.MkdirAll(path, 0750))
+		require.NoError(t, os.WriteFile(filepath.Join(path, "part-00000.csv"), []byte("v\n1\n"), 0600))
+	}
+	// Files that are not data are ignored
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "_SUCCESS"), nil, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "year=2023", "notes.txt"), []byte("x"), 0600))
+
+	inferred, err := ReadPartitioned(dir)
+	require.NoError(t, err)
+	defer inferred.Release()
+	assert.Equal(t, arrow.PrimitiveTypes.Int64, inferred.Record().Schema().Field(1).Type)
+	assert.Equal(t, arrow.PrimitiveTypes.Int64, inferred.Record().Schema().Field(2).Type)
+
+	typed, err := ReadPartitionedWithOptions(dir, PartitionReadOptions{
+		PartitionTypes: map[string]arrow.DataType{
+			"year": arrow.PrimitiveTypes.Int32,
+			"zip":  arrow.BinaryTypes.String,
+		},
+	})
+	require.NoError(t, err)
+	defer typed.Release()
+	// Directories are read in lexical order
+	assert.Equal(t, []int32{2023, 2024}, typed.Record().Column(1).(*array.Int32).Int32Values())
+	assert.Equal(t, "01234", typed.Record().Column(2).(*array.String).Value(0))
+
+	_, err = ReadPartitionedWithOptions(dir, PartitionReadOptions{
+		PartitionTypes: map[string]arrow.DataType{"year": arrow.FixedWidthTypes.Date32},
+	})
+	assert.ErrorContains(t, err, "not a valid date32")
+}
+
+func TestReadPartitionedWithPruning(t *testing.T) {
+	df := partitionedTestDataFrame(t)
+	defer df.Release()
+	dir := t.TempDir()
+	require.NoError(t, WritePartitionedWithOptions(df, dir, []string{"day", "country"}, PartitionWriteOptions{Format: PartitionFormatParquet}))
+
+	// Files of pruned partitions are never opened, so corrupting them must
+	// not matter
+	corrupt := filepath.Join(dir, "day=2024-01-15", "country=a%2Fb%3Dc", "part-00000.parquet")
+	require.NoError(t, os.WriteFile(corrupt, []byte("not parquet"), 0600))
+
+	ids := func(df *DataFrame) []int64 {
+		values := slices.Clone(df.Record().Column(0).(*array.Int64).Int64Values())
+		slices.Sort(values)
+		return values
+	}
+	secondDay := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		predicate expr.Expr
+		want      []int64
+	}{
+		{"date range", Col("day").Ge(Lit(secondDay)), []int64{4, 5, 6}},
+		{"or of values", Col("country").Eq(Lit("US")).Or(Col("country").IsNull()), []int64{1, 3, 4, 5}},
+		{"partition and row predicates", Col("country").Eq(Lit("US")).And(Col("amount").Gt(Lit(25.0))), []int64{3, 5}},
+		{"mixed or applied to rows", Col("day").Ge(Lit(secondDay)).Or(Col("id").Eq(Lit(int64(1)))).And(Col("country").Ne(Lit("a/b=c"))), []int64{1, 5}},
+		{"row predicate with a partition range", Col("id").Gt(Lit(int64(2))).And(Col("day").Gt(Lit(secondDay.AddDate(0, 0, -1)))), []int64{4, 5, 6}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ReadPartitionedWithPruning(dir, tt.predicate)
+			require.NoError(t, err)
+			defer result.Release()
+			assert.Equal(t, tt.want, ids(result))
+			assert.Equal(t, []string{"id", "amount", "day", "country"}, result.ColumnNames())
+		})
+	}
+
+	// The corrupt partition is read when it is not pruned
+	_, err := ReadPartitionedWithPruning(dir, nil)
+	assert.Error(t, err)
+
+	_, err = ReadPartitionedWithPruning(dir, Col("country").Eq(Lit("FR")))
+	assert.ErrorContains(t, err, "no matching partitions")
+
+	_, err = ReadPartitionedWithPruning(dir, Col("day").Add(Lit(int64(1))))
+	assert.ErrorContains(t, err, "partition predicate")
+}
+
+func TestWritePartitionedWithOptions_Errors(t *testing.T) {
+	df := partitionedTestDataFrame(t)
+	defer df.Release()
+	dir := t.TempDir()
+
+	assert.ErrorContains(t, WritePartitioned(df, dir, []string{"missing"}), "partition column not found")
+	assert.ErrorContains(t, WritePartitioned(df, dir, []string{"id", "amount", "day", "country"}), "must not be a partition column")
+	assert.ErrorContains(t, WritePartitionedWithOptions(df, dir, []string{"day"}, PartitionWriteOptions{MaxRowsPerFile: -1}), "cannot be negative")
+	assert.ErrorContains(t, WritePartitionedWithOptions(df, dir, []string{"day"}, PartitionWriteOptions{Format: PartitionFormat(9)}), "PartitionFormat(9)")
+
+	_, err := ReadPartitioned(dir)
+	assert.ErrorContains(t, err, "no data files found")
+}
@@ -1,12 +1,14 @@
 package gopherframe
 
 import (
+	"context"
 	"fmt"
-	"sort"
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/compute"
 	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/felixgeelhaar/GopherFrame/pkg/rowhash"
 )
 
 // Pivot transforms long-format data to wide-format.
@@ -34,10 +36,8 @@ func (df *DataFrame) Pivot(indexCols []string, pivotCol, valueCol string) *DataF
 		return &DataFrame{err: fmt.Errorf("value column not found: %s", valueCol)}
 	}
 
-	pool := memory.NewGoAllocator()
 	record := df.coreDF.Record()
 	schema := record.Schema()
-	numRows := int(record.NumRows())
 
 	// Find column indices
 	colIdx := func(name string) int {
@@ -56,150 +56,41 @@ func (df *DataFrame) Pivot(indexCols []string, pivotCol, valueCol string) *DataF
 		indexIndices = append(indexIndices, colIdx(col))
 	}
 
-	// Extract unique pivot values (sorted for deterministic output)
-	pivotValues := make(map[string]bool)
 	pivotArr := record.Column(pivotIdx)
-	for i := 0; i < numRows; i++ {
-		if !pivotArr.IsNull(i) {
-			pivotValues[getStringValue(pivotArr, i)] = true
-		}
-	}
-	var pivotKeys []string
-	for k := range pivotValues {
-		pivotKeys = append(pivotKeys, k)
-	}
-	sort.Strings(pivotKeys)
-
-	// Build lookup: compositeIndexKey + pivotValue -> value
-	type cellKey struct {
-		indexKey string
-		pivotVal string
-	}
-	lookup := make(map[cellKey]interface{})
-	indexKeySet := make(map[string]bool)
-	var indexKeyOrder []string
-
-	for i := 0; i < numRows; i++ {
-		// Build composite index key
-		var parts []string
-		for _, idx := range indexIndices {
-			parts = append(parts, getStringValue(record.Column(idx), i))
-		}
-		ik := fmt.Sprintf("%v", parts)
-
-		if !indexKeySet[ik] {
-			indexKeySet[ik] = true
-			indexKeyOrder = append(indexKeyOrder, ik)
-		}
-
-		pv := getStringValue(pivotArr, i)
-		val := getTypedValue(record.Column(valueIdx), i)
-		lookup[cellKey{indexKey: ik, pivotVal: pv}] = val
-	}
-
-	// Build index column -> first row index mapping
-	indexFirstRow := make(map[string]int)
-	for i := 0; i < numRows; i++ {
-		var parts []string
-		for _, idx := range indexIndices {
-			parts = append(parts, getStringValue(record.Column(idx), i))
-		}
-		ik := fmt.Sprintf("%v", parts)
-		if _, exists := indexFirstRow[ik]; !exists {
-			indexFirstRow[ik] = i
-		}
+	indexRows, pivotRows, cells, err := pivotCells(record, indexIndices, pivotIdx)
+	if err != nil {
+		return &DataFrame{err: err}
 	}
 
 	// Build result
-	resultRows := len(indexKeyOrder)
+	resultRows := len(indexRows)
 	var resultFields []arrow.Field
 	var resultColumns []arrow.Array
+	defer func() {
+		for _, col := range resultColumns {
+			col.Release()
+		}
+	}()
 
 	// Add index columns
-	for _, ic := range indexCols {
-		idx := colIdx(ic)
-		resultFields = append(resultFields, schema.Field(idx))
-		srcArr := record.Column(idx)
-
-		switch a := srcArr.(type) {
-		case *array.String:
-			b := array.NewStringBuilder(pool)
-			for _, ik := range indexKeyOrder {
-				ri := indexFirstRow[ik]
-				if a.IsNull(ri) {
-					b.AppendNull()
-				} else {
-					b.Append(a.Value(ri))
-				}
-			}
-			resultColumns = append(resultColumns, b.NewArray())
-			b.Release()
-		case *array.Int64:
-			b := array.NewInt64Builder(pool)
-			for _, ik := range indexKeyOrder {
-				ri := indexFirstRow[ik]
-				if a.IsNull(ri) {
-					b.AppendNull()
-				} else {
-					b.Append(a.Value(ri))
-				}
-			}
-			resultColumns = append(resultColumns, b.NewArray())
-			b.Release()
-		default:
-			b := array.NewStringBuilder(pool)
-			for _, ik := range indexKeyOrder {
-				ri := indexFirstRow[ik]
-				b.Append(getStringValue(srcArr, ri))
-			}
-			resultColumns = append(resultColumns, b.NewArray())
-			b.Release()
+	for _, idx := range indexIndices {
+		col, err := takeWithNulls(record.Column(idx), indexRows)
+		if err != nil {
+			return &DataFrame{err: fmt.Errorf("failed to build index column: %w", err)}
 		}
+		resultFields = append(resultFields, schema.Field(idx))
+		resultColumns = append(resultColumns, col)
 	}
 
 	// Add pivot value columns
-	valueType := schema.Field(valueIdx).Type
-	for _, pv := range pivotKeys {
-		resultFields = append(resultFields, arrow.Field{Name: pv, Type: valueType})
-
-		switch valueType.ID() {
-		case arrow.FLOAT64:
-			b := array.NewFloat64Builder(pool)
-			for _, ik := range indexKeyOrder {
-				val, ok := lookup[cellKey{indexKey: ik, pivotVal: pv}]
-				if !ok || val == nil {
-					b.AppendNull()
-				} else {
-					b.Append(val.(float64))
-				}
-			}
-			resultColumns = append(resultColumns, b.NewArray())
-			b.Release()
-		case arrow.INT64:
-			b := array.NewInt64Builder(pool)
-			for _, ik := range indexKeyOrder {
-				val, ok := lookup[cellKey{indexKey: ik, pivotVal: pv}]
-				if !ok || val == nil {
-					b.AppendNull()
-				} else {
-					b.Append(val.(int64))
-				}
-			}
-			resultColumns = append(resultColumns, b.NewArray())
-			b.Release()
-		default:
-			b := array.NewStringBuilder(pool)
-			for _, ik := range indexKeyOrder {
-				val, ok := lookup[cellKey{indexKey: ik, pivotVal: pv}]
-				if !ok || val == nil {
-					b.AppendNull()
-				} else {
-					b.Append(fmt.Sprintf("%v", val))
-				}
-			}
-			resultColumns = append(resultColumns, b.NewArray())
-			b.Release()
+	valueArr := record.Column(valueIdx)
+	for p, rows := range cells {
+		col, err := takeWithNulls(valueArr, rows)
+		if err != nil {
+			return &DataFrame{err: fmt.Errorf("failed to build pivot column: %w", err)}
 		}
+		resultFields = append(resultFields, arrow.Field{Name: getStringValue(pivotArr, pivotRows[p]), Type: valueArr.DataType()})
+		resultColumns = append(resultColumns, col)
 	}
 
 	resultSchema := arrow.NewSchema(resultFields, nil)
@@ -351,6 +242,81 @@ func (df *DataFrame) Unpivot(idCols, valueCols []string, variableName, valueName
 	return NewDataFrame(resultRecord)
 }
 
+// pivotCells groups the rows of record by their index key, in order of first
+// appearance, and by their pivot value, in value order. Rows with a null
+// pivot value are skipped. It returns the first row of every index key and
+// of every pivot value, and for every pivot value the row that fills the cell
+// of each index key, or -1 if there is none. When several rows share a cell
+// the last one wins.
+func pivotCells(record arrow.Record, indexIndices []int, pivotIdx int) (indexRows, pivotRows []int, cells [][]int, err error) {
+	numRows := int(record.NumRows())
+
+	// Without index columns all rows share one index key
+	indexIDs := make([]int, numRows)
+	if len(indexIndices) == 0 {
+		if numRows > 0 {
+			indexRows = []int{0}
+		}
+	} else {
+		indexArrays := make([]arrow.Array, len(indexIndices))
+		for i, idx := range indexIndices {
+			indexArrays[i] = record.Column(idx)
+		}
+		indexKeys, err := rowhash.NewKeys(indexArrays...)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to hash index columns: %w", err)
+		}
+		indexGroups := rowhash.NewGroups(indexKeys)
+		for i := range indexIDs {
+			indexIDs[i] = indexGroups.GroupOf(i)
+		}
+		indexRows = indexGroups.FirstRows()
+	}
+
+	pivotKeys, err := rowhash.NewKeys(record.Column(pivotIdx))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to hash pivot column: %w", err)
+	}
+	pivotGroups := rowhash.NewGroups(pivotKeys)
+	cellsByGroup := make([][]int, pivotGroups.Len())
+	for _, id := range pivotGroups.Sorted() {
+		if pivotGroups.HasNull(id) {
+			continue
+		}
+		rows := make([]int, len(indexRows))
+		for i := range rows {
+			rows[i] = -1
+		}
+		cellsByGroup[id] = rows
+		pivotRows = append(pivotRows, pivotGroups.FirstRow(id))
+		cells = append(cells, rows)
+	}
+
+	for i := 0; i < numRows; i++ {
+		if rows := cellsByGroup[pivotGroups.GroupOf(i)]; rows != nil {
+			rows[indexIDs[i]] = i
+		}
+	}
+	return indexRows, pivotRows, cells, nil
+}
+
+// takeWithNulls returns the values of arr at the given rows, with a null
+// wherever the row is -1.
+func takeWithNulls(arr arrow.Array, rows []int) (arrow.Array, error) {
+	builder := array.NewInt64Builder(memory.NewGoAllocator())
+	defer builder.Release()
+	for _, row := range rows {
+		if row < 0 {
+			builder.AppendNull()
+		} else {
+			builder.Append(int64(row))
+		}
+	}
+	indices := builder.NewArray()
+	defer indices.Release()
+	return compute.TakeArray(context.Background(), arr, indices)
+}
+
 // getStringValue extracts a string representation of a value from an Arrow array.
 func getStringValue(arr arrow.Array, i int) string {
 	if arr.IsNull(i) {
@@ -366,7 +332,7 @@ func getStringValue(arr arrow.Array, i int) string {
 	case *array.Boolean:
 		return fmt.Sprintf("%t", a.Value(i))
 	default:
-		return fmt.Sprintf("%v", a)
+		return arr.ValueStr(i)
 	}
 }
 
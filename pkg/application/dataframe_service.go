@@ -3,6 +3,8 @@
 package application
 
 import (
+	"fmt"
+
 	"github.com/felixgeelhaar/GopherFrame/pkg/domain/aggregation"
 	"github.com/felixgeelhaar/GopherFrame/pkg/domain/dataframe"
 	"github.com/felixgeelhaar/GopherFrame/pkg/infrastructure/io"
@@ -103,6 +105,220 @@ func (b *GroupByBuilder) Max(column string) *GroupByBuilder {
 	return b
 }
 
+// Percentile adds a percentile aggregation (p between 0.0 and 1.0) to the group-by operation.
+func (b *GroupByBuilder) Percentile(column string, p float64) *GroupByBuilder {
+	b.aggregations = append(b.aggregations, aggregation.AggregationSpec{
+		Column:     column,
+		Type:       aggregation.Percentile,
+		Alias:      fmt.Sprintf("%s_p%.0f", column, p*100),
+		Percentile: p,
+	})
+	return b
+}
+
+// Median adds a median aggregation to the group-by operation.
+func (b *GroupByBuilder) Median(column string) *GroupByBuilder {
+	b.aggregations = append(b.aggregations, aggregation.AggregationSpec{
+		Column: column,
+		Type:   aggregation.Median,
+		Alias:  column + "_median",
+	})
+	return b
+}
+
+// Mode adds a mode (most frequent value) aggregation to the group-by operation.
+func (b *GroupByBuilder) Mode(column string) *GroupByBuilder {
+	b.aggregations = append(b.aggregations, aggregation.AggregationSpec{
+		Column: column,
+		Type:   aggregation.Mode,
+		Alias:  column + "_mode",
+	})
+	return b
+}
+
+// Variance adds a sample variance aggregation to the group-by operation.
+func (b *GroupByBuilder) Variance(column string) *GroupByBuilder {
+	b.aggregations = append(b.aggregations, aggregation.AggregationSpec{
+		Column: column,
+		Type:   aggregation.VarianceAgg,
+		Alias:  column + "_variance",
+	})
+	return b
+}
+
+// StdDev adds a sample standard deviation aggregation to the group-by operation.
+func (b *GroupByBuilder) StdDev(column string) *GroupByBuilder {
+	b.aggregations = append(b.aggregations, aggregation.AggregationSpec{
+		Column: column,
+		Type:   aggregation.StdDevAgg,
+		Alias:  column + "_stddev",
+	})
+	return b
+}
+
+// Skew adds a sample skewness aggregation to the group-by operation.
+func (b *GroupByBuilder) Skew(column string) *GroupByBuilder {
+	b.aggregations = append(b.aggregations, aggregation.AggregationSpec{
+		Column: column,
+		Type:   aggregation.Skew,
+		Alias:  column + "_skew",
+	})
+	return b
+}
+
+// Kurtosis adds a sample excess kurtosis aggregation to the group-by operation.
+func (b *GroupByBuilder) Kurtosis(column string) *GroupByBuilder {
+	b.aggregations = append(b.aggregations, aggregation.AggregationSpec{
+		Column: column,
+		Type:   aggregation.Kurtosis,
+		Alias:  column + "_kurtosis",
+	})
+	return b
+}
+
+// CountDistinct adds a distinct count aggregation to the group-by operation.
+func (b *GroupByBuilder) CountDistinct(column string) *GroupByBuilder {
+	b.aggregations = append(b.aggregations, aggregation.AggregationSpec{
+		Column: column,
+		Type:   aggregation.CountDistinct,
+		Alias:  column + "_count_distinct",
+	})
+	return b
+}
+
+// First adds a first non-null value aggregation to the group-by operation.
+func (b *GroupByBuilder) First(column string) *GroupByBuilder {
+	b.aggregations = append(b.aggregations, aggregation.AggregationSpec{
+		Column: column,
+		Type:   aggregation.First,
+		Alias:  column + "_first",
+	})
+	return b
+}
+
+// Last adds a last non-null value aggregation to the group-by operation.
+func (b *GroupByBuilder) Last(column string) *GroupByBuilder {
+	b.aggregations = append(b.aggregations, aggregation.AggregationSpec{
+		Column: column,
+		Type:   aggregation.Last,
+		Alias:  column + "_last",
+	})
+	return b
+}
+
+// Any adds a boolean any aggregation to the group-by operation.
+func (b *GroupByBuilder) Any(column string) *GroupByBuilder {
+	b.aggregations = append(b.aggregations, aggregation.AggregationSpec{
+		Column: column,
+		Type:   aggregation.Any,
+		Alias:  column + "_any",
+	})
+	return b
+}
+
+// All adds a boolean all aggregation to the group-by operation.
+func (b *GroupByBuilder) All(column string) *GroupByBuilder {
+	b.aggregations = append(b.aggregations, aggregation.AggregationSpec{
+		Column: column,
+		Type:   aggregation.All,
+		Alias:  column + "_all",
+	})
+	return b
+}
+
+// CollectList adds a list aggregation to the group-by operation.
+func (b *GroupByBuilder) CollectList(column string) *GroupByBuilder {
+	b.aggregations = append(b.aggregations, aggregation.AggregationSpec{
+		Column: column,
+		Type:   aggregation.CollectList,
+		Alias:  column + "_list",
+	})
+	return b
+}
+
+// Correlation adds a Pearson correlation aggregation of two columns to the group-by operation.
+func (b *GroupByBuilder) Correlation(column1, column2 string) *GroupByBuilder {
+	b.aggregations = append(b.aggregations, aggregation.AggregationSpec{
+		Column:       column1,
+		SecondColumn: column2,
+		Type:         aggregation.Correlation,
+		Alias:        fmt.Sprintf("corr_%s_%s", column1, column2),
+	})
+	return b
+}
+
+// Covariance adds a sample covariance aggregation of two columns to the group-by operation.
+func (b *GroupByBuilder) Covariance(column1, column2 string) *GroupByBuilder {
+	b.aggregations = append(b.aggregations, aggregation.AggregationSpec{
+		Column:       column1,
+		SecondColumn: column2,
+		Type:         aggregation.Covariance,
+		Alias:        fmt.Sprintf("cov_%s_%s", column1, column2),
+	})
+	return b
+}
+
+// ArgMin adds an aggregation of the value of column in the row where by is
+// smallest to the group-by operation.
+func (b *GroupByBuilder) ArgMin(column, by string) *GroupByBuilder {
+	b.aggregations = append(b.aggregations, aggregation.AggregationSpec{
+		Column:       column,
+		SecondColumn: by,
+		Type:         aggregation.ArgMin,
+		Alias:        column + "_arg_min",
+	})
+	return b
+}
+
+// ArgMax adds an aggregation of the value of column in the row where by is
+// largest to the group-by operation.
+func (b *GroupByBuilder) ArgMax(column, by string) *GroupByBuilder {
+	b.aggregations = append(b.aggregations, aggregation.AggregationSpec{
+		Column:       column,
+		SecondColumn: by,
+		Type:         aggregation.ArgMax,
+		Alias:        column + "_arg_max",
+	})
+	return b
+}
+
+// Concat adds an aggregation that joins the non-null values of every group
+// with a separator to the group-by operation.
+func (b *GroupByBuilder) Concat(column, separator string) *GroupByBuilder {
+	b.aggregations = append(b.aggregations, aggregation.AggregationSpec{
+		Column:    column,
+		Type:      aggregation.Concat,
+		Alias:     column + "_concat",
+		Separator: separator,
+	})
+	return b
+}
+
+// ApproxCountDistinct adds a distinct count estimated with a HyperLogLog sketch
+// of the given precision (4-18, 0 uses 14) to the group-by operation.
+func (b *GroupByBuilder) ApproxCountDistinct(column string, precision int) *GroupByBuilder {
+	b.aggregations = append(b.aggregations, aggregation.AggregationSpec{
+		Column:    column,
+		Type:      aggregation.ApproxCountDistinct,
+		Alias:     column + "_approx_count_distinct",
+		Precision: precision,
+	})
+	return b
+}
+
+// ApproxQuantile adds a q-th quantile estimated with a t-digest sketch of the
+// given compression (at least 10, 0 uses 100) to the group-by operation.
+func (b *GroupByBuilder) ApproxQuantile(column string, q, accuracy float64) *GroupByBuilder {
+	b.aggregations = append(b.aggregations, aggregation.AggregationSpec{
+		Column:     column,
+		Type:       aggregation.ApproxQuantile,
+		Alias:      fmt.Sprintf("%s_approx_p%.0f", column, q*100),
+		Percentile: q,
+		Accuracy:   accuracy,
+	})
+	return b
+}
+
 // As sets a custom alias for the last added aggregation.
 func (b *GroupByBuilder) As(alias string) *GroupByBuilder {
 	if len(b.aggregations) > 0 {
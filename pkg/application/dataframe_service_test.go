@@ -334,3 +334,77 @@ func TestGroupByBuilder_Build(t *testing.T) {
 		t.Errorf("Expected alias 'total_value', got %s", agg.Alias)
 	}
 }
+
+func TestGroupByBuilder_Statistics(t *testing.T) {
+	request := NewGroupByBuilder("category").
+		Percentile("value", 0.9).
+		Median("value").
+		Skew("value").
+		CountDistinct("label").
+		Covariance("value", "weight").
+		ArgMax("label", "value").
+		Concat("label", ", ").As("labels").
+		ApproxCountDistinct("label", 12).
+		ApproxQuantile("value", 0.99, 200).
+		Build()
+
+	expected := []aggregation.AggregationSpec{
+		{Column: "value", Type: aggregation.Percentile, Alias: "value_p90", Percentile: 0.9},
+		{Column: "value", Type: aggregation.Median, Alias: "value_median"},
+		{Column: "value", Type: aggregation.Skew, Alias: "value_skew"},
+		{Column: "label", Type: aggregation.CountDistinct, Alias: "label_count_distinct"},
+		{Column: "value", SecondColumn: "weight", Type: aggregation.Covariance, Alias: "cov_value_weight"},
+		{Column: "label", SecondColumn: "value", Type: aggregation.ArgMax, Alias: "label_arg_max"},
+		{Column: "label", Type: aggregation.Concat, Alias: "labels", Separator: ", "},
+		{Column: "label", Type: aggregation.ApproxCountDistinct, Alias: "label_approx_count_distinct", Precision: 12},
+		{Column: "value", Type: aggregation.ApproxQuantile, Alias: "value_approx_p99", Percentile: 0.99, Accuracy: 200},
+	}
+	if len(request.Aggregations) != len(expected) {
+		t.Fatalf("Expected %d aggregations, got %d", len(expected), len(request.Aggregations))
+	}
+	for i, want := range expected {
+		got := request.Aggregations[i]
+		if got.Column != want.Column || got.SecondColumn != want.SecondColumn || got.Type != want.Type ||
+			got.Alias != want.Alias || got.Percentile != want.Percentile || got.Separator != want.Separator ||
+			got.Precision != want.Precision || got.Accuracy != want.Accuracy {
+			t.Errorf("Aggregation %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+}
+
+func TestDataFrameService_GroupBy_Int64Values(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	schema := arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "category", Type: arrow.BinaryTypes.String},
+			{Name: "value", Type: arrow.PrimitiveTypes.Int64},
+		},
+		nil,
+	)
+
+	builder := array.NewRecordBuilder(pool, schema)
+	defer builder.Release()
+	builder.Field(0).(*array.StringBuilder).AppendValues([]string{"A", "A", "A", "B"}, nil)
+	builder.Field(1).(*array.Int64Builder).AppendValues([]int64{3, 1, 2, 5}, nil)
+	record := builder.NewRecord()
+	defer record.Release()
+	df := dataframe.NewDataFrame(record)
+	defer df.Release()
+
+	request := NewGroupByBuilder("category").Sum("value").Mean("value").Median("value").Skew("value").Build()
+	result := NewDataFrameService().GroupBy(df, request)
+	if result.Error != nil {
+		t.Fatalf("GroupBy failed: %v", result.Error)
+	}
+	defer result.DataFrame.Release()
+
+	out := result.DataFrame.Record()
+	for col, want := range map[int]float64{1: 6, 2: 2, 3: 2} {
+		if got := out.Column(col).(*array.Float64).Value(0); got != want {
+			t.Errorf("Column %s: expected %v, got %v", out.ColumnName(col), want, got)
+		}
+	}
+	if !out.Column(4).IsNull(1) {
+		t.Errorf("Expected null skew for a single value")
+	}
+}
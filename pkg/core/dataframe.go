@@ -9,7 +9,9 @@ import (
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/compute"
 	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/felixgeelhaar/GopherFrame/pkg/rowhash"
 	"github.com/felixgeelhaar/GopherFrame/pkg/storage"
 )
 
@@ -210,6 +212,12 @@ func (df *DataFrame) Record() arrow.Record {
 	return df.record
 }
 
+// Allocator returns the memory allocator the DataFrame's operations build
+// new arrays with.
+func (df *DataFrame) Allocator() memory.Allocator {
+	return df.allocator
+}
+
 // ColumnNames returns the names of all columns in order.
 //
 // The returned slice contains column names in the same order as they appear in the schema.
@@ -771,7 +779,7 @@ func (df *DataFrame) Filter(predicateArray arrow.Array) (*DataFrame, error) {
 		schema := df.record.Schema()
 		emptyColumns := make([]arrow.Array, len(schema.Fields()))
 
-		pool := memory.NewGoAllocator()
+		pool := df.allocator
 		for i, field := range schema.Fields() {
 			switch field.Type.ID() {
 			case arrow.INT64:
@@ -787,7 +795,7 @@ func (df *DataFrame) Filter(predicateArray arrow.Array) (*DataFrame, error) {
 				emptyColumns[i] = builder.NewArray()
 				builder.Release()
 			default:
-				return nil, fmt.Errorf("unsupported data type for empty filter: %s", field.Type)
+				emptyColumns[i] = array.MakeArrayOfNull(pool, field.Type, 0)
 			}
 		}
 
@@ -799,7 +807,7 @@ func (df *DataFrame) Filter(predicateArray arrow.Array) (*DataFrame, error) {
 	schema := df.record.Schema()
 	filteredColumns := make([]arrow.Array, len(schema.Fields()))
 
-	pool := memory.NewGoAllocator()
+	pool := df.allocator
 	for colIdx, field := range schema.Fields() {
 		column := df.record.Column(colIdx)
 
@@ -862,7 +870,16 @@ func (df *DataFrame) Filter(predicateArray arrow.Array) (*DataFrame, error) {
 			builder.Release()
 
 		default:
-			return nil, fmt.Errorf("unsupported data type for filtering: %s", field.Type)
+			// Other types go through the Arrow compute kernel, which drops
+			// rows whose predicate is null like the loops above
+			filtered, err := compute.FilterArray(compute.WithAllocator(context.Background(), pool), column, boolArray, *compute.DefaultFilterOptions())
+			if err != nil {
+				for _, col := range filteredColumns[:colIdx] {
+					col.Release()
+				}
+				return nil, fmt.Errorf("failed to filter column %s: %w", field.Name, err)
+			}
+			filteredColumns[colIdx] = filtered
 		}
 	}
 
@@ -984,7 +1001,7 @@ func (df *DataFrame) SortMultiple(sortKeys []SortKey) (*DataFrame, error) {
 
 	// Create new arrays with sorted data
 	newColumns := make([]arrow.Array, df.NumCols())
-	pool := memory.NewGoAllocator()
+	pool := df.allocator
 
 	for colIdx, field := range schema.Fields() {
 		column := df.record.Column(colIdx)
@@ -1385,14 +1402,12 @@ func (df *DataFrame) Join(other *DataFrame, leftKey, rightKey string, joinType J
 
 	// Perform the join based on type
 	switch joinType {
-	case InnerJoin:
-		return df.performInnerJoin(other, leftKey, rightKey, leftKeyArray, rightKeyArray)
-	case LeftJoin:
-		return df.performLeftJoin(other, leftKey, rightKey, leftKeyArray, rightKeyArray)
-	case RightJoin:
-		return df.performRightJoin(other, leftKey, rightKey, leftKeyArray, rightKeyArray)
-	case FullOuterJoin:
-		return df.performFullOuterJoin(other, leftKey, rightKey, leftKeyArray, rightKeyArray)
+	case InnerJoin, LeftJoin, RightJoin, FullOuterJoin:
+		leftIndices, rightIndices, err := hashJoinIndices([]arrow.Array{leftKeyArray}, []arrow.Array{rightKeyArray}, joinType)
+		if err != nil {
+			return nil, err
+		}
+		return df.buildJoinResult(other, leftKey, rightKey, leftIndices, rightIndices, joinType != InnerJoin)
 	default:
 		return nil, fmt.Errorf("unsupported join type: %d", joinType)
 	}
@@ -1583,215 +1598,67 @@ func (df *DataFrame) getColumnIndex(columnName string) int {
 	return -1
 }
 
-// performInnerJoin implements the inner join logic
-func (df *DataFrame) performInnerJoin(other *DataFrame, leftKey, rightKey string, leftKeyArray, rightKeyArray arrow.Array) (*DataFrame, error) {
-	// Build hash map from right DataFrame for efficient lookups
-	rightHashMap := make(map[interface{}][]int)
-
-	// Populate hash map with right side values
-	for i := 0; i < rightKeyArray.Len(); i++ {
-		if rightKeyArray.IsNull(i) {
-			continue // Skip null values in joins
-		}
-
-		key := extractValue(rightKeyArray, i)
-		if key != nil {
-			rightHashMap[key] = append(rightHashMap[key], i)
-		}
+// hashJoinIndices matches rows of the left and right key columns with a
+// hash join on their typed row hashes. It returns the paired row indices of
+// the result, where -1 marks a row without a match on that side. Null keys
+// never match.
+func hashJoinIndices(leftKeyArrays, rightKeyArrays []arrow.Array, joinType JoinType) ([]int, []int, error) {
+	leftKeys, err := rowhash.NewKeys(leftKeyArrays...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to hash left join keys: %w", err)
 	}
-
-	// Find matching rows
-	var leftIndices, rightIndices []int
-	for i := 0; i < leftKeyArray.Len(); i++ {
-		if leftKeyArray.IsNull(i) {
-			continue // Skip null values
-		}
-
-		leftValue := extractValue(leftKeyArray, i)
-		if leftValue == nil {
-			continue
-		}
-
-		if rightRows, exists := rightHashMap[leftValue]; exists {
-			for _, rightRow := range rightRows {
-				leftIndices = append(leftIndices, i)
-				rightIndices = append(rightIndices, rightRow)
-			}
-		}
+	rightKeys, err := rowhash.NewKeys(rightKeyArrays...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to hash right join keys: %w", err)
 	}
 
-	return df.buildJoinResult(other, leftKey, rightKey, leftIndices, rightIndices, false)
-}
-
-// performLeftJoin implements the left join logic
-func (df *DataFrame) performLeftJoin(other *DataFrame, leftKey, rightKey string, leftKeyArray, rightKeyArray arrow.Array) (*DataFrame, error) {
-	// Build hash map from right DataFrame
-	rightHashMap := make(map[interface{}][]int)
-
-	for i := 0; i < rightKeyArray.Len(); i++ {
-		if rightKeyArray.IsNull(i) {
-			continue
-		}
-
-		key := extractValue(rightKeyArray, i)
-		if key != nil {
-			rightHashMap[key] = append(rightHashMap[key], i)
-		}
+	// A right join is a left join with the sides swapped
+	if joinType == RightJoin {
+		rightIndices, leftIndices := probeHashTable(rightKeys, leftKeys, true, false)
+		return leftIndices, rightIndices, nil
 	}
-
-	// Find matching rows, including unmatched left rows
-	var leftIndices, rightIndices []int
-	for i := 0; i < leftKeyArray.Len(); i++ {
-		if leftKeyArray.IsNull(i) {
-			// Include left row with null values for right side
-			leftIndices = append(leftIndices, i)
-			rightIndices = append(rightIndices, -1) // -1 indicates no match
-			continue
-		}
-
-		leftValue := extractValue(leftKeyArray, i)
-		if leftValue == nil {
-			leftIndices = append(leftIndices, i)
-			rightIndices = append(rightIndices, -1)
-			continue
-		}
-
-		if rightRows, exists := rightHashMap[leftValue]; exists {
-			for _, rightRow := range rightRows {
-				leftIndices = append(leftIndices, i)
-				rightIndices = append(rightIndices, rightRow)
-			}
-		} else {
-			// No match found, include left row with nulls for right side
-			leftIndices = append(leftIndices, i)
-			rightIndices = append(rightIndices, -1)
-		}
-	}
-
-	return df.buildJoinResult(other, leftKey, rightKey, leftIndices, rightIndices, true)
+	leftIndices, rightIndices := probeHashTable(leftKeys, rightKeys, joinType != InnerJoin, joinType == FullOuterJoin)
+	return leftIndices, rightIndices, nil
 }
 
-// performRightJoin implements the right join logic
-//
-// A right join is equivalent to a left join with the DataFrames swapped.
-// All rows from the right DataFrame are included, with matching rows from the left.
-// Unmatched right rows have null values for left DataFrame columns.
-func (df *DataFrame) performRightJoin(other *DataFrame, leftKey, rightKey string, leftKeyArray, rightKeyArray arrow.Array) (*DataFrame, error) {
-	// Build hash map from left DataFrame
-	leftHashMap := make(map[interface{}][]int)
-
-	for i := 0; i < leftKeyArray.Len(); i++ {
-		if leftKeyArray.IsNull(i) {
-			continue
-		}
-
-		key := extractValue(leftKeyArray, i)
-		if key != nil {
-			leftHashMap[key] = append(leftHashMap[key], i)
-		}
-	}
-
-	// Find matching rows, including unmatched right rows
-	var leftIndices, rightIndices []int
-	for i := 0; i < rightKeyArray.Len(); i++ {
-		if rightKeyArray.IsNull(i) {
-			// Include right row with null values for left side
-			leftIndices = append(leftIndices, -1) // -1 indicates no match
-			rightIndices = append(rightIndices, i)
-			continue
-		}
-
-		rightValue := extractValue(rightKeyArray, i)
-		if rightValue == nil {
-			leftIndices = append(leftIndices, -1)
-			rightIndices = append(rightIndices, i)
-			continue
-		}
-
-		if leftRows, exists := leftHashMap[rightValue]; exists {
-			for _, leftRow := range leftRows {
-				leftIndices = append(leftIndices, leftRow)
-				rightIndices = append(rightIndices, i)
+// probeHashTable builds a hash table over the build keys and probes it with
+// every probe row in order. Unmatched probe rows are kept when keepProbe is
+// set, and build rows that matched nothing are appended when keepBuild is set.
+func probeHashTable(probe, build *rowhash.Keys, keepProbe, keepBuild bool) (probeIndices, buildIndices []int) {
+	groups := rowhash.NewGroups(build)
+	rows := groups.Rows()
+	var matched []bool
+	if keepBuild {
+		matched = make([]bool, groups.Len())
+	}
+
+	for i := 0; i < probe.Len(); i++ {
+		id := groups.Find(probe, i)
+		if id < 0 {
+			if keepProbe {
+				probeIndices = append(probeIndices, i)
+				buildIndices = append(buildIndices, -1)
 			}
-		} else {
-			// No match found, include right row with nulls for left side
-			leftIndices = append(leftIndices, -1)
-			rightIndices = append(rightIndices, i)
-		}
-	}
-
-	return df.buildJoinResult(other, leftKey, rightKey, leftIndices, rightIndices, true)
-}
-
-// performFullOuterJoin implements the full outer join logic
-//
-// A full outer join includes all rows from both DataFrames. Rows without matches
-// in either DataFrame have null values for the non-matching side's columns.
-// This is implemented as a union of left join and right join results.
-func (df *DataFrame) performFullOuterJoin(other *DataFrame, leftKey, rightKey string, leftKeyArray, rightKeyArray arrow.Array) (*DataFrame, error) {
-	// Build hash maps for both sides
-	leftHashMap := make(map[interface{}][]int)
-	rightHashMap := make(map[interface{}][]int)
-	matchedRightRows := make(map[int]bool)
-
-	for i := 0; i < leftKeyArray.Len(); i++ {
-		if leftKeyArray.IsNull(i) {
 			continue
 		}
-		key := extractValue(leftKeyArray, i)
-		if key != nil {
-			leftHashMap[key] = append(leftHashMap[key], i)
+		for _, row := range rows[id] {
+			probeIndices = append(probeIndices, i)
+			buildIndices = append(buildIndices, row)
 		}
-	}
-
-	for i := 0; i < rightKeyArray.Len(); i++ {
-		if rightKeyArray.IsNull(i) {
-			continue
-		}
-		key := extractValue(rightKeyArray, i)
-		if key != nil {
-			rightHashMap[key] = append(rightHashMap[key], i)
+		if keepBuild {
+			matched[id] = true
 		}
 	}
 
-	var leftIndices, rightIndices []int
-
-	// First pass: Include all left rows (with or without matches)
-	for i := 0; i < leftKeyArray.Len(); i++ {
-		if leftKeyArray.IsNull(i) {
-			leftIndices = append(leftIndices, i)
-			rightIndices = append(rightIndices, -1)
-			continue
-		}
-
-		leftValue := extractValue(leftKeyArray, i)
-		if leftValue == nil {
-			leftIndices = append(leftIndices, i)
-			rightIndices = append(rightIndices, -1)
-			continue
-		}
-
-		if rightRows, exists := rightHashMap[leftValue]; exists {
-			for _, rightRow := range rightRows {
-				leftIndices = append(leftIndices, i)
-				rightIndices = append(rightIndices, rightRow)
-				matchedRightRows[rightRow] = true
+	if keepBuild {
+		for row := 0; row < build.Len(); row++ {
+			if !matched[groups.GroupOf(row)] {
+				probeIndices = append(probeIndices, -1)
+				buildIndices = append(buildIndices, row)
 			}
-		} else {
-			leftIndices = append(leftIndices, i)
-			rightIndices = append(rightIndices, -1)
 		}
 	}
-
-	// Second pass: Include unmatched right rows
-	for i := 0; i < rightKeyArray.Len(); i++ {
-		if !matchedRightRows[i] {
-			leftIndices = append(leftIndices, -1)
-			rightIndices = append(rightIndices, i)
-		}
-	}
-
-	return df.buildJoinResult(other, leftKey, rightKey, leftIndices, rightIndices, true)
+	return probeIndices, buildIndices
 }
 
 // performCrossJoin implements the cross join logic
@@ -1897,23 +1764,6 @@ func (df *DataFrame) buildCrossJoinResult(other *DataFrame, leftIndices, rightIn
 	return NewDataFrameWithAllocator(resultRecord, pool), nil
 }
 
-// extractValue extracts a comparable value from an Arrow array at given index
-func extractValue(arr arrow.Array, index int) interface{} {
-	switch typedArr := arr.(type) {
-	case *array.Int64:
-		return typedArr.Value(index)
-	case *array.Float64:
-		return typedArr.Value(index)
-	case *array.String:
-		return typedArr.Value(index)
-	case *array.Boolean:
-		return typedArr.Value(index)
-	default:
-		// For other types, convert to string representation
-		return fmt.Sprintf("%v", arr.GetOneForMarshal(index))
-	}
-}
-
 // buildJoinResult constructs the final joined DataFrame
 func (df *DataFrame) buildJoinResult(other *DataFrame, leftKey, rightKey string, leftIndices, rightIndices []int, isLeftJoin bool) (*DataFrame, error) {
 	if len(leftIndices) != len(rightIndices) {
@@ -1955,7 +1805,7 @@ func (df *DataFrame) buildJoinResult(other *DataFrame, leftKey, rightKey string,
 
 	// Build result arrays
 	resultArrays := make([]arrow.Array, len(resultFields))
-	pool := memory.NewGoAllocator()
+	pool := df.allocator
 
 	fieldIndex := 0
 
@@ -2133,14 +1983,12 @@ func (df *DataFrame) JoinMulti(other *DataFrame, leftKeys, rightKeys []string, j
 	}
 
 	switch joinType {
-	case InnerJoin:
-		return df.performInnerJoinMulti(other, leftKeys, rightKeys, leftKeyArrays, rightKeyArrays)
-	case LeftJoin:
-		return df.performLeftJoinMulti(other, leftKeys, rightKeys, leftKeyArrays, rightKeyArrays)
-	case RightJoin:
-		return df.performRightJoinMulti(other, leftKeys, rightKeys, leftKeyArrays, rightKeyArrays)
-	case FullOuterJoin:
-		return df.performFullOuterJoinMulti(other, leftKeys, rightKeys, leftKeyArrays, rightKeyArrays)
+	case InnerJoin, LeftJoin, RightJoin, FullOuterJoin:
+		leftIndices, rightIndices, err := hashJoinIndices(leftKeyArrays, rightKeyArrays, joinType)
+		if err != nil {
+			return nil, err
+		}
+		return df.buildJoinResultMulti(other, leftKeys, rightKeys, leftIndices, rightIndices, joinType != InnerJoin)
 	default:
 		return nil, fmt.Errorf("unsupported join type for multi-key join: %d", joinType)
 	}
@@ -2174,179 +2022,6 @@ func (df *DataFrame) FullOuterJoinMulti(other *DataFrame, leftKeys, rightKeys []
 	return df.JoinMulti(other, leftKeys, rightKeys, FullOuterJoin)
 }
 
-// buildCompositeKey builds a composite hash key string from multiple columns at a given row.
-// Uses "\x00" as separator to avoid collisions (e.g., "ab"+"c" vs "a"+"bc").
-// Returns "" and false if any key column has a null value at the given row.
-func buildCompositeKey(arrays []arrow.Array, row int) (string, bool) {
-	var key string
-	for i, arr := range arrays {
-		if arr.IsNull(row) {
-			return "", false
-		}
-		val := extractValue(arr, row)
-		if val == nil {
-			return "", false
-		}
-		if i > 0 {
-			key += "\x00"
-		}
-		key += fmt.Sprintf("%v", val)
-	}
-	return key, true
-}
-
-// performInnerJoinMulti implements inner join logic with composite keys.
-func (df *DataFrame) performInnerJoinMulti(other *DataFrame, leftKeys, rightKeys []string, leftKeyArrays, rightKeyArrays []arrow.Array) (*DataFrame, error) {
-	rightHashMap := make(map[string][]int)
-
-	numRightRows := rightKeyArrays[0].Len()
-	for i := 0; i < numRightRows; i++ {
-		key, valid := buildCompositeKey(rightKeyArrays, i)
-		if !valid {
-			continue
-		}
-		rightHashMap[key] = append(rightHashMap[key], i)
-	}
-
-	var leftIndices, rightIndices []int
-	numLeftRows := leftKeyArrays[0].Len()
-	for i := 0; i < numLeftRows; i++ {
-		key, valid := buildCompositeKey(leftKeyArrays, i)
-		if !valid {
-			continue
-		}
-		if rightRows, exists := rightHashMap[key]; exists {
-			for _, rightRow := range rightRows {
-				leftIndices = append(leftIndices, i)
-				rightIndices = append(rightIndices, rightRow)
-			}
-		}
-	}
-
-	return df.buildJoinResultMulti(other, leftKeys, rightKeys, leftIndices, rightIndices, false)
-}
-
-// performLeftJoinMulti implements left join logic with composite keys.
-func (df *DataFrame) performLeftJoinMulti(other *DataFrame, leftKeys, rightKeys []string, leftKeyArrays, rightKeyArrays []arrow.Array) (*DataFrame, error) {
-	rightHashMap := make(map[string][]int)
-
-	numRightRows := rightKeyArrays[0].Len()
-	for i := 0; i < numRightRows; i++ {
-		key, valid := buildCompositeKey(rightKeyArrays, i)
-		if !valid {
-			continue
-		}
-		rightHashMap[key] = append(rightHashMap[key], i)
-	}
-
-	var leftIndices, rightIndices []int
-	numLeftRows := leftKeyArrays[0].Len()
-	for i := 0; i < numLeftRows; i++ {
-		key, valid := buildCompositeKey(leftKeyArrays, i)
-		if !valid {
-			// Null key: include left row with no right match
-			leftIndices = append(leftIndices, i)
-			rightIndices = append(rightIndices, -1)
-			continue
-		}
-		if rightRows, exists := rightHashMap[key]; exists {
-			for _, rightRow := range rightRows {
-				leftIndices = append(leftIndices, i)
-				rightIndices = append(rightIndices, rightRow)
-			}
-		} else {
-			leftIndices = append(leftIndices, i)
-			rightIndices = append(rightIndices, -1)
-		}
-	}
-
-	return df.buildJoinResultMulti(other, leftKeys, rightKeys, leftIndices, rightIndices, true)
-}
-
-// performRightJoinMulti implements right join logic with composite keys.
-func (df *DataFrame) performRightJoinMulti(other *DataFrame, leftKeys, rightKeys []string, leftKeyArrays, rightKeyArrays []arrow.Array) (*DataFrame, error) {
-	leftHashMap := make(map[string][]int)
-
-	numLeftRows := leftKeyArrays[0].Len()
-	for i := 0; i < numLeftRows; i++ {
-		key, valid := buildCompositeKey(leftKeyArrays, i)
-		if !valid {
-			continue
-		}
-		leftHashMap[key] = append(leftHashMap[key], i)
-	}
-
-	var leftIndices, rightIndices []int
-	numRightRows := rightKeyArrays[0].Len()
-	for i := 0; i < numRightRows; i++ {
-		key, valid := buildCompositeKey(rightKeyArrays, i)
-		if !valid {
-			leftIndices = append(leftIndices, -1)
-			rightIndices = append(rightIndices, i)
-			continue
-		}
-		if leftRows, exists := leftHashMap[key]; exists {
-			for _, leftRow := range leftRows {
-				leftIndices = append(leftIndices, leftRow)
-				rightIndices = append(rightIndices, i)
-			}
-		} else {
-			leftIndices = append(leftIndices, -1)
-			rightIndices = append(rightIndices, i)
-		}
-	}
-
-	return df.buildJoinResultMulti(other, leftKeys, rightKeys, leftIndices, rightIndices, true)
-}
-
-// performFullOuterJoinMulti implements full outer join logic with composite keys.
-func (df *DataFrame) performFullOuterJoinMulti(other *DataFrame, leftKeys, rightKeys []string, leftKeyArrays, rightKeyArrays []arrow.Array) (*DataFrame, error) {
-	rightHashMap := make(map[string][]int)
-	matchedRightRows := make(map[int]bool)
-
-	numRightRows := rightKeyArrays[0].Len()
-	for i := 0; i < numRightRows; i++ {
-		key, valid := buildCompositeKey(rightKeyArrays, i)
-		if !valid {
-			continue
-		}
-		rightHashMap[key] = append(rightHashMap[key], i)
-	}
-
-	var leftIndices, rightIndices []int
-
-	// First pass: all left rows
-	numLeftRows := leftKeyArrays[0].Len()
-	for i := 0; i < numLeftRows; i++ {
-		key, valid := buildCompositeKey(leftKeyArrays, i)
-		if !valid {
-			leftIndices = append(leftIndices, i)
-			rightIndices = append(rightIndices, -1)
-			continue
-		}
-		if rightRows, exists := rightHashMap[key]; exists {
-			for _, rightRow := range rightRows {
-				leftIndices = append(leftIndices, i)
-				rightIndices = append(rightIndices, rightRow)
-				matchedRightRows[rightRow] = true
-			}
-		} else {
-			leftIndices = append(leftIndices, i)
-			rightIndices = append(rightIndices, -1)
-		}
-	}
-
-	// Second pass: unmatched right rows
-	for i := 0; i < numRightRows; i++ {
-		if !matchedRightRows[i] {
-			leftIndices = append(leftIndices, -1)
-			rightIndices = append(rightIndices, i)
-		}
-	}
-
-	return df.buildJoinResultMulti(other, leftKeys, rightKeys, leftIndices, rightIndices, true)
-}
-
 // buildJoinResultMulti constructs the final joined DataFrame for multi-key joins.
 // It skips all right key columns (since the left keys are kept) and handles name conflicts.
 func (df *DataFrame) buildJoinResultMulti(other *DataFrame, leftKeys, rightKeys []string, leftIndices, rightIndices []int, allowNulls bool) (*DataFrame, error) {
@@ -2392,7 +2067,7 @@ func (df *DataFrame) buildJoinResultMulti(other *DataFrame, leftKeys, rightKeys
 	resultSchema := arrow.NewSchema(resultFields, nil)
 
 	resultArrays := make([]arrow.Array, len(resultFields))
-	pool := memory.NewGoAllocator()
+	pool := df.allocator
 
 	fieldIndex := 0
 
@@ -278,6 +278,61 @@ func TestDataFrame_FilterEdgeCases(t *testing.T) {
 	assert.Equal(t, int64(2), withNulls.NumRows(), "Filter with nulls should only keep true values")
 }
 
+// TestDataFrame_FilterOtherTypes tests filtering columns without a
+// dedicated loop, such as dates and int32
+func TestDataFrame_FilterOtherTypes(t *testing.T) {
+	pool := memory.NewGoAllocator()
+
+	schema := arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "day", Type: arrow.FixedWidthTypes.Date32, Nullable: true},
+			{Name: "count", Type: arrow.PrimitiveTypes.Int32},
+		},
+		nil,
+	)
+
+	dayBuilder := array.NewDate32Builder(pool)
+	dayBuilder.AppendValues([]arrow.Date32{19000, 19001, 19002}, []bool{true, false, true})
+	dayArray := dayBuilder.NewArray()
+	defer dayArray.Release()
+
+	countBuilder := array.NewInt32Builder(pool)
+	countBuilder.AppendValues([]int32{1, 2, 3}, nil)
+	countArray := countBuilder.NewArray()
+	defer countArray.Release()
+
+	record := array.NewRecord(schema, []arrow.Array{dayArray, countArray}, 3)
+	defer record.Release()
+
+	df := NewDataFrame(record)
+	defer df.Release()
+
+	maskBuilder := array.NewBooleanBuilder(pool)
+	maskBuilder.AppendValues([]bool{false, true, true}, []bool{true, true, true})
+	mask := maskBuilder.NewArray()
+	defer mask.Release()
+
+	filtered, err := df.Filter(mask)
+	require.NoError(t, err)
+	defer filtered.Release()
+	assert.Equal(t, int64(2), filtered.NumRows())
+	days := filtered.Record().Column(0).(*array.Date32)
+	assert.True(t, days.IsNull(0))
+	assert.Equal(t, arrow.Date32(19002), days.Value(1))
+	assert.Equal(t, []int32{2, 3}, filtered.Record().Column(1).(*array.Int32).Int32Values())
+
+	noneBuilder := array.NewBooleanBuilder(pool)
+	noneBuilder.AppendValues([]bool{false, false, false}, nil)
+	none := noneBuilder.NewArray()
+	defer none.Release()
+
+	empty, err := df.Filter(none)
+	require.NoError(t, err)
+	defer empty.Release()
+	assert.Equal(t, int64(0), empty.NumRows())
+	assert.True(t, empty.Record().Schema().Equal(schema))
+}
+
 // TestCompareValuesEdgeCases tests compareValues helper with edge cases
 func TestCompareValuesEdgeCases(t *testing.T) {
 	pool := memory.NewGoAllocator()
@@ -26,6 +26,9 @@ type LimitedAllocator struct {
 	// allocated tracks the current number of bytes allocated
 	// Uses atomic operations for thread-safe access
 	allocated atomic.Int64
+
+	// refused counts the allocations refused for exceeding the limit
+	refused atomic.Int64
 }
 
 // NewLimitedAllocator creates a new memory allocator with a hard limit.
@@ -64,6 +67,7 @@ func (a *LimitedAllocator) Allocate(size int) []byte {
 	if currentlyAllocated+int64(size) > a.limit {
 		// Return nil to indicate allocation failure
 		// The caller should check for nil and handle the OOM condition
+		a.refused.Add(1)
 		return nil
 	}
 
@@ -93,6 +97,7 @@ func (a *LimitedAllocator) Reallocate(size int, b []byte) []byte {
 	// Check if reallocation would exceed limit
 	currentlyAllocated := a.allocated.Load()
 	if currentlyAllocated+sizeDelta > a.limit {
+		a.refused.Add(1)
 		return nil
 	}
 
@@ -143,6 +148,15 @@ func (a *LimitedAllocator) Limit() int64 {
 	return a.limit
 }
 
+// Refusals returns the number of allocations and reallocations refused
+// because they would have exceeded the limit.
+//
+// Returns:
+//   - int64: Number of refused allocations since the allocator was created
+func (a *LimitedAllocator) Refusals() int64 {
+	return a.refused.Load()
+}
+
 // UsagePercent returns the current memory usage as a percentage of the limit.
 //
 // Returns:
@@ -58,6 +58,14 @@ func TestLimitedAllocator_ExceedLimit(t *testing.T) {
 	if allocator.AllocatedBytes() != 912 {
 		t.Errorf("expected 912 bytes allocated, got %d", allocator.AllocatedBytes())
 	}
+
+	// Refused allocations and reallocations are counted
+	if buf := allocator.Reallocate(1024, buf2); buf != nil {
+		t.Fatal("expected reallocation to fail when exceeding limit")
+	}
+	if allocator.Refusals() != 2 {
+		t.Errorf("expected 2 refusals, got %d", allocator.Refusals())
+	}
 }
 
 func TestLimitedAllocator_FreeMemory(t *testing.T) {
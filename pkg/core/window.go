@@ -8,6 +8,7 @@ import (
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/felixgeelhaar/GopherFrame/pkg/rowhash"
 )
 
 // WindowSpec defines a window function specification.
@@ -324,49 +325,30 @@ func (ws *WindowSpec) getPartitions() ([]partition, error) {
 		return []partition{{rows: allRows}}, nil
 	}
 
-	// Build partition key for each row
-	partitionMap := make(map[string][]int)
-
-	for rowIdx := 0; rowIdx < int(ws.df.record.NumRows()); rowIdx++ {
-		key, err := ws.getPartitionKey(rowIdx)
+	// Group rows by the typed hash of their partition key; nulls form
+	// partitions of their own
+	keyArrays := make([]arrow.Array, len(ws.partitionCols))
+	for i, colName := range ws.partitionCols {
+		series, err := ws.df.Column(colName)
 		if err != nil {
 			return nil, err
 		}
-		partitionMap[key] = append(partitionMap[key], rowIdx)
+		keyArrays[i] = series.Array()
+	}
+	keys, err := rowhash.NewKeys(keyArrays...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash partition keys: %w", err)
 	}
 
-	// Convert map to slice of partitions
-	partitions := make([]partition, 0, len(partitionMap))
-	for _, rows := range partitionMap {
-		partitions = append(partitions, partition{rows: rows})
+	groupRows := rowhash.NewGroups(keys).Rows()
+	partitions := make([]partition, len(groupRows))
+	for i, rows := range groupRows {
+		partitions[i] = partition{rows: rows}
 	}
 
 	return partitions, nil
 }
 
-// getPartitionKey creates a unique key string for a row based on partition columns.
-func (ws *WindowSpec) getPartitionKey(rowIdx int) (string, error) {
-	var key string
-	for i, colName := range ws.partitionCols {
-		series, err := ws.df.Column(colName)
-		if err != nil {
-			return "", err
-		}
-
-		arr := series.Array()
-		if arr.IsNull(rowIdx) {
-			key += "<NULL>"
-		} else {
-			key += fmt.Sprintf("%v", arr.GetOneForMarshal(rowIdx))
-		}
-
-		if i < len(ws.partitionCols)-1 {
-			key += "|"
-		}
-	}
-	return key, nil
-}
-
 // sortPartition sorts rows within a partition based on order columns.
 func (ws *WindowSpec) sortPartition(p *partition) error {
 	if len(ws.orderCols) == 0 {
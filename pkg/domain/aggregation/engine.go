@@ -0,0 +1,322 @@
+package aggregation
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/compute"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/felixgeelhaar/GopherFrame/pkg/rowhash"
+)
+
+// morselRows is the number of rows aggregated into one set of partial
+// states. Morsels are always merged in row order, so results do not depend
+// on how many goroutines aggregate them.
+var morselRows = 64 * 1024
+
+// SetMorselRowsForTesting sets the number of rows per morsel and returns a
+// function that restores the previous value. Small morsels let tests merge
+// many partial states without building large inputs; it must not be called
+// while an aggregation is running.
+func SetMorselRowsForTesting(rows int) (restore func()) {
+	previous := morselRows
+	morselRows = rows
+	return func() { morselRows = previous }
+}
+
+// Input is an aggregation together with the columns it reads. All columns
+// must have as many rows as the group key columns.
+type Input struct {
+	Spec AggregationSpec
+	// Values holds the values to aggregate (Spec.Column).
+	Values arrow.Array
+	// Second holds the second column of Correlation and Covariance, and the
+	// column ArgMin and ArgMax order by (Spec.SecondColumn).
+	Second arrow.Array
+	// Filter, if not nil, restricts the aggregation to the rows where it
+	// is true; rows where it is false or null are skipped.
+	Filter *array.Boolean
+}
+
+// Options configures Aggregate.
+type Options struct {
+	// Parallelism is the number of goroutines that group and aggregate
+	// rows. Zero uses runtime.GOMAXPROCS(0) and one runs on the calling
+	// goroutine. Results are identical for every setting.
+	Parallelism int
+	// Allocator allocates the result columns; nil uses the default allocator.
+	Allocator memory.Allocator
+}
+
+// Aggregate groups the rows of keys by the typed hash of its columns and
+// computes every input for every group. The result holds the key columns,
+// with the values of every group ordered by key, followed by one column
+// per input named by its alias. Rows with a null in any key column belong
+// to no group.
+//
+// Rows are split into fixed-size morsels that are aggregated into partial
+// states on up to opts.Parallelism goroutines, and the partial states are
+// merged in row order.
+func Aggregate(keys arrow.Record, inputs []Input, opts Options) (arrow.Record, error) {
+	parallelism := opts.Parallelism
+	if parallelism < 0 {
+		return nil, fmt.Errorf("parallelism cannot be negative: %d", parallelism)
+	}
+	if parallelism == 0 {
+		parallelism = runtime.GOMAXPROCS(0)
+	}
+	pool := opts.Allocator
+	if pool == nil {
+		pool = memory.DefaultAllocator
+	}
+
+	// Numeric aggregations compute in float64, so other numeric types are
+	// converted first
+	var converted []arrow.Array
+	defer func() {
+		for _, arr := range converted {
+			arr.Release()
+		}
+	}()
+
+	// Validate every input before grouping, so unsupported aggregations
+	// fail fast
+	kernels := make([]kernel, len(inputs))
+	for i, input := range inputs {
+		input, err := toFloat64Input(input, pool, &converted)
+		if err != nil {
+			return nil, fmt.Errorf("failed to perform aggregation %s: %w", input.Spec.Alias, err)
+		}
+		k, err := newKernel(input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to perform aggregation %s: %w", input.Spec.Alias, err)
+		}
+		kernels[i] = k
+	}
+
+	firstRows, groupOf, err := assignGroups(keys.Columns(), parallelism)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract groups: %w", err)
+	}
+
+	// The group key values are taken from the first row of every group
+	keyRows := make([]int32, len(firstRows))
+	for i, row := range firstRows {
+		keyRows[i] = int32(row)
+	}
+	fields := append([]arrow.Field{}, keys.Schema().Fields()...)
+	columns := make([]arrow.Array, 0, len(fields)+len(inputs))
+	defer func() {
+		for _, column := range columns {
+			column.Release()
+		}
+	}()
+	for _, keyColumn := range keys.Columns() {
+		column, err := takeRows(keyColumn, keyRows, pool)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build group key columns: %w", err)
+		}
+		columns = append(columns, column)
+	}
+
+	for i, states := range aggregateMorsels(kernels, groupOf, len(firstRows), parallelism) {
+		column, err := states.build(pool)
+		if err != nil {
+			return nil, fmt.Errorf("failed to perform aggregation %s: %w", inputs[i].Spec.Alias, err)
+		}
+		fields = append(fields, arrow.Field{Name: inputs[i].Spec.Alias, Type: column.DataType()})
+		columns = append(columns, column)
+	}
+
+	return array.NewRecord(arrow.NewSchema(fields, nil), columns, int64(len(firstRows))), nil
+}
+
+// toFloat64Input converts the values of aggregations that compute in float64,
+// and the second column of Correlation and Covariance, to float64. Converted
+// arrays are appended to converted for the caller to release.
+func toFloat64Input(input Input, pool memory.Allocator, converted *[]arrow.Array) (Input, error) {
+	if !readsFloat64(input.Spec) {
+		return input, nil
+	}
+	var err error
+	if input.Values, err = castToFloat64(input.Values, pool, converted); err != nil {
+		return input, err
+	}
+	if input.Second != nil && input.Spec.Type != ArgMin && input.Spec.Type != ArgMax {
+		if input.Second, err = castToFloat64(input.Second, pool, converted); err != nil {
+			return input, err
+		}
+	}
+	return input, nil
+}
+
+// readsFloat64 reports whether an aggregation reads its values as float64.
+func readsFloat64(spec AggregationSpec) bool {
+	switch spec.Type {
+	case Sum, Mean, Min, Max, VarianceAgg, StdDevAgg, Skew, Kurtosis,
+		Correlation, Covariance, Percentile, Median, Mode, Custom:
+		return true
+	case ApproxQuantile:
+		return !spec.FromSketches
+	}
+	return false
+}
+
+// castToFloat64 converts an integer, float32 or decimal array to float64.
+// Other arrays, including nil, are returned as they are. Converted arrays are
+// appended to converted for the caller to release.
+func castToFloat64(arr arrow.Array, pool memory.Allocator, converted *[]arrow.Array) (arrow.Array, error) {
+	if arr == nil {
+		return nil, nil
+	}
+	id := arr.DataType().ID()
+	if id == arrow.FLOAT64 || !(arrow.IsInteger(id) || arrow.IsFloating(id) || arrow.IsDecimal(id)) {
+		return arr, nil
+	}
+	ctx := compute.WithAllocator(context.Background(), pool)
+	result, err := compute.CastToType(ctx, arr, arrow.PrimitiveTypes.Float64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s to float64: %w", arr.DataType(), err)
+	}
+	*converted = append(*converted, result)
+	return result, nil
+}
+
+// assignGroups groups rows by the typed hash of their key columns. It
+// returns the first row of every group, ordered by key value, and the
+// position of every row's group in that order. Rows with a null key belong
+// to no group and are -1.
+func assignGroups(keyColumns []arrow.Array, parallelism int) ([]int, []int32, error) {
+	keys, err := rowhash.NewKeys(keyColumns...)
+	if err != nil {
+		return nil, nil, err
+	}
+	groups := rowhash.NewGroupsParallel(keys, parallelism)
+
+	rank := make([]int32, groups.Len())
+	var firstRows []int
+	for _, id := range groups.Sorted() {
+		if groups.HasNull(id) {
+			rank[id] = -1
+			continue
+		}
+		rank[id] = int32(len(firstRows))
+		firstRows = append(firstRows, groups.FirstRow(id))
+	}
+
+	groupOf := make([]int32, keys.Len())
+	for row := range groupOf {
+		groupOf[row] = rank[groups.GroupOf(row)]
+	}
+	return firstRows, groupOf, nil
+}
+
+// aggregateMorsels runs every kernel over the rows of groupOf, which holds
+// the group of every row or -1 for rows that belong to no group. Rows are
+// split into morsels that up to parallelism goroutines aggregate into
+// partial states; the partial states are then merged in row order.
+func aggregateMorsels(kernels []kernel, groupOf []int32, numGroups, parallelism int) []states {
+	results := make([]states, len(kernels))
+	for k, kernel := range kernels {
+		results[k] = kernel.newStates(numGroups)
+	}
+	numMorsels := (len(groupOf) + morselRows - 1) / morselRows
+	if numMorsels <= 1 {
+		buf := make([]int32, len(groupOf))
+		for k, s := range results {
+			s.add(0, kernels[k].filterSlots(0, groupOf, buf))
+		}
+		return results
+	}
+
+	type partial struct {
+		groups []int32
+		states []states
+	}
+	partials := make([]partial, numMorsels)
+	workers := min(parallelism, numMorsels)
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slotOf := make([]int32, numGroups)
+			for i := range slotOf {
+				slotOf[i] = -1
+			}
+			slots := make([]int32, morselRows)
+			buf := make([]int32, morselRows)
+			for {
+				m := int(next.Add(1)) - 1
+				if m >= numMorsels {
+					return
+				}
+				lo := m * morselRows
+				hi := min(lo+morselRows, len(groupOf))
+				var groups []int32
+				for row := lo; row < hi; row++ {
+					g := groupOf[row]
+					if g < 0 {
+						slots[row-lo] = -1
+						continue
+					}
+					if slotOf[g] < 0 {
+						slotOf[g] = int32(len(groups))
+						groups = append(groups, g)
+					}
+					slots[row-lo] = slotOf[g]
+				}
+				partialStates := make([]states, len(kernels))
+				for k, kernel := range kernels {
+					partialStates[k] = kernel.newStates(len(groups))
+					partialStates[k].add(lo, kernel.filterSlots(lo, slots[:hi-lo], buf))
+				}
+				for _, g := range groups {
+					slotOf[g] = -1
+				}
+				partials[m] = partial{groups: groups, states: partialStates}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Every aggregation merges its partial states on its own goroutine
+	for k := range kernels {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, p := range partials {
+				for slot, g := range p.groups {
+					results[k].merge(int(g), p.states[k], slot)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// takeRows returns the values of arr at rows; a row of -1 gives a null.
+func takeRows(arr arrow.Array, rows []int32, pool memory.Allocator) (arrow.Array, error) {
+	builder := array.NewInt32Builder(pool)
+	defer builder.Release()
+	builder.Reserve(len(rows))
+	for _, row := range rows {
+		if row < 0 {
+			builder.AppendNull()
+		} else {
+			builder.UnsafeAppend(row)
+		}
+	}
+	indices := builder.NewArray()
+	defer indices.Release()
+
+	ctx := compute.WithAllocator(context.Background(), pool)
+	return compute.TakeArray(ctx, arr, indices)
+}
@@ -0,0 +1,409 @@
+package aggregation
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/felixgeelhaar/GopherFrame/pkg/domain/dataframe"
+)
+
+func newEngineTestRecord(t *testing.T, rows int) arrow.Record {
+	t.Helper()
+	pool := memory.NewGoAllocator()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "key", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "value", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "other", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "label", Type: arrow.BinaryTypes.String},
+		{Name: "flag", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
+	}, nil)
+	builder := array.NewRecordBuilder(pool, schema)
+	defer builder.Release()
+	for i := 0; i < rows; i++ {
+		if i%97 == 0 {
+			builder.Field(0).AppendNull()
+		} else {
+			builder.Field(0).(*array.Int64Builder).Append(int64(i*31) % 37)
+		}
+		if i%13 == 0 {
+			builder.Field(1).AppendNull()
+		} else {
+			builder.Field(1).(*array.Float64Builder).Append(float64(i%101) / 7)
+		}
+		builder.Field(2).(*array.Float64Builder).Append(math.Sin(float64(i)))
+		builder.Field(3).(*array.StringBuilder).Append(fmt.Sprintf("r%d", i%211))
+		if i%11 == 0 {
+			builder.Field(4).AppendNull()
+		} else {
+			builder.Field(4).(*array.BooleanBuilder).Append(i%7 != 0)
+		}
+	}
+	return builder.NewRecord()
+}
+
+// engineTestInputs returns one input of every aggregation type over the
+// columns of newEngineTestRecord.
+func engineTestInputs(record arrow.Record) []Input {
+	value, other, label, flag := record.Column(1), record.Column(2), record.Column(3), record.Column(4)
+	specs := []struct {
+		spec   AggregationSpec
+		values arrow.Array
+		second arrow.Array
+	}{
+		{AggregationSpec{Type: Sum}, value, nil},
+		{AggregationSpec{Type: Mean}, value, nil},
+		{AggregationSpec{Type: Count}, value, nil},
+		{AggregationSpec{Type: Min}, value, nil},
+		{AggregationSpec{Type: Max}, value, nil},
+		{AggregationSpec{Type: Percentile, Percentile: 0.9}, value, nil},
+		{AggregationSpec{Type: Median}, value, nil},
+		{AggregationSpec{Type: Mode}, value, nil},
+		{AggregationSpec{Type: Correlation}, value, other},
+		{AggregationSpec{Type: VarianceAgg}, value, nil},
+		{AggregationSpec{Type: StdDevAgg}, value, nil},
+		{AggregationSpec{Type: CountDistinct}, label, nil},
+		{AggregationSpec{Type: First}, label, nil},
+		{AggregationSpec{Type: Last}, value, nil},
+		{AggregationSpec{Type: Any}, flag, nil},
+		{AggregationSpec{Type: All}, flag, nil},
+		{AggregationSpec{Type: Skew}, value, nil},
+		{AggregationSpec{Type: Kurtosis}, value, nil},
+		{AggregationSpec{Type: Covariance}, value, other},
+		{AggregationSpec{Type: ArgMin}, label, value},
+		{AggregationSpec{Type: ArgMax}, label, value},
+		{AggregationSpec{Type: CollectList}, label, nil},
+		{AggregationSpec{Type: Concat, Separator: ","}, label, nil},
+		{AggregationSpec{Type: ApproxCountDistinct, Precision: 10}, label, nil},
+		{AggregationSpec{Type: ApproxQuantile, Percentile: 0.9}, value, nil},
+		{AggregationSpec{Type: Custom, Func: func(values []float64) float64 {
+			// Order sensitive, so partial states must be merged in row order
+			return values[len(values)-1] - values[0]
+		}}, value, nil},
+	}
+	inputs := make([]Input, len(specs))
+	for i, s := range specs {
+		s.spec.Alias = s.spec.Type.String()
+		inputs[i] = Input{Spec: s.spec, Values: s.values, Second: s.second}
+	}
+	return inputs
+}
+
+func TestAggregate_ParallelMatchesSerial(t *testing.T) {
+	// Small morsels so that the rows are aggregated as many partial states
+	defer SetMorselRowsForTesting(64)()
+
+	record := newEngineTestRecord(t, 5000)
+	defer record.Release()
+	keySchema := arrow.NewSchema([]arrow.Field{record.Schema().Field(0)}, nil)
+	keyRecord := array.NewRecord(keySchema, []arrow.Array{record.Column(0)}, record.NumRows())
+	defer keyRecord.Release()
+	inputs := engineTestInputs(record)
+
+	serial, err := Aggregate(keyRecord, inputs, Options{Parallelism: 1})
+	if err != nil {
+		t.Fatalf("Serial aggregation failed: %v", err)
+	}
+	defer serial.Release()
+	if serial.NumRows() != 37 {
+		t.Fatalf("Expected 37 groups, got %d", serial.NumRows())
+	}
+
+	for _, parallelism := range []int{2, 4, 0} {
+		parallel, err := Aggregate(keyRecord, inputs, Options{Parallelism: parallelism})
+		if err != nil {
+			t.Fatalf("Parallel aggregation failed: %v", err)
+		}
+		if !array.RecordEqual(serial, parallel) {
+			t.Errorf("Parallelism %d: results differ from the serial aggregation", parallelism)
+		}
+		parallel.Release()
+	}
+
+	// Merging partial states agrees with aggregating each group at once
+	SetMorselRowsForTesting(1 << 20)
+	whole, err := Aggregate(keyRecord, inputs, Options{Parallelism: 1})
+	if err != nil {
+		t.Fatalf("Whole aggregation failed: %v", err)
+	}
+	defer whole.Release()
+	for col := 1; col < int(whole.NumCols()); col++ {
+		name := whole.ColumnName(col)
+		want, got := whole.Column(col), serial.Column(col)
+		floats, ok := want.(*array.Float64)
+		if !ok {
+			if !array.Equal(want, got) {
+				t.Errorf("Column %s differs", name)
+			}
+			continue
+		}
+		for i := 0; i < floats.Len(); i++ {
+			if floats.IsNull(i) != got.IsNull(i) ||
+				math.Abs(floats.Value(i)-got.(*array.Float64).Value(i)) > 1e-9 {
+				t.Errorf("Column %s group %d: expected %v, got %v",
+					name, i, floats.Value(i), got.(*array.Float64).Value(i))
+			}
+		}
+	}
+
+	if _, err := Aggregate(keyRecord, inputs, Options{Parallelism: -1}); err == nil {
+		t.Error("Expected error for negative parallelism")
+	}
+}
+
+func TestAggregate_Types(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "group", Type: arrow.BinaryTypes.String},
+		{Name: "x", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "y", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "flag", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
+	}, nil)
+	builder := array.NewRecordBuilder(pool, schema)
+	defer builder.Release()
+	builder.Field(0).(*array.StringBuilder).AppendValues(
+		[]string{"a", "a", "a", "a", "a", "b", "b"}, nil)
+	builder.Field(1).(*array.Float64Builder).AppendValues(
+		[]float64{1, 2, 3, 4, 10, 0, 5}, []bool{true, true, true, true, true, false, true})
+	builder.Field(2).(*array.Float64Builder).AppendValues(
+		[]float64{2, 4, 5, 9, 0, 1, 1}, []bool{true, true, true, true, false, true, true})
+	builder.Field(3).(*array.StringBuilder).AppendValues(
+		[]string{"", "ann", "bob", "ann", "cid", "dan", "eve"}, []bool{false, true, true, true, true, true, true})
+	builder.Field(4).(*array.BooleanBuilder).AppendValues(
+		[]bool{true, false, true, true, true, false, false}, []bool{true, true, true, true, true, false, false})
+	record := builder.NewRecord()
+	defer record.Release()
+	df := dataframe.NewDataFrame(record)
+	defer df.Release()
+
+	result := NewGroupByService().Execute(df, GroupByRequest{
+		GroupColumns: []string{"group"},
+		Aggregations: []AggregationSpec{
+			{Column: "name", Type: CountDistinct, Alias: "distinct"},
+			{Column: "name", Type: First, Alias: "first"},
+			{Column: "name", Type: Last, Alias: "last"},
+			{Column: "flag", Type: Any, Alias: "any"},
+			{Column: "flag", Type: All, Alias: "all"},
+			{Column: "x", Type: Skew, Alias: "skew"},
+			{Column: "x", Type: Kurtosis, Alias: "kurtosis"},
+			{Column: "x", SecondColumn: "y", Type: Covariance, Alias: "cov"},
+			{Column: "name", SecondColumn: "x", Type: ArgMin, Alias: "arg_min"},
+			{Column: "name", SecondColumn: "y", Type: ArgMax, Alias: "arg_max"},
+			{Column: "name", Type: CollectList, Alias: "names"},
+			{Column: "name", Type: Concat, Separator: "|", Alias: "joined"},
+		},
+	})
+	if result.Error != nil {
+		t.Fatalf("GroupBy failed: %v", result.Error)
+	}
+	defer result.DataFrame.Release()
+	out := result.DataFrame.Record()
+	column := func(name string) arrow.Array {
+		return out.Column(out.Schema().FieldIndices(name)[0])
+	}
+	str := func(name string) string {
+		arr := column(name)
+		return arr.ValueStr(0) + " " + arr.ValueStr(1)
+	}
+
+	expected := map[string]string{
+		"distinct": "3 2",
+		"first":    "ann dan",
+		"last":     "cid eve",
+		"any":      "true (null)",
+		"all":      "false (null)",
+		"cov":      "3.6666666666666665 (null)",
+		"arg_min":  "(null) eve",
+		"arg_max":  "ann dan",
+		"names":    `["ann","bob","ann","cid"] ["dan","eve"]`,
+		"joined":   "ann|bob|ann|cid dan|eve",
+	}
+	for name, want := range expected {
+		if got := str(name); got != want {
+			t.Errorf("%s: expected %q, got %q", name, want, got)
+		}
+	}
+	// Bias-corrected like pandas' Series.skew and Series.kurt
+	for name, want := range map[string]float64{"skew": 1.697056274847714, "kurtosis": 3.152} {
+		moments := column(name).(*array.Float64)
+		if got := moments.Value(0); math.Abs(got-want) > 1e-9 || moments.IsValid(1) {
+			t.Errorf("%s: expected [%v null], got %s", name, want, moments)
+		}
+	}
+	if !arrow.TypeEqual(column("names").DataType(), arrow.ListOf(arrow.BinaryTypes.String)) {
+		t.Errorf("Expected list<string> column, got %s", column("names").DataType())
+	}
+}
+
+func TestAggregate_Filter(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	keyBuilder := array.NewStringBuilder(pool)
+	defer keyBuilder.Release()
+	keyBuilder.AppendValues([]string{"a", "b", "a", "b"}, nil)
+	keyArray := keyBuilder.NewArray()
+	defer keyArray.Release()
+	valueBuilder := array.NewFloat64Builder(pool)
+	defer valueBuilder.Release()
+	valueBuilder.AppendValues([]float64{1, 2, 3, 4}, nil)
+	values := valueBuilder.NewArray()
+	defer values.Release()
+	filterBuilder := array.NewBooleanBuilder(pool)
+	defer filterBuilder.Release()
+	filterBuilder.AppendValues([]bool{false, true, true, false}, []bool{true, true, true, false})
+	filter := filterBuilder.NewBooleanArray()
+	defer filter.Release()
+
+	keys := array.NewRecord(arrow.NewSchema([]arrow.Field{{Name: "key", Type: arrow.BinaryTypes.String}}, nil),
+		[]arrow.Array{keyArray}, 4)
+	defer keys.Release()
+	result, err := Aggregate(keys, []Input{
+		{Spec: AggregationSpec{Type: Sum, Alias: "sum"}, Values: values, Filter: filter},
+		{Spec: AggregationSpec{Type: Count, Alias: "count"}, Values: values},
+	}, Options{})
+	if err != nil {
+		t.Fatalf("Aggregate failed: %v", err)
+	}
+	defer result.Release()
+	if got := result.Column(1).(*array.Float64).Float64Values(); got[0] != 3 || got[1] != 2 {
+		t.Errorf("Expected filtered sums [3 2], got %v", got)
+	}
+	if got := result.Column(2).(*array.Int64).Int64Values(); got[0] != 2 || got[1] != 2 {
+		t.Errorf("Expected unfiltered counts [2 2], got %v", got)
+	}
+}
+
+func TestAggregate_Errors(t *testing.T) {
+	record := newEngineTestRecord(t, 10)
+	defer record.Release()
+	keys := array.NewRecord(arrow.NewSchema([]arrow.Field{record.Schema().Field(0)}, nil),
+		[]arrow.Array{record.Column(0)}, record.NumRows())
+	defer keys.Release()
+
+	tests := []struct {
+		name  string
+		input Input
+	}{
+		{"sum of strings", Input{Spec: AggregationSpec{Type: Sum}, Values: record.Column(3)}},
+		{"any of floats", Input{Spec: AggregationSpec{Type: Any}, Values: record.Column(1)}},
+		{"covariance without second column", Input{Spec: AggregationSpec{Type: Covariance}, Values: record.Column(1)}},
+		{"custom without function", Input{Spec: AggregationSpec{Type: Custom}, Values: record.Column(1)}},
+		{"unknown type", Input{Spec: AggregationSpec{Type: AggregationType(99)}, Values: record.Column(1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Aggregate(keys, []Input{tt.input}, Options{}); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestAggregate_ApproxMatchesExact(t *testing.T) {
+	record := newEngineTestRecord(t, 5000)
+	defer record.Release()
+	keys := array.NewRecord(arrow.NewSchema([]arrow.Field{record.Schema().Field(0)}, nil),
+		[]arrow.Array{record.Column(0)}, record.NumRows())
+	defer keys.Release()
+
+	result, err := Aggregate(keys, []Input{
+		{Spec: AggregationSpec{Type: CountDistinct, Alias: "exact_distinct"}, Values: record.Column(3)},
+		{Spec: AggregationSpec{Type: ApproxCountDistinct, Alias: "approx_distinct"}, Values: record.Column(3)},
+		{Spec: AggregationSpec{Type: Percentile, Percentile: 0.3, Alias: "exact_p30"}, Values: record.Column(1)},
+		{Spec: AggregationSpec{Type: ApproxQuantile, Percentile: 0.3, Alias: "approx_p30"}, Values: record.Column(1)},
+	}, Options{})
+	if err != nil {
+		t.Fatalf("Aggregate failed: %v", err)
+	}
+	defer result.Release()
+
+	// Groups of ~130 values are small enough for the t-digest to keep every
+	// value and for the HyperLogLog to be off by at most one
+	exactDistinct, approxDistinct := result.Column(1).(*array.Int64), result.Column(2).(*array.Int64)
+	exactQuantile, approxQuantile := result.Column(3).(*array.Float64), result.Column(4).(*array.Float64)
+	for i := 0; i < int(result.NumRows()); i++ {
+		if d := exactDistinct.Value(i) - approxDistinct.Value(i); d < -1 || d > 1 {
+			t.Errorf("Group %d: expected %d distinct values, got %d", i, exactDistinct.Value(i), approxDistinct.Value(i))
+		}
+		if math.Abs(exactQuantile.Value(i)-approxQuantile.Value(i)) > 1e-9 {
+			t.Errorf("Group %d: expected 30th percentile %v, got %v", i, exactQuantile.Value(i), approxQuantile.Value(i))
+		}
+	}
+}
+
+func TestAggregate_MergeSketches(t *testing.T) {
+	record := newEngineTestRecord(t, 5000)
+	defer record.Release()
+	specs := []AggregationSpec{
+		{Column: "label", Type: ApproxCountDistinct, Alias: "distinct"},
+		{Column: "value", Type: ApproxQuantile, Percentile: 0.5, Alias: "median"},
+	}
+	aggregate := func(record arrow.Record, specs []AggregationSpec) arrow.Record {
+		t.Helper()
+		inputs := make([]Input, len(specs))
+		for i, spec := range specs {
+			inputs[i] = Input{Spec: spec, Values: record.Column(record.Schema().FieldIndices(spec.Column)[0])}
+		}
+		keys := array.NewRecord(arrow.NewSchema([]arrow.Field{record.Schema().Field(0)}, nil),
+			[]arrow.Array{record.Column(0)}, record.NumRows())
+		defer keys.Release()
+		result, err := Aggregate(keys, inputs, Options{})
+		if err != nil {
+			t.Fatalf("Aggregate failed: %v", err)
+		}
+		return result
+	}
+
+	direct := aggregate(record, specs)
+	defer direct.Release()
+
+	// Sketch two chunks separately, as a stream would, and merge them
+	sketchSpecs := make([]AggregationSpec, len(specs))
+	mergeSpecs := make([]AggregationSpec, len(specs))
+	for i, spec := range specs {
+		spec.Sketch = true
+		sketchSpecs[i] = spec
+		mergeSpecs[i] = AggregationSpec{Column: spec.Alias, Type: spec.Type, Percentile: spec.Percentile,
+			Alias: spec.Alias, FromSketches: true}
+	}
+	var partials []arrow.Record
+	for _, bounds := range [][2]int64{{0, 2000}, {2000, 5000}} {
+		chunk := record.NewSlice(bounds[0], bounds[1])
+		partials = append(partials, aggregate(chunk, sketchSpecs))
+		chunk.Release()
+	}
+	if typ := partials[0].Column(1).DataType(); !arrow.TypeEqual(typ, arrow.BinaryTypes.Binary) {
+		t.Fatalf("Expected binary sketch column, got %s", typ)
+	}
+	columns := make([]arrow.Array, partials[0].NumCols())
+	for i := range columns {
+		column, err := array.Concatenate([]arrow.Array{partials[0].Column(i), partials[1].Column(i)}, memory.NewGoAllocator())
+		if err != nil {
+			t.Fatalf("Concatenate failed: %v", err)
+		}
+		defer column.Release()
+		columns[i] = column
+	}
+	combined := array.NewRecord(partials[0].Schema(), columns, int64(columns[0].Len()))
+	defer combined.Release()
+	for _, partial := range partials {
+		partial.Release()
+	}
+
+	merged := aggregate(combined, mergeSpecs)
+	defer merged.Release()
+	if !array.RecordEqual(direct, merged) {
+		t.Errorf("Merged sketches differ from aggregating all rows at once:\n%v\n%v", direct, merged)
+	}
+
+	// Sketch columns must hold sketches of the aggregation's kind
+	if _, err := Aggregate(direct, []Input{{
+		Spec:   AggregationSpec{Type: ApproxQuantile, FromSketches: true},
+		Values: columns[1],
+	}}, Options{}); err == nil {
+		t.Error("Expected error merging HyperLogLog sketches as t-digests")
+	}
+}
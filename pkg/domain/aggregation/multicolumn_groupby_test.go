@@ -355,3 +355,78 @@ func TestGroupByService_MultiColumnGroupBy_EmptyGroups(t *testing.T) {
 		t.Errorf("Expected 3 columns, got %d", result.DataFrame.NumCols())
 	}
 }
+
+func TestGroupByService_MultiColumnGroupBy_TypedKeys(t *testing.T) {
+	service := NewGroupByService()
+
+	pool := memory.NewGoAllocator()
+	schema := arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "year", Type: arrow.PrimitiveTypes.Int32},
+			{Name: "active", Type: arrow.FixedWidthTypes.Boolean},
+			{Name: "amount", Type: arrow.PrimitiveTypes.Float64},
+		},
+		nil,
+	)
+
+	yearBuilder := array.NewInt32Builder(pool)
+	yearBuilder.AppendValues([]int32{2024, 2023, 2024, 2024, 2023}, nil)
+	yearArray := yearBuilder.NewArray()
+	defer yearArray.Release()
+
+	activeBuilder := array.NewBooleanBuilder(pool)
+	activeBuilder.AppendValues([]bool{true, true, false, true, true}, nil)
+	activeArray := activeBuilder.NewArray()
+	defer activeArray.Release()
+
+	amountBuilder := array.NewFloat64Builder(pool)
+	amountBuilder.AppendValues([]float64{1, 2, 3, 4, 5}, nil)
+	amountArray := amountBuilder.NewArray()
+	defer amountArray.Release()
+
+	record := array.NewRecord(schema, []arrow.Array{yearArray, activeArray, amountArray}, 5)
+	defer record.Release()
+
+	df := dataframe.NewDataFrame(record)
+	defer df.Release()
+
+	request := GroupByRequest{
+		GroupColumns: []string{"year", "active"},
+		Aggregations: []AggregationSpec{
+			{Column: "amount", Type: Sum, Alias: "total"},
+		},
+	}
+
+	result := service.Execute(df, request)
+	if result.Error != nil {
+		t.Fatalf("Typed key GroupBy failed: %v", result.Error)
+	}
+	defer result.DataFrame.Release()
+
+	// Group columns keep their types and are sorted by value
+	out := result.DataFrame.Record()
+	years, ok := out.Column(0).(*array.Int32)
+	if !ok {
+		t.Fatalf("Expected int32 year column, got %s", out.Column(0).DataType())
+	}
+	actives := out.Column(1).(*array.Boolean)
+	totals := out.Column(2).(*array.Float64)
+
+	expected := []struct {
+		year   int32
+		active bool
+		total  float64
+	}{
+		{2023, true, 7},
+		{2024, false, 3},
+		{2024, true, 5},
+	}
+	if years.Len() != len(expected) {
+		t.Fatalf("Expected %d groups, got %d", len(expected), years.Len())
+	}
+	for i, want := range expected {
+		if years.Value(i) != want.year || actives.Value(i) != want.active || totals.Value(i) != want.total {
+			t.Errorf("Group %d: expected %v, got {%d %v %v}", i, want, years.Value(i), actives.Value(i), totals.Value(i))
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package aggregation
+
+import (
+	"encoding"
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/felixgeelhaar/GopherFrame/pkg/rowhash"
+	"github.com/felixgeelhaar/GopherFrame/pkg/sketch"
+)
+
+// newHLLKernel returns the kernel of ApproxCountDistinct, which adds the
+// typed hash of every non-null value to a HyperLogLog per group, or merges
+// the serialized sketches of the input.
+func newHLLKernel(input Input) (kernel, error) {
+	spec := input.Spec
+	precision := spec.Precision
+	if precision == 0 {
+		precision = sketch.DefaultPrecision
+	}
+	if _, err := sketch.NewHyperLogLog(precision); err != nil {
+		return kernel{}, err
+	}
+
+	var keys *rowhash.Keys
+	var sketches []*sketch.HyperLogLog
+	var err error
+	if spec.FromSketches {
+		sketches, err = decodeSketches(input.Values, func() *sketch.HyperLogLog { return new(sketch.HyperLogLog) })
+	} else {
+		keys, err = rowhash.NewKeys(input.Values)
+	}
+	if err != nil {
+		return kernel{}, err
+	}
+
+	newStates := func(n int) states {
+		return &hllStates{
+			keys:      keys,
+			sketches:  sketches,
+			precision: precision,
+			sketch:    spec.Sketch,
+			groups:    make([]*sketch.HyperLogLog, n),
+		}
+	}
+	return kernel{newStates: newStates, filter: input.Filter}, nil
+}
+
+// hllStates holds a HyperLogLog per group, created on its first value.
+type hllStates struct {
+	// keys hashes the values, or sketches holds the sketch of every row
+	keys      *rowhash.Keys
+	sketches  []*sketch.HyperLogLog
+	precision int
+	sketch    bool
+	groups    []*sketch.HyperLogLog
+}
+
+func (s *hllStates) add(lo int, slots []int32) {
+	for i, slot := range slots {
+		row := lo + i
+		if slot < 0 {
+			continue
+		}
+		if s.sketches != nil {
+			if rowSketch := s.sketches[row]; rowSketch != nil {
+				s.mergeSketch(int(slot), rowSketch.Clone())
+			}
+			continue
+		}
+		if s.keys.HasNull(row) {
+			continue
+		}
+		if s.groups[slot] == nil {
+			s.groups[slot], _ = sketch.NewHyperLogLog(s.precision)
+		}
+		s.groups[slot].AddHash(s.keys.Hash(row))
+	}
+}
+
+// mergeSketch merges h into the sketch of slot, taking ownership of h.
+func (s *hllStates) mergeSketch(slot int, h *sketch.HyperLogLog) {
+	if s.groups[slot] == nil {
+		s.groups[slot] = h
+	} else {
+		s.groups[slot].Merge(h)
+	}
+}
+
+func (s *hllStates) merge(slot int, other states, otherSlot int) {
+	// Partial states are discarded once merged, so their sketches are taken
+	if h := other.(*hllStates).groups[otherSlot]; h != nil {
+		s.mergeSketch(slot, h)
+	}
+}
+
+func (s *hllStates) build(pool memory.Allocator) (arrow.Array, error) {
+	if s.sketch {
+		return buildSketches(pool, s.groups)
+	}
+	builder := array.NewInt64Builder(pool)
+	defer builder.Release()
+	for _, h := range s.groups {
+		if h == nil {
+			builder.Append(0)
+		} else {
+			builder.Append(int64(h.Estimate()))
+		}
+	}
+	return builder.NewArray(), nil
+}
+
+// newTDigestKernel returns the kernel of ApproxQuantile, which adds every
+// non-null value to a t-digest per group, or merges the serialized sketches
+// of the input.
+func newTDigestKernel(input Input) (kernel, error) {
+	spec := input.Spec
+	if spec.Percentile < 0.0 || spec.Percentile > 1.0 {
+		return kernel{}, fmt.Errorf("quantile must be between 0.0 and 1.0, got %.2f", spec.Percentile)
+	}
+	compression := spec.Accuracy
+	if compression == 0 {
+		compression = sketch.DefaultCompression
+	}
+	if _, err := sketch.NewTDigest(compression); err != nil {
+		return kernel{}, err
+	}
+
+	var values *array.Float64
+	var sketches []*sketch.TDigest
+	if spec.FromSketches {
+		var err error
+		sketches, err = decodeSketches(input.Values, func() *sketch.TDigest { return new(sketch.TDigest) })
+		if err != nil {
+			return kernel{}, err
+		}
+	} else {
+		var ok bool
+		values, ok = input.Values.(*array.Float64)
+		if !ok {
+			return kernel{}, fmt.Errorf("%s aggregation only supports float64, got %s", spec.Type, input.Values.DataType())
+		}
+	}
+
+	newStates := func(n int) states {
+		return &tdigestStates{
+			values:      values,
+			sketches:    sketches,
+			compression: compression,
+			quantile:    spec.Percentile,
+			sketch:      spec.Sketch,
+			groups:      make([]*sketch.TDigest, n),
+		}
+	}
+	return kernel{newStates: newStates, filter: input.Filter}, nil
+}
+
+// tdigestStates holds a t-digest per group, created on its first value.
+type tdigestStates struct {
+	// values holds the values, or sketches the sketch of every row
+	values      *array.Float64
+	sketches    []*sketch.TDigest
+	compression float64
+	quantile    float64
+	sketch      bool
+	groups      []*sketch.TDigest
+}
+
+func (s *tdigestStates) add(lo int, slots []int32) {
+	for i, slot := range slots {
+		row := lo + i
+		if slot < 0 {
+			continue
+		}
+		if s.sketches != nil {
+			if rowSketch := s.sketches[row]; rowSketch != nil {
+				s.mergeSketch(int(slot), rowSketch.Clone())
+			}
+			continue
+		}
+		if s.values.IsNull(row) {
+			continue
+		}
+		if s.groups[slot] == nil {
+			s.groups[slot], _ = sketch.NewTDigest(s.compression)
+		}
+		s.groups[slot].Add(s.values.Value(row))
+	}
+}
+
+// mergeSketch merges t into the sketch of slot, taking ownership of t.
+func (s *tdigestStates) mergeSketch(slot int, t *sketch.TDigest) {
+	if s.groups[slot] == nil {
+		s.groups[slot] = t
+	} else {
+		s.groups[slot].Merge(t)
+	}
+}
+
+func (s *tdigestStates) merge(slot int, other states, otherSlot int) {
+	// Partial states are discarded once merged, so their sketches are taken
+	if t := other.(*tdigestStates).groups[otherSlot]; t != nil {
+		s.mergeSketch(slot, t)
+	}
+}
+
+func (s *tdigestStates) build(pool memory.Allocator) (arrow.Array, error) {
+	if s.sketch {
+		return buildSketches(pool, s.groups)
+	}
+	builder := array.NewFloat64Builder(pool)
+	defer builder.Release()
+	for _, t := range s.groups {
+		if t == nil || t.Count() == 0 {
+			builder.AppendNull()
+		} else {
+			builder.Append(t.Quantile(s.quantile))
+		}
+	}
+	return builder.NewArray(), nil
+}
+
+// decodeSketches decodes a binary column of serialized sketches. Null rows
+// have no sketch.
+func decodeSketches[S interface {
+	comparable
+	encoding.BinaryUnmarshaler
+}](values arrow.Array, newSketch func() S) ([]S, error) {
+	binaryValues, ok := values.(*array.Binary)
+	if !ok {
+		return nil, fmt.Errorf("sketches must be binary, got %s", values.DataType())
+	}
+	sketches := make([]S, binaryValues.Len())
+	for row := range sketches {
+		if binaryValues.IsNull(row) {
+			continue
+		}
+		s := newSketch()
+		if err := s.UnmarshalBinary(binaryValues.Value(row)); err != nil {
+			return nil, fmt.Errorf("failed to decode sketch in row %d: %w", row, err)
+		}
+		sketches[row] = s
+	}
+	return sketches, nil
+}
+
+// buildSketches serializes the sketch of every group into a binary column.
+// Groups without a sketch are null.
+func buildSketches[S interface {
+	comparable
+	encoding.BinaryMarshaler
+}](pool memory.Allocator, groups []S) (arrow.Array, error) {
+	var none S
+	builder := array.NewBinaryBuilder(pool, arrow.BinaryTypes.Binary)
+	defer builder.Release()
+	for _, g := range groups {
+		if g == none {
+			builder.AppendNull()
+			continue
+		}
+		data, err := g.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize sketch: %w", err)
+		}
+		builder.Append(data)
+	}
+	return builder.NewArray(), nil
+}
@@ -0,0 +1,784 @@
+package aggregation
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/felixgeelhaar/GopherFrame/pkg/rowhash"
+)
+
+// states holds the partial states of one aggregation for a set of groups.
+type states interface {
+	// add accumulates the rows lo, lo+1, ... into the states given by
+	// slots, one slot per row. Rows with a negative slot are skipped.
+	add(lo int, slots []int32)
+	// merge folds other's state otherSlot into slot, as if its rows came
+	// after the rows already accumulated in slot.
+	merge(slot int, other states, otherSlot int)
+	// build returns the result of every state.
+	build(pool memory.Allocator) (arrow.Array, error)
+}
+
+// kernel creates the states of one aggregation over its input columns.
+type kernel struct {
+	newStates func(n int) states
+	// filter, if not nil, holds the rows to aggregate.
+	filter *array.Boolean
+}
+
+// filterSlots returns slots, the slots of the rows lo, lo+1, ..., with the
+// rows the kernel's filter skips set to -1. The result is written to buf.
+func (k kernel) filterSlots(lo int, slots, buf []int32) []int32 {
+	if k.filter == nil {
+		return slots
+	}
+	buf = buf[:len(slots)]
+	for i, slot := range slots {
+		if k.filter.IsValid(lo+i) && k.filter.Value(lo+i) {
+			buf[i] = slot
+		} else {
+			buf[i] = -1
+		}
+	}
+	return buf
+}
+
+// newKernel validates the columns of an aggregation and returns the kernel
+// that aggregates them.
+func newKernel(input Input) (kernel, error) {
+	spec := input.Spec
+	if input.Values == nil {
+		return kernel{}, fmt.Errorf("aggregation column not found: %s", spec.Column)
+	}
+	float64Values := func(arr arrow.Array) (*array.Float64, error) {
+		values, ok := arr.(*array.Float64)
+		if !ok {
+			return nil, fmt.Errorf("%s aggregation only supports float64, got %s", spec.Type, arr.DataType())
+		}
+		return values, nil
+	}
+	if (spec.Sketch || spec.FromSketches) && spec.Type != ApproxCountDistinct && spec.Type != ApproxQuantile {
+		return kernel{}, fmt.Errorf("%s aggregation has no sketch", spec.Type)
+	}
+	needsSecond := spec.Type == Correlation || spec.Type == Covariance || spec.Type == ArgMin || spec.Type == ArgMax
+	if needsSecond && input.Second == nil {
+		return kernel{}, fmt.Errorf("%s second column not found: %s", spec.Type, spec.SecondColumn)
+	}
+
+	var newStates func(n int) states
+	switch spec.Type {
+	case Sum, Mean:
+		values, err := float64Values(input.Values)
+		if err != nil {
+			return kernel{}, err
+		}
+		mean := spec.Type == Mean
+		newStates = func(n int) states {
+			return &sumStates{values: values, mean: mean, sums: make([]float64, n), counts: make([]int64, n)}
+		}
+	case Count:
+		values := input.Values
+		newStates = func(n int) states {
+			return &countStates{values: values, counts: make([]int64, n)}
+		}
+	case CountDistinct:
+		keys, err := rowhash.NewKeys(input.Values)
+		if err != nil {
+			return kernel{}, err
+		}
+		newStates = func(n int) states {
+			return &distinctStates{keys: keys, rows: make([][]int32, n), seen: make(map[distinctKey][]int32)}
+		}
+	case Min, Max:
+		values, err := float64Values(input.Values)
+		if err != nil {
+			return kernel{}, err
+		}
+		isMax := spec.Type == Max
+		newStates = func(n int) states {
+			return &extremeStates{values: values, max: isMax, extremes: make([]float64, n), seen: make([]bool, n)}
+		}
+	case VarianceAgg, StdDevAgg, Skew, Kurtosis:
+		values, err := float64Values(input.Values)
+		if err != nil {
+			return kernel{}, err
+		}
+		aggType := spec.Type
+		newStates = func(n int) states {
+			return &momentStates{
+				values: values,
+				result: aggType,
+				counts: make([]float64, n),
+				means:  make([]float64, n),
+				m2s:    make([]float64, n),
+				m3s:    make([]float64, n),
+				m4s:    make([]float64, n),
+			}
+		}
+	case Correlation, Covariance:
+		x, err := float64Values(input.Values)
+		if err != nil {
+			return kernel{}, err
+		}
+		y, err := float64Values(input.Second)
+		if err != nil {
+			return kernel{}, err
+		}
+		correlation := spec.Type == Correlation
+		newStates = func(n int) states {
+			return &comomentStates{
+				x:           x,
+				y:           y,
+				correlation: correlation,
+				counts:      make([]float64, n),
+				meanXs:      make([]float64, n),
+				meanYs:      make([]float64, n),
+				m2Xs:        make([]float64, n),
+				m2Ys:        make([]float64, n),
+				cXYs:        make([]float64, n),
+			}
+		}
+	case Percentile, Median, Mode, Custom:
+		values, err := float64Values(input.Values)
+		if err != nil {
+			return kernel{}, err
+		}
+		var finish func(values []float64) float64
+		switch spec.Type {
+		case Percentile, Median:
+			p := spec.Percentile
+			if spec.Type == Median {
+				p = 0.5
+			}
+			if p < 0.0 || p > 1.0 {
+				return kernel{}, fmt.Errorf("percentile must be between 0.0 and 1.0, got %.2f", p)
+			}
+			finish = func(values []float64) float64 { return percentile(values, p) }
+		case Mode:
+			finish = mode
+		case Custom:
+			if spec.Func == nil {
+				return kernel{}, fmt.Errorf("custom aggregation function not found: %s", spec.Alias)
+			}
+			finish = spec.Func
+		}
+		newStates = func(n int) states {
+			return &collectStates{values: values, finish: finish, groups: make([][]float64, n)}
+		}
+	case First, Last:
+		values := input.Values
+		last := spec.Type == Last
+		newStates = func(n int) states {
+			return &positionStates{values: values, last: last, rows: filled(n)}
+		}
+	case ArgMin, ArgMax:
+		by, err := rowhash.NewKeys(input.Second)
+		if err != nil {
+			return kernel{}, err
+		}
+		values := input.Values
+		isMax := spec.Type == ArgMax
+		newStates = func(n int) states {
+			return &argStates{values: values, by: by, max: isMax, rows: filled(n)}
+		}
+	case Any, All:
+		values, ok := input.Values.(*array.Boolean)
+		if !ok {
+			return kernel{}, fmt.Errorf("%s aggregation only supports boolean, got %s", spec.Type, input.Values.DataType())
+		}
+		all := spec.Type == All
+		newStates = func(n int) states {
+			return &boolStates{values: values, all: all, results: make([]bool, n), seen: make([]bool, n)}
+		}
+	case CollectList, Concat:
+		values := input.Values
+		separator := spec.Separator
+		concat := spec.Type == Concat
+		newStates = func(n int) states {
+			return &listStates{values: values, concat: concat, separator: separator, rows: make([][]int32, n)}
+		}
+	case ApproxCountDistinct:
+		return newHLLKernel(input)
+	case ApproxQuantile:
+		return newTDigestKernel(input)
+	default:
+		return kernel{}, fmt.Errorf("unsupported aggregation type: %s", spec.Type)
+	}
+
+	return kernel{newStates: newStates, filter: input.Filter}, nil
+}
+
+// filled returns n rows of -1.
+func filled(n int) []int32 {
+	rows := make([]int32, n)
+	for i := range rows {
+		rows[i] = -1
+	}
+	return rows
+}
+
+// sumStates sums, and for means counts, the non-null values of every group.
+type sumStates struct {
+	values *array.Float64
+	mean   bool
+	sums   []float64
+	counts []int64
+}
+
+func (s *sumStates) add(lo int, slots []int32) {
+	for i, slot := range slots {
+		if slot >= 0 && s.values.IsValid(lo+i) {
+			s.sums[slot] += s.values.Value(lo + i)
+			s.counts[slot]++
+		}
+	}
+}
+
+func (s *sumStates) merge(slot int, other states, otherSlot int) {
+	o := other.(*sumStates)
+	s.sums[slot] += o.sums[otherSlot]
+	s.counts[slot] += o.counts[otherSlot]
+}
+
+func (s *sumStates) build(pool memory.Allocator) (arrow.Array, error) {
+	builder := array.NewFloat64Builder(pool)
+	defer builder.Release()
+	if !s.mean {
+		builder.AppendValues(s.sums, nil)
+		return builder.NewArray(), nil
+	}
+	for i, count := range s.counts {
+		if count > 0 {
+			builder.Append(s.sums[i] / float64(count))
+		} else {
+			builder.AppendNull()
+		}
+	}
+	return builder.NewArray(), nil
+}
+
+// countStates counts the non-null values of every group.
+type countStates struct {
+	values arrow.Array
+	counts []int64
+}
+
+func (s *countStates) add(lo int, slots []int32) {
+	for i, slot := range slots {
+		if slot >= 0 && s.values.IsValid(lo+i) {
+			s.counts[slot]++
+		}
+	}
+}
+
+func (s *countStates) merge(slot int, other states, otherSlot int) {
+	s.counts[slot] += other.(*countStates).counts[otherSlot]
+}
+
+func (s *countStates) build(pool memory.Allocator) (arrow.Array, error) {
+	builder := array.NewInt64Builder(pool)
+	defer builder.Release()
+	builder.AppendValues(s.counts, nil)
+	return builder.NewArray(), nil
+}
+
+// distinctKey identifies the values of one group with one hash.
+type distinctKey struct {
+	slot int32
+	hash uint64
+}
+
+// distinctStates counts the distinct non-null values of every group. Values
+// are told apart by their typed hash and compared on collision.
+type distinctStates struct {
+	keys *rowhash.Keys
+	// rows holds one row of every distinct value of a group
+	rows [][]int32
+	seen map[distinctKey][]int32
+}
+
+func (s *distinctStates) insert(slot int, row int32) {
+	key := distinctKey{slot: int32(slot), hash: s.keys.Hash(int(row))}
+	for _, other := range s.seen[key] {
+		if s.keys.Equal(int(row), s.keys, int(other)) {
+			return
+		}
+	}
+	s.seen[key] = append(s.seen[key], row)
+	s.rows[slot] = append(s.rows[slot], row)
+}
+
+func (s *distinctStates) add(lo int, slots []int32) {
+	for i, slot := range slots {
+		if slot >= 0 && !s.keys.HasNull(lo+i) {
+			s.insert(int(slot), int32(lo+i))
+		}
+	}
+}
+
+func (s *distinctStates) merge(slot int, other states, otherSlot int) {
+	for _, row := range other.(*distinctStates).rows[otherSlot] {
+		s.insert(slot, row)
+	}
+}
+
+func (s *distinctStates) build(pool memory.Allocator) (arrow.Array, error) {
+	builder := array.NewInt64Builder(pool)
+	defer builder.Release()
+	for _, rows := range s.rows {
+		builder.Append(int64(len(rows)))
+	}
+	return builder.NewArray(), nil
+}
+
+// extremeStates tracks the minimum or maximum non-null value of every group.
+type extremeStates struct {
+	values   *array.Float64
+	max      bool
+	extremes []float64
+	seen     []bool
+}
+
+func (s *extremeStates) update(slot int, v float64) {
+	if !s.seen[slot] || (s.max && v > s.extremes[slot]) || (!s.max && v < s.extremes[slot]) {
+		s.extremes[slot] = v
+		s.seen[slot] = true
+	}
+}
+
+func (s *extremeStates) add(lo int, slots []int32) {
+	for i, slot := range slots {
+		if slot >= 0 && s.values.IsValid(lo+i) {
+			s.update(int(slot), s.values.Value(lo+i))
+		}
+	}
+}
+
+func (s *extremeStates) merge(slot int, other states, otherSlot int) {
+	o := other.(*extremeStates)
+	if o.seen[otherSlot] {
+		s.update(slot, o.extremes[otherSlot])
+	}
+}
+
+func (s *extremeStates) build(pool memory.Allocator) (arrow.Array, error) {
+	builder := array.NewFloat64Builder(pool)
+	defer builder.Release()
+	builder.AppendValues(s.extremes, s.seen)
+	return builder.NewArray(), nil
+}
+
+// momentStates tracks the count, mean and central moments of the non-null
+// values of every group, updated one value at a time (Welford, Terriberry)
+// and combined with Pébay's pairwise formulas. The moments give the sample
+// variance and standard deviation, and the bias-corrected skewness and
+// excess kurtosis that pandas reports.
+type momentStates struct {
+	values *array.Float64
+	result AggregationType
+	counts []float64
+	means  []float64
+	m2s    []float64
+	m3s    []float64
+	m4s    []float64
+}
+
+func (s *momentStates) add(lo int, slots []int32) {
+	for i, slot := range slots {
+		if slot < 0 || s.values.IsNull(lo+i) {
+			continue
+		}
+		v := s.values.Value(lo + i)
+		n1 := s.counts[slot]
+		n := n1 + 1
+		delta := v - s.means[slot]
+		deltaN := delta / n
+		deltaN2 := deltaN * deltaN
+		term := delta * deltaN * n1
+		s.m4s[slot] += term*deltaN2*(n*n-3*n+3) + 6*deltaN2*s.m2s[slot] - 4*deltaN*s.m3s[slot]
+		s.m3s[slot] += term*deltaN*(n-2) - 3*deltaN*s.m2s[slot]
+		s.m2s[slot] += term
+		s.means[slot] += deltaN
+		s.counts[slot] = n
+	}
+}
+
+func (s *momentStates) merge(slot int, other states, otherSlot int) {
+	o := other.(*momentStates)
+	nb := o.counts[otherSlot]
+	if nb == 0 {
+		return
+	}
+	na := s.counts[slot]
+	if na == 0 {
+		s.counts[slot], s.means[slot] = nb, o.means[otherSlot]
+		s.m2s[slot], s.m3s[slot], s.m4s[slot] = o.m2s[otherSlot], o.m3s[otherSlot], o.m4s[otherSlot]
+		return
+	}
+	n := na + nb
+	delta := o.means[otherSlot] - s.means[slot]
+	delta2 := delta * delta
+	m2a, m3a := s.m2s[slot], s.m3s[slot]
+	m2b, m3b := o.m2s[otherSlot], o.m3s[otherSlot]
+
+	s.m4s[slot] += o.m4s[otherSlot] +
+		delta2*delta2*na*nb*(na*na-na*nb+nb*nb)/(n*n*n) +
+		6*delta2*(na*na*m2b+nb*nb*m2a)/(n*n) +
+		4*delta*(na*m3b-nb*m3a)/n
+	s.m3s[slot] += m3b + delta2*delta*na*nb*(na-nb)/(n*n) + 3*delta*(na*m2b-nb*m2a)/n
+	s.m2s[slot] += m2b + delta2*na*nb/n
+	s.means[slot] += delta * nb / n
+	s.counts[slot] = n
+}
+
+func (s *momentStates) build(pool memory.Allocator) (arrow.Array, error) {
+	builder := array.NewFloat64Builder(pool)
+	defer builder.Release()
+	for i, n := range s.counts {
+		m2 := s.m2s[i]
+		switch {
+		case (s.result == VarianceAgg || s.result == StdDevAgg) && n >= 2:
+			// Sample variance uses N-1 denominator (Bessel's correction)
+			variance := m2 / (n - 1)
+			if s.result == StdDevAgg {
+				variance = math.Sqrt(variance)
+			}
+			builder.Append(variance)
+		case s.result == Skew && n >= 3 && m2 > 0:
+			g1 := math.Sqrt(n) * s.m3s[i] / math.Pow(m2, 1.5)
+			builder.Append(g1 * math.Sqrt(n*(n-1)) / (n - 2))
+		case s.result == Kurtosis && n >= 4 && m2 > 0:
+			g2 := n*s.m4s[i]/(m2*m2) - 3
+			builder.Append(((n+1)*g2 + 6) * (n - 1) / ((n - 2) * (n - 3)))
+		default:
+			builder.AppendNull()
+		}
+	}
+	return builder.NewArray(), nil
+}
+
+// comomentStates tracks the means, second moments and co-moment of the
+// rows of every group where both columns are non-null, for the sample
+// covariance and the Pearson correlation.
+type comomentStates struct {
+	x, y        *array.Float64
+	correlation bool
+	counts      []float64
+	meanXs      []float64
+	meanYs      []float64
+	m2Xs        []float64
+	m2Ys        []float64
+	cXYs        []float64
+}
+
+func (s *comomentStates) add(lo int, slots []int32) {
+	for i, slot := range slots {
+		row := lo + i
+		if slot < 0 || s.x.IsNull(row) || s.y.IsNull(row) {
+			continue
+		}
+		x, y := s.x.Value(row), s.y.Value(row)
+		s.counts[slot]++
+		n := s.counts[slot]
+		dx := x - s.meanXs[slot]
+		dy := y - s.meanYs[slot]
+		s.meanXs[slot] += dx / n
+		s.meanYs[slot] += dy / n
+		s.m2Xs[slot] += dx * (x - s.meanXs[slot])
+		s.m2Ys[slot] += dy * (y - s.meanYs[slot])
+		s.cXYs[slot] += dx * (y - s.meanYs[slot])
+	}
+}
+
+func (s *comomentStates) merge(slot int, other states, otherSlot int) {
+	o := other.(*comomentStates)
+	nb := o.counts[otherSlot]
+	if nb == 0 {
+		return
+	}
+	na := s.counts[slot]
+	n := na + nb
+	dx := o.meanXs[otherSlot] - s.meanXs[slot]
+	dy := o.meanYs[otherSlot] - s.meanYs[slot]
+	s.m2Xs[slot] += o.m2Xs[otherSlot] + dx*dx*na*nb/n
+	s.m2Ys[slot] += o.m2Ys[otherSlot] + dy*dy*na*nb/n
+	s.cXYs[slot] += o.cXYs[otherSlot] + dx*dy*na*nb/n
+	s.meanXs[slot] += dx * nb / n
+	s.meanYs[slot] += dy * nb / n
+	s.counts[slot] = n
+}
+
+func (s *comomentStates) build(pool memory.Allocator) (arrow.Array, error) {
+	builder := array.NewFloat64Builder(pool)
+	defer builder.Release()
+	for i, n := range s.counts {
+		switch {
+		case n < 2:
+			// Need at least 2 paired values
+			builder.AppendNull()
+		case !s.correlation:
+			builder.Append(s.cXYs[i] / (n - 1))
+		case s.m2Xs[i] == 0 || s.m2Ys[i] == 0:
+			// No variance - undefined correlation
+			builder.AppendNull()
+		default:
+			builder.Append(s.cXYs[i] / math.Sqrt(s.m2Xs[i]*s.m2Ys[i]))
+		}
+	}
+	return builder.NewArray(), nil
+}
+
+// collectStates collects the non-null values of every group in row order
+// and reduces them with finish once all rows have been merged.
+type collectStates struct {
+	values *array.Float64
+	finish func(values []float64) float64
+	groups [][]float64
+}
+
+func (s *collectStates) add(lo int, slots []int32) {
+	for i, slot := range slots {
+		if slot >= 0 && s.values.IsValid(lo+i) {
+			s.groups[slot] = append(s.groups[slot], s.values.Value(lo+i))
+		}
+	}
+}
+
+func (s *collectStates) merge(slot int, other states, otherSlot int) {
+	s.groups[slot] = append(s.groups[slot], other.(*collectStates).groups[otherSlot]...)
+}
+
+func (s *collectStates) build(pool memory.Allocator) (arrow.Array, error) {
+	builder := array.NewFloat64Builder(pool)
+	defer builder.Release()
+	for _, values := range s.groups {
+		if len(values) > 0 {
+			builder.Append(s.finish(values))
+		} else {
+			builder.AppendNull()
+		}
+	}
+	return builder.NewArray(), nil
+}
+
+// percentile returns the p-th quantile of values, interpolating linearly
+// between the closest ranks.
+func percentile(values []float64, p float64) float64 {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	rank := p * float64(len(sorted)-1)
+	lower := int(rank)
+	if lower+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	fraction := rank - float64(lower)
+	return sorted[lower] + fraction*(sorted[lower+1]-sorted[lower])
+}
+
+// mode returns the most frequent of values, the smallest one on ties.
+func mode(values []float64) float64 {
+	frequency := make(map[float64]int)
+	for _, v := range values {
+		frequency[v]++
+	}
+	var modeValue float64
+	maxFreq := 0
+	for v, freq := range frequency {
+		if freq > maxFreq || (freq == maxFreq && v < modeValue) {
+			maxFreq = freq
+			modeValue = v
+		}
+	}
+	return modeValue
+}
+
+// positionStates tracks the first or last row of every group with a
+// non-null value.
+type positionStates struct {
+	values arrow.Array
+	last   bool
+	rows   []int32
+}
+
+func (s *positionStates) add(lo int, slots []int32) {
+	for i, slot := range slots {
+		if slot >= 0 && s.values.IsValid(lo+i) && (s.last || s.rows[slot] < 0) {
+			s.rows[slot] = int32(lo + i)
+		}
+	}
+}
+
+func (s *positionStates) merge(slot int, other states, otherSlot int) {
+	row := other.(*positionStates).rows[otherSlot]
+	if row >= 0 && (s.last || s.rows[slot] < 0) {
+		s.rows[slot] = row
+	}
+}
+
+func (s *positionStates) build(pool memory.Allocator) (arrow.Array, error) {
+	return takeRows(s.values, s.rows, pool)
+}
+
+// argStates tracks the row of every group with the smallest or largest
+// non-null value of the by column, the first such row on ties.
+type argStates struct {
+	values arrow.Array
+	by     *rowhash.Keys
+	max    bool
+	rows   []int32
+}
+
+func (s *argStates) update(slot int, row int32) {
+	best := s.rows[slot]
+	if best < 0 {
+		s.rows[slot] = row
+		return
+	}
+	c := s.by.Compare(int(row), s.by, int(best))
+	if (s.max && c > 0) || (!s.max && c < 0) {
+		s.rows[slot] = row
+	}
+}
+
+func (s *argStates) add(lo int, slots []int32) {
+	for i, slot := range slots {
+		if slot >= 0 && !s.by.HasNull(lo+i) {
+			s.update(int(slot), int32(lo+i))
+		}
+	}
+}
+
+func (s *argStates) merge(slot int, other states, otherSlot int) {
+	if row := other.(*argStates).rows[otherSlot]; row >= 0 {
+		s.update(slot, row)
+	}
+}
+
+func (s *argStates) build(pool memory.Allocator) (arrow.Array, error) {
+	return takeRows(s.values, s.rows, pool)
+}
+
+// boolStates tracks whether any or all non-null values of every group are
+// true. Groups without non-null values are null.
+type boolStates struct {
+	values  *array.Boolean
+	all     bool
+	results []bool
+	seen    []bool
+}
+
+func (s *boolStates) update(slot int, v bool) {
+	if !s.seen[slot] {
+		s.results[slot], s.seen[slot] = v, true
+	} else if s.all {
+		s.results[slot] = s.results[slot] && v
+	} else {
+		s.results[slot] = s.results[slot] || v
+	}
+}
+
+func (s *boolStates) add(lo int, slots []int32) {
+	for i, slot := range slots {
+		if slot >= 0 && s.values.IsValid(lo+i) {
+			s.update(int(slot), s.values.Value(lo+i))
+		}
+	}
+}
+
+func (s *boolStates) merge(slot int, other states, otherSlot int) {
+	o := other.(*boolStates)
+	if o.seen[otherSlot] {
+		s.update(slot, o.results[otherSlot])
+	}
+}
+
+func (s *boolStates) build(pool memory.Allocator) (arrow.Array, error) {
+	builder := array.NewBooleanBuilder(pool)
+	defer builder.Release()
+	builder.AppendValues(s.results, s.seen)
+	return builder.NewArray(), nil
+}
+
+// listStates collects the rows of the non-null values of every group, and
+// builds a list of the values or, for Concat, joins their text with a
+// separator. Groups without non-null values are null strings but empty
+// lists.
+type listStates struct {
+	values    arrow.Array
+	concat    bool
+	separator string
+	rows      [][]int32
+}
+
+func (s *listStates) add(lo int, slots []int32) {
+	for i, slot := range slots {
+		if slot >= 0 && s.values.IsValid(lo+i) {
+			s.rows[slot] = append(s.rows[slot], int32(lo+i))
+		}
+	}
+}
+
+func (s *listStates) merge(slot int, other states, otherSlot int) {
+	s.rows[slot] = append(s.rows[slot], other.(*listStates).rows[otherSlot]...)
+}
+
+func (s *listStates) build(pool memory.Allocator) (arrow.Array, error) {
+	if s.concat {
+		builder := array.NewStringBuilder(pool)
+		defer builder.Release()
+		var parts []string
+		for _, rows := range s.rows {
+			if len(rows) == 0 {
+				builder.AppendNull()
+				continue
+			}
+			parts = parts[:0]
+			for _, row := range rows {
+				parts = append(parts, s.values.ValueStr(int(row)))
+			}
+			builder.Append(strings.Join(parts, s.separator))
+		}
+		return builder.NewArray(), nil
+	}
+
+	offsets := make([]int32, 1, len(s.rows)+1)
+	var all []int32
+	for _, rows := range s.rows {
+		all = append(all, rows...)
+		offsets = append(offsets, int32(len(all)))
+	}
+	values, err := takeRows(s.values, all, pool)
+	if err != nil {
+		return nil, err
+	}
+	defer values.Release()
+	data := array.NewData(
+		arrow.ListOf(s.values.DataType()), len(s.rows),
+		[]*memory.Buffer{nil, memory.NewBufferBytes(arrow.Int32Traits.CastToBytes(offsets))},
+		[]arrow.ArrayData{values.Data()}, 0, 0)
+	defer data.Release()
+	return array.NewListData(data), nil
+}
+
+// String returns the name of an aggregation type as used in error messages
+// and query plans.
+func (t AggregationType) String() string {
+	names := [...]string{
+		Sum: "sum", Mean: "mean", Count: "count", Min: "min", Max: "max",
+		Percentile: "percentile", Median: "median", Mode: "mode", Correlation: "correlation",
+		VarianceAgg: "variance", StdDevAgg: "stddev", CountDistinct: "count_distinct",
+		First: "first", Last: "last", Any: "any", All: "all", Skew: "skew",
+		Kurtosis: "kurtosis", Covariance: "covariance", ArgMin: "arg_min", ArgMax: "arg_max",
+		CollectList: "collect_list", Concat: "concat", ApproxCountDistinct: "approx_count_distinct",
+		ApproxQuantile: "approx_quantile", Custom: "custom",
+	}
+	if t >= 0 && int(t) < len(names) {
+		return names[t]
+	}
+	return fmt.Sprintf("AggregationType(%d)", int(t))
+}
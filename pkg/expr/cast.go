@@ -0,0 +1,480 @@
+package expr
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/compute"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/felixgeelhaar/GopherFrame/pkg/core"
+)
+
+// ====================
+// Type Casting
+// ====================
+
+// CastMode controls how a cast handles values that cannot be converted.
+type CastMode int
+
+const (
+	// CastStrict fails the cast when any value cannot be converted.
+	CastStrict CastMode = iota
+	// CastSafe turns values that cannot be converted into nulls.
+	CastSafe
+)
+
+// String returns the name of the cast mode.
+func (m CastMode) String() string {
+	if m == CastSafe {
+		return "safe"
+	}
+	return "strict"
+}
+
+// CastExpr converts the result of an expression to another Arrow data type.
+type CastExpr struct {
+	operand  Expr
+	dataType arrow.DataType
+	mode     CastMode
+}
+
+// NewCastExpr creates a new cast expression.
+func NewCastExpr(operand Expr, dataType arrow.DataType, mode CastMode) Expr {
+	return &CastExpr{
+		operand:  operand,
+		dataType: dataType,
+		mode:     mode,
+	}
+}
+
+// Evaluate implements Expr.Evaluate for cast expressions.
+func (ce *CastExpr) Evaluate(df *core.DataFrame) (arrow.Array, error) {
+	operandArray, err := ce.operand.Evaluate(df)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate operand: %w", err)
+	}
+	defer operandArray.Release()
+
+	return CastArray(operandArray, ce.dataType, ce.mode)
+}
+
+// EvaluateRecord implements Expr.EvaluateRecord for cast expressions.
+func (ce *CastExpr) EvaluateRecord(record arrow.Record) (arrow.Array, error) {
+	return EvaluateRecord(ce, record)
+}
+
+// Name implements Expr.Name for cast expressions.
+func (ce *CastExpr) Name() string {
+	return fmt.Sprintf("%s(%s as %s)", ce.function(), ce.operand.Name(), ce.dataType)
+}
+
+// String implements Expr.String for cast expressions.
+func (ce *CastExpr) String() string {
+	return fmt.Sprintf("%s(%s as %s)", ce.function(), ce.operand.String(), ce.dataType)
+}
+
+func (ce *CastExpr) function() string {
+	if ce.mode == CastSafe {
+		return "try_cast"
+	}
+	return "cast"
+}
+
+// Introspection methods for CastExpr
+func (ce *CastExpr) Children() []Expr {
+	return []Expr{ce.operand}
+}
+
+func (ce *CastExpr) Op() string {
+	return ce.function()
+}
+
+func (ce *CastExpr) Columns() []string {
+	return collectColumns(ce)
+}
+
+func (ce *CastExpr) WithChildren(children []Expr) (Expr, error) {
+	if err := checkArity(ce.function(), children, 1); err != nil {
+		return nil, err
+	}
+	return NewCastExpr(children[0], ce.dataType, ce.mode), nil
+}
+
+// DataType returns the target type of the cast.
+func (ce *CastExpr) DataType() arrow.DataType {
+	return ce.dataType
+}
+
+// CastArray converts an array to the given data type.
+//
+// Supported targets include int32, int64, float32, float64, string, bool, date32,
+// timestamp and decimal128. Conversions are backed by Arrow's compute cast kernels,
+// with dedicated parsers for strings (base-10 integers, dates, timestamps, decimals)
+// and for integer to decimal conversion. Lossy conversions such as truncating
+// floats or overflowing integers are treated as failures.
+//
+// In CastStrict mode the first value that cannot be converted returns an error.
+// In CastSafe mode such values become null. The caller must release the result.
+func CastArray(arr arrow.Array, dataType arrow.DataType, mode CastMode) (arrow.Array, error) {
+	if arrow.TypeEqual(arr.DataType(), dataType) {
+		arr.Retain()
+		return arr, nil
+	}
+
+	pool := memory.NewGoAllocator()
+	builder := array.NewBuilder(pool, dataType)
+	defer builder.Release()
+
+	if convert, ok := elementConverter(arr, builder); ok {
+		builder.Reserve(arr.Len())
+		for i := 0; i < arr.Len(); i++ {
+			if arr.IsNull(i) {
+				builder.AppendNull()
+				continue
+			}
+			if err := convert(i); err != nil {
+				if mode == CastStrict {
+					return nil, fmt.Errorf("cannot cast %s to %s at index %d: %w", arr.DataType(), dataType, i, err)
+				}
+				builder.AppendNull()
+			}
+		}
+		return builder.NewArray(), nil
+	}
+
+	if !compute.CanCast(arr.DataType(), dataType) {
+		return nil, fmt.Errorf("unsupported cast from %s to %s", arr.DataType(), dataType)
+	}
+
+	result, err := compute.CastArray(context.Background(), arr, compute.SafeCastOptions(dataType))
+	if err == nil {
+		return result, nil
+	}
+	if mode == CastStrict {
+		return nil, fmt.Errorf("cannot cast %s to %s: %w", arr.DataType(), dataType, err)
+	}
+	return castReplacingFailures(pool, arr, dataType)
+}
+
+// castReplacingFailures casts arr with Arrow's kernels, replacing values that
+// fail to convert with nulls. Failing ranges are bisected so the kernel still
+// runs over large valid runs.
+func castReplacingFailures(pool memory.Allocator, arr arrow.Array, dataType arrow.DataType) (arrow.Array, error) {
+	opts := compute.SafeCastOptions(dataType)
+	var pieces []arrow.Array
+	defer func() { releaseArrays(pieces) }()
+
+	var castRange func(offset, length int64)
+	castRange = func(offset, length int64) {
+		slice := array.NewSlice(arr, offset, offset+length)
+		defer slice.Release()
+
+		converted, err := compute.CastArray(context.Background(), slice, opts)
+		switch {
+		case err == nil:
+			pieces = append(pieces, converted)
+		case length == 1:
+			pieces = append(pieces, array.MakeArrayOfNull(pool, dataType, 1))
+		default:
+			half := length / 2
+			castRange(offset, half)
+			castRange(offset+half, length-half)
+		}
+	}
+	castRange(0, int64(arr.Len()))
+
+	result, err := array.Concatenate(pieces, pool)
+	if err != nil {
+		return nil, fmt.Errorf("failed to concatenate cast results: %w", err)
+	}
+	return result, nil
+}
+
+// elementConverter returns a function that converts the value at index i of arr
+// and appends it to builder, for conversions that Arrow's kernels either lack or
+// get wrong (for example, strings with leading zeros are parsed as octal).
+func elementConverter(arr arrow.Array, builder array.Builder) (func(i int) error, bool) {
+	if str, ok := arr.(*array.String); ok {
+		return stringConverter(str, builder)
+	}
+
+	if decBuilder, ok := builder.(*array.Decimal128Builder); ok && arrow.IsInteger(arr.DataType().ID()) {
+		decType := decBuilder.Type().(*arrow.Decimal128Type)
+		return func(i int) error {
+			n := decimal128.FromI64(extractInt64Value(arr, i)).IncreaseScaleBy(decType.Scale)
+			if !n.FitsInPrecision(decType.Precision) {
+				return fmt.Errorf("value %d does not fit in %s", extractInt64Value(arr, i), decType)
+			}
+			decBuilder.Append(n)
+			return nil
+		}, true
+	}
+
+	return nil, false
+}
+
+// stringConverter parses string values into the builder's type.
+func stringConverter(str *array.String, builder array.Builder) (func(i int) error, bool) {
+	switch b := builder.(type) {
+	case *array.Int64Builder:
+		return func(i int) error {
+			v, err := strconv.ParseInt(strings.TrimSpace(str.Value(i)), 10, 64)
+			if err != nil {
+				return err
+			}
+			b.Append(v)
+			return nil
+		}, true
+
+	case *array.Int32Builder:
+		return func(i int) error {
+			v, err := strconv.ParseInt(strings.TrimSpace(str.Value(i)), 10, 32)
+			if err != nil {
+				return err
+			}
+			b.Append(int32(v))
+			return nil
+		}, true
+
+	case *array.Date32Builder:
+		return func(i int) error {
+			t, err := time.Parse("2006-01-02", strings.TrimSpace(str.Value(i)))
+			if err != nil {
+				return err
+			}
+			b.Append(arrow.Date32FromTime(t))
+			return nil
+		}, true
+
+	case *array.TimestampBuilder:
+		tsType := b.Type().(*arrow.TimestampType)
+		loc, err := tsType.GetZone()
+		if err != nil || loc == nil {
+			loc = time.UTC
+		}
+		return func(i int) error {
+			ts, _, err := arrow.TimestampFromStringInLocation(strings.TrimSpace(str.Value(i)), tsType.Unit, loc)
+			if err != nil {
+				return err
+			}
+			b.Append(ts)
+			return nil
+		}, true
+
+	case *array.Decimal128Builder:
+		decType := b.Type().(*arrow.Decimal128Type)
+		return func(i int) error {
+			n, err := decimal128.FromString(strings.TrimSpace(str.Value(i)), decType.Precision, decType.Scale)
+			if err != nil {
+				return err
+			}
+			if !n.FitsInPrecision(decType.Precision) {
+				return fmt.Errorf("value %q does not fit in %s", str.Value(i), decType)
+			}
+			b.Append(n)
+			return nil
+		}, true
+	}
+
+	return nil, false
+}
+
+// Fluent methods for CastExpr
+func (ce *CastExpr) Add(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "add")
+}
+
+func (ce *CastExpr) Sub(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "subtract")
+}
+
+func (ce *CastExpr) Mul(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "multiply")
+}
+
+func (ce *CastExpr) Div(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "divide")
+}
+
+func (ce *CastExpr) Gt(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "greater")
+}
+
+func (ce *CastExpr) Lt(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "less")
+}
+
+func (ce *CastExpr) Eq(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "equal")
+}
+
+func (ce *CastExpr) Ne(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "not_equal")
+}
+
+func (ce *CastExpr) Ge(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "greater_equal")
+}
+
+func (ce *CastExpr) Le(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "less_equal")
+}
+
+// Boolean logic methods for CastExpr
+func (ce *CastExpr) And(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "and")
+}
+
+func (ce *CastExpr) Or(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "or")
+}
+
+func (ce *CastExpr) Not() Expr {
+	return NewUnaryExpr(ce, "not")
+}
+
+// Null handling methods for CastExpr
+func (ce *CastExpr) IsNull() Expr {
+	return NewUnaryExpr(ce, "is_null")
+}
+
+func (ce *CastExpr) IsNotNull() Expr {
+	return NewUnaryExpr(ce, "is_not_null")
+}
+
+func (ce *CastExpr) FillNull(value Expr) Expr {
+	return Coalesce(ce, value)
+}
+
+func (ce *CastExpr) NullIf(value Expr) Expr {
+	return NewBinaryExpr(ce, value, "null_if")
+}
+
+// Type conversion methods for CastExpr
+func (ce *CastExpr) Cast(dataType arrow.DataType) Expr {
+	return NewCastExpr(ce, dataType, CastStrict)
+}
+
+func (ce *CastExpr) TryCast(dataType arrow.DataType) Expr {
+	return NewCastExpr(ce, dataType, CastSafe)
+}
+
+// String manipulation methods for CastExpr
+func (ce *CastExpr) Contains(substring Expr) Expr {
+	return NewBinaryExpr(ce, substring, "contains")
+}
+
+func (ce *CastExpr) StartsWith(prefix Expr) Expr {
+	return NewBinaryExpr(ce, prefix, "starts_with")
+}
+
+func (ce *CastExpr) EndsWith(suffix Expr) Expr {
+	return NewBinaryExpr(ce, suffix, "ends_with")
+}
+
+func (ce *CastExpr) Upper() Expr {
+	return NewUnaryExpr(ce, "upper")
+}
+
+func (ce *CastExpr) Lower() Expr {
+	return NewUnaryExpr(ce, "lower")
+}
+
+func (ce *CastExpr) Trim() Expr {
+	return NewUnaryExpr(ce, "trim")
+}
+
+func (ce *CastExpr) TrimLeft() Expr {
+	return NewUnaryExpr(ce, "trim_left")
+}
+
+func (ce *CastExpr) TrimRight() Expr {
+	return NewUnaryExpr(ce, "trim_right")
+}
+
+func (ce *CastExpr) Length() Expr {
+	return NewUnaryExpr(ce, "length")
+}
+
+func (ce *CastExpr) Match(pattern Expr) Expr {
+	return NewBinaryExpr(ce, pattern, "match")
+}
+
+func (ce *CastExpr) Replace(old, new Expr) Expr {
+	return NewTernaryExpr(ce, old, new, "replace")
+}
+
+func (ce *CastExpr) PadLeft(length, pad Expr) Expr {
+	return NewTernaryExpr(ce, length, pad, "pad_left")
+}
+
+func (ce *CastExpr) PadRight(length, pad Expr) Expr {
+	return NewTernaryExpr(ce, length, pad, "pad_right")
+}
+
+func (ce *CastExpr) SplitPart(separator, index Expr) Expr {
+	return NewTernaryExpr(ce, separator, index, "split_part")
+}
+
+// Temporal methods for CastExpr
+func (ce *CastExpr) Year() Expr {
+	return NewUnaryExpr(ce, "year")
+}
+
+func (ce *CastExpr) Month() Expr {
+	return NewUnaryExpr(ce, "month")
+}
+
+func (ce *CastExpr) Day() Expr {
+	return NewUnaryExpr(ce, "day")
+}
+
+func (ce *CastExpr) Hour() Expr {
+	return NewUnaryExpr(ce, "hour")
+}
+
+func (ce *CastExpr) Minute() Expr {
+	return NewUnaryExpr(ce, "minute")
+}
+
+func (ce *CastExpr) Second() Expr {
+	return NewUnaryExpr(ce, "second")
+}
+
+func (ce *CastExpr) TruncateToYear() Expr {
+	return NewUnaryExpr(ce, "trunc_year")
+}
+
+func (ce *CastExpr) TruncateToMonth() Expr {
+	return NewUnaryExpr(ce, "trunc_month")
+}
+
+func (ce *CastExpr) TruncateToDay() Expr {
+	return NewUnaryExpr(ce, "trunc_day")
+}
+
+func (ce *CastExpr) TruncateToHour() Expr {
+	return NewUnaryExpr(ce, "trunc_hour")
+}
+
+func (ce *CastExpr) AddDays(days Expr) Expr {
+	return NewBinaryExpr(ce, days, "add_days")
+}
+
+func (ce *CastExpr) AddHours(hours Expr) Expr {
+	return NewBinaryExpr(ce, hours, "add_hours")
+}
+
+func (ce *CastExpr) AddMinutes(minutes Expr) Expr {
+	return NewBinaryExpr(ce, minutes, "add_minutes")
+}
+
+func (ce *CastExpr) AddSeconds(seconds Expr) Expr {
+	return NewBinaryExpr(ce, seconds, "add_seconds")
+}
@@ -0,0 +1,180 @@
+package expr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/felixgeelhaar/GopherFrame/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createRawStringDataFrame builds a single string column named "raw".
+func createRawStringDataFrame(t *testing.T, values []string, valid []bool) *core.DataFrame {
+	pool := memory.NewGoAllocator()
+
+	builder := array.NewStringBuilder(pool)
+	defer builder.Release()
+	builder.AppendValues(values, valid)
+	arr := builder.NewArray()
+	defer arr.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "raw", Type: arrow.BinaryTypes.String, Nullable: true}}, nil)
+	record := array.NewRecord(schema, []arrow.Array{arr}, int64(len(values)))
+	defer record.Release()
+
+	return core.NewDataFrame(record)
+}
+
+func TestCast_StringToInt64_ParsesBase10(t *testing.T) {
+	df := createRawStringDataFrame(t, []string{"01234", " 42", "-7", ""}, []bool{true, true, true, false})
+	defer df.Release()
+
+	result, err := Col("raw").Cast(arrow.PrimitiveTypes.Int64).Evaluate(df)
+	require.NoError(t, err)
+	defer result.Release()
+
+	ints := result.(*array.Int64)
+	assert.Equal(t, int64(1234), ints.Value(0))
+	assert.Equal(t, int64(42), ints.Value(1))
+	assert.Equal(t, int64(-7), ints.Value(2))
+	assert.True(t, ints.IsNull(3))
+}
+
+func TestCast_StrictFailsOnInvalidValue(t *testing.T) {
+	df := createRawStringDataFrame(t, []string{"1", "abc", "3"}, nil)
+	defer df.Release()
+
+	_, err := Col("raw").Cast(arrow.PrimitiveTypes.Int64).Evaluate(df)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "at index 1")
+}
+
+func TestTryCast_InvalidValuesBecomeNull(t *testing.T) {
+	df := createRawStringDataFrame(t, []string{"1.5", "abc", "3", "x"}, nil)
+	defer df.Release()
+
+	result, err := Col("raw").TryCast(arrow.PrimitiveTypes.Float64).Evaluate(df)
+	require.NoError(t, err)
+	defer result.Release()
+
+	floats := result.(*array.Float64)
+	assert.Equal(t, 1.5, floats.Value(0))
+	assert.True(t, floats.IsNull(1))
+	assert.Equal(t, 3.0, floats.Value(2))
+	assert.True(t, floats.IsNull(3))
+}
+
+func TestCast_FloatToInt(t *testing.T) {
+	df := createTestDataFrame(t)
+	defer df.Release()
+
+	// score: 95.5, 87.2, 92.1 cannot be converted without truncation
+	_, err := Col("score").Cast(arrow.PrimitiveTypes.Int64).Evaluate(df)
+	require.Error(t, err)
+
+	result, err := Col("score").TryCast(arrow.PrimitiveTypes.Int64).Evaluate(df)
+	require.NoError(t, err)
+	defer result.Release()
+	assert.Equal(t, 3, result.NullN())
+
+	// id: 1, 2, 3 round-trips through float64
+	asFloat, err := Col("id").Cast(arrow.PrimitiveTypes.Float64).Cast(arrow.PrimitiveTypes.Int32).Evaluate(df)
+	require.NoError(t, err)
+	defer asFloat.Release()
+	assert.Equal(t, []int32{1, 2, 3}, asFloat.(*array.Int32).Int32Values())
+}
+
+func TestCast_ToString(t *testing.T) {
+	df := createTestDataFrame(t)
+	defer df.Release()
+
+	result, err := Col("id").Cast(arrow.BinaryTypes.String).Evaluate(df)
+	require.NoError(t, err)
+	defer result.Release()
+
+	strs := result.(*array.String)
+	assert.Equal(t, "1", strs.Value(0))
+	assert.Equal(t, "3", strs.Value(2))
+}
+
+func TestCast_StringToTemporal(t *testing.T) {
+	df := createRawStringDataFrame(t, []string{"2024-03-15", "not a date"}, nil)
+	defer df.Release()
+
+	dates, err := Col("raw").TryCast(arrow.FixedWidthTypes.Date32).Evaluate(df)
+	require.NoError(t, err)
+	defer dates.Release()
+
+	dateArr := dates.(*array.Date32)
+	assert.Equal(t, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), dateArr.Value(0).ToTime())
+	assert.True(t, dateArr.IsNull(1))
+
+	tsType := &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}
+	stamps, err := Col("raw").TryCast(tsType).Evaluate(df)
+	require.NoError(t, err)
+	defer stamps.Release()
+
+	tsArr := stamps.(*array.Timestamp)
+	assert.Equal(t, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), tsArr.Value(0).ToTime(arrow.Microsecond))
+	assert.True(t, tsArr.IsNull(1))
+
+	_, err = Col("raw").Cast(tsType).Evaluate(df)
+	require.Error(t, err)
+}
+
+func TestCast_Decimal(t *testing.T) {
+	decType := &arrow.Decimal128Type{Precision: 10, Scale: 2}
+
+	df := createRawStringDataFrame(t, []string{"12.34", "100", "123456789.00"}, nil)
+	defer df.Release()
+
+	result, err := Col("raw").TryCast(decType).Evaluate(df)
+	require.NoError(t, err)
+	defer result.Release()
+
+	decimals := result.(*array.Decimal128)
+	assert.Equal(t, "12.34", decimals.ValueStr(0))
+	assert.Equal(t, "100.00", decimals.Value(1).ToString(decType.Scale))
+	assert.True(t, decimals.IsNull(2), "value exceeds precision 10")
+
+	ids := createTestDataFrame(t)
+	defer ids.Release()
+
+	fromInt, err := Col("id").Cast(decType).Evaluate(ids)
+	require.NoError(t, err)
+	defer fromInt.Release()
+	assert.Equal(t, "3.00", fromInt.(*array.Decimal128).Value(2).ToString(decType.Scale))
+}
+
+func TestCast_BoolAndSameType(t *testing.T) {
+	df := createTestDataFrame(t)
+	defer df.Release()
+
+	result, err := Col("id").Cast(arrow.PrimitiveTypes.Int64).Evaluate(df)
+	require.NoError(t, err)
+	defer result.Release()
+	assert.Equal(t, []int64{1, 2, 3}, result.(*array.Int64).Int64Values())
+
+	flags, err := Col("id").Gt(Lit(int64(1))).Cast(arrow.PrimitiveTypes.Int64).Evaluate(df)
+	require.NoError(t, err)
+	defer flags.Release()
+	assert.Equal(t, []int64{0, 1, 1}, flags.(*array.Int64).Int64Values())
+}
+
+func TestCast_Names(t *testing.T) {
+	assert.Equal(t, "cast(zip as utf8)", Col("zip").Cast(arrow.BinaryTypes.String).Name())
+	assert.Equal(t, "try_cast(Col(amount) as float64)", Col("amount").TryCast(arrow.PrimitiveTypes.Float64).String())
+}
+
+func TestCast_UnsupportedConversion(t *testing.T) {
+	df := createTestDataFrame(t)
+	defer df.Release()
+
+	_, err := Col("id").TryCast(arrow.ListOf(arrow.PrimitiveTypes.Int64)).Evaluate(df)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported cast")
+}
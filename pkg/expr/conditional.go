@@ -0,0 +1,414 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/felixgeelhaar/GopherFrame/pkg/core"
+)
+
+// ====================
+// Conditional Expression
+// ====================
+
+// whenClause is a single WHEN condition THEN value branch.
+type whenClause struct {
+	condition Expr
+	value     Expr
+}
+
+// WhenBuilder is a CASE expression whose last WHEN condition still needs a
+// THEN value. It is not an Expr itself; call Then to complete the branch.
+type WhenBuilder struct {
+	clauses   []whenClause
+	condition Expr
+}
+
+// When starts a conditional CASE WHEN expression.
+//
+// Example:
+//
+//	size := When(Col("amount").Gt(Lit(1000.0))).Then(Lit("large")).
+//	    When(Col("amount").Gt(Lit(100.0))).Then(Lit("medium")).
+//	    Otherwise(Lit("small"))
+func When(condition Expr) *WhenBuilder {
+	return &WhenBuilder{condition: condition}
+}
+
+// Then sets the value produced when the pending condition is true.
+func (wb *WhenBuilder) Then(value Expr) *CaseExpr {
+	clauses := make([]whenClause, len(wb.clauses), len(wb.clauses)+1)
+	copy(clauses, wb.clauses)
+	clauses = append(clauses, whenClause{condition: wb.condition, value: value})
+	return &CaseExpr{clauses: clauses}
+}
+
+// CaseExpr evaluates a chain of WHEN/THEN branches and an optional OTHERWISE value.
+// For each row the value of the first branch whose condition is true is used; a null
+// condition counts as not matched. Rows matching no branch get the OTHERWISE value, or
+// null when none is set. Branch values are unified to a common type as in Coalesce.
+//
+// All branch values are evaluated over the whole DataFrame (vectorized), so an error in
+// any branch fails the expression even for rows that would not select it.
+type CaseExpr struct {
+	clauses   []whenClause
+	otherwise Expr
+}
+
+// When adds another WHEN branch, checked after all previous branches.
+func (ce *CaseExpr) When(condition Expr) *WhenBuilder {
+	return &WhenBuilder{clauses: ce.clauses, condition: condition}
+}
+
+// Otherwise sets the value for rows that match no branch and completes the expression.
+func (ce *CaseExpr) Otherwise(value Expr) Expr {
+	return &CaseExpr{clauses: ce.clauses, otherwise: value}
+}
+
+// Evaluate implements Expr.Evaluate for conditional expressions.
+func (ce *CaseExpr) Evaluate(df *core.DataFrame) (arrow.Array, error) {
+	if len(ce.clauses) == 0 {
+		return nil, fmt.Errorf("case expression requires at least one WHEN branch")
+	}
+
+	conditions := make([]*array.Boolean, 0, len(ce.clauses))
+	values := make([]arrow.Array, 0, len(ce.clauses)+1)
+	defer func() {
+		for _, cond := range conditions {
+			cond.Release()
+		}
+		releaseArrays(values)
+	}()
+
+	for i, clause := range ce.clauses {
+		condArray, err := clause.condition.Evaluate(df)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate WHEN condition %d: %w", i, err)
+		}
+		cond, ok := condArray.(*array.Boolean)
+		if !ok {
+			condArray.Release()
+			return nil, fmt.Errorf("WHEN condition %d must be boolean, got %s", i, condArray.DataType())
+		}
+		conditions = append(conditions, cond)
+
+		value, err := clause.value.Evaluate(df)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate THEN value %d: %w", i, err)
+		}
+		values = append(values, value)
+	}
+	if ce.otherwise != nil {
+		value, err := ce.otherwise.Evaluate(df)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate OTHERWISE value: %w", err)
+		}
+		values = append(values, value)
+	}
+
+	unified, err := unifyArrays(values)
+	if err != nil {
+		return nil, fmt.Errorf("case expression branches: %w", err)
+	}
+	defer releaseArrays(unified)
+
+	numRows := int(df.NumRows())
+	for _, arr := range unified {
+		if arr.Len() != numRows {
+			return nil, fmt.Errorf("array length mismatch: %d vs %d", numRows, arr.Len())
+		}
+	}
+
+	// Map each row to its selected branch within the concatenation of all values
+	pool := memory.NewGoAllocator()
+	indexBuilder := array.NewInt64Builder(pool)
+	defer indexBuilder.Release()
+	indexBuilder.Reserve(numRows)
+
+	for i := 0; i < numRows; i++ {
+		selected := -1
+		for k, cond := range conditions {
+			if cond.IsValid(i) && cond.Value(i) {
+				selected = k
+				break
+			}
+		}
+		switch {
+		case selected >= 0:
+			indexBuilder.Append(int64(selected*numRows + i))
+		case ce.otherwise != nil:
+			indexBuilder.Append(int64(len(conditions)*numRows + i))
+		default:
+			indexBuilder.AppendNull()
+		}
+	}
+	indices := indexBuilder.NewArray()
+	defer indices.Release()
+
+	return takeFromConcatenation(pool, unified, indices)
+}
+
+// EvaluateRecord implements Expr.EvaluateRecord for conditional expressions.
+func (ce *CaseExpr) EvaluateRecord(record arrow.Record) (arrow.Array, error) {
+	return EvaluateRecord(ce, record)
+}
+
+// Name implements Expr.Name for conditional expressions.
+func (ce *CaseExpr) Name() string {
+	return ce.format(Expr.Name)
+}
+
+// String implements Expr.String for conditional expressions.
+func (ce *CaseExpr) String() string {
+	return ce.format(Expr.String)
+}
+
+func (ce *CaseExpr) format(render func(Expr) string) string {
+	var sb strings.Builder
+	sb.WriteString("CASE")
+	for _, clause := range ce.clauses {
+		fmt.Fprintf(&sb, " WHEN %s THEN %s", render(clause.condition), render(clause.value))
+	}
+	if ce.otherwise != nil {
+		fmt.Fprintf(&sb, " ELSE %s", render(ce.otherwise))
+	}
+	sb.WriteString(" END")
+	return sb.String()
+}
+
+// Children returns the branch conditions and values interleaved
+// (condition, value, condition, value, ...), followed by the OTHERWISE value if set.
+func (ce *CaseExpr) Children() []Expr {
+	children := make([]Expr, 0, 2*len(ce.clauses)+1)
+	for _, clause := range ce.clauses {
+		children = append(children, clause.condition, clause.value)
+	}
+	if ce.otherwise != nil {
+		children = append(children, ce.otherwise)
+	}
+	return children
+}
+
+// Op implements Expr.Op for conditional expressions.
+func (ce *CaseExpr) Op() string {
+	return "case"
+}
+
+// Columns implements Expr.Columns for conditional expressions.
+func (ce *CaseExpr) Columns() []string {
+	return collectColumns(ce)
+}
+
+// WithChildren implements Expr.WithChildren, taking children in the order of Children.
+func (ce *CaseExpr) WithChildren(children []Expr) (Expr, error) {
+	if err := checkArity("case", children, len(ce.Children())); err != nil {
+		return nil, err
+	}
+
+	clauses := make([]whenClause, len(ce.clauses))
+	for i := range clauses {
+		clauses[i] = whenClause{condition: children[2*i], value: children[2*i+1]}
+	}
+	var otherwise Expr
+	if ce.otherwise != nil {
+		otherwise = children[len(children)-1]
+	}
+	return &CaseExpr{clauses: clauses, otherwise: otherwise}, nil
+}
+
+// Fluent methods for CaseExpr
+func (ce *CaseExpr) Add(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "add")
+}
+
+func (ce *CaseExpr) Sub(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "subtract")
+}
+
+func (ce *CaseExpr) Mul(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "multiply")
+}
+
+func (ce *CaseExpr) Div(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "divide")
+}
+
+func (ce *CaseExpr) Gt(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "greater")
+}
+
+func (ce *CaseExpr) Lt(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "less")
+}
+
+func (ce *CaseExpr) Eq(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "equal")
+}
+
+func (ce *CaseExpr) Ne(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "not_equal")
+}
+
+func (ce *CaseExpr) Ge(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "greater_equal")
+}
+
+func (ce *CaseExpr) Le(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "less_equal")
+}
+
+// Boolean logic methods for CaseExpr
+func (ce *CaseExpr) And(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "and")
+}
+
+func (ce *CaseExpr) Or(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "or")
+}
+
+func (ce *CaseExpr) Not() Expr {
+	return NewUnaryExpr(ce, "not")
+}
+
+// Null handling methods for CaseExpr
+func (ce *CaseExpr) IsNull() Expr {
+	return NewUnaryExpr(ce, "is_null")
+}
+
+func (ce *CaseExpr) IsNotNull() Expr {
+	return NewUnaryExpr(ce, "is_not_null")
+}
+
+func (ce *CaseExpr) FillNull(value Expr) Expr {
+	return Coalesce(ce, value)
+}
+
+func (ce *CaseExpr) NullIf(value Expr) Expr {
+	return NewBinaryExpr(ce, value, "null_if")
+}
+
+// Type conversion methods for CaseExpr
+func (ce *CaseExpr) Cast(dataType arrow.DataType) Expr {
+	return NewCastExpr(ce, dataType, CastStrict)
+}
+
+func (ce *CaseExpr) TryCast(dataType arrow.DataType) Expr {
+	return NewCastExpr(ce, dataType, CastSafe)
+}
+
+// String manipulation methods for CaseExpr
+func (ce *CaseExpr) Contains(substring Expr) Expr {
+	return NewBinaryExpr(ce, substring, "contains")
+}
+
+func (ce *CaseExpr) StartsWith(prefix Expr) Expr {
+	return NewBinaryExpr(ce, prefix, "starts_with")
+}
+
+func (ce *CaseExpr) EndsWith(suffix Expr) Expr {
+	return NewBinaryExpr(ce, suffix, "ends_with")
+}
+
+func (ce *CaseExpr) Upper() Expr {
+	return NewUnaryExpr(ce, "upper")
+}
+
+func (ce *CaseExpr) Lower() Expr {
+	return NewUnaryExpr(ce, "lower")
+}
+
+func (ce *CaseExpr) Trim() Expr {
+	return NewUnaryExpr(ce, "trim")
+}
+
+func (ce *CaseExpr) TrimLeft() Expr {
+	return NewUnaryExpr(ce, "trim_left")
+}
+
+func (ce *CaseExpr) TrimRight() Expr {
+	return NewUnaryExpr(ce, "trim_right")
+}
+
+func (ce *CaseExpr) Length() Expr {
+	return NewUnaryExpr(ce, "length")
+}
+
+func (ce *CaseExpr) Match(pattern Expr) Expr {
+	return NewBinaryExpr(ce, pattern, "match")
+}
+
+func (ce *CaseExpr) Replace(old, new Expr) Expr {
+	return NewTernaryExpr(ce, old, new, "replace")
+}
+
+func (ce *CaseExpr) PadLeft(length, pad Expr) Expr {
+	return NewTernaryExpr(ce, length, pad, "pad_left")
+}
+
+func (ce *CaseExpr) PadRight(length, pad Expr) Expr {
+	return NewTernaryExpr(ce, length, pad, "pad_right")
+}
+
+func (ce *CaseExpr) SplitPart(separator, index Expr) Expr {
+	return NewTernaryExpr(ce, separator, index, "split_part")
+}
+
+// Temporal methods for CaseExpr
+func (ce *CaseExpr) Year() Expr {
+	return NewUnaryExpr(ce, "year")
+}
+
+func (ce *CaseExpr) Month() Expr {
+	return NewUnaryExpr(ce, "month")
+}
+
+func (ce *CaseExpr) Day() Expr {
+	return NewUnaryExpr(ce, "day")
+}
+
+func (ce *CaseExpr) Hour() Expr {
+	return NewUnaryExpr(ce, "hour")
+}
+
+func (ce *CaseExpr) Minute() Expr {
+	return NewUnaryExpr(ce, "minute")
+}
+
+func (ce *CaseExpr) Second() Expr {
+	return NewUnaryExpr(ce, "second")
+}
+
+func (ce *CaseExpr) TruncateToYear() Expr {
+	return NewUnaryExpr(ce, "trunc_year")
+}
+
+func (ce *CaseExpr) TruncateToMonth() Expr {
+	return NewUnaryExpr(ce, "trunc_month")
+}
+
+func (ce *CaseExpr) TruncateToDay() Expr {
+	return NewUnaryExpr(ce, "trunc_day")
+}
+
+func (ce *CaseExpr) TruncateToHour() Expr {
+	return NewUnaryExpr(ce, "trunc_hour")
+}
+
+func (ce *CaseExpr) AddDays(days Expr) Expr {
+	return NewBinaryExpr(ce, days, "add_days")
+}
+
+func (ce *CaseExpr) AddHours(hours Expr) Expr {
+	return NewBinaryExpr(ce, hours, "add_hours")
+}
+
+func (ce *CaseExpr) AddMinutes(minutes Expr) Expr {
+	return NewBinaryExpr(ce, minutes, "add_minutes")
+}
+
+func (ce *CaseExpr) AddSeconds(seconds Expr) Expr {
+	return NewBinaryExpr(ce, seconds, "add_seconds")
+}
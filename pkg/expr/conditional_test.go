@@ -0,0 +1,128 @@
+package expr
+
+import (
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/felixgeelhaar/GopherFrame/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createAmountDataFrame(t *testing.T) *core.DataFrame {
+	pool := memory.NewGoAllocator()
+
+	builder := array.NewFloat64Builder(pool)
+	defer builder.Release()
+	builder.AppendValues([]float64{5000, 500, 50, 0}, []bool{true, true, true, false})
+	amounts := builder.NewArray()
+	defer amounts.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "amount", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	}, nil)
+	record := array.NewRecord(schema, []arrow.Array{amounts}, 4)
+	defer record.Release()
+
+	return core.NewDataFrame(record)
+}
+
+func TestWhen_Bucketing(t *testing.T) {
+	df := createAmountDataFrame(t)
+	defer df.Release()
+
+	bucket := When(Col("amount").Gt(Lit(1000.0))).Then(Lit("large")).
+		When(Col("amount").Gt(Lit(100.0))).Then(Lit("medium")).
+		Otherwise(Lit("small"))
+
+	result, err := bucket.Evaluate(df)
+	require.NoError(t, err)
+	defer result.Release()
+
+	strResult := result.(*array.String)
+	assert.Equal(t, "large", strResult.Value(0))
+	assert.Equal(t, "medium", strResult.Value(1))
+	assert.Equal(t, "small", strResult.Value(2))
+	// A null condition does not match, so the OTHERWISE value is used
+	assert.Equal(t, "small", strResult.Value(3))
+}
+
+func TestWhen_WithoutOtherwise(t *testing.T) {
+	df := createAmountDataFrame(t)
+	defer df.Release()
+
+	result, err := When(Col("amount").Gt(Lit(100.0))).Then(Col("amount")).Evaluate(df)
+	require.NoError(t, err)
+	defer result.Release()
+
+	floatResult := result.(*array.Float64)
+	assert.Equal(t, 5000.0, floatResult.Value(0))
+	assert.Equal(t, 500.0, floatResult.Value(1))
+	assert.True(t, floatResult.IsNull(2))
+	assert.True(t, floatResult.IsNull(3))
+}
+
+func TestWhen_TypeUnification(t *testing.T) {
+	df := createAmountDataFrame(t)
+	defer df.Release()
+
+	// int64 and float64 branches unify to float64
+	expr := When(Col("amount").Gt(Lit(100.0))).Then(Lit(int64(1))).Otherwise(Col("amount"))
+	result, err := expr.Evaluate(df)
+	require.NoError(t, err)
+	defer result.Release()
+
+	require.Equal(t, arrow.FLOAT64, result.DataType().ID())
+	floatResult := result.(*array.Float64)
+	assert.Equal(t, 1.0, floatResult.Value(0))
+	assert.Equal(t, 1.0, floatResult.Value(1))
+	assert.Equal(t, 50.0, floatResult.Value(2))
+	// The OTHERWISE value is itself null
+	assert.True(t, floatResult.IsNull(3))
+
+	// string and float64 branches cannot be unified
+	_, err = When(Col("amount").Gt(Lit(100.0))).Then(Lit("x")).Otherwise(Col("amount")).Evaluate(df)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "incompatible types")
+}
+
+func TestWhen_Errors(t *testing.T) {
+	df := createAmountDataFrame(t)
+	defer df.Release()
+
+	_, err := When(Col("amount")).Then(Lit(1.0)).Evaluate(df)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be boolean")
+
+	_, err = When(Col("missing").Gt(Lit(1.0))).Then(Lit(1.0)).Evaluate(df)
+	require.Error(t, err)
+}
+
+func TestWhen_BuilderIsImmutable(t *testing.T) {
+	df := createAmountDataFrame(t)
+	defer df.Release()
+
+	base := When(Col("amount").Gt(Lit(1000.0))).Then(Lit("large"))
+	withMedium := base.When(Col("amount").Gt(Lit(100.0))).Then(Lit("medium"))
+
+	result, err := base.Evaluate(df)
+	require.NoError(t, err)
+	defer result.Release()
+	assert.True(t, result.IsNull(1), "branches added later must not affect the base expression")
+
+	result2, err := withMedium.Evaluate(df)
+	require.NoError(t, err)
+	defer result2.Release()
+	assert.Equal(t, "medium", result2.(*array.String).Value(1))
+}
+
+func TestWhen_String(t *testing.T) {
+	expr := When(Col("a").Gt(Lit(int64(1)))).Then(Lit("x")).Otherwise(Lit("y"))
+	assert.Equal(t, "CASE WHEN (Col(a) greater Lit(1)) THEN Lit(x) ELSE Lit(y) END", expr.String())
+
+	// CaseExpr composes with other expressions
+	composed := expr.Eq(Lit("x"))
+	assert.Contains(t, composed.String(), "CASE WHEN")
+}
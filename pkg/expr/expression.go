@@ -4,6 +4,7 @@
 package expr
 
 import (
+	"cmp"
 	"fmt"
 	"regexp"
 	"strings"
@@ -12,7 +13,9 @@ import (
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/arrow/scalar"
 	"github.com/felixgeelhaar/GopherFrame/pkg/core"
+	"github.com/felixgeelhaar/GopherFrame/pkg/storage"
 )
 
 // Expr is the interface that all expression types must implement.
@@ -20,12 +23,29 @@ type Expr interface {
 	// Evaluate executes the expression against a DataFrame and returns the result.
 	Evaluate(df *core.DataFrame) (arrow.Array, error)
 
+	// EvaluateRecord executes the expression against the columns of a
+	// record, which lets storage backends filter the records they read.
+	EvaluateRecord(record arrow.Record) (arrow.Array, error)
+
 	// Name returns the output name of this expression.
 	Name() string
 
 	// String returns a string representation for debugging.
 	String() string
 
+	// Introspection methods
+	//
+	// Children returns the direct sub-expressions, in evaluation order.
+	Children() []Expr
+	// Op returns the node kind: OpColumn, OpLiteral, or the operator name
+	// (for example "add", "greater_equal", "cast").
+	Op() string
+	// Columns returns the distinct columns the expression reads, in order of first use.
+	Columns() []string
+	// WithChildren returns a copy of the expression with its children replaced.
+	// The slice must have the same length as the one returned by Children.
+	WithChildren(children []Expr) (Expr, error)
+
 	// Fluent methods for building expressions
 	Add(other Expr) Expr
 	Sub(other Expr) Expr
@@ -34,6 +54,24 @@ type Expr interface {
 	Gt(other Expr) Expr
 	Lt(other Expr) Expr
 	Eq(other Expr) Expr
+	Ne(other Expr) Expr
+	Ge(other Expr) Expr
+	Le(other Expr) Expr
+
+	// Boolean logic methods (Kleene three-valued logic over nulls)
+	And(other Expr) Expr
+	Or(other Expr) Expr
+	Not() Expr
+
+	// Null handling methods
+	IsNull() Expr
+	IsNotNull() Expr
+	FillNull(value Expr) Expr
+	NullIf(value Expr) Expr
+
+	// Type conversion methods
+	Cast(dataType arrow.DataType) Expr
+	TryCast(dataType arrow.DataType) Expr
 
 	// String manipulation methods
 	Contains(substring Expr) Expr
@@ -68,6 +106,12 @@ type Expr interface {
 	AddSeconds(seconds Expr) Expr
 }
 
+// Node kinds returned by Op for leaf expressions.
+const (
+	OpColumn  = "column"
+	OpLiteral = "literal"
+)
+
 // ColumnExpr represents a reference to an existing column.
 type ColumnExpr struct {
 	columnName string
@@ -78,6 +122,18 @@ func NewColumnExpr(name string) Expr {
 	return &ColumnExpr{columnName: name}
 }
 
+// Every expression can filter storage reads.
+var _ storage.RecordPredicate = Expr(nil)
+
+// EvaluateRecord evaluates e against a DataFrame holding the columns of
+// record. Expressions defined outside this package can implement
+// Expr.EvaluateRecord with it.
+func EvaluateRecord(e Expr, record arrow.Record) (arrow.Array, error) {
+	df := core.NewDataFrame(record)
+	defer df.Release()
+	return e.Evaluate(df)
+}
+
 // Col creates a column reference expression.
 func Col(name string) Expr {
 	return NewColumnExpr(name)
@@ -96,6 +152,11 @@ func (c *ColumnExpr) Evaluate(df *core.DataFrame) (arrow.Array, error) {
 	return arr, nil
 }
 
+// EvaluateRecord implements Expr.EvaluateRecord for column references.
+func (c *ColumnExpr) EvaluateRecord(record arrow.Record) (arrow.Array, error) {
+	return EvaluateRecord(c, record)
+}
+
 // Name implements Expr.Name for column references.
 func (c *ColumnExpr) Name() string {
 	return c.columnName
@@ -106,6 +167,29 @@ func (c *ColumnExpr) String() string {
 	return fmt.Sprintf("Col(%s)", c.columnName)
 }
 
+// Children implements Expr.Children; a column reference has no children.
+func (c *ColumnExpr) Children() []Expr {
+	return nil
+}
+
+// Op implements Expr.Op for column references.
+func (c *ColumnExpr) Op() string {
+	return OpColumn
+}
+
+// Columns implements Expr.Columns for column references.
+func (c *ColumnExpr) Columns() []string {
+	return []string{c.columnName}
+}
+
+// WithChildren implements Expr.WithChildren for column references.
+func (c *ColumnExpr) WithChildren(children []Expr) (Expr, error) {
+	if err := checkArity(OpColumn, children, 0); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
 // Add creates a binary expression that adds this column to another expression.
 func (c *ColumnExpr) Add(other Expr) Expr {
 	return NewBinaryExpr(c, other, "add")
@@ -141,6 +225,71 @@ func (c *ColumnExpr) Eq(other Expr) Expr {
 	return NewBinaryExpr(c, other, "equal")
 }
 
+// Ne creates a binary expression that tests if this column is not equal to another expression.
+func (c *ColumnExpr) Ne(other Expr) Expr {
+	return NewBinaryExpr(c, other, "not_equal")
+}
+
+// Ge creates a binary expression that tests if this column is greater than or equal to another expression.
+func (c *ColumnExpr) Ge(other Expr) Expr {
+	return NewBinaryExpr(c, other, "greater_equal")
+}
+
+// Le creates a binary expression that tests if this column is less than or equal to another expression.
+func (c *ColumnExpr) Le(other Expr) Expr {
+	return NewBinaryExpr(c, other, "less_equal")
+}
+
+// And creates a logical AND of this boolean column and another boolean expression.
+// Nulls follow Kleene logic: false AND null is false, true AND null is null.
+func (c *ColumnExpr) And(other Expr) Expr {
+	return NewBinaryExpr(c, other, "and")
+}
+
+// Or creates a logical OR of this boolean column and another boolean expression.
+// Nulls follow Kleene logic: true OR null is true, false OR null is null.
+func (c *ColumnExpr) Or(other Expr) Expr {
+	return NewBinaryExpr(c, other, "or")
+}
+
+// Not negates this boolean column. Null values stay null.
+func (c *ColumnExpr) Not() Expr {
+	return NewUnaryExpr(c, "not")
+}
+
+// IsNull creates a boolean expression that is true where this column is null.
+func (c *ColumnExpr) IsNull() Expr {
+	return NewUnaryExpr(c, "is_null")
+}
+
+// IsNotNull creates a boolean expression that is true where this column is not null.
+func (c *ColumnExpr) IsNotNull() Expr {
+	return NewUnaryExpr(c, "is_not_null")
+}
+
+// FillNull replaces null values in this column with the given expression.
+// Equivalent to Coalesce(c, value).
+func (c *ColumnExpr) FillNull(value Expr) Expr {
+	return Coalesce(c, value)
+}
+
+// NullIf returns null where this column equals value, and the column value otherwise.
+func (c *ColumnExpr) NullIf(value Expr) Expr {
+	return NewBinaryExpr(c, value, "null_if")
+}
+
+// Cast converts this column to the given data type. Values that cannot be
+// converted cause an evaluation error (strict mode).
+func (c *ColumnExpr) Cast(dataType arrow.DataType) Expr {
+	return NewCastExpr(c, dataType, CastStrict)
+}
+
+// TryCast converts this column to the given data type. Values that cannot be
+// converted become null (safe mode).
+func (c *ColumnExpr) TryCast(dataType arrow.DataType) Expr {
+	return NewCastExpr(c, dataType, CastSafe)
+}
+
 // Contains creates a binary expression that tests if this string column contains a substring.
 func (c *ColumnExpr) Contains(substring Expr) Expr {
 	return NewBinaryExpr(c, substring, "contains")
@@ -300,7 +449,8 @@ func NewLiteralExpr(value interface{}) Expr {
 	}
 }
 
-// Lit creates a literal value expression.
+// Lit creates a literal value expression. Besides Go values, value may be an
+// Arrow scalar.Scalar, which gives a literal of any Arrow type.
 func Lit(value interface{}) Expr {
 	return NewLiteralExpr(value)
 }
@@ -310,6 +460,14 @@ func (l *LiteralExpr) Evaluate(df *core.DataFrame) (arrow.Array, error) {
 	numRows := int(df.NumRows())
 	pool := memory.NewGoAllocator()
 
+	if sc, ok := l.value.(scalar.Scalar); ok {
+		arr, err := scalar.MakeArrayFromScalar(sc, numRows, pool)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create literal array: %w", err)
+		}
+		return arr, nil
+	}
+
 	// Create an array filled with the literal value
 	switch l.dataType.ID() {
 	case arrow.INT64:
@@ -373,11 +531,35 @@ func (l *LiteralExpr) Evaluate(df *core.DataFrame) (arrow.Array, error) {
 		}
 		return builder.NewArray(), nil
 
+	case arrow.TIMESTAMP:
+		tsType := l.dataType.(*arrow.TimestampType)
+		builder := array.NewTimestampBuilder(pool, tsType)
+		defer builder.Release()
+
+		timeVal, ok := l.value.(time.Time)
+		if !ok {
+			return nil, fmt.Errorf("type mismatch: expected time.Time, got %T", l.value)
+		}
+		ts, err := arrow.TimestampFromTime(timeVal, tsType.Unit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert time to timestamp: %w", err)
+		}
+
+		for i := 0; i < numRows; i++ {
+			builder.Append(ts)
+		}
+		return builder.NewArray(), nil
+
 	default:
 		return nil, fmt.Errorf("unsupported literal type: %s", l.dataType)
 	}
 }
 
+// EvaluateRecord implements Expr.EvaluateRecord for literal values.
+func (l *LiteralExpr) EvaluateRecord(record arrow.Record) (arrow.Array, error) {
+	return EvaluateRecord(l, record)
+}
+
 // Name implements Expr.Name for literals.
 func (l *LiteralExpr) Name() string {
 	return l.name
@@ -388,6 +570,36 @@ func (l *LiteralExpr) String() string {
 	return l.name
 }
 
+// Value returns the Go value of the literal.
+func (l *LiteralExpr) Value() interface{} {
+	return l.value
+}
+
+// DataType returns the Arrow type of the literal.
+func (l *LiteralExpr) DataType() arrow.DataType {
+	return l.dataType
+}
+
+// Introspection methods for literals
+func (l *LiteralExpr) Children() []Expr {
+	return nil
+}
+
+func (l *LiteralExpr) Op() string {
+	return OpLiteral
+}
+
+func (l *LiteralExpr) Columns() []string {
+	return nil
+}
+
+func (l *LiteralExpr) WithChildren(children []Expr) (Expr, error) {
+	if err := checkArity(OpLiteral, children, 0); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
 // Add creates a binary expression that adds this literal to another expression.
 func (l *LiteralExpr) Add(other Expr) Expr {
 	return NewBinaryExpr(l, other, "add")
@@ -420,6 +632,57 @@ func (l *LiteralExpr) Eq(other Expr) Expr {
 	return NewBinaryExpr(l, other, "equal")
 }
 
+func (l *LiteralExpr) Ne(other Expr) Expr {
+	return NewBinaryExpr(l, other, "not_equal")
+}
+
+func (l *LiteralExpr) Ge(other Expr) Expr {
+	return NewBinaryExpr(l, other, "greater_equal")
+}
+
+func (l *LiteralExpr) Le(other Expr) Expr {
+	return NewBinaryExpr(l, other, "less_equal")
+}
+
+// Boolean logic methods for literals
+func (l *LiteralExpr) And(other Expr) Expr {
+	return NewBinaryExpr(l, other, "and")
+}
+
+func (l *LiteralExpr) Or(other Expr) Expr {
+	return NewBinaryExpr(l, other, "or")
+}
+
+func (l *LiteralExpr) Not() Expr {
+	return NewUnaryExpr(l, "not")
+}
+
+// Null handling methods for literals
+func (l *LiteralExpr) IsNull() Expr {
+	return NewUnaryExpr(l, "is_null")
+}
+
+func (l *LiteralExpr) IsNotNull() Expr {
+	return NewUnaryExpr(l, "is_not_null")
+}
+
+func (l *LiteralExpr) FillNull(value Expr) Expr {
+	return Coalesce(l, value)
+}
+
+func (l *LiteralExpr) NullIf(value Expr) Expr {
+	return NewBinaryExpr(l, value, "null_if")
+}
+
+// Type conversion methods for literals
+func (l *LiteralExpr) Cast(dataType arrow.DataType) Expr {
+	return NewCastExpr(l, dataType, CastStrict)
+}
+
+func (l *LiteralExpr) TryCast(dataType arrow.DataType) Expr {
+	return NewCastExpr(l, dataType, CastSafe)
+}
+
 // String manipulation methods for literals
 func (l *LiteralExpr) Contains(substring Expr) Expr {
 	return NewBinaryExpr(l, substring, "contains")
@@ -567,12 +830,14 @@ func (b *BinaryExpr) Evaluate(df *core.DataFrame) (arrow.Array, error) {
 
 	// Implement basic binary operations
 	switch b.operator {
-	case "greater":
-		return b.evaluateGreater(leftArray, rightArray)
-	case "less":
-		return b.evaluateLess(leftArray, rightArray)
-	case "equal":
-		return b.evaluateEqual(leftArray, rightArray)
+	case "greater", "greater_equal", "less", "less_equal", "equal", "not_equal":
+		return compareArrays(leftArray, rightArray, b.operator)
+	case "and":
+		return b.evaluateAnd(leftArray, rightArray)
+	case "or":
+		return b.evaluateOr(leftArray, rightArray)
+	case "null_if":
+		return b.evaluateNullIf(leftArray, rightArray)
 	case "add":
 		return b.evaluateAdd(leftArray, rightArray)
 	case "subtract":
@@ -602,160 +867,218 @@ func (b *BinaryExpr) Evaluate(df *core.DataFrame) (arrow.Array, error) {
 	}
 }
 
-// evaluateGreater implements greater-than comparison
-func (b *BinaryExpr) evaluateGreater(left, right arrow.Array) (arrow.Array, error) {
-	if left.Len() != right.Len() {
-		return nil, fmt.Errorf("array length mismatch: %d vs %d", left.Len(), right.Len())
+// EvaluateRecord implements Expr.EvaluateRecord for binary operations.
+func (b *BinaryExpr) EvaluateRecord(record arrow.Record) (arrow.Array, error) {
+	return EvaluateRecord(b, record)
+}
+
+// comparisonOperators describes the comparison operators supported by BinaryExpr,
+// used for error messages.
+var comparisonOperators = map[string]struct {
+	desc   string
+	symbol string
+}{
+	"greater":       {"greater", ">"},
+	"greater_equal": {"greater-or-equal", ">="},
+	"less":          {"less-than", "<"},
+	"less_equal":    {"less-or-equal", "<="},
+	"equal":         {"equality", "=="},
+	"not_equal":     {"inequality", "!="},
+}
+
+// orderedPredicate returns the Go comparison function for a comparison operator.
+func orderedPredicate[T cmp.Ordered](operator string) func(l, r T) bool {
+	switch operator {
+	case "greater":
+		return func(l, r T) bool { return l > r }
+	case "greater_equal":
+		return func(l, r T) bool { return l >= r }
+	case "less":
+		return func(l, r T) bool { return l < r }
+	case "less_equal":
+		return func(l, r T) bool { return l <= r }
+	case "equal":
+		return func(l, r T) bool { return l == r }
+	default:
+		return func(l, r T) bool { return l != r }
 	}
+}
 
+// buildComparison applies pred element-wise. Nulls in either operand produce null.
+func buildComparison[T cmp.Ordered](left, right arrow.Array, leftAt, rightAt func(i int) T, pred func(l, r T) bool) arrow.Array {
 	pool := memory.NewGoAllocator()
 	builder := array.NewBooleanBuilder(pool)
 	defer builder.Release()
+	builder.Reserve(left.Len())
 
-	// Handle Float64 comparisons (most common case for our test)
-	if left.DataType().ID() == arrow.FLOAT64 && right.DataType().ID() == arrow.FLOAT64 {
-		leftFloat, ok := asFloat64Array(left)
-		if !ok {
-			return nil, fmt.Errorf("failed to cast left array to Float64")
-		}
-		rightFloat, ok := asFloat64Array(right)
-		if !ok {
-			return nil, fmt.Errorf("failed to cast right array to Float64")
-		}
-
-		for i := 0; i < left.Len(); i++ {
-			if leftFloat.IsNull(i) || rightFloat.IsNull(i) {
-				builder.AppendNull()
-			} else {
-				builder.Append(leftFloat.Value(i) > rightFloat.Value(i))
-			}
-		}
-
-		return builder.NewArray(), nil
-	}
-
-	// Handle Int64 comparisons
-	if left.DataType().ID() == arrow.INT64 && right.DataType().ID() == arrow.INT64 {
-		leftInt := left.(*array.Int64)
-		rightInt := right.(*array.Int64)
-
-		for i := 0; i < left.Len(); i++ {
-			if leftInt.IsNull(i) || rightInt.IsNull(i) {
-				builder.AppendNull()
-			} else {
-				builder.Append(leftInt.Value(i) > rightInt.Value(i))
-			}
+	for i := 0; i < left.Len(); i++ {
+		if left.IsNull(i) || right.IsNull(i) {
+			builder.AppendNull()
+		} else {
+			builder.Append(pred(leftAt(i), rightAt(i)))
 		}
-
-		return builder.NewArray(), nil
 	}
 
-	return nil, fmt.Errorf("unsupported types for greater comparison: %s > %s", left.DataType(), right.DataType())
+	return builder.NewArray()
 }
 
-// Add placeholder implementations for other operations
-func (b *BinaryExpr) evaluateLess(left, right arrow.Array) (arrow.Array, error) {
+// compareArrays evaluates a comparison operator over two arrays of the same type.
+// Supports int64, float64, string, bool, date32 and timestamp operands. Timestamps
+// with different units are normalized to nanoseconds before comparing, and dates
+// compare with timestamps as midnight UTC.
+func compareArrays(left, right arrow.Array, operator string) (arrow.Array, error) {
 	if left.Len() != right.Len() {
 		return nil, fmt.Errorf("array length mismatch: %d vs %d", left.Len(), right.Len())
 	}
 
-	pool := memory.NewGoAllocator()
-	builder := array.NewBooleanBuilder(pool)
-	defer builder.Release()
-
-	// Handle Float64 comparisons
-	if left.DataType().ID() == arrow.FLOAT64 && right.DataType().ID() == arrow.FLOAT64 {
-		leftFloat := left.(*array.Float64)
-		rightFloat := right.(*array.Float64)
-
-		for i := 0; i < left.Len(); i++ {
-			if leftFloat.IsNull(i) || rightFloat.IsNull(i) {
-				builder.AppendNull()
-			} else {
-				builder.Append(leftFloat.Value(i) < rightFloat.Value(i))
+	leftID, rightID := left.DataType().ID(), right.DataType().ID()
+	if leftID == arrow.DATE32 && rightID == arrow.TIMESTAMP {
+		dates := datesAsTimestamps(left.(*array.Date32))
+		defer dates.Release()
+		return compareArrays(dates, right, operator)
+	}
+	if leftID == arrow.TIMESTAMP && rightID == arrow.DATE32 {
+		dates := datesAsTimestamps(right.(*array.Date32))
+		defer dates.Release()
+		return compareArrays(left, dates, operator)
+	}
+	if leftID == rightID {
+		switch leftID {
+		case arrow.INT64:
+			l, r := left.(*array.Int64), right.(*array.Int64)
+			return buildComparison(left, right, l.Value, r.Value, orderedPredicate[int64](operator)), nil
+
+		case arrow.FLOAT64:
+			l, r := left.(*array.Float64), right.(*array.Float64)
+			return buildComparison(left, right, l.Value, r.Value, orderedPredicate[float64](operator)), nil
+
+		case arrow.STRING:
+			l, r := left.(*array.String), right.(*array.String)
+			return buildComparison(left, right, l.Value, r.Value, orderedPredicate[string](operator)), nil
+
+		case arrow.BOOL:
+			// false orders before true
+			l, r := left.(*array.Boolean), right.(*array.Boolean)
+			leftAt := func(i int) int8 { return boolToInt8(l.Value(i)) }
+			rightAt := func(i int) int8 { return boolToInt8(r.Value(i)) }
+			return buildComparison(left, right, leftAt, rightAt, orderedPredicate[int8](operator)), nil
+
+		case arrow.DATE32:
+			l, r := left.(*array.Date32), right.(*array.Date32)
+			leftAt := func(i int) int32 { return int32(l.Value(i)) }
+			rightAt := func(i int) int32 { return int32(r.Value(i)) }
+			return buildComparison(left, right, leftAt, rightAt, orderedPredicate[int32](operator)), nil
+
+		case arrow.TIMESTAMP:
+			l, r := left.(*array.Timestamp), right.(*array.Timestamp)
+			leftUnit := l.DataType().(*arrow.TimestampType).Unit
+			rightUnit := r.DataType().(*arrow.TimestampType).Unit
+			leftAt := func(i int) int64 { return int64(l.Value(i)) }
+			rightAt := func(i int) int64 { return int64(r.Value(i)) }
+			if leftUnit != rightUnit {
+				leftMul, rightMul := int64(leftUnit.Multiplier()), int64(rightUnit.Multiplier())
+				leftAt = func(i int) int64 { return int64(l.Value(i)) * leftMul }
+				rightAt = func(i int) int64 { return int64(r.Value(i)) * rightMul }
 			}
+			return buildComparison(left, right, leftAt, rightAt, orderedPredicate[int64](operator)), nil
 		}
-
-		return builder.NewArray(), nil
 	}
 
-	// Handle Int64 comparisons
-	if left.DataType().ID() == arrow.INT64 && right.DataType().ID() == arrow.INT64 {
-		leftInt := left.(*array.Int64)
-		rightInt := right.(*array.Int64)
+	op := comparisonOperators[operator]
+	return nil, fmt.Errorf("unsupported types for %s comparison: %s %s %s", op.desc, left.DataType(), op.symbol, right.DataType())
+}
 
-		for i := 0; i < left.Len(); i++ {
-			if leftInt.IsNull(i) || rightInt.IsNull(i) {
-				builder.AppendNull()
-			} else {
-				builder.Append(leftInt.Value(i) < rightInt.Value(i))
-			}
+// datesAsTimestamps returns dates as second timestamps at midnight UTC.
+func datesAsTimestamps(dates *array.Date32) arrow.Array {
+	builder := array.NewTimestampBuilder(memory.NewGoAllocator(), &arrow.TimestampType{Unit: arrow.Second, TimeZone: "UTC"})
+	defer builder.Release()
+	builder.Reserve(dates.Len())
+	for i := 0; i < dates.Len(); i++ {
+		if dates.IsNull(i) {
+			builder.AppendNull()
+		} else {
+			builder.Append(arrow.Timestamp(int64(dates.Value(i)) * 86400))
 		}
-
-		return builder.NewArray(), nil
 	}
+	return builder.NewArray()
+}
 
-	return nil, fmt.Errorf("unsupported types for less-than comparison: %s < %s", left.DataType(), right.DataType())
+func boolToInt8(v bool) int8 {
+	if v {
+		return 1
+	}
+	return 0
 }
 
-func (b *BinaryExpr) evaluateEqual(left, right arrow.Array) (arrow.Array, error) {
-	if left.Len() != right.Len() {
-		return nil, fmt.Errorf("array length mismatch: %d vs %d", left.Len(), right.Len())
+// evaluateAnd implements logical AND with Kleene semantics:
+// false dominates null, and null dominates true.
+func (b *BinaryExpr) evaluateAnd(left, right arrow.Array) (arrow.Array, error) {
+	leftBool, rightBool, err := asBooleanOperands(left, right, "and")
+	if err != nil {
+		return nil, err
 	}
 
 	pool := memory.NewGoAllocator()
 	builder := array.NewBooleanBuilder(pool)
 	defer builder.Release()
+	builder.Reserve(left.Len())
 
-	// Handle String comparisons
-	if left.DataType().ID() == arrow.STRING && right.DataType().ID() == arrow.STRING {
-		leftStr := left.(*array.String)
-		rightStr := right.(*array.String)
-
-		for i := 0; i < left.Len(); i++ {
-			if leftStr.IsNull(i) || rightStr.IsNull(i) {
-				builder.AppendNull()
-			} else {
-				builder.Append(leftStr.Value(i) == rightStr.Value(i))
-			}
+	for i := 0; i < left.Len(); i++ {
+		leftFalse := leftBool.IsValid(i) && !leftBool.Value(i)
+		rightFalse := rightBool.IsValid(i) && !rightBool.Value(i)
+		switch {
+		case leftFalse || rightFalse:
+			builder.Append(false)
+		case leftBool.IsNull(i) || rightBool.IsNull(i):
+			builder.AppendNull()
+		default:
+			builder.Append(true)
 		}
-
-		return builder.NewArray(), nil
 	}
 
-	// Handle Int64 comparisons
-	if left.DataType().ID() == arrow.INT64 && right.DataType().ID() == arrow.INT64 {
-		leftInt := left.(*array.Int64)
-		rightInt := right.(*array.Int64)
-
-		for i := 0; i < left.Len(); i++ {
-			if leftInt.IsNull(i) || rightInt.IsNull(i) {
-				builder.AppendNull()
-			} else {
-				builder.Append(leftInt.Value(i) == rightInt.Value(i))
-			}
-		}
+	return builder.NewArray(), nil
+}
 
-		return builder.NewArray(), nil
+// evaluateOr implements logical OR with Kleene semantics:
+// true dominates null, and null dominates false.
+func (b *BinaryExpr) evaluateOr(left, right arrow.Array) (arrow.Array, error) {
+	leftBool, rightBool, err := asBooleanOperands(left, right, "or")
+	if err != nil {
+		return nil, err
 	}
 
-	// Handle Float64 comparisons
-	if left.DataType().ID() == arrow.FLOAT64 && right.DataType().ID() == arrow.FLOAT64 {
-		leftFloat := left.(*array.Float64)
-		rightFloat := right.(*array.Float64)
+	pool := memory.NewGoAllocator()
+	builder := array.NewBooleanBuilder(pool)
+	defer builder.Release()
+	builder.Reserve(left.Len())
 
-		for i := 0; i < left.Len(); i++ {
-			if leftFloat.IsNull(i) || rightFloat.IsNull(i) {
-				builder.AppendNull()
-			} else {
-				builder.Append(leftFloat.Value(i) == rightFloat.Value(i))
-			}
+	for i := 0; i < left.Len(); i++ {
+		leftTrue := leftBool.IsValid(i) && leftBool.Value(i)
+		rightTrue := rightBool.IsValid(i) && rightBool.Value(i)
+		switch {
+		case leftTrue || rightTrue:
+			builder.Append(true)
+		case leftBool.IsNull(i) || rightBool.IsNull(i):
+			builder.AppendNull()
+		default:
+			builder.Append(false)
 		}
-
-		return builder.NewArray(), nil
 	}
 
-	return nil, fmt.Errorf("unsupported types for equality comparison: %s == %s", left.DataType(), right.DataType())
+	return builder.NewArray(), nil
+}
+
+// asBooleanOperands checks that both operands of a logical operator are boolean arrays of equal length.
+func asBooleanOperands(left, right arrow.Array, operator string) (*array.Boolean, *array.Boolean, error) {
+	if left.Len() != right.Len() {
+		return nil, nil, fmt.Errorf("array length mismatch: %d vs %d", left.Len(), right.Len())
+	}
+	leftBool, leftOK := left.(*array.Boolean)
+	rightBool, rightOK := right.(*array.Boolean)
+	if !leftOK || !rightOK {
+		return nil, nil, fmt.Errorf("%s operation requires boolean operands, got %s and %s", operator, left.DataType(), right.DataType())
+	}
+	return leftBool, rightBool, nil
 }
 
 func (b *BinaryExpr) evaluateAdd(left, right arrow.Array) (arrow.Array, error) {
@@ -880,6 +1203,25 @@ func (b *BinaryExpr) evaluateMultiply(left, right arrow.Array) (arrow.Array, err
 		return builder.NewArray(), nil
 	}
 
+	// Handle Int64 multiplication
+	if left.DataType().ID() == arrow.INT64 && right.DataType().ID() == arrow.INT64 {
+		builder := array.NewInt64Builder(pool)
+		defer builder.Release()
+
+		leftInt := left.(*array.Int64)
+		rightInt := right.(*array.Int64)
+
+		for i := 0; i < left.Len(); i++ {
+			if leftInt.IsNull(i) || rightInt.IsNull(i) {
+				builder.AppendNull()
+			} else {
+				builder.Append(leftInt.Value(i) * rightInt.Value(i))
+			}
+		}
+
+		return builder.NewArray(), nil
+	}
+
 	return nil, fmt.Errorf("unsupported types for multiply: %s * %s", left.DataType(), right.DataType())
 }
 
@@ -949,6 +1291,26 @@ func (b *BinaryExpr) String() string {
 	return fmt.Sprintf("(%s %s %s)", b.left.String(), b.operator, b.right.String())
 }
 
+// Introspection methods for BinaryExpr
+func (b *BinaryExpr) Children() []Expr {
+	return []Expr{b.left, b.right}
+}
+
+func (b *BinaryExpr) Op() string {
+	return b.operator
+}
+
+func (b *BinaryExpr) Columns() []string {
+	return collectColumns(b)
+}
+
+func (b *BinaryExpr) WithChildren(children []Expr) (Expr, error) {
+	if err := checkArity(b.operator, children, 2); err != nil {
+		return nil, err
+	}
+	return NewBinaryExpr(children[0], children[1], b.operator), nil
+}
+
 // Fluent methods for BinaryExpr to enable further chaining
 func (b *BinaryExpr) Add(other Expr) Expr {
 	return NewBinaryExpr(b, other, "add")
@@ -978,6 +1340,57 @@ func (b *BinaryExpr) Eq(other Expr) Expr {
 	return NewBinaryExpr(b, other, "equal")
 }
 
+func (b *BinaryExpr) Ne(other Expr) Expr {
+	return NewBinaryExpr(b, other, "not_equal")
+}
+
+func (b *BinaryExpr) Ge(other Expr) Expr {
+	return NewBinaryExpr(b, other, "greater_equal")
+}
+
+func (b *BinaryExpr) Le(other Expr) Expr {
+	return NewBinaryExpr(b, other, "less_equal")
+}
+
+// Boolean logic methods for binary expressions
+func (b *BinaryExpr) And(other Expr) Expr {
+	return NewBinaryExpr(b, other, "and")
+}
+
+func (b *BinaryExpr) Or(other Expr) Expr {
+	return NewBinaryExpr(b, other, "or")
+}
+
+func (b *BinaryExpr) Not() Expr {
+	return NewUnaryExpr(b, "not")
+}
+
+// Null handling methods for binary expressions
+func (b *BinaryExpr) IsNull() Expr {
+	return NewUnaryExpr(b, "is_null")
+}
+
+func (b *BinaryExpr) IsNotNull() Expr {
+	return NewUnaryExpr(b, "is_not_null")
+}
+
+func (b *BinaryExpr) FillNull(value Expr) Expr {
+	return Coalesce(b, value)
+}
+
+func (b *BinaryExpr) NullIf(value Expr) Expr {
+	return NewBinaryExpr(b, value, "null_if")
+}
+
+// Type conversion methods for binary expressions
+func (b *BinaryExpr) Cast(dataType arrow.DataType) Expr {
+	return NewCastExpr(b, dataType, CastStrict)
+}
+
+func (b *BinaryExpr) TryCast(dataType arrow.DataType) Expr {
+	return NewCastExpr(b, dataType, CastSafe)
+}
+
 // String manipulation methods for binary expressions
 func (b *BinaryExpr) Contains(substring Expr) Expr {
 	return NewBinaryExpr(b, substring, "contains")
@@ -1266,11 +1679,22 @@ func (u *UnaryExpr) Evaluate(df *core.DataFrame) (arrow.Array, error) {
 		return u.evaluateTrimRight(operandArray)
 	case "length":
 		return u.evaluateLength(operandArray)
+	case "not":
+		return u.evaluateNot(operandArray)
+	case "is_null":
+		return u.evaluateIsNull(operandArray, true)
+	case "is_not_null":
+		return u.evaluateIsNull(operandArray, false)
 	default:
 		return nil, fmt.Errorf("unsupported unary operator: %s", u.operator)
 	}
 }
 
+// EvaluateRecord implements Expr.EvaluateRecord for unary operations.
+func (u *UnaryExpr) EvaluateRecord(record arrow.Record) (arrow.Array, error) {
+	return EvaluateRecord(u, record)
+}
+
 // Name implements Expr.Name for unary operations.
 func (u *UnaryExpr) Name() string {
 	return fmt.Sprintf("%s(%s)", u.operator, u.operand.Name())
@@ -1281,6 +1705,26 @@ func (u *UnaryExpr) String() string {
 	return fmt.Sprintf("%s(%s)", u.operator, u.operand.String())
 }
 
+// Introspection methods for UnaryExpr
+func (u *UnaryExpr) Children() []Expr {
+	return []Expr{u.operand}
+}
+
+func (u *UnaryExpr) Op() string {
+	return u.operator
+}
+
+func (u *UnaryExpr) Columns() []string {
+	return collectColumns(u)
+}
+
+func (u *UnaryExpr) WithChildren(children []Expr) (Expr, error) {
+	if err := checkArity(u.operator, children, 1); err != nil {
+		return nil, err
+	}
+	return NewUnaryExpr(children[0], u.operator), nil
+}
+
 // Fluent methods for UnaryExpr (delegate to BinaryExpr)
 func (u *UnaryExpr) Add(other Expr) Expr {
 	return NewBinaryExpr(u, other, "add")
@@ -1310,6 +1754,57 @@ func (u *UnaryExpr) Eq(other Expr) Expr {
 	return NewBinaryExpr(u, other, "equal")
 }
 
+func (u *UnaryExpr) Ne(other Expr) Expr {
+	return NewBinaryExpr(u, other, "not_equal")
+}
+
+func (u *UnaryExpr) Ge(other Expr) Expr {
+	return NewBinaryExpr(u, other, "greater_equal")
+}
+
+func (u *UnaryExpr) Le(other Expr) Expr {
+	return NewBinaryExpr(u, other, "less_equal")
+}
+
+// Boolean logic methods for UnaryExpr
+func (u *UnaryExpr) And(other Expr) Expr {
+	return NewBinaryExpr(u, other, "and")
+}
+
+func (u *UnaryExpr) Or(other Expr) Expr {
+	return NewBinaryExpr(u, other, "or")
+}
+
+func (u *UnaryExpr) Not() Expr {
+	return NewUnaryExpr(u, "not")
+}
+
+// Null handling methods for UnaryExpr
+func (u *UnaryExpr) IsNull() Expr {
+	return NewUnaryExpr(u, "is_null")
+}
+
+func (u *UnaryExpr) IsNotNull() Expr {
+	return NewUnaryExpr(u, "is_not_null")
+}
+
+func (u *UnaryExpr) FillNull(value Expr) Expr {
+	return Coalesce(u, value)
+}
+
+func (u *UnaryExpr) NullIf(value Expr) Expr {
+	return NewBinaryExpr(u, value, "null_if")
+}
+
+// Type conversion methods for UnaryExpr
+func (u *UnaryExpr) Cast(dataType arrow.DataType) Expr {
+	return NewCastExpr(u, dataType, CastStrict)
+}
+
+func (u *UnaryExpr) TryCast(dataType arrow.DataType) Expr {
+	return NewCastExpr(u, dataType, CastSafe)
+}
+
 // String manipulation methods
 func (u *UnaryExpr) Contains(substring Expr) Expr {
 	return NewBinaryExpr(u, substring, "contains")
@@ -1442,7 +1937,9 @@ func asStringArray(arr arrow.Array) (*array.String, bool) {
 
 // Helper function to infer Arrow data type from Go value
 func inferDataType(value interface{}) arrow.DataType {
-	switch value.(type) {
+	switch v := value.(type) {
+	case scalar.Scalar:
+		return v.DataType()
 	case bool:
 		return arrow.FixedWidthTypes.Boolean
 	case int8:
@@ -1467,6 +1964,8 @@ func inferDataType(value interface{}) arrow.DataType {
 		return arrow.PrimitiveTypes.Float64
 	case string:
 		return arrow.BinaryTypes.String
+	case time.Time:
+		return &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}
 	default:
 		// Default to string for unknown types
 		return arrow.BinaryTypes.String
@@ -2083,6 +2582,29 @@ func (u *UnaryExpr) evaluateLength(arr arrow.Array) (arrow.Array, error) {
 	return builder.NewArray(), nil
 }
 
+// evaluateNot negates boolean values, leaving nulls as null
+func (u *UnaryExpr) evaluateNot(arr arrow.Array) (arrow.Array, error) {
+	boolArray, ok := arr.(*array.Boolean)
+	if !ok {
+		return nil, fmt.Errorf("not operation requires boolean type, got %s", arr.DataType())
+	}
+
+	pool := memory.NewGoAllocator()
+	builder := array.NewBooleanBuilder(pool)
+	defer builder.Release()
+	builder.Reserve(arr.Len())
+
+	for i := 0; i < arr.Len(); i++ {
+		if boolArray.IsNull(i) {
+			builder.AppendNull()
+		} else {
+			builder.Append(!boolArray.Value(i))
+		}
+	}
+
+	return builder.NewArray(), nil
+}
+
 // evaluateMatch tests if strings match a regular expression pattern
 func (b *BinaryExpr) evaluateMatch(left, right arrow.Array) (arrow.Array, error) {
 	if left.Len() != right.Len() {
@@ -2193,6 +2715,11 @@ func (te *TernaryExpr) Evaluate(df *core.DataFrame) (arrow.Array, error) {
 	}
 }
 
+// EvaluateRecord implements Expr.EvaluateRecord for ternary operations.
+func (te *TernaryExpr) EvaluateRecord(record arrow.Record) (arrow.Array, error) {
+	return EvaluateRecord(te, record)
+}
+
 // Name implements Expr.Name for ternary operations.
 func (te *TernaryExpr) Name() string {
 	return fmt.Sprintf("%s(%s, %s, %s)", te.operator, te.first.Name(), te.second.Name(), te.third.Name())
@@ -2203,6 +2730,26 @@ func (te *TernaryExpr) String() string {
 	return fmt.Sprintf("%s(%s, %s, %s)", te.operator, te.first.String(), te.second.String(), te.third.String())
 }
 
+// Introspection methods for TernaryExpr
+func (te *TernaryExpr) Children() []Expr {
+	return []Expr{te.first, te.second, te.third}
+}
+
+func (te *TernaryExpr) Op() string {
+	return te.operator
+}
+
+func (te *TernaryExpr) Columns() []string {
+	return collectColumns(te)
+}
+
+func (te *TernaryExpr) WithChildren(children []Expr) (Expr, error) {
+	if err := checkArity(te.operator, children, 3); err != nil {
+		return nil, err
+	}
+	return NewTernaryExpr(children[0], children[1], children[2], te.operator), nil
+}
+
 // Fluent methods for TernaryExpr
 func (te *TernaryExpr) Add(other Expr) Expr {
 	return NewBinaryExpr(te, other, "add")
@@ -2232,6 +2779,57 @@ func (te *TernaryExpr) Eq(other Expr) Expr {
 	return NewBinaryExpr(te, other, "equal")
 }
 
+func (te *TernaryExpr) Ne(other Expr) Expr {
+	return NewBinaryExpr(te, other, "not_equal")
+}
+
+func (te *TernaryExpr) Ge(other Expr) Expr {
+	return NewBinaryExpr(te, other, "greater_equal")
+}
+
+func (te *TernaryExpr) Le(other Expr) Expr {
+	return NewBinaryExpr(te, other, "less_equal")
+}
+
+// Boolean logic methods for TernaryExpr
+func (te *TernaryExpr) And(other Expr) Expr {
+	return NewBinaryExpr(te, other, "and")
+}
+
+func (te *TernaryExpr) Or(other Expr) Expr {
+	return NewBinaryExpr(te, other, "or")
+}
+
+func (te *TernaryExpr) Not() Expr {
+	return NewUnaryExpr(te, "not")
+}
+
+// Null handling methods for TernaryExpr
+func (te *TernaryExpr) IsNull() Expr {
+	return NewUnaryExpr(te, "is_null")
+}
+
+func (te *TernaryExpr) IsNotNull() Expr {
+	return NewUnaryExpr(te, "is_not_null")
+}
+
+func (te *TernaryExpr) FillNull(value Expr) Expr {
+	return Coalesce(te, value)
+}
+
+func (te *TernaryExpr) NullIf(value Expr) Expr {
+	return NewBinaryExpr(te, value, "null_if")
+}
+
+// Type conversion methods for TernaryExpr
+func (te *TernaryExpr) Cast(dataType arrow.DataType) Expr {
+	return NewCastExpr(te, dataType, CastStrict)
+}
+
+func (te *TernaryExpr) TryCast(dataType arrow.DataType) Expr {
+	return NewCastExpr(te, dataType, CastSafe)
+}
+
 // String manipulation methods for TernaryExpr
 func (te *TernaryExpr) Contains(substring Expr) Expr {
 	return NewBinaryExpr(te, substring, "contains")
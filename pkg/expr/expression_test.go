@@ -454,6 +454,12 @@ func TestBinaryExpressionMultiply(t *testing.T) {
 			right:    Col("score"),
 			expected: []interface{}{9120.25, 7603.84, 8482.41}, // score * score
 		},
+		{
+			name:     "Multiply int64 column by literal",
+			left:     Col("id"),
+			right:    Lit(int64(3)),
+			expected: []interface{}{int64(3), int64(6), int64(9)}, // id * 3
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,293 @@
+package expr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/felixgeelhaar/GopherFrame/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createKleeneDataFrame builds two boolean columns covering every
+// combination of true, false and null.
+func createKleeneDataFrame(t *testing.T) *core.DataFrame {
+	pool := memory.NewGoAllocator()
+
+	// a: T T T F F F N N N
+	// b: T F N T F N T F N
+	aBuilder := array.NewBooleanBuilder(pool)
+	defer aBuilder.Release()
+	aBuilder.AppendValues(
+		[]bool{true, true, true, false, false, false, false, false, false},
+		[]bool{true, true, true, true, true, true, false, false, false},
+	)
+	aArray := aBuilder.NewArray()
+	defer aArray.Release()
+
+	bBuilder := array.NewBooleanBuilder(pool)
+	defer bBuilder.Release()
+	bBuilder.AppendValues(
+		[]bool{true, false, false, true, false, false, true, false, false},
+		[]bool{true, true, false, true, true, false, true, true, false},
+	)
+	bArray := bBuilder.NewArray()
+	defer bArray.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "a", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
+		{Name: "b", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
+	}, nil)
+	record := array.NewRecord(schema, []arrow.Array{aArray, bArray}, 9)
+	defer record.Release()
+
+	return core.NewDataFrame(record)
+}
+
+// assertBooleans checks a boolean result; nil entries in expected mean null.
+func assertBooleans(t *testing.T, result arrow.Array, expected []interface{}) {
+	t.Helper()
+	boolResult, ok := result.(*array.Boolean)
+	require.True(t, ok, "expected boolean result, got %s", result.DataType())
+	require.Equal(t, len(expected), boolResult.Len())
+
+	for i, want := range expected {
+		if want == nil {
+			assert.True(t, boolResult.IsNull(i), "expected null at index %d", i)
+			continue
+		}
+		require.False(t, boolResult.IsNull(i), "unexpected null at index %d", i)
+		assert.Equal(t, want, boolResult.Value(i), "value at index %d", i)
+	}
+}
+
+func TestExpr_And_KleeneLogic(t *testing.T) {
+	df := createKleeneDataFrame(t)
+	defer df.Release()
+
+	result, err := Col("a").And(Col("b")).Evaluate(df)
+	require.NoError(t, err)
+	defer result.Release()
+
+	assertBooleans(t, result, []interface{}{
+		true, false, nil,
+		false, false, false,
+		nil, false, nil,
+	})
+}
+
+func TestExpr_Or_KleeneLogic(t *testing.T) {
+	df := createKleeneDataFrame(t)
+	defer df.Release()
+
+	result, err := Col("a").Or(Col("b")).Evaluate(df)
+	require.NoError(t, err)
+	defer result.Release()
+
+	assertBooleans(t, result, []interface{}{
+		true, true, true,
+		true, false, nil,
+		true, nil, nil,
+	})
+}
+
+func TestExpr_Not(t *testing.T) {
+	df := createKleeneDataFrame(t)
+	defer df.Release()
+
+	result, err := Col("a").Not().Evaluate(df)
+	require.NoError(t, err)
+	defer result.Release()
+
+	assertBooleans(t, result, []interface{}{
+		false, false, false,
+		true, true, true,
+		nil, nil, nil,
+	})
+
+	assert.Equal(t, "not(a)", Col("a").Not().Name())
+}
+
+func TestExpr_Logical_TypeErrors(t *testing.T) {
+	df := createTestDataFrame(t)
+	defer df.Release()
+
+	_, err := Col("id").And(Lit(true)).Evaluate(df)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "and operation requires boolean operands")
+
+	_, err = Lit(true).Or(Col("name")).Evaluate(df)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "or operation requires boolean operands")
+
+	_, err = Col("score").Not().Evaluate(df)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not operation requires boolean type")
+}
+
+func TestExpr_ComparisonOperators(t *testing.T) {
+	df := createTestDataFrame(t)
+	defer df.Release()
+
+	// id: 1, 2, 3 / name: Alice, Bob, Charlie / score: 95.5, 87.2, 92.1
+	tests := []struct {
+		name     string
+		expr     Expr
+		expected []interface{}
+	}{
+		{"int64 Ne", Col("id").Ne(Lit(int64(2))), []interface{}{true, false, true}},
+		{"int64 Ge", Col("id").Ge(Lit(int64(2))), []interface{}{false, true, true}},
+		{"int64 Le", Col("id").Le(Lit(int64(2))), []interface{}{true, true, false}},
+		{"float64 Ge", Col("score").Ge(Lit(92.1)), []interface{}{true, false, true}},
+		{"float64 Le", Col("score").Le(Lit(92.1)), []interface{}{false, true, true}},
+		{"float64 Ne", Col("score").Ne(Lit(87.2)), []interface{}{true, false, true}},
+		{"string Gt", Col("name").Gt(Lit("Bob")), []interface{}{false, false, true}},
+		{"string Lt", Col("name").Lt(Lit("Bob")), []interface{}{true, false, false}},
+		{"string Ge", Col("name").Ge(Lit("Bob")), []interface{}{false, true, true}},
+		{"string Ne", Col("name").Ne(Lit("Bob")), []interface{}{true, false, true}},
+		{"bool Eq", Col("id").Gt(Lit(int64(1))).Eq(Lit(true)), []interface{}{false, true, true}},
+		{"bool Gt", Col("id").Gt(Lit(int64(1))).Gt(Lit(false)), []interface{}{false, true, true}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := tc.expr.Evaluate(df)
+			require.NoError(t, err)
+			defer result.Release()
+			assertBooleans(t, result, tc.expected)
+		})
+	}
+}
+
+func TestExpr_Comparison_Nulls(t *testing.T) {
+	pool := memory.NewGoAllocator()
+
+	builder := array.NewInt64Builder(pool)
+	defer builder.Release()
+	builder.AppendValues([]int64{1, 0, 3}, []bool{true, false, true})
+	arr := builder.NewArray()
+	defer arr.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "v", Type: arrow.PrimitiveTypes.Int64, Nullable: true}}, nil)
+	record := array.NewRecord(schema, []arrow.Array{arr}, 3)
+	defer record.Release()
+
+	df := core.NewDataFrame(record)
+	defer df.Release()
+
+	for _, e := range []Expr{
+		Col("v").Ne(Lit(int64(1))),
+		Col("v").Ge(Lit(int64(1))),
+		Col("v").Le(Lit(int64(1))),
+	} {
+		result, err := e.Evaluate(df)
+		require.NoError(t, err)
+		assert.True(t, result.IsNull(1), "%s should be null for a null operand", e)
+		result.Release()
+	}
+}
+
+func TestExpr_Comparison_Timestamps(t *testing.T) {
+	pool := memory.NewGoAllocator()
+
+	// Nanosecond column compared against a microsecond literal
+	tsType := &arrow.TimestampType{Unit: arrow.Nanosecond, TimeZone: "UTC"}
+	builder := array.NewTimestampBuilder(pool, tsType)
+	defer builder.Release()
+
+	times := []time.Time{
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+	}
+	for _, tm := range times {
+		ts, err := arrow.TimestampFromTime(tm, arrow.Nanosecond)
+		require.NoError(t, err)
+		builder.Append(ts)
+	}
+	builder.AppendNull()
+	tsArray := builder.NewArray()
+	defer tsArray.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "ts", Type: tsType, Nullable: true}}, nil)
+	record := array.NewRecord(schema, []arrow.Array{tsArray}, 4)
+	defer record.Release()
+
+	df := core.NewDataFrame(record)
+	defer df.Release()
+
+	cutoff := Lit(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+
+	result, err := Col("ts").Ge(cutoff).Evaluate(df)
+	require.NoError(t, err)
+	defer result.Release()
+	assertBooleans(t, result, []interface{}{false, true, true, nil})
+
+	result2, err := Col("ts").Lt(cutoff).Evaluate(df)
+	require.NoError(t, err)
+	defer result2.Release()
+	assertBooleans(t, result2, []interface{}{true, false, false, nil})
+}
+
+func TestExpr_Comparison_Dates(t *testing.T) {
+	pool := memory.NewGoAllocator()
+
+	builder := array.NewDate32Builder(pool)
+	defer builder.Release()
+	for _, day := range []int{1, 15, 31} {
+		builder.Append(arrow.Date32FromTime(time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC)))
+	}
+	builder.AppendNull()
+	dates := builder.NewArray()
+	defer dates.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "day", Type: arrow.FixedWidthTypes.Date32, Nullable: true}}, nil)
+	record := array.NewRecord(schema, []arrow.Array{dates}, 4)
+	defer record.Release()
+
+	df := core.NewDataFrame(record)
+	defer df.Release()
+
+	// Dates compare with timestamps as midnight UTC
+	result, err := Col("day").Ge(Lit(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))).Evaluate(df)
+	require.NoError(t, err)
+	defer result.Release()
+	assertBooleans(t, result, []interface{}{false, true, true, nil})
+
+	result2, err := Lit(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)).Lt(Col("day")).Evaluate(df)
+	require.NoError(t, err)
+	defer result2.Release()
+	assertBooleans(t, result2, []interface{}{false, false, true, nil})
+
+	result3, err := Col("day").Eq(Col("day")).Evaluate(df)
+	require.NoError(t, err)
+	defer result3.Release()
+	assertBooleans(t, result3, []interface{}{true, true, true, nil})
+}
+
+func TestExpr_CombinedPredicate(t *testing.T) {
+	df := createTestDataFrame(t)
+	defer df.Release()
+
+	// (id > 1 AND score >= 90) OR name == "Alice"
+	predicate := Col("id").Gt(Lit(int64(1))).And(Col("score").Ge(Lit(90.0))).
+		Or(Col("name").Eq(Lit("Alice")))
+
+	result, err := predicate.Evaluate(df)
+	require.NoError(t, err)
+	defer result.Release()
+
+	assertBooleans(t, result, []interface{}{true, false, true})
+}
+
+func TestExpr_Comparison_TypeMismatch(t *testing.T) {
+	df := createTestDataFrame(t)
+	defer df.Release()
+
+	_, err := Col("id").Ge(Col("name")).Evaluate(df)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported types for greater-or-equal comparison")
+}
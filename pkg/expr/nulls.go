@@ -0,0 +1,481 @@
+package expr
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/compute"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/felixgeelhaar/GopherFrame/pkg/core"
+	"github.com/felixgeelhaar/GopherFrame/pkg/rowhash"
+)
+
+// ====================
+// Null Handling
+// ====================
+
+// CoalesceExpr returns, for each row, the first non-null value among its operands.
+type CoalesceExpr struct {
+	exprs []Expr
+}
+
+// NewCoalesceExpr creates a new coalesce expression.
+func NewCoalesceExpr(exprs ...Expr) Expr {
+	return &CoalesceExpr{exprs: exprs}
+}
+
+// Coalesce returns the first non-null value among the given expressions for each row.
+// Operands of different numeric types are promoted to a common type (int64 or float64),
+// and timestamps with different units are converted to the unit of the first timestamp.
+//
+// Example:
+//
+//	Coalesce(Col("nickname"), Col("name"), Lit("unknown"))
+func Coalesce(exprs ...Expr) Expr {
+	return NewCoalesceExpr(exprs...)
+}
+
+// Evaluate implements Expr.Evaluate for coalesce expressions.
+func (ce *CoalesceExpr) Evaluate(df *core.DataFrame) (arrow.Array, error) {
+	if len(ce.exprs) == 0 {
+		return nil, fmt.Errorf("coalesce requires at least one expression")
+	}
+
+	arrays := make([]arrow.Array, 0, len(ce.exprs))
+	defer func() {
+		for _, arr := range arrays {
+			arr.Release()
+		}
+	}()
+	for i, e := range ce.exprs {
+		arr, err := e.Evaluate(df)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate coalesce operand %d: %w", i, err)
+		}
+		arrays = append(arrays, arr)
+	}
+
+	unified, err := unifyArrays(arrays)
+	if err != nil {
+		return nil, fmt.Errorf("coalesce: %w", err)
+	}
+	defer releaseArrays(unified)
+
+	numRows := unified[0].Len()
+	for _, arr := range unified[1:] {
+		if arr.Len() != numRows {
+			return nil, fmt.Errorf("array length mismatch: %d vs %d", numRows, arr.Len())
+		}
+	}
+
+	// Pick, for every row, the position of its first non-null value within the
+	// concatenation of all operands. Rows that are null everywhere point at the
+	// (null) value of the last operand.
+	pool := memory.NewGoAllocator()
+	indexBuilder := array.NewInt64Builder(pool)
+	defer indexBuilder.Release()
+	indexBuilder.Reserve(numRows)
+
+	last := len(unified) - 1
+	for i := 0; i < numRows; i++ {
+		source := last
+		for j, arr := range unified {
+			if arr.IsValid(i) {
+				source = j
+				break
+			}
+		}
+		indexBuilder.Append(int64(source*numRows + i))
+	}
+	indices := indexBuilder.NewArray()
+	defer indices.Release()
+
+	return takeFromConcatenation(pool, unified, indices)
+}
+
+// EvaluateRecord implements Expr.EvaluateRecord for coalesce expressions.
+func (ce *CoalesceExpr) EvaluateRecord(record arrow.Record) (arrow.Array, error) {
+	return EvaluateRecord(ce, record)
+}
+
+// Name implements Expr.Name for coalesce expressions.
+func (ce *CoalesceExpr) Name() string {
+	names := make([]string, len(ce.exprs))
+	for i, e := range ce.exprs {
+		names[i] = e.Name()
+	}
+	return fmt.Sprintf("coalesce(%s)", strings.Join(names, ", "))
+}
+
+// String implements Expr.String for coalesce expressions.
+func (ce *CoalesceExpr) String() string {
+	parts := make([]string, len(ce.exprs))
+	for i, e := range ce.exprs {
+		parts[i] = e.String()
+	}
+	return fmt.Sprintf("coalesce(%s)", strings.Join(parts, ", "))
+}
+
+// Introspection methods for CoalesceExpr
+func (ce *CoalesceExpr) Children() []Expr {
+	return ce.exprs
+}
+
+func (ce *CoalesceExpr) Op() string {
+	return "coalesce"
+}
+
+func (ce *CoalesceExpr) Columns() []string {
+	return collectColumns(ce)
+}
+
+func (ce *CoalesceExpr) WithChildren(children []Expr) (Expr, error) {
+	if err := checkArity("coalesce", children, len(ce.exprs)); err != nil {
+		return nil, err
+	}
+	return NewCoalesceExpr(append([]Expr(nil), children...)...), nil
+}
+
+// evaluateIsNull tests each value for null (wantNull) or non-null (!wantNull).
+// Works for any Arrow type; the result never contains nulls.
+func (u *UnaryExpr) evaluateIsNull(arr arrow.Array, wantNull bool) (arrow.Array, error) {
+	pool := memory.NewGoAllocator()
+	builder := array.NewBooleanBuilder(pool)
+	defer builder.Release()
+	builder.Reserve(arr.Len())
+
+	for i := 0; i < arr.Len(); i++ {
+		builder.Append(arr.IsNull(i) == wantNull)
+	}
+
+	return builder.NewArray(), nil
+}
+
+// evaluateNullIf returns null where left equals right and left otherwise.
+// Values of any type compare by value after unifying the operand types, so
+// an int32 column matches an int64 literal, and the result keeps the type of
+// left.
+func (b *BinaryExpr) evaluateNullIf(left, right arrow.Array) (arrow.Array, error) {
+	unified, err := unifyArrays([]arrow.Array{left, right})
+	if err != nil {
+		return nil, fmt.Errorf("null_if: %w", err)
+	}
+	defer releaseArrays(unified)
+
+	leftKeys, err := rowhash.NewKeys(unified[0])
+	if err != nil {
+		return nil, fmt.Errorf("null_if: %w", err)
+	}
+	rightKeys, err := rowhash.NewKeys(unified[1])
+	if err != nil {
+		return nil, fmt.Errorf("null_if: %w", err)
+	}
+
+	pool := memory.NewGoAllocator()
+	indexBuilder := array.NewInt64Builder(pool)
+	defer indexBuilder.Release()
+	indexBuilder.Reserve(left.Len())
+
+	for i := 0; i < left.Len(); i++ {
+		if !leftKeys.HasNull(i) && !rightKeys.HasNull(i) && leftKeys.Equal(i, rightKeys, i) {
+			indexBuilder.AppendNull()
+		} else {
+			indexBuilder.Append(int64(i))
+		}
+	}
+	indices := indexBuilder.NewArray()
+	defer indices.Release()
+
+	return takeFromConcatenation(pool, []arrow.Array{left}, indices)
+}
+
+// takeFromConcatenation concatenates arrays of identical type and gathers the
+// values at indices. Null indices produce null values.
+func takeFromConcatenation(pool memory.Allocator, arrays []arrow.Array, indices arrow.Array) (arrow.Array, error) {
+	values := arrays[0]
+	if len(arrays) > 1 {
+		concatenated, err := array.Concatenate(arrays, pool)
+		if err != nil {
+			return nil, fmt.Errorf("failed to concatenate arrays: %w", err)
+		}
+		defer concatenated.Release()
+		values = concatenated
+	}
+
+	result, err := compute.TakeArray(context.Background(), values, indices)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select values: %w", err)
+	}
+	return result, nil
+}
+
+// unifyArrays converts arrays to a common data type so they can be combined
+// row by row. Identical types are returned as-is; mixed integer and floating
+// point types are promoted to int64 or float64; timestamps are converted to the
+// unit of the first timestamp. The returned arrays are new references that the
+// caller must release.
+func unifyArrays(arrays []arrow.Array) ([]arrow.Array, error) {
+	target, err := commonType(arrays)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]arrow.Array, 0, len(arrays))
+	for _, arr := range arrays {
+		if arrow.TypeEqual(arr.DataType(), target) {
+			arr.Retain()
+			result = append(result, arr)
+			continue
+		}
+		converted, err := compute.CastToType(context.Background(), arr, target)
+		if err != nil {
+			releaseArrays(result)
+			return nil, fmt.Errorf("cannot convert %s to %s: %w", arr.DataType(), target, err)
+		}
+		result = append(result, converted)
+	}
+	return result, nil
+}
+
+// commonType determines the type all arrays are unified to.
+func commonType(arrays []arrow.Array) (arrow.DataType, error) {
+	first := arrays[0].DataType()
+	allEqual, allNumeric, anyFloat, allTimestamp := true, true, false, true
+	for _, arr := range arrays {
+		dt := arr.DataType()
+		if !arrow.TypeEqual(dt, first) {
+			allEqual = false
+		}
+		switch {
+		case arrow.IsInteger(dt.ID()):
+		case arrow.IsFloating(dt.ID()):
+			anyFloat = true
+		default:
+			allNumeric = false
+		}
+		if dt.ID() != arrow.TIMESTAMP {
+			allTimestamp = false
+		}
+	}
+
+	switch {
+	case allEqual:
+		return first, nil
+	case allNumeric && anyFloat:
+		return arrow.PrimitiveTypes.Float64, nil
+	case allNumeric:
+		return arrow.PrimitiveTypes.Int64, nil
+	case allTimestamp:
+		return first, nil
+	}
+
+	names := make([]string, len(arrays))
+	for i, arr := range arrays {
+		names[i] = arr.DataType().String()
+	}
+	return nil, fmt.Errorf("incompatible types: %s", strings.Join(names, ", "))
+}
+
+// releaseArrays releases every array in the slice.
+func releaseArrays(arrays []arrow.Array) {
+	for _, arr := range arrays {
+		arr.Release()
+	}
+}
+
+// Fluent methods for CoalesceExpr
+func (ce *CoalesceExpr) Add(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "add")
+}
+
+func (ce *CoalesceExpr) Sub(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "subtract")
+}
+
+func (ce *CoalesceExpr) Mul(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "multiply")
+}
+
+func (ce *CoalesceExpr) Div(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "divide")
+}
+
+func (ce *CoalesceExpr) Gt(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "greater")
+}
+
+func (ce *CoalesceExpr) Lt(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "less")
+}
+
+func (ce *CoalesceExpr) Eq(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "equal")
+}
+
+func (ce *CoalesceExpr) Ne(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "not_equal")
+}
+
+func (ce *CoalesceExpr) Ge(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "greater_equal")
+}
+
+func (ce *CoalesceExpr) Le(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "less_equal")
+}
+
+// Boolean logic methods for CoalesceExpr
+func (ce *CoalesceExpr) And(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "and")
+}
+
+func (ce *CoalesceExpr) Or(other Expr) Expr {
+	return NewBinaryExpr(ce, other, "or")
+}
+
+func (ce *CoalesceExpr) Not() Expr {
+	return NewUnaryExpr(ce, "not")
+}
+
+// Null handling methods for CoalesceExpr
+func (ce *CoalesceExpr) IsNull() Expr {
+	return NewUnaryExpr(ce, "is_null")
+}
+
+func (ce *CoalesceExpr) IsNotNull() Expr {
+	return NewUnaryExpr(ce, "is_not_null")
+}
+
+func (ce *CoalesceExpr) FillNull(value Expr) Expr {
+	return Coalesce(ce, value)
+}
+
+func (ce *CoalesceExpr) NullIf(value Expr) Expr {
+	return NewBinaryExpr(ce, value, "null_if")
+}
+
+// Type conversion methods for CoalesceExpr
+func (ce *CoalesceExpr) Cast(dataType arrow.DataType) Expr {
+	return NewCastExpr(ce, dataType, CastStrict)
+}
+
+func (ce *CoalesceExpr) TryCast(dataType arrow.DataType) Expr {
+	return NewCastExpr(ce, dataType, CastSafe)
+}
+
+// String manipulation methods for CoalesceExpr
+func (ce *CoalesceExpr) Contains(substring Expr) Expr {
+	return NewBinaryExpr(ce, substring, "contains")
+}
+
+func (ce *CoalesceExpr) StartsWith(prefix Expr) Expr {
+	return NewBinaryExpr(ce, prefix, "starts_with")
+}
+
+func (ce *CoalesceExpr) EndsWith(suffix Expr) Expr {
+	return NewBinaryExpr(ce, suffix, "ends_with")
+}
+
+func (ce *CoalesceExpr) Upper() Expr {
+	return NewUnaryExpr(ce, "upper")
+}
+
+func (ce *CoalesceExpr) Lower() Expr {
+	return NewUnaryExpr(ce, "lower")
+}
+
+func (ce *CoalesceExpr) Trim() Expr {
+	return NewUnaryExpr(ce, "trim")
+}
+
+func (ce *CoalesceExpr) TrimLeft() Expr {
+	return NewUnaryExpr(ce, "trim_left")
+}
+
+func (ce *CoalesceExpr) TrimRight() Expr {
+	return NewUnaryExpr(ce, "trim_right")
+}
+
+func (ce *CoalesceExpr) Length() Expr {
+	return NewUnaryExpr(ce, "length")
+}
+
+func (ce *CoalesceExpr) Match(pattern Expr) Expr {
+	return NewBinaryExpr(ce, pattern, "match")
+}
+
+func (ce *CoalesceExpr) Replace(old, new Expr) Expr {
+	return NewTernaryExpr(ce, old, new, "replace")
+}
+
+func (ce *CoalesceExpr) PadLeft(length, pad Expr) Expr {
+	return NewTernaryExpr(ce, length, pad, "pad_left")
+}
+
+func (ce *CoalesceExpr) PadRight(length, pad Expr) Expr {
+	return NewTernaryExpr(ce, length, pad, "pad_right")
+}
+
+func (ce *CoalesceExpr) SplitPart(separator, index Expr) Expr {
+	return NewTernaryExpr(ce, separator, index, "split_part")
+}
+
+// Temporal methods for CoalesceExpr
+func (ce *CoalesceExpr) Year() Expr {
+	return NewUnaryExpr(ce, "year")
+}
+
+func (ce *CoalesceExpr) Month() Expr {
+	return NewUnaryExpr(ce, "month")
+}
+
+func (ce *CoalesceExpr) Day() Expr {
+	return NewUnaryExpr(ce, "day")
+}
+
+func (ce *CoalesceExpr) Hour() Expr {
+	return NewUnaryExpr(ce, "hour")
+}
+
+func (ce *CoalesceExpr) Minute() Expr {
+	return NewUnaryExpr(ce, "minute")
+}
+
+func (ce *CoalesceExpr) Second() Expr {
+	return NewUnaryExpr(ce, "second")
+}
+
+func (ce *CoalesceExpr) TruncateToYear() Expr {
+	return NewUnaryExpr(ce, "trunc_year")
+}
+
+func (ce *CoalesceExpr) TruncateToMonth() Expr {
+	return NewUnaryExpr(ce, "trunc_month")
+}
+
+func (ce *CoalesceExpr) TruncateToDay() Expr {
+	return NewUnaryExpr(ce, "trunc_day")
+}
+
+func (ce *CoalesceExpr) TruncateToHour() Expr {
+	return NewUnaryExpr(ce, "trunc_hour")
+}
+
+func (ce *CoalesceExpr) AddDays(days Expr) Expr {
+	return NewBinaryExpr(ce, days, "add_days")
+}
+
+func (ce *CoalesceExpr) AddHours(hours Expr) Expr {
+	return NewBinaryExpr(ce, hours, "add_hours")
+}
+
+func (ce *CoalesceExpr) AddMinutes(minutes Expr) Expr {
+	return NewBinaryExpr(ce, minutes, "add_minutes")
+}
+
+func (ce *CoalesceExpr) AddSeconds(seconds Expr) Expr {
+	return NewBinaryExpr(ce, seconds, "add_seconds")
+}
@@ -0,0 +1,265 @@
+package expr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/felixgeelhaar/GopherFrame/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createNullableDataFrame builds a DataFrame with nulls in every column type.
+func createNullableDataFrame(t *testing.T) *core.DataFrame {
+	pool := memory.NewGoAllocator()
+	valid := []bool{true, false, true, false}
+
+	intBuilder := array.NewInt64Builder(pool)
+	defer intBuilder.Release()
+	intBuilder.AppendValues([]int64{1, 0, 3, 0}, valid)
+	intArray := intBuilder.NewArray()
+	defer intArray.Release()
+
+	floatBuilder := array.NewFloat64Builder(pool)
+	defer floatBuilder.Release()
+	floatBuilder.AppendValues([]float64{1.5, 0, 0, 4.5}, []bool{true, false, false, true})
+	floatArray := floatBuilder.NewArray()
+	defer floatArray.Release()
+
+	strBuilder := array.NewStringBuilder(pool)
+	defer strBuilder.Release()
+	strBuilder.AppendValues([]string{"a", "", "c", ""}, valid)
+	strArray := strBuilder.NewArray()
+	defer strArray.Release()
+
+	boolBuilder := array.NewBooleanBuilder(pool)
+	defer boolBuilder.Release()
+	boolBuilder.AppendValues([]bool{true, false, false, false}, valid)
+	boolArray := boolBuilder.NewArray()
+	defer boolArray.Release()
+
+	tsType := &arrow.TimestampType{Unit: arrow.Second, TimeZone: "UTC"}
+	tsBuilder := array.NewTimestampBuilder(pool, tsType)
+	defer tsBuilder.Release()
+	tsBuilder.AppendValues([]arrow.Timestamp{1000, 0, 3000, 0}, valid)
+	tsArray := tsBuilder.NewArray()
+	defer tsArray.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "i", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "f", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "s", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "b", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
+		{Name: "ts", Type: tsType, Nullable: true},
+	}, nil)
+	record := array.NewRecord(schema, []arrow.Array{intArray, floatArray, strArray, boolArray, tsArray}, 4)
+	defer record.Release()
+
+	return core.NewDataFrame(record)
+}
+
+func TestExpr_IsNull_AllTypes(t *testing.T) {
+	df := createNullableDataFrame(t)
+	defer df.Release()
+
+	for _, col := range []string{"i", "s", "b", "ts"} {
+		t.Run(col, func(t *testing.T) {
+			result, err := Col(col).IsNull().Evaluate(df)
+			require.NoError(t, err)
+			defer result.Release()
+			assertBooleans(t, result, []interface{}{false, true, false, true})
+
+			notNull, err := Col(col).IsNotNull().Evaluate(df)
+			require.NoError(t, err)
+			defer notNull.Release()
+			assertBooleans(t, notNull, []interface{}{true, false, true, false})
+		})
+	}
+}
+
+func TestExpr_Coalesce(t *testing.T) {
+	df := createNullableDataFrame(t)
+	defer df.Release()
+
+	t.Run("first non-null wins", func(t *testing.T) {
+		result, err := Coalesce(Col("i"), Col("f"), Lit(-1.0)).Evaluate(df)
+		require.NoError(t, err)
+		defer result.Release()
+
+		// i and f are promoted to float64
+		floatResult, ok := result.(*array.Float64)
+		require.True(t, ok, "expected float64 result, got %s", result.DataType())
+		assert.Equal(t, []float64{1, -1, 3, 4.5}, floatResult.Float64Values())
+		assert.Equal(t, 0, floatResult.NullN())
+	})
+
+	t.Run("all null stays null", func(t *testing.T) {
+		result, err := Coalesce(Col("s"), Col("s")).Evaluate(df)
+		require.NoError(t, err)
+		defer result.Release()
+
+		strResult := result.(*array.String)
+		assert.Equal(t, "a", strResult.Value(0))
+		assert.True(t, strResult.IsNull(1))
+		assert.Equal(t, "c", strResult.Value(2))
+		assert.True(t, strResult.IsNull(3))
+	})
+
+	t.Run("incompatible types", func(t *testing.T) {
+		_, err := Coalesce(Col("s"), Col("i")).Evaluate(df)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "incompatible types")
+	})
+
+	t.Run("no operands", func(t *testing.T) {
+		_, err := Coalesce().Evaluate(df)
+		require.Error(t, err)
+	})
+
+	assert.Equal(t, "coalesce(i, Lit(0))", Coalesce(Col("i"), Lit(0)).Name())
+}
+
+func TestExpr_FillNull_AllTypes(t *testing.T) {
+	df := createNullableDataFrame(t)
+	defer df.Release()
+
+	t.Run("int64", func(t *testing.T) {
+		result, err := Col("i").FillNull(Lit(int64(0))).Evaluate(df)
+		require.NoError(t, err)
+		defer result.Release()
+		assert.Equal(t, []int64{1, 0, 3, 0}, result.(*array.Int64).Int64Values())
+		assert.Equal(t, 0, result.NullN())
+	})
+
+	t.Run("string", func(t *testing.T) {
+		result, err := Col("s").FillNull(Lit("missing")).Evaluate(df)
+		require.NoError(t, err)
+		defer result.Release()
+		strResult := result.(*array.String)
+		assert.Equal(t, "missing", strResult.Value(1))
+		assert.Equal(t, "c", strResult.Value(2))
+	})
+
+	t.Run("bool", func(t *testing.T) {
+		result, err := Col("b").FillNull(Lit(true)).Evaluate(df)
+		require.NoError(t, err)
+		defer result.Release()
+		assertBooleans(t, result, []interface{}{true, true, false, true})
+	})
+
+	t.Run("timestamp with different unit", func(t *testing.T) {
+		fill := time.Unix(60, 0).UTC()
+		result, err := Col("ts").FillNull(Lit(fill)).Evaluate(df)
+		require.NoError(t, err)
+		defer result.Release()
+
+		// Result keeps the column's unit (seconds)
+		require.True(t, arrow.TypeEqual(result.DataType(), &arrow.TimestampType{Unit: arrow.Second, TimeZone: "UTC"}))
+		tsResult := result.(*array.Timestamp)
+		assert.Equal(t, arrow.Timestamp(1000), tsResult.Value(0))
+		assert.Equal(t, arrow.Timestamp(60), tsResult.Value(1))
+	})
+}
+
+func TestExpr_NullIf(t *testing.T) {
+	df := createNullableDataFrame(t)
+	defer df.Release()
+
+	result, err := Col("i").NullIf(Lit(int64(3))).Evaluate(df)
+	require.NoError(t, err)
+	defer result.Release()
+
+	intResult := result.(*array.Int64)
+	assert.Equal(t, int64(1), intResult.Value(0))
+	assert.True(t, intResult.IsNull(1), "existing null stays null")
+	assert.True(t, intResult.IsNull(2), "matching value becomes null")
+	assert.True(t, intResult.IsNull(3))
+
+	strResult, err := Col("s").NullIf(Lit("a")).Evaluate(df)
+	require.NoError(t, err)
+	defer strResult.Release()
+	assert.True(t, strResult.IsNull(0))
+	assert.Equal(t, "c", strResult.(*array.String).Value(2))
+}
+
+func TestExpr_NullIf_TypeGeneric(t *testing.T) {
+	pool := memory.NewGoAllocator()
+
+	aBuilder := array.NewInt32Builder(pool)
+	defer aBuilder.Release()
+	aBuilder.AppendValues([]int32{1, 2, 3, 4}, []bool{true, true, true, false})
+	aArray := aBuilder.NewArray()
+	defer aArray.Release()
+
+	bBuilder := array.NewInt32Builder(pool)
+	defer bBuilder.Release()
+	bBuilder.AppendValues([]int32{1, 5, 0, 4}, []bool{true, true, false, true})
+	bArray := bBuilder.NewArray()
+	defer bArray.Release()
+
+	decType := &arrow.Decimal128Type{Precision: 10, Scale: 2}
+	dBuilder := array.NewDecimal128Builder(pool, decType)
+	defer dBuilder.Release()
+	dBuilder.AppendValues([]decimal128.Num{decimal128.FromI64(150), decimal128.FromI64(200), decimal128.FromI64(325), decimal128.FromI64(0)}, nil)
+	dArray := dBuilder.NewArray()
+	defer dArray.Release()
+
+	eBuilder := array.NewDecimal128Builder(pool, decType)
+	defer eBuilder.Release()
+	eBuilder.AppendValues([]decimal128.Num{decimal128.FromI64(150), decimal128.FromI64(201), decimal128.FromI64(325), decimal128.FromI64(1)}, nil)
+	eArray := eBuilder.NewArray()
+	defer eArray.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "a", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		{Name: "b", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		{Name: "d", Type: decType},
+		{Name: "e", Type: decType},
+	}, nil)
+	record := array.NewRecord(schema, []arrow.Array{aArray, bArray, dArray, eArray}, 4)
+	defer record.Release()
+	df := core.NewDataFrame(record)
+	defer df.Release()
+
+	t.Run("int32", func(t *testing.T) {
+		result, err := Col("a").NullIf(Col("b")).Evaluate(df)
+		require.NoError(t, err)
+		defer result.Release()
+
+		require.True(t, arrow.TypeEqual(arrow.PrimitiveTypes.Int32, result.DataType()))
+		ints := result.(*array.Int32)
+		assert.True(t, ints.IsNull(0), "matching value becomes null")
+		assert.Equal(t, int32(2), ints.Value(1))
+		assert.Equal(t, int32(3), ints.Value(2), "null right operand keeps the value")
+		assert.True(t, ints.IsNull(3))
+	})
+
+	t.Run("mixed width", func(t *testing.T) {
+		result, err := Col("a").NullIf(Lit(int64(2))).Evaluate(df)
+		require.NoError(t, err)
+		defer result.Release()
+
+		require.True(t, arrow.TypeEqual(arrow.PrimitiveTypes.Int32, result.DataType()), "result keeps the left type")
+		ints := result.(*array.Int32)
+		assert.Equal(t, int32(1), ints.Value(0))
+		assert.True(t, ints.IsNull(1))
+		assert.Equal(t, int32(3), ints.Value(2))
+	})
+
+	t.Run("decimal", func(t *testing.T) {
+		result, err := Col("d").NullIf(Col("e")).Evaluate(df)
+		require.NoError(t, err)
+		defer result.Release()
+
+		require.True(t, arrow.TypeEqual(decType, result.DataType()))
+		decimals := result.(*array.Decimal128)
+		assert.True(t, decimals.IsNull(0))
+		assert.Equal(t, decimal128.FromI64(200), decimals.Value(1))
+		assert.True(t, decimals.IsNull(2))
+		assert.Equal(t, decimal128.FromI64(0), decimals.Value(3))
+	})
+}
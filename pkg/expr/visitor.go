@@ -0,0 +1,103 @@
+package expr
+
+import (
+	"fmt"
+)
+
+// ====================
+// Expression Tree Traversal
+// ====================
+
+// Walk traverses an expression tree depth-first, visiting each node before its
+// children. If visit returns false, the children of that node are skipped.
+//
+// Example:
+//
+//	Walk(e, func(node Expr) bool {
+//		fmt.Println(node.Op())
+//		return true
+//	})
+func Walk(e Expr, visit func(Expr) bool) {
+	if e == nil || !visit(e) {
+		return
+	}
+	for _, child := range e.Children() {
+		Walk(child, visit)
+	}
+}
+
+// Transform rewrites an expression tree bottom-up. The children of each node are
+// transformed first; the node, rebuilt around any changed children, is then passed
+// to fn, whose result replaces it. Returning the node unchanged keeps it as is.
+//
+// Example (replace every reference to "a" with "b"):
+//
+//	rewritten, err := Transform(e, func(node Expr) (Expr, error) {
+//		if node.Op() == OpColumn && node.Name() == "a" {
+//			return Col("b"), nil
+//		}
+//		return node, nil
+//	})
+func Transform(e Expr, fn func(Expr) (Expr, error)) (Expr, error) {
+	children := e.Children()
+	if len(children) > 0 {
+		newChildren := make([]Expr, len(children))
+		changed := false
+		for i, child := range children {
+			newChild, err := Transform(child, fn)
+			if err != nil {
+				return nil, err
+			}
+			newChildren[i] = newChild
+			if newChild != child {
+				changed = true
+			}
+		}
+		if changed {
+			rebuilt, err := e.WithChildren(newChildren)
+			if err != nil {
+				return nil, err
+			}
+			e = rebuilt
+		}
+	}
+	return fn(e)
+}
+
+// ReferencesColumn reports whether an expression reads the named column.
+func ReferencesColumn(e Expr, name string) bool {
+	for _, col := range e.Columns() {
+		if col == name {
+			return true
+		}
+	}
+	return false
+}
+
+// collectColumns gathers the distinct columns read by the leaves of an
+// expression tree, in order of first appearance.
+func collectColumns(e Expr) []string {
+	var columns []string
+	seen := make(map[string]bool)
+	Walk(e, func(node Expr) bool {
+		if len(node.Children()) > 0 {
+			return true
+		}
+		for _, col := range node.Columns() {
+			if !seen[col] {
+				seen[col] = true
+				columns = append(columns, col)
+			}
+		}
+		return false
+	})
+	return columns
+}
+
+// checkArity validates the number of children passed to WithChildren.
+func checkArity(op string, children []Expr, want int) error {
+	if len(children) != want {
+		return fmt.Errorf("%s expression expects %d children, got %d", op, want, len(children))
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package expr
+
+import (
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpr_Introspection(t *testing.T) {
+	e := Col("price").Mul(Col("qty")).Gt(Lit(100.0))
+
+	assert.Equal(t, "greater", e.Op())
+	require.Len(t, e.Children(), 2)
+	assert.Equal(t, "multiply", e.Children()[0].Op())
+	assert.Equal(t, OpLiteral, e.Children()[1].Op())
+	assert.Equal(t, []string{"price", "qty"}, e.Columns())
+
+	assert.Equal(t, OpColumn, Col("a").Op())
+	assert.Empty(t, Col("a").Children())
+	assert.Empty(t, Lit(1).Columns())
+}
+
+func TestExpr_Columns_AllNodeTypes(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     Expr
+		expected []string
+	}{
+		{"unary", Col("name").Upper(), []string{"name"}},
+		{"ternary", Col("s").Replace(Col("old"), Lit("x")), []string{"s", "old"}},
+		{"coalesce", Coalesce(Col("a"), Col("b"), Col("a")), []string{"a", "b"}},
+		{"case", When(Col("x").Gt(Lit(1))).Then(Col("y")).Otherwise(Col("z")), []string{"x", "y", "z"}},
+		{"cast", Col("zip").Cast(arrow.BinaryTypes.String), []string{"zip"}},
+		{"literal only", Lit(1).Add(Lit(2)), nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.expr.Columns())
+		})
+	}
+}
+
+func TestReferencesColumn_NoSubstringMatches(t *testing.T) {
+	// "a" is a substring of "amount" and of the literal "banana", but not referenced
+	e := Col("amount").Gt(Lit(10.0)).And(Col("fruit").Eq(Lit("banana")))
+
+	assert.False(t, ReferencesColumn(e, "a"))
+	assert.True(t, ReferencesColumn(e, "amount"))
+	assert.True(t, ReferencesColumn(e, "fruit"))
+}
+
+func TestWalk(t *testing.T) {
+	e := Col("a").Add(Lit(1)).Mul(Col("b"))
+
+	var ops []string
+	Walk(e, func(node Expr) bool {
+		ops = append(ops, node.Op())
+		return true
+	})
+	assert.Equal(t, []string{"multiply", "add", OpColumn, OpLiteral, OpColumn}, ops)
+
+	// Returning false skips the children of a node
+	ops = nil
+	Walk(e, func(node Expr) bool {
+		ops = append(ops, node.Op())
+		return node.Op() != "add"
+	})
+	assert.Equal(t, []string{"multiply", "add", OpColumn}, ops)
+}
+
+func TestTransform_RenameColumn(t *testing.T) {
+	e := When(Col("a").Gt(Lit(int64(1)))).Then(Col("a")).Otherwise(Coalesce(Col("b"), Col("a")))
+
+	renames := map[string]string{"a": "id", "b": "score"}
+	rewritten, err := Transform(e, func(node Expr) (Expr, error) {
+		if node.Op() == OpColumn && renames[node.Name()] != "" {
+			return Col(renames[node.Name()]), nil
+		}
+		return node, nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"id", "score"}, rewritten.Columns())
+	assert.Equal(t, []string{"a", "b"}, e.Columns(), "original expression is unchanged")
+
+	df := createTestDataFrame(t)
+	defer df.Release()
+
+	result, err := rewritten.Evaluate(df)
+	require.NoError(t, err)
+	defer result.Release()
+	assert.Equal(t, 3, result.Len())
+}
+
+func TestTransform_KeepsUnchangedNodes(t *testing.T) {
+	e := Col("a").Add(Col("b")).Cast(arrow.PrimitiveTypes.Float64)
+
+	rewritten, err := Transform(e, func(node Expr) (Expr, error) { return node, nil })
+	require.NoError(t, err)
+	assert.Same(t, e, rewritten)
+}
+
+func TestWithChildren_ArityMismatch(t *testing.T) {
+	_, err := Col("a").Add(Col("b")).WithChildren([]Expr{Col("a")})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expects 2 children, got 1")
+
+	_, err = Col("a").WithChildren([]Expr{Col("b")})
+	require.Error(t, err)
+}
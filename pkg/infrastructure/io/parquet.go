@@ -16,6 +16,7 @@ import (
 	"github.com/apache/arrow-go/v18/parquet/file"
 	"github.com/apache/arrow-go/v18/parquet/pqarrow"
 	"github.com/felixgeelhaar/GopherFrame/pkg/domain/dataframe"
+	"github.com/felixgeelhaar/GopherFrame/pkg/storage"
 )
 
 // validateFilePath performs basic security validation on file paths
@@ -127,13 +128,22 @@ func (r *ParquetReader) ReadFile(filename string) (*dataframe.DataFrame, error)
 }
 
 // ParquetWriter provides functionality to write DataFrames to Parquet files.
-type ParquetWriter struct{}
+type ParquetWriter struct {
+	options storage.ParquetWriteOptions
+}
 
-// NewParquetWriter creates a new ParquetWriter.
+// NewParquetWriter creates a new ParquetWriter that writes uncompressed files
+// with the Parquet library's default encodings.
 func NewParquetWriter() *ParquetWriter {
 	return &ParquetWriter{}
 }
 
+// NewParquetWriterWithOptions creates a ParquetWriter that encodes files as
+// described by opts.
+func NewParquetWriterWithOptions(opts storage.ParquetWriteOptions) *ParquetWriter {
+	return &ParquetWriter{options: opts}
+}
+
 // WriteFile writes a DataFrame to a Parquet file.
 func (w *ParquetWriter) WriteFile(df *dataframe.DataFrame, filename string) error {
 	if df == nil {
@@ -158,25 +168,24 @@ func (w *ParquetWriter) WriteFile(df *dataframe.DataFrame, filename string) erro
 	table := array.NewTableFromRecords(record.Schema(), []arrow.Record{record})
 	defer table.Release()
 
-	// Set up Parquet writer properties
-	writerProps := parquet.NewWriterProperties()
-	arrowProps := pqarrow.DefaultWriterProps()
-
 	// Create Parquet writer
-	writer, err := pqarrow.NewFileWriter(record.Schema(), f, writerProps, arrowProps)
+	writer, err := storage.NewParquetWriter(record.Schema(), f, memory.DefaultAllocator, w.options)
 	if err != nil {
-		return fmt.Errorf("failed to create Parquet writer: %w", err)
+		return err
 	}
-	defer func() { _ = writer.Close() }()
 
-	// Write the table with proper chunk size
-	chunkSize := table.NumRows()
+	// Write the table as one row group unless a row group size is configured
+	chunkSize := w.options.RowGroupSize
 	if chunkSize <= 0 {
-		chunkSize = 1000 // Default chunk size for empty tables
+		chunkSize = max(table.NumRows(), 1)
 	}
 	if err := writer.WriteTable(table, chunkSize); err != nil {
+		_ = writer.Close()
 		return fmt.Errorf("failed to write table: %w", err)
 	}
 
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to close Parquet writer: %w", err)
+	}
 	return nil
 }
@@ -7,7 +7,9 @@ import (
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/file"
 	"github.com/felixgeelhaar/GopherFrame/pkg/domain/dataframe"
+	"github.com/felixgeelhaar/GopherFrame/pkg/storage"
 )
 
 func TestParquetReader_NewParquetReader(t *testing.T) {
@@ -100,3 +102,49 @@ func TestParquetAPI_WorksCorrectly(t *testing.T) {
 		}
 	}
 }
+
+func TestParquetWriter_WriteFile_WithOptions(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "options.parquet")
+
+	pool := memory.NewGoAllocator()
+	schema := arrow.NewSchema(
+		[]arrow.Field{{Name: "test", Type: arrow.PrimitiveTypes.Int64}},
+		nil,
+	)
+	builder := array.NewInt64Builder(pool)
+	builder.AppendValues([]int64{1, 2, 3, 4, 5}, nil)
+	arr := builder.NewArray()
+	defer arr.Release()
+
+	record := array.NewRecord(schema, []arrow.Array{arr}, 5)
+	df := dataframe.NewDataFrame(record)
+	defer df.Release()
+
+	writer := NewParquetWriterWithOptions(storage.ParquetWriteOptions{Compression: "snappy", RowGroupSize: 2})
+	if err := writer.WriteFile(df, testFile); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	parquetFile, err := file.OpenParquetFile(testFile, false)
+	if err != nil {
+		t.Fatalf("OpenParquetFile failed: %v", err)
+	}
+	defer func() { _ = parquetFile.Close() }()
+	if n := len(parquetFile.MetaData().RowGroups); n != 3 {
+		t.Errorf("Expected 3 row groups, got %d", n)
+	}
+
+	readDF, err := NewParquetReader().ReadFile(testFile)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	defer readDF.Release()
+	if readDF.NumRows() != 5 {
+		t.Errorf("Expected 5 rows, got %d", readDF.NumRows())
+	}
+
+	badWriter := NewParquetWriterWithOptions(storage.ParquetWriteOptions{Compression: "lzo"})
+	if err := badWriter.WriteFile(df, filepath.Join(t.TempDir(), "bad.parquet")); err == nil {
+		t.Error("Expected error for unknown compression codec")
+	}
+}
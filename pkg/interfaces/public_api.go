@@ -180,6 +180,9 @@ func (gdf *GroupedDataFrame) Agg(specs ...AggregationSpec) *DataFrame {
 			Alias:        spec.Alias,
 			Percentile:   spec.Percentile,
 			SecondColumn: spec.SecondColumn,
+			Separator:    spec.Separator,
+			Precision:    spec.Precision,
+			Accuracy:     spec.Accuracy,
 		}
 	}
 
@@ -212,6 +215,22 @@ const (
 	MedianAgg
 	ModeAgg
 	CorrelationAgg
+	VarianceAgg
+	StdDevAgg
+	CountDistinctAgg
+	FirstAgg
+	LastAgg
+	AnyAgg
+	AllAgg
+	SkewAgg
+	KurtosisAgg
+	CovarianceAgg
+	ArgMinAgg
+	ArgMaxAgg
+	CollectListAgg
+	ConcatAgg
+	ApproxCountDistinctAgg
+	ApproxQuantileAgg
 )
 
 // AggregationSpec specifies an aggregation operation for the public API.
@@ -219,8 +238,11 @@ type AggregationSpec struct {
 	Column       string
 	Type         AggregationType
 	Alias        string
-	Percentile   float64 // For Percentile aggregation (0.0-1.0)
-	SecondColumn string  // For Correlation aggregation
+	Percentile   float64 // For Percentile and ApproxQuantile aggregation (0.0-1.0)
+	SecondColumn string  // For Correlation and Covariance, and the ordering column of ArgMin and ArgMax
+	Separator    string  // For Concat aggregation
+	Precision    int     // For ApproxCountDistinct: HyperLogLog precision (0 uses 14)
+	Accuracy     float64 // For ApproxQuantile: t-digest compression (0 uses 100)
 }
 
 // Sum creates a sum aggregation specification.
@@ -309,6 +331,159 @@ func Correlation(column1, column2 string) AggregationSpec {
 	}
 }
 
+// Variance creates a sample variance aggregation specification (N-1 denominator).
+func Variance(column string) AggregationSpec {
+	return AggregationSpec{
+		Column: column,
+		Type:   VarianceAgg,
+		Alias:  column + "_variance",
+	}
+}
+
+// StdDev creates a sample standard deviation aggregation specification.
+func StdDev(column string) AggregationSpec {
+	return AggregationSpec{
+		Column: column,
+		Type:   StdDevAgg,
+		Alias:  column + "_stddev",
+	}
+}
+
+// Skew creates a sample skewness aggregation specification, bias-corrected like pandas.
+func Skew(column string) AggregationSpec {
+	return AggregationSpec{
+		Column: column,
+		Type:   SkewAgg,
+		Alias:  column + "_skew",
+	}
+}
+
+// Kurtosis creates a sample excess kurtosis aggregation specification, bias-corrected like pandas.
+func Kurtosis(column string) AggregationSpec {
+	return AggregationSpec{
+		Column: column,
+		Type:   KurtosisAgg,
+		Alias:  column + "_kurtosis",
+	}
+}
+
+// Covariance creates a sample covariance aggregation specification of two columns.
+func Covariance(column1, column2 string) AggregationSpec {
+	return AggregationSpec{
+		Column:       column1,
+		SecondColumn: column2,
+		Type:         CovarianceAgg,
+		Alias:        fmt.Sprintf("cov_%s_%s", column1, column2),
+	}
+}
+
+// CountDistinct creates a specification that counts distinct non-null values of any type.
+func CountDistinct(column string) AggregationSpec {
+	return AggregationSpec{
+		Column: column,
+		Type:   CountDistinctAgg,
+		Alias:  column + "_count_distinct",
+	}
+}
+
+// First creates a specification of the first non-null value of every group.
+func First(column string) AggregationSpec {
+	return AggregationSpec{
+		Column: column,
+		Type:   FirstAgg,
+		Alias:  column + "_first",
+	}
+}
+
+// Last creates a specification of the last non-null value of every group.
+func Last(column string) AggregationSpec {
+	return AggregationSpec{
+		Column: column,
+		Type:   LastAgg,
+		Alias:  column + "_last",
+	}
+}
+
+// Any creates a specification that is true when any value of a boolean column is true.
+func Any(column string) AggregationSpec {
+	return AggregationSpec{
+		Column: column,
+		Type:   AnyAgg,
+		Alias:  column + "_any",
+	}
+}
+
+// All creates a specification that is true when every value of a boolean column is true.
+func All(column string) AggregationSpec {
+	return AggregationSpec{
+		Column: column,
+		Type:   AllAgg,
+		Alias:  column + "_all",
+	}
+}
+
+// ArgMin creates a specification of the value of column in the row where by is smallest.
+func ArgMin(column, by string) AggregationSpec {
+	return AggregationSpec{
+		Column:       column,
+		SecondColumn: by,
+		Type:         ArgMinAgg,
+		Alias:        column + "_arg_min",
+	}
+}
+
+// ArgMax creates a specification of the value of column in the row where by is largest.
+func ArgMax(column, by string) AggregationSpec {
+	return AggregationSpec{
+		Column:       column,
+		SecondColumn: by,
+		Type:         ArgMaxAgg,
+		Alias:        column + "_arg_max",
+	}
+}
+
+// CollectList creates a specification that collects the non-null values of every group into a list.
+func CollectList(column string) AggregationSpec {
+	return AggregationSpec{
+		Column: column,
+		Type:   CollectListAgg,
+		Alias:  column + "_list",
+	}
+}
+
+// Concat creates a specification that joins the non-null values of every group with a separator.
+func Concat(column, separator string) AggregationSpec {
+	return AggregationSpec{
+		Column:    column,
+		Type:      ConcatAgg,
+		Alias:     column + "_concat",
+		Separator: separator,
+	}
+}
+
+// ApproxCountDistinct creates a specification that estimates the distinct count
+// with a HyperLogLog sketch of the given precision (4-18, 0 uses 14).
+func ApproxCountDistinct(column string, precision int) AggregationSpec {
+	return AggregationSpec{
+		Column:    column,
+		Type:      ApproxCountDistinctAgg,
+		Alias:     column + "_approx_count_distinct",
+		Precision: precision,
+	}
+}
+
+// ApproxQuantile creates a specification that estimates the q-th quantile with
+// a t-digest sketch; accuracy is its compression (at least 10, 0 uses 100).
+func ApproxQuantile(column string, q, accuracy float64) AggregationSpec {
+	return AggregationSpec{
+		Column:     column,
+		Type:       ApproxQuantileAgg,
+		Alias:      fmt.Sprintf("%s_approx_p%.0f", column, q*100),
+		Percentile: q,
+		Accuracy:   accuracy,
+	}
+}
+
 // As sets a custom alias for the aggregation result.
 func (spec AggregationSpec) As(alias string) AggregationSpec {
 	spec.Alias = alias
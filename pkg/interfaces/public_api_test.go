@@ -6,6 +6,7 @@ import (
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/felixgeelhaar/GopherFrame/pkg/domain/aggregation"
 )
 
 const testValueColumn = "value"
@@ -326,3 +327,92 @@ func TestAggregationSpecs_DDD(t *testing.T) {
 		t.Errorf("Expected CountAgg type, got %d", countSpec.Type)
 	}
 }
+
+func TestAggregationTypes_MatchDomain(t *testing.T) {
+	// Agg converts the public types to domain types by value
+	if AggregationType(aggregation.Concat) != ConcatAgg || AggregationType(aggregation.Covariance) != CovarianceAgg ||
+		AggregationType(aggregation.ApproxQuantile) != ApproxQuantileAgg {
+		t.Errorf("Public aggregation types do not match the domain types")
+	}
+}
+
+func TestGroupBy_Agg_Statistics(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	schema := arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "category", Type: arrow.BinaryTypes.String},
+			{Name: "value", Type: arrow.PrimitiveTypes.Float64},
+			{Name: "label", Type: arrow.BinaryTypes.String},
+		},
+		nil,
+	)
+
+	builder := array.NewRecordBuilder(pool, schema)
+	defer builder.Release()
+	builder.Field(0).(*array.StringBuilder).AppendValues([]string{"A", "A", "A", "B"}, nil)
+	builder.Field(1).(*array.Float64Builder).AppendValues([]float64{3, 1, 2, 5}, nil)
+	builder.Field(2).(*array.StringBuilder).AppendValues([]string{"x", "y", "x", "z"}, nil)
+	record := builder.NewRecord()
+	df := NewDataFrame(record)
+	defer df.Release()
+
+	result := df.GroupBy("category").Agg(
+		CountDistinct("label"), ArgMin("label", "value"), Concat("label", "-"), StdDev("value"),
+		ApproxCountDistinct("label", 10), ApproxQuantile("value", 0.5, 0))
+	if result.Err() != nil {
+		t.Fatalf("GroupBy Agg failed: %v", result.Err())
+	}
+	defer result.Release()
+
+	out := result.domainDF.Record()
+	if got := out.Column(1).(*array.Int64).Value(0); got != 2 {
+		t.Errorf("Expected 2 distinct labels, got %d", got)
+	}
+	if got := out.Column(2).(*array.String).Value(0); got != "y" {
+		t.Errorf("Expected label of the smallest value 'y', got '%s'", got)
+	}
+	if got := out.Column(3).(*array.String).Value(0); got != "x-y-x" {
+		t.Errorf("Expected concatenated labels 'x-y-x', got '%s'", got)
+	}
+	if got := out.Column(4).(*array.Float64).Value(0); got != 1 {
+		t.Errorf("Expected standard deviation 1, got %v", got)
+	}
+	if got := out.Column(5).(*array.Int64).Value(0); got != 2 {
+		t.Errorf("Expected about 2 distinct labels, got %d", got)
+	}
+	if got := out.Column(6).(*array.Float64).Value(0); got != 2 {
+		t.Errorf("Expected approximate median 2, got %v", got)
+	}
+}
+
+func TestGroupBy_Agg_Int64Values(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	schema := arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "category", Type: arrow.BinaryTypes.String},
+			{Name: "value", Type: arrow.PrimitiveTypes.Int64},
+		},
+		nil,
+	)
+
+	builder := array.NewRecordBuilder(pool, schema)
+	defer builder.Release()
+	builder.Field(0).(*array.StringBuilder).AppendValues([]string{"A", "A", "A", "B"}, nil)
+	builder.Field(1).(*array.Int64Builder).AppendValues([]int64{3, 1, 2, 5}, nil)
+	record := builder.NewRecord()
+	df := NewDataFrame(record)
+	defer df.Release()
+
+	result := df.GroupBy("category").Agg(Sum("value"), Max("value"), Median("value"), Variance("value"))
+	if result.Err() != nil {
+		t.Fatalf("GroupBy Agg failed: %v", result.Err())
+	}
+	defer result.Release()
+
+	out := result.domainDF.Record()
+	for col, want := range map[int]float64{1: 6, 2: 3, 3: 2, 4: 1} {
+		if got := out.Column(col).(*array.Float64).Value(0); got != want {
+			t.Errorf("Column %s: expected %v, got %v", out.ColumnName(col), want, got)
+		}
+	}
+}
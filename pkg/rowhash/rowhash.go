@@ -0,0 +1,645 @@
+// Package rowhash hashes and compares the rows of Arrow key columns.
+// It is the shared kernel behind group-by, joins, pivots and window
+// partitioning: keys are hashed column by column straight from the Arrow
+// buffers, and rows whose hashes collide are told apart by comparing their
+// typed values, so no per-row string keys are ever built.
+package rowhash
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/zeebo/xxh3"
+)
+
+// kind is the representation a key column is hashed and compared in.
+// Columns of the same kind compare by value, so int32 and int64 keys match.
+type kind int
+
+const (
+	kindInt      kind = iota // signed integers and unsigned integers up to 32 bits
+	kindUint                 // uint64
+	kindFloat                // float32 and float64
+	kindBool                 // boolean
+	kindString               // string, large string and string view
+	kindBinary               // binary, large binary, fixed size binary and binary view
+	kindTemporal             // dates, times, timestamps and durations of one type
+	kindOther                // everything else, by its value string
+)
+
+// nullHash is mixed into the row hash for a null key value.
+const nullHash = 0x2545f4914f6cdd1d
+
+// column is one key column decoded for hashing and comparison.
+type column struct {
+	kind kind
+	// tag distinguishes types of the same kind that are not comparable,
+	// such as timestamps of different units.
+	tag string
+	// values holds the key values. For dictionary columns it is the
+	// dictionary and index maps every row to a dictionary entry (-1 if null).
+	values  arrow.Array
+	index   []int
+	noNulls bool
+
+	ints   []int64
+	floats []float64
+	bools  *array.Boolean
+	// String and binary values are read straight from the offsets and data
+	// buffers when the offsets are 32 bits wide, and through the accessors
+	// otherwise.
+	offsets []int32
+	data    []byte
+	strs    interface{ Value(int) string }
+	binary  interface{ Value(int) []byte }
+}
+
+// Keys holds the per-row hashes of one or more key columns. Keys built from
+// different arrays can be compared with each other, which is how joins probe
+// one side with the other.
+type Keys struct {
+	columns []column
+	hashes  []uint64
+	nulls   []bool
+}
+
+// NewKeys hashes every row of the given key columns. All columns must have
+// the same length.
+func NewKeys(columns ...arrow.Array) (*Keys, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("at least one key column is required")
+	}
+	n := columns[0].Len()
+	k := &Keys{
+		columns: make([]column, len(columns)),
+		hashes:  make([]uint64, n),
+	}
+	for i, arr := range columns {
+		if arr.Len() != n {
+			return nil, fmt.Errorf("key column %d has %d rows, expected %d", i, arr.Len(), n)
+		}
+		k.columns[i] = newColumn(arr)
+		k.hashColumn(&k.columns[i])
+	}
+	return k, nil
+}
+
+// Len returns the number of rows.
+func (k *Keys) Len() int {
+	return len(k.hashes)
+}
+
+// Hash returns the hash of a row's key.
+func (k *Keys) Hash(row int) uint64 {
+	return k.hashes[row]
+}
+
+// HasNull reports whether any key value of the row is null.
+func (k *Keys) HasNull(row int) bool {
+	return k.nulls != nil && k.nulls[row]
+}
+
+// Equal reports whether the key of row equals the key of otherRow in other.
+// Nulls are equal to each other, and values of incomparable types are never
+// equal.
+func (k *Keys) Equal(row int, other *Keys, otherRow int) bool {
+	if k.hashes[row] != other.hashes[otherRow] || len(k.columns) != len(other.columns) {
+		return false
+	}
+	for c := range k.columns {
+		a, b := &k.columns[c], &other.columns[c]
+		if k != other && (a.kind != b.kind || a.tag != b.tag) {
+			return false
+		}
+		if a.noNulls && b.noNulls {
+			if !a.equal(row, b, otherRow) {
+				return false
+			}
+			continue
+		}
+		i, aValid := a.valueIndex(row)
+		j, bValid := b.valueIndex(otherRow)
+		if aValid != bValid {
+			return false
+		}
+		if aValid && !a.equal(i, b, j) {
+			return false
+		}
+	}
+	return true
+}
+
+// Comparable returns an error unless every key column of k has the same
+// kind of values as the matching column of other, which Compare requires.
+func (k *Keys) Comparable(other *Keys) error {
+	if len(k.columns) != len(other.columns) {
+		return fmt.Errorf("key column counts differ: %d and %d", len(k.columns), len(other.columns))
+	}
+	for c := range k.columns {
+		a, b := &k.columns[c], &other.columns[c]
+		if a.kind != b.kind || a.tag != b.tag {
+			return fmt.Errorf("key column %d has incomparable types %s and %s", c, a.values.DataType(), b.values.DataType())
+		}
+	}
+	return nil
+}
+
+// Compare orders the key of row against the key of otherRow in other, column
+// by column. Nulls sort first and NaN sorts after all other floats. The keys
+// must be Comparable.
+func (k *Keys) Compare(row int, other *Keys, otherRow int) int {
+	for c := range k.columns {
+		a, b := &k.columns[c], &other.columns[c]
+		i, aValid := a.valueIndex(row)
+		j, bValid := b.valueIndex(otherRow)
+		switch {
+		case !aValid && !bValid:
+			continue
+		case !aValid:
+			return -1
+		case !bValid:
+			return 1
+		}
+		if r := a.compare(i, b, j); r != 0 {
+			return r
+		}
+	}
+	return 0
+}
+
+// hashColumn mixes the hashes of col's values into the row hashes.
+func (k *Keys) hashColumn(col *column) {
+	valueHashes := col.valueHashes()
+	if col.noNulls {
+		for row, h := range valueHashes {
+			k.hashes[row] = combine(k.hashes[row], h)
+		}
+		return
+	}
+	for row := range k.hashes {
+		h := uint64(nullHash)
+		if i, valid := col.valueIndex(row); valid {
+			h = valueHashes[i]
+		} else {
+			if k.nulls == nil {
+				k.nulls = make([]bool, len(k.hashes))
+			}
+			k.nulls[row] = true
+		}
+		k.hashes[row] = combine(k.hashes[row], h)
+	}
+}
+
+func newColumn(arr arrow.Array) column {
+	if dict, ok := arr.(*array.Dictionary); ok {
+		col := newColumn(dict.Dictionary())
+		col.noNulls = false
+		col.index = make([]int, dict.Len())
+		for i := range col.index {
+			if dict.IsNull(i) {
+				col.index[i] = -1
+			} else {
+				col.index[i] = dict.GetValueIndex(i)
+			}
+		}
+		return col
+	}
+
+	col := column{values: arr}
+	switch a := arr.(type) {
+	case *array.Int8:
+		col.kind, col.ints = kindInt, widen(a.Int8Values())
+	case *array.Int16:
+		col.kind, col.ints = kindInt, widen(a.Int16Values())
+	case *array.Int32:
+		col.kind, col.ints = kindInt, widen(a.Int32Values())
+	case *array.Int64:
+		col.kind, col.ints = kindInt, a.Int64Values()
+	case *array.Uint8:
+		col.kind, col.ints = kindInt, widen(a.Uint8Values())
+	case *array.Uint16:
+		col.kind, col.ints = kindInt, widen(a.Uint16Values())
+	case *array.Uint32:
+		col.kind, col.ints = kindInt, widen(a.Uint32Values())
+	case *array.Uint64:
+		col.kind, col.ints = kindUint, widen(a.Uint64Values())
+	case *array.Float32:
+		col.kind = kindFloat
+		col.floats = make([]float64, a.Len())
+		for i, v := range a.Float32Values() {
+			col.floats[i] = float64(v)
+		}
+	case *array.Float64:
+		col.kind, col.floats = kindFloat, a.Float64Values()
+	case *array.Boolean:
+		col.kind, col.bools = kindBool, a
+	case *array.String:
+		col.kind, col.offsets, col.data = kindString, a.ValueOffsets(), a.ValueBytes()
+	case *array.LargeString:
+		col.kind, col.strs = kindString, a
+	case *array.StringView:
+		col.kind, col.strs = kindString, a
+	case *array.Binary:
+		col.kind, col.offsets, col.data = kindBinary, a.ValueOffsets(), a.ValueBytes()
+	case *array.LargeBinary:
+		col.kind, col.binary = kindBinary, a
+	case *array.BinaryView:
+		col.kind, col.binary = kindBinary, a
+	case *array.FixedSizeBinary:
+		col.kind, col.binary = kindBinary, a
+	case *array.Date32:
+		col.kind, col.ints = kindTemporal, widen(a.Date32Values())
+	case *array.Date64:
+		col.kind, col.ints = kindTemporal, widen(a.Date64Values())
+	case *array.Time32:
+		col.kind, col.ints = kindTemporal, widen(a.Time32Values())
+	case *array.Time64:
+		col.kind, col.ints = kindTemporal, widen(a.Time64Values())
+	case *array.Timestamp:
+		col.kind, col.ints = kindTemporal, widen(a.TimestampValues())
+	case *array.Duration:
+		col.kind, col.ints = kindTemporal, widen(a.DurationValues())
+	default:
+		col.kind = kindOther
+	}
+	if col.kind == kindTemporal || col.kind == kindOther {
+		col.tag = arr.DataType().Fingerprint()
+	}
+	col.noNulls = arr.NullN() == 0
+	return col
+}
+
+// widen converts integer values to int64. uint64 values keep their bits.
+func widen[T ~int8 | ~int16 | ~int32 | ~int64 | ~uint8 | ~uint16 | ~uint32 | ~uint64](values []T) []int64 {
+	out := make([]int64, len(values))
+	for i, v := range values {
+		out[i] = int64(v)
+	}
+	return out
+}
+
+// valueIndex maps a row to the index of its value and reports whether the
+// value is valid.
+func (c *column) valueIndex(row int) (int, bool) {
+	if c.index == nil {
+		return row, c.noNulls || c.values.IsValid(row)
+	}
+	i := c.index[row]
+	return i, i >= 0 && c.values.IsValid(i)
+}
+
+// bytesAt returns value i of a string or binary column without copying.
+func (c *column) bytesAt(i int) []byte {
+	switch {
+	case c.offsets != nil:
+		base := c.offsets[0]
+		return c.data[c.offsets[i]-base : c.offsets[i+1]-base]
+	case c.binary != nil:
+		return c.binary.Value(i)
+	default:
+		s := c.strs.Value(i)
+		return unsafe.Slice(unsafe.StringData(s), len(s))
+	}
+}
+
+// valueHashes hashes every value of the column, ignoring validity.
+func (c *column) valueHashes() []uint64 {
+	out := make([]uint64, c.values.Len())
+	switch c.kind {
+	case kindInt, kindUint, kindTemporal:
+		for i, v := range c.ints {
+			out[i] = mix(uint64(v))
+		}
+	case kindFloat:
+		for i, v := range c.floats {
+			switch {
+			case v == 0:
+				v = 0 // -0 and +0 are equal
+			case math.IsNaN(v):
+				v = math.NaN()
+			}
+			out[i] = mix(math.Float64bits(v))
+		}
+	case kindBool:
+		for i := range out {
+			if c.bools.Value(i) {
+				out[i] = mix(1)
+			} else {
+				out[i] = mix(2)
+			}
+		}
+	case kindString, kindBinary:
+		if c.offsets != nil {
+			base := c.offsets[0]
+			for i := range out {
+				out[i] = xxh3.Hash(c.data[c.offsets[i]-base : c.offsets[i+1]-base])
+			}
+			break
+		}
+		for i := range out {
+			out[i] = xxh3.Hash(c.bytesAt(i))
+		}
+	default:
+		for i := range out {
+			out[i] = xxh3.HashString(c.values.ValueStr(i))
+		}
+	}
+	return out
+}
+
+// equal compares value i of c with value j of other, which has the same
+// kind.
+func (c *column) equal(i int, other *column, j int) bool {
+	switch c.kind {
+	case kindInt, kindUint, kindTemporal:
+		return c.ints[i] == other.ints[j]
+	case kindFloat:
+		a, b := c.floats[i], other.floats[j]
+		return a == b || (math.IsNaN(a) && math.IsNaN(b))
+	case kindBool:
+		return c.bools.Value(i) == other.bools.Value(j)
+	case kindString, kindBinary:
+		return bytes.Equal(c.bytesAt(i), other.bytesAt(j))
+	default:
+		return c.values.ValueStr(i) == other.values.ValueStr(j)
+	}
+}
+
+// compare orders value i of c against value j of other, which has the
+// same kind.
+func (c *column) compare(i int, other *column, j int) int {
+	switch c.kind {
+	case kindInt, kindTemporal:
+		return cmp.Compare(c.ints[i], other.ints[j])
+	case kindUint:
+		return cmp.Compare(uint64(c.ints[i]), uint64(other.ints[j]))
+	case kindFloat:
+		// cmp.Compare sorts NaN first; keep it after the numbers instead
+		a, b := c.floats[i], other.floats[j]
+		switch aNaN, bNaN := math.IsNaN(a), math.IsNaN(b); {
+		case aNaN && bNaN:
+			return 0
+		case aNaN:
+			return 1
+		case bNaN:
+			return -1
+		}
+		return cmp.Compare(a, b)
+	case kindBool:
+		a, b := c.bools.Value(i), other.bools.Value(j)
+		switch {
+		case a == b:
+			return 0
+		case !a:
+			return -1
+		default:
+			return 1
+		}
+	case kindString, kindBinary:
+		return bytes.Compare(c.bytesAt(i), other.bytesAt(j))
+	default:
+		return strings.Compare(c.values.ValueStr(i), other.values.ValueStr(j))
+	}
+}
+
+// mix is the splitmix64 finalizer, which spreads the bits of fixed-width
+// values over the whole hash.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// combine folds the hash of the next key column into a row hash. It is not
+// symmetric, so (a, b) and (b, a) hash differently.
+func combine(h, v uint64) uint64 {
+	return mix(h*0x9e3779b97f4a7c15 + v)
+}
+
+// Groups assigns every row of a set of keys to a group of equal keys.
+// Group ids are dense and numbered in order of first appearance.
+type Groups struct {
+	keys  *Keys
+	heads map[uint64]int32
+	next  []int32
+	first []int
+	ids   []int
+}
+
+// NewGroups groups the rows of keys. Rows whose keys contain nulls form
+// groups of their own, which HasNull reports.
+func NewGroups(keys *Keys) *Groups {
+	n := keys.Len()
+	g := &Groups{
+		keys:  keys,
+		heads: make(map[uint64]int32),
+		ids:   make([]int, n),
+	}
+	for row := 0; row < n; row++ {
+		g.ids[row] = g.insert(row)
+	}
+	return g
+}
+
+func (g *Groups) insert(row int) int {
+	h := g.keys.hashes[row]
+	head, ok := g.heads[h]
+	if ok {
+		for id := head; id >= 0; id = g.next[id] {
+			if g.keys.Equal(row, g.keys, g.first[id]) {
+				return int(id)
+			}
+		}
+	} else {
+		head = -1
+	}
+	id := len(g.first)
+	g.first = append(g.first, row)
+	g.next = append(g.next, head)
+	g.heads[h] = int32(id)
+	return id
+}
+
+// minParallelRows is the number of rows below which NewGroupsParallel
+// groups on the calling goroutine.
+const minParallelRows = 1 << 14
+
+// NewGroupsParallel groups the rows of keys like NewGroups, on up to
+// parallelism goroutines. Rows are divided among the goroutines by hash, so
+// every group is built by exactly one of them; group ids are then
+// renumbered in order of first appearance and match those of NewGroups.
+func NewGroupsParallel(keys *Keys, parallelism int) *Groups {
+	n := keys.Len()
+	parts := min(parallelism, n/minParallelRows)
+	if parts <= 1 {
+		return NewGroups(keys)
+	}
+	partOf := func(row int) int {
+		return int((keys.hashes[row] >> 32) % uint64(parts))
+	}
+
+	// Every partition numbers its groups locally; the ids of rows of
+	// different partitions are disjoint entries of the shared slice.
+	ids := make([]int, n)
+	local := make([]*Groups, parts)
+	var wg sync.WaitGroup
+	for p := range local {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			g := &Groups{keys: keys, heads: make(map[uint64]int32)}
+			for row := 0; row < n; row++ {
+				if partOf(row) == p {
+					ids[row] = g.insert(row)
+				}
+			}
+			local[p] = g
+		}()
+	}
+	wg.Wait()
+
+	// Merge the partitions' first rows, each already ascending, into
+	// global ids in order of first appearance
+	total := 0
+	for _, g := range local {
+		total += len(g.first)
+	}
+	global := make([][]int, parts)
+	for p, g := range local {
+		global[p] = make([]int, len(g.first))
+	}
+	g := &Groups{
+		keys:  keys,
+		heads: make(map[uint64]int32, total),
+		next:  make([]int32, 0, total),
+		first: make([]int, 0, total),
+		ids:   ids,
+	}
+	pos := make([]int, parts)
+	for len(g.first) < total {
+		best := -1
+		for p, l := range local {
+			if pos[p] < len(l.first) && (best < 0 || l.first[pos[p]] < local[best].first[pos[best]]) {
+				best = p
+			}
+		}
+		row := local[best].first[pos[best]]
+		id := len(g.first)
+		global[best][pos[best]] = id
+		pos[best]++
+
+		h := keys.hashes[row]
+		head, ok := g.heads[h]
+		if !ok {
+			head = -1
+		}
+		g.first = append(g.first, row)
+		g.next = append(g.next, head)
+		g.heads[h] = int32(id)
+	}
+
+	// Renumber the rows' local ids
+	chunk := (n + parts - 1) / parts
+	for lo := 0; lo < n; lo += chunk {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for row := lo; row < min(lo+chunk, n); row++ {
+				ids[row] = global[partOf(row)][ids[row]]
+			}
+		}()
+	}
+	wg.Wait()
+	return g
+}
+
+// Len returns the number of groups.
+func (g *Groups) Len() int {
+	return len(g.first)
+}
+
+// Keys returns the keys that were grouped.
+func (g *Groups) Keys() *Keys {
+	return g.keys
+}
+
+// FirstRow returns the first row of a group.
+func (g *Groups) FirstRow(id int) int {
+	return g.first[id]
+}
+
+// FirstRows returns the first row of every group, indexed by group id.
+func (g *Groups) FirstRows() []int {
+	return g.first
+}
+
+// GroupOf returns the group id of a row.
+func (g *Groups) GroupOf(row int) int {
+	return g.ids[row]
+}
+
+// HasNull reports whether the key of a group contains a null.
+func (g *Groups) HasNull(id int) bool {
+	return g.keys.HasNull(g.first[id])
+}
+
+// Rows returns the rows of every group in ascending order, indexed by group
+// id. The row slices share one backing array.
+func (g *Groups) Rows() [][]int {
+	counts := make([]int, len(g.first))
+	for _, id := range g.ids {
+		counts[id]++
+	}
+	backing := make([]int, len(g.ids))
+	rows := make([][]int, len(g.first))
+	offset := 0
+	for id, count := range counts {
+		rows[id] = backing[offset : offset : offset+count]
+		offset += count
+	}
+	for row, id := range g.ids {
+		rows[id] = append(rows[id], row)
+	}
+	return rows
+}
+
+// Sorted returns the group ids ordered by their key values.
+func (g *Groups) Sorted() []int {
+	ids := make([]int, len(g.first))
+	for i := range ids {
+		ids[i] = i
+	}
+	slices.SortStableFunc(ids, func(a, b int) int {
+		return g.keys.Compare(g.first[a], g.keys, g.first[b])
+	})
+	return ids
+}
+
+// Find returns the group whose key equals the key of row in probe, or -1 if
+// there is none. Probe keys containing nulls never match, as in SQL joins.
+func (g *Groups) Find(probe *Keys, row int) int {
+	if probe.HasNull(row) {
+		return -1
+	}
+	head, ok := g.heads[probe.hashes[row]]
+	if !ok {
+		return -1
+	}
+	for id := head; id >= 0; id = g.next[id] {
+		if probe.Equal(row, g.keys, g.first[id]) {
+			return int(id)
+		}
+	}
+	return -1
+}
@@ -0,0 +1,213 @@
+package rowhash
+
+import (
+	"math"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func int64Array(values []int64, valid []bool) arrow.Array {
+	b := array.NewInt64Builder(memory.NewGoAllocator())
+	defer b.Release()
+	b.AppendValues(values, valid)
+	return b.NewArray()
+}
+
+func stringArray(values []string, valid []bool) arrow.Array {
+	b := array.NewStringBuilder(memory.NewGoAllocator())
+	defer b.Release()
+	b.AppendValues(values, valid)
+	return b.NewArray()
+}
+
+func TestGroups_MultiColumn(t *testing.T) {
+	ids := int64Array([]int64{2, 1, 2, 1, 2, 0}, []bool{true, true, true, true, true, false})
+	defer ids.Release()
+	names := stringArray([]string{"b", "a", "b", "b", "b", "a"}, nil)
+	defer names.Release()
+
+	keys, err := NewKeys(ids, names)
+	require.NoError(t, err)
+	groups := NewGroups(keys)
+
+	require.Equal(t, 4, groups.Len())
+	assert.Equal(t, []int{0, 1, 3, 5}, groups.FirstRows())
+	assert.Equal(t, [][]int{{0, 2, 4}, {1}, {3}, {5}}, groups.Rows())
+	assert.Equal(t, 0, groups.GroupOf(4))
+	assert.True(t, groups.HasNull(3))
+	assert.False(t, groups.HasNull(0))
+
+	// Nulls first, then ordered by id and name
+	assert.Equal(t, []int{3, 1, 2, 0}, groups.Sorted())
+}
+
+func TestKeys_TypedEquality(t *testing.T) {
+	pool := memory.NewGoAllocator()
+
+	ints := int64Array([]int64{1, 10}, nil)
+	defer ints.Release()
+	strs := stringArray([]string{"1", "10"}, nil)
+	defer strs.Release()
+	i32b := array.NewInt32Builder(pool)
+	i32b.AppendValues([]int32{10, 1}, nil)
+	i32 := i32b.NewArray()
+	i32b.Release()
+	defer i32.Release()
+
+	intKeys, err := NewKeys(ints)
+	require.NoError(t, err)
+	strKeys, err := NewKeys(strs)
+	require.NoError(t, err)
+	i32Keys, err := NewKeys(i32)
+	require.NoError(t, err)
+
+	// Integers of different widths match, strings never match integers
+	groups := NewGroups(intKeys)
+	assert.Equal(t, 1, groups.Find(i32Keys, 0))
+	assert.Equal(t, 0, groups.Find(i32Keys, 1))
+	assert.Equal(t, -1, groups.Find(strKeys, 0))
+	assert.NoError(t, intKeys.Comparable(i32Keys))
+	assert.Error(t, intKeys.Comparable(strKeys))
+	assert.Less(t, intKeys.Compare(0, i32Keys, 0), 0)
+
+	// Timestamps of different units are not comparable
+	msType := &arrow.TimestampType{Unit: arrow.Millisecond}
+	sType := &arrow.TimestampType{Unit: arrow.Second}
+	msb := array.NewTimestampBuilder(pool, msType)
+	msb.Append(1)
+	ms := msb.NewArray()
+	msb.Release()
+	defer ms.Release()
+	sb := array.NewTimestampBuilder(pool, sType)
+	sb.Append(1)
+	s := sb.NewArray()
+	sb.Release()
+	defer s.Release()
+	msKeys, err := NewKeys(ms)
+	require.NoError(t, err)
+	sKeys, err := NewKeys(s)
+	require.NoError(t, err)
+	assert.False(t, msKeys.Equal(0, sKeys, 0))
+	assert.True(t, msKeys.Equal(0, msKeys, 0))
+	assert.Error(t, msKeys.Comparable(sKeys))
+}
+
+func TestKeys_Floats(t *testing.T) {
+	b := array.NewFloat64Builder(memory.NewGoAllocator())
+	b.AppendValues([]float64{0, math.Copysign(0, -1), math.NaN(), 1.5, math.NaN()}, nil)
+	arr := b.NewArray()
+	b.Release()
+	defer arr.Release()
+
+	keys, err := NewKeys(arr)
+	require.NoError(t, err)
+	groups := NewGroups(keys)
+	assert.Equal(t, [][]int{{0, 1}, {2, 4}, {3}}, groups.Rows())
+	// NaN sorts after the numbers
+	assert.Equal(t, []int{0, 2, 1}, groups.Sorted())
+}
+
+func TestKeys_Dictionary(t *testing.T) {
+	pool := memory.NewGoAllocator()
+	dictType := &arrow.DictionaryType{IndexType: arrow.PrimitiveTypes.Int8, ValueType: arrow.BinaryTypes.String}
+	db := array.NewDictionaryBuilder(pool, dictType).(*array.BinaryDictionaryBuilder)
+	require.NoError(t, db.AppendString("x"))
+	require.NoError(t, db.AppendString("y"))
+	db.AppendNull()
+	require.NoError(t, db.AppendString("x"))
+	dict := db.NewArray()
+	db.Release()
+	defer dict.Release()
+
+	plain := stringArray([]string{"y", "x", "z"}, nil)
+	defer plain.Release()
+
+	dictKeys, err := NewKeys(dict)
+	require.NoError(t, err)
+	plainKeys, err := NewKeys(plain)
+	require.NoError(t, err)
+
+	groups := NewGroups(dictKeys)
+	assert.Equal(t, [][]int{{0, 3}, {1}, {2}}, groups.Rows())
+	assert.True(t, groups.HasNull(2))
+	// Dictionary and plain string keys compare by value
+	assert.Equal(t, 1, groups.Find(plainKeys, 0))
+	assert.Equal(t, 0, groups.Find(plainKeys, 1))
+	assert.Equal(t, -1, groups.Find(plainKeys, 2))
+}
+
+func TestGroups_FindSkipsNullProbes(t *testing.T) {
+	build := int64Array([]int64{1, 0}, []bool{true, false})
+	defer build.Release()
+	probe := int64Array([]int64{0, 1}, []bool{false, true})
+	defer probe.Release()
+
+	buildKeys, err := NewKeys(build)
+	require.NoError(t, err)
+	probeKeys, err := NewKeys(probe)
+	require.NoError(t, err)
+
+	groups := NewGroups(buildKeys)
+	assert.Equal(t, -1, groups.Find(probeKeys, 0))
+	assert.Equal(t, 0, groups.Find(probeKeys, 1))
+}
+
+func TestNewGroupsParallel_MatchesSerial(t *testing.T) {
+	n := 4*minParallelRows + 17
+	values := make([]int64, n)
+	valid := make([]bool, n)
+	names := make([]string, n)
+	for i := range values {
+		values[i] = int64(i*7919) % 5003
+		valid[i] = i%101 != 0
+		names[i] = string(rune('a' + i%3))
+	}
+	ids := int64Array(values, valid)
+	defer ids.Release()
+	strs := stringArray(names, nil)
+	defer strs.Release()
+	keys, err := NewKeys(ids, strs)
+	require.NoError(t, err)
+
+	serial := NewGroups(keys)
+	for _, parallelism := range []int{2, 3, 8} {
+		parallel := NewGroupsParallel(keys, parallelism)
+		require.Equal(t, serial.FirstRows(), parallel.FirstRows())
+		assert.Equal(t, serial.Rows(), parallel.Rows())
+		for row := 0; row < n; row += 97 {
+			assert.Equal(t, serial.Find(keys, row), parallel.Find(keys, row))
+		}
+	}
+}
+
+func TestNewKeys_Errors(t *testing.T) {
+	_, err := NewKeys()
+	assert.Error(t, err)
+
+	a := int64Array([]int64{1, 2}, nil)
+	defer a.Release()
+	b := int64Array([]int64{1}, nil)
+	defer b.Release()
+	_, err = NewKeys(a, b)
+	assert.ErrorContains(t, err, "expected 2")
+}
+
+func BenchmarkGroups_Int64(b *testing.B) {
+	values := make([]int64, 100000)
+	for i := range values {
+		values[i] = int64(i % 1000)
+	}
+	arr := int64Array(values, nil)
+	defer arr.Release()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		keys, _ := NewKeys(arr)
+		NewGroups(keys).Rows()
+	}
+}
@@ -0,0 +1,201 @@
+// Package sketch implements mergeable summaries of large sets of values:
+// HyperLogLog for distinct counts and t-digest for quantiles. Sketches of
+// separate parts of a dataset, such as the chunks of a stream or the
+// partitions of a parallel aggregation, merge into the sketch of the whole,
+// and serialize to bytes so partial results can be stored and combined
+// later.
+package sketch
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+const (
+	// MinPrecision and MaxPrecision bound the precision of a HyperLogLog.
+	MinPrecision = 4
+	MaxPrecision = 18
+	// DefaultPrecision gives a standard error of about 0.8% in 16 KiB.
+	DefaultPrecision = 14
+)
+
+// hllMagic starts every serialized HyperLogLog, followed by the format
+// version.
+var hllMagic = [2]byte{'H', 'L'}
+
+const hllVersion = 1
+
+// HyperLogLog estimates the number of distinct values among the hashes
+// added to it. A sketch of precision p keeps 2^p registers and has a
+// standard error of about 1.04/sqrt(2^p).
+type HyperLogLog struct {
+	precision uint8
+	registers []uint8
+}
+
+// NewHyperLogLog returns an empty HyperLogLog of the given precision,
+// between MinPrecision and MaxPrecision.
+func NewHyperLogLog(precision int) (*HyperLogLog, error) {
+	if precision < MinPrecision || precision > MaxPrecision {
+		return nil, fmt.Errorf("precision must be between %d and %d, got %d", MinPrecision, MaxPrecision, precision)
+	}
+	return &HyperLogLog{precision: uint8(precision), registers: make([]uint8, 1<<precision)}, nil
+}
+
+// Precision returns the precision of the sketch.
+func (h *HyperLogLog) Precision() int {
+	return int(h.precision)
+}
+
+// AddHash adds the 64-bit hash of a value. Hashes must be uniformly
+// distributed, and equal values must have equal hashes in every sketch
+// that is merged.
+func (h *HyperLogLog) AddHash(hash uint64) {
+	p := h.precision
+	index := hash >> (64 - p)
+	// The sentinel bit caps the rank at 64-p+1 when the remaining bits are 0
+	rank := uint8(bits.LeadingZeros64(hash<<p|1<<(p-1))) + 1
+	if rank > h.registers[index] {
+		h.registers[index] = rank
+	}
+}
+
+// Merge adds the values of other to h. Sketches of different precision
+// merge at the lower of the two.
+func (h *HyperLogLog) Merge(other *HyperLogLog) {
+	if other.precision < h.precision {
+		h.reduce(other.precision)
+	} else if other.precision > h.precision {
+		other = other.Clone()
+		other.reduce(h.precision)
+	}
+	for i, r := range other.registers {
+		if r > h.registers[i] {
+			h.registers[i] = r
+		}
+	}
+}
+
+// reduce lowers the precision of h to p, as if its hashes had been added to
+// a sketch of precision p.
+func (h *HyperLogLog) reduce(p uint8) {
+	shift := h.precision - p
+	registers := make([]uint8, 1<<p)
+	for index, r := range h.registers {
+		if r == 0 {
+			continue
+		}
+		// The low index bits become the leading bits of the rank
+		dropped := uint64(index) & (1<<shift - 1)
+		rank := shift + r
+		if dropped != 0 {
+			rank = shift - uint8(bits.Len64(dropped)) + 1
+		}
+		if j := index >> shift; rank > registers[j] {
+			registers[j] = rank
+		}
+	}
+	h.precision, h.registers = p, registers
+}
+
+// Estimate returns the estimated number of distinct values, using Ertl's
+// improved raw estimator, which needs no empirical bias correction and is
+// accurate for small and large counts alike.
+func (h *HyperLogLog) Estimate() uint64 {
+	m := float64(len(h.registers))
+	q := 64 - int(h.precision)
+	counts := make([]float64, q+2)
+	for _, r := range h.registers {
+		counts[r]++
+	}
+	if counts[0] == m {
+		return 0
+	}
+	z := m * tau(1-counts[q+1]/m)
+	for k := q; k >= 1; k-- {
+		z = 0.5 * (z + counts[k])
+	}
+	z += m * sigma(counts[0]/m)
+	return uint64(math.Round(m * m / (2 * math.Ln2 * z)))
+}
+
+func sigma(x float64) float64 {
+	if x == 1 {
+		return math.Inf(1)
+	}
+	y, z := 1.0, x
+	for {
+		x *= x
+		previous := z
+		z += x * y
+		y += y
+		if z == previous {
+			return z
+		}
+	}
+}
+
+func tau(x float64) float64 {
+	if x == 0 || x == 1 {
+		return 0
+	}
+	y, z := 1.0, 1-x
+	for {
+		x = math.Sqrt(x)
+		previous := z
+		y *= 0.5
+		z -= (1 - x) * (1 - x) * y
+		if z == previous {
+			return z / 3
+		}
+	}
+}
+
+// Clone returns a copy of the sketch.
+func (h *HyperLogLog) Clone() *HyperLogLog {
+	return &HyperLogLog{precision: h.precision, registers: append([]uint8(nil), h.registers...)}
+}
+
+// MarshalBinary serializes the sketch.
+func (h *HyperLogLog) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, 4+len(h.registers))
+	data = append(data, hllMagic[0], hllMagic[1], hllVersion, h.precision)
+	return append(data, h.registers...), nil
+}
+
+// UnmarshalBinary replaces the sketch with one serialized by MarshalBinary.
+func (h *HyperLogLog) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 || data[0] != hllMagic[0] || data[1] != hllMagic[1] {
+		return fmt.Errorf("not a HyperLogLog sketch")
+	}
+	if data[2] != hllVersion {
+		return fmt.Errorf("unsupported HyperLogLog version: %d", data[2])
+	}
+	p := data[3]
+	if p < MinPrecision || p > MaxPrecision {
+		return fmt.Errorf("invalid HyperLogLog precision: %d", p)
+	}
+	registers := data[4:]
+	if len(registers) != 1<<p {
+		return fmt.Errorf("HyperLogLog sketch has %d registers, expected %d", len(registers), 1<<p)
+	}
+	for _, r := range registers {
+		if int(r) > 64-int(p)+1 {
+			return fmt.Errorf("invalid HyperLogLog register value: %d", r)
+		}
+	}
+	h.precision, h.registers = p, append([]uint8(nil), registers...)
+	return nil
+}
+
+// appendFloat64 appends v to data in little-endian order.
+func appendFloat64(data []byte, v float64) []byte {
+	return binary.LittleEndian.AppendUint64(data, math.Float64bits(v))
+}
+
+// readFloat64 reads a little-endian float64 from the start of data.
+func readFloat64(data []byte) float64 {
+	return math.Float64frombits(binary.LittleEndian.Uint64(data))
+}
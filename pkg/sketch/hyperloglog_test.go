@@ -0,0 +1,98 @@
+package sketch
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zeebo/xxh3"
+)
+
+func hashOf(i int) uint64 {
+	return xxh3.HashString(string(rune(i%1000)) + string(rune(i/1000)))
+}
+
+func TestHyperLogLog_Estimate(t *testing.T) {
+	for _, n := range []int{0, 1, 3, 100, 5000, 100_000, 1_000_000} {
+		h, err := NewHyperLogLog(DefaultPrecision)
+		require.NoError(t, err)
+		for i := 0; i < n; i++ {
+			// Every value twice
+			h.AddHash(hashOf(i))
+			h.AddHash(hashOf(i))
+		}
+		assert.InDelta(t, float64(n), float64(h.Estimate()), math.Max(0.5, 0.03*float64(n)), "n=%d", n)
+	}
+}
+
+func TestHyperLogLog_Merge(t *testing.T) {
+	a, err := NewHyperLogLog(12)
+	require.NoError(t, err)
+	b, err := NewHyperLogLog(12)
+	require.NoError(t, err)
+	whole, err := NewHyperLogLog(12)
+	require.NoError(t, err)
+	for i := 0; i < 20_000; i++ {
+		whole.AddHash(hashOf(i))
+		if i < 15_000 {
+			a.AddHash(hashOf(i))
+		}
+		if i >= 5_000 {
+			b.AddHash(hashOf(i))
+		}
+	}
+	a.Merge(b)
+	assert.Equal(t, whole.Estimate(), a.Estimate())
+}
+
+func TestHyperLogLog_MergeDifferentPrecision(t *testing.T) {
+	high, err := NewHyperLogLog(14)
+	require.NoError(t, err)
+	low, err := NewHyperLogLog(10)
+	require.NoError(t, err)
+	direct, err := NewHyperLogLog(10)
+	require.NoError(t, err)
+	for i := 0; i < 30_000; i++ {
+		direct.AddHash(hashOf(i))
+		if i%2 == 0 {
+			high.AddHash(hashOf(i))
+		} else {
+			low.AddHash(hashOf(i))
+		}
+	}
+
+	// Merging either way is the same as adding every hash at the lower precision
+	merged := high.Clone()
+	merged.Merge(low)
+	assert.Equal(t, 10, merged.Precision())
+	assert.Equal(t, direct.registers, merged.registers)
+
+	low.Merge(high)
+	assert.Equal(t, direct.registers, low.registers)
+}
+
+func TestHyperLogLog_Binary(t *testing.T) {
+	h, err := NewHyperLogLog(8)
+	require.NoError(t, err)
+	for i := 0; i < 1000; i++ {
+		h.AddHash(hashOf(i))
+	}
+	data, err := h.MarshalBinary()
+	require.NoError(t, err)
+
+	var decoded HyperLogLog
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, h.Estimate(), decoded.Estimate())
+	assert.Equal(t, 8, decoded.Precision())
+
+	assert.Error(t, decoded.UnmarshalBinary(data[:len(data)-1]))
+	assert.Error(t, decoded.UnmarshalBinary([]byte("TD\x01\x08")))
+}
+
+func TestNewHyperLogLog_InvalidPrecision(t *testing.T) {
+	_, err := NewHyperLogLog(3)
+	assert.Error(t, err)
+	_, err = NewHyperLogLog(19)
+	assert.Error(t, err)
+}
@@ -0,0 +1,214 @@
+package sketch
+
+import (
+	"cmp"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// DefaultCompression keeps a few hundred centroids, for quantile errors
+// well below 1% and smaller still near the tails.
+const DefaultCompression = 100
+
+// tdigestMagic starts every serialized TDigest, followed by the format
+// version.
+var tdigestMagic = [2]byte{'T', 'D'}
+
+const tdigestVersion = 1
+
+// centroid is the mean of a run of adjacent values and their weight.
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+// TDigest estimates quantiles of the values added to it. Values are
+// summarized as centroids that are small near the tails and larger around
+// the median, with at most about compression centroids in total, so
+// extreme quantiles stay accurate. Groups of up to a few hundred values
+// keep every value and give exact quantiles.
+type TDigest struct {
+	compression float64
+	centroids   []centroid
+	// buffer holds values and centroids not yet merged into centroids
+	buffer   []centroid
+	count    float64
+	min, max float64
+}
+
+// NewTDigest returns an empty TDigest with the given compression, at least
+// 10. Higher compression keeps more centroids and is more accurate.
+func NewTDigest(compression float64) (*TDigest, error) {
+	if !(compression >= 10) || math.IsInf(compression, 1) {
+		return nil, fmt.Errorf("compression must be at least 10, got %v", compression)
+	}
+	return &TDigest{compression: compression, min: math.Inf(1), max: math.Inf(-1)}, nil
+}
+
+// Compression returns the compression of the digest.
+func (t *TDigest) Compression() float64 {
+	return t.compression
+}
+
+// Count returns the number of values added to the digest.
+func (t *TDigest) Count() float64 {
+	return t.count
+}
+
+// Add adds a value. NaN is ignored.
+func (t *TDigest) Add(x float64) {
+	if math.IsNaN(x) {
+		return
+	}
+	t.add(centroid{mean: x, weight: 1}, x, x)
+}
+
+func (t *TDigest) add(c centroid, min, max float64) {
+	t.buffer = append(t.buffer, c)
+	t.count += c.weight
+	t.min = math.Min(t.min, min)
+	t.max = math.Max(t.max, max)
+	if len(t.buffer) >= int(5*t.compression) {
+		t.compress()
+	}
+}
+
+// Merge adds the values of other to t. The result depends on the order of
+// merges only within the accuracy of the digest.
+func (t *TDigest) Merge(other *TDigest) {
+	for _, c := range other.centroids {
+		t.add(c, other.min, other.max)
+	}
+	for _, c := range other.buffer {
+		t.add(c, other.min, other.max)
+	}
+}
+
+// compress merges the buffer into the centroids. Adjacent centroids are
+// combined while their weight stays within the bound 4·n·q·(1−q)/δ at both
+// ends of the combined centroid, where q is the quantile of that end.
+func (t *TDigest) compress() {
+	if len(t.buffer) == 0 {
+		return
+	}
+	all := append(t.centroids, t.buffer...)
+	slices.SortStableFunc(all, func(a, b centroid) int { return cmp.Compare(a.mean, b.mean) })
+
+	limit := func(q float64) float64 { return 4 * t.count * q * (1 - q) / t.compression }
+	merged := make([]centroid, 0, len(all))
+	current := all[0]
+	var before float64
+	for _, c := range all[1:] {
+		weight := current.weight + c.weight
+		if weight <= min(limit(before/t.count), limit((before+weight)/t.count)) {
+			current.mean += (c.mean - current.mean) * c.weight / weight
+			current.weight = weight
+			continue
+		}
+		before += current.weight
+		merged = append(merged, current)
+		current = c
+	}
+	t.centroids = append(merged, current)
+	t.buffer = t.buffer[:0]
+}
+
+// Quantile returns the estimated q-th quantile (0.0-1.0), interpolating
+// linearly between the closest values like an exact percentile, or NaN for
+// an empty digest.
+func (t *TDigest) Quantile(q float64) float64 {
+	t.compress()
+	if t.count == 0 || math.IsNaN(q) {
+		return math.NaN()
+	}
+	if q <= 0 {
+		return t.min
+	}
+	if q >= 1 {
+		return t.max
+	}
+	cs := t.centroids
+	if len(cs) == 1 {
+		return cs[0].mean
+	}
+
+	// Every centroid sits at the center of its run of values, and the
+	// minimum and maximum at the first and last value. Rank i of n sorted
+	// values is at position i+0.5.
+	interpolate := func(x0, y0, x1, y1, x float64) float64 {
+		if x1 <= x0 {
+			return y1
+		}
+		return y0 + (x-x0)/(x1-x0)*(y1-y0)
+	}
+	index := q*(t.count-1) + 0.5
+	position := cs[0].weight / 2
+	if index <= position {
+		return interpolate(0.5, t.min, position, cs[0].mean, index)
+	}
+	for i := 0; i < len(cs)-1; i++ {
+		next := position + (cs[i].weight+cs[i+1].weight)/2
+		if index <= next {
+			return interpolate(position, cs[i].mean, next, cs[i+1].mean, index)
+		}
+		position = next
+	}
+	return interpolate(position, cs[len(cs)-1].mean, t.count-0.5, t.max, index)
+}
+
+// Clone returns a copy of the digest.
+func (t *TDigest) Clone() *TDigest {
+	clone := *t
+	clone.centroids = slices.Clone(t.centroids)
+	clone.buffer = slices.Clone(t.buffer)
+	return &clone
+}
+
+// MarshalBinary serializes the digest, merging its buffered values first.
+func (t *TDigest) MarshalBinary() ([]byte, error) {
+	t.compress()
+	data := make([]byte, 0, 4+8*3+4+16*len(t.centroids))
+	data = append(data, tdigestMagic[0], tdigestMagic[1], tdigestVersion, 0)
+	data = appendFloat64(data, t.compression)
+	data = appendFloat64(data, t.min)
+	data = appendFloat64(data, t.max)
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(t.centroids)))
+	for _, c := range t.centroids {
+		data = appendFloat64(data, c.mean)
+		data = appendFloat64(data, c.weight)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary replaces the digest with one serialized by MarshalBinary.
+func (t *TDigest) UnmarshalBinary(data []byte) error {
+	const header = 4 + 8*3 + 4
+	if len(data) < header || data[0] != tdigestMagic[0] || data[1] != tdigestMagic[1] {
+		return fmt.Errorf("not a t-digest sketch")
+	}
+	if data[2] != tdigestVersion {
+		return fmt.Errorf("unsupported t-digest version: %d", data[2])
+	}
+	digest, err := NewTDigest(readFloat64(data[4:]))
+	if err != nil {
+		return err
+	}
+	digest.min, digest.max = readFloat64(data[12:]), readFloat64(data[20:])
+	n := int(binary.LittleEndian.Uint32(data[28:]))
+	if len(data) != header+16*n {
+		return fmt.Errorf("t-digest sketch has %d bytes, expected %d", len(data), header+16*n)
+	}
+	digest.centroids = make([]centroid, n)
+	for i := range digest.centroids {
+		c := centroid{mean: readFloat64(data[header+16*i:]), weight: readFloat64(data[header+16*i+8:])}
+		if math.IsNaN(c.mean) || !(c.weight > 0) || (i > 0 && c.mean < digest.centroids[i-1].mean) {
+			return fmt.Errorf("invalid t-digest centroid %d", i)
+		}
+		digest.centroids[i] = c
+		digest.count += c.weight
+	}
+	*t = *digest
+	return nil
+}
@@ -0,0 +1,113 @@
+package sketch
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exactQuantile interpolates linearly between the closest ranks.
+func exactQuantile(sorted []float64, q float64) float64 {
+	rank := q * float64(len(sorted)-1)
+	lower := int(rank)
+	if lower+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lower] + (rank-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
+
+func TestTDigest_SmallInputsAreExact(t *testing.T) {
+	d, err := NewTDigest(DefaultCompression)
+	require.NoError(t, err)
+	values := []float64{7, 1, 3, 9, 5, 3}
+	for _, v := range values {
+		d.Add(v)
+	}
+	d.Add(math.NaN())
+	slices.Sort(values)
+	for _, q := range []float64{0, 0.1, 0.25, 0.5, 0.9, 1} {
+		assert.InDelta(t, exactQuantile(values, q), d.Quantile(q), 1e-12, "q=%v", q)
+	}
+	assert.Equal(t, float64(6), d.Count())
+}
+
+func TestTDigest_Accuracy(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	d, err := NewTDigest(DefaultCompression)
+	require.NoError(t, err)
+	values := make([]float64, 200_000)
+	for i := range values {
+		values[i] = rng.NormFloat64()
+		d.Add(values[i])
+	}
+	slices.Sort(values)
+	for _, q := range []float64{0.001, 0.01, 0.25, 0.5, 0.75, 0.99, 0.999} {
+		// Compare ranks, since errors are bounded in quantile space
+		got := d.Quantile(q)
+		rank, _ := slices.BinarySearch(values, got)
+		assert.InDelta(t, q, float64(rank)/float64(len(values)), 0.005, "q=%v", q)
+	}
+}
+
+func TestTDigest_Merge(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	whole, err := NewTDigest(DefaultCompression)
+	require.NoError(t, err)
+	var parts []*TDigest
+	for p := 0; p < 8; p++ {
+		part, err := NewTDigest(DefaultCompression)
+		require.NoError(t, err)
+		for i := 0; i < 10_000; i++ {
+			v := rng.ExpFloat64()
+			part.Add(v)
+			whole.Add(v)
+		}
+		parts = append(parts, part)
+	}
+	merged := parts[0]
+	for _, part := range parts[1:] {
+		merged.Merge(part)
+	}
+	assert.Equal(t, whole.Count(), merged.Count())
+	for _, q := range []float64{0.01, 0.5, 0.99} {
+		assert.InDelta(t, whole.Quantile(q), merged.Quantile(q), 0.02*whole.Quantile(q)+1e-3, "q=%v", q)
+	}
+	assert.Equal(t, whole.Quantile(0), merged.Quantile(0))
+	assert.Equal(t, whole.Quantile(1), merged.Quantile(1))
+}
+
+func TestTDigest_Binary(t *testing.T) {
+	d, err := NewTDigest(50)
+	require.NoError(t, err)
+	for i := 0; i < 10_000; i++ {
+		d.Add(float64(i % 977))
+	}
+	data, err := d.MarshalBinary()
+	require.NoError(t, err)
+
+	var decoded TDigest
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, d.Count(), decoded.Count())
+	assert.Equal(t, float64(50), decoded.Compression())
+	for _, q := range []float64{0, 0.3, 0.5, 0.95, 1} {
+		assert.Equal(t, d.Quantile(q), decoded.Quantile(q))
+	}
+
+	assert.Error(t, decoded.UnmarshalBinary(data[:len(data)-1]))
+	assert.Error(t, decoded.UnmarshalBinary([]byte("HL\x01\x0e")))
+
+	empty, err := NewTDigest(DefaultCompression)
+	require.NoError(t, err)
+	assert.True(t, math.IsNaN(empty.Quantile(0.5)))
+}
+
+func TestNewTDigest_InvalidCompression(t *testing.T) {
+	for _, compression := range []float64{0, 5, math.NaN(), math.Inf(1)} {
+		_, err := NewTDigest(compression)
+		assert.Error(t, err, "compression=%v", compression)
+	}
+}
@@ -1,5 +1,6 @@
 // Package arrow provides an Apache Arrow storage backend implementation.
-// This is the default backend that handles Arrow IPC format files.
+// This is the default backend that handles Arrow IPC format files, and
+// Parquet files through Arrow's Parquet integration.
 package arrow
 
 import (
@@ -10,6 +11,8 @@ import (
 	"strings"
 
 	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/compute"
 	"github.com/apache/arrow-go/v18/arrow/ipc"
 	"github.com/felixgeelhaar/GopherFrame/pkg/storage"
 )
@@ -44,7 +47,10 @@ func NewBackend() storage.Backend {
 	return &Backend{}
 }
 
-// Read implements storage.Backend.Read for Arrow IPC files.
+// Read implements storage.Backend.Read for Arrow IPC and Parquet files.
+// Records are projected to opts.Columns and filtered by opts.Filter, which
+// must be a storage.RecordPredicate such as an expr.Expr, as they are read. Sources with a .parquet extension
+// are read as Parquet in batches of opts.BatchSize rows.
 func (b *Backend) Read(ctx context.Context, source string, opts storage.ReadOptions) (storage.RecordReader, error) {
 	// Validate source path
 	if source == "" {
@@ -64,6 +70,15 @@ func (b *Backend) Read(ctx context.Context, source string, opts storage.ReadOpti
 		return nil, fmt.Errorf("failed to open Arrow file: %w", err)
 	}
 
+	if isParquet(source) {
+		rr, err := newParquetRecordReader(ctx, file, opts)
+		if err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+		return rr, nil
+	}
+
 	// Create Arrow IPC file reader
 	reader, err := ipc.NewFileReader(file)
 	if err != nil {
@@ -71,16 +86,25 @@ func (b *Backend) Read(ctx context.Context, source string, opts storage.ReadOpti
 		return nil, fmt.Errorf("failed to create Arrow file reader: %w", err)
 	}
 
-	// Create our record reader wrapper
-	return &recordReader{
+	rr := &recordReader{
 		reader: reader,
 		file:   file,
 		opts:   opts,
 		ctx:    ctx,
-	}, nil
+	}
+	if rr.pushdown, err = newPushdown(reader.Schema(), opts); err != nil {
+		_ = rr.Close()
+		return nil, err
+	}
+	return rr, nil
 }
 
 // Write implements storage.Backend.Write for Arrow IPC files.
+// opts.Compression selects IPC body compression ("lz4" or "zstd").
+// Destinations with a .parquet extension are written as Parquet, encoded by
+// the storage.ParquetWriteOptions under ParquetOptionsKey in opts.Options;
+// opts.Compression and opts.BatchSize supply its codec and row group size
+// when those are not set.
 func (b *Backend) Write(ctx context.Context, destination string, records storage.RecordReader, opts storage.WriteOptions) error {
 	// Validate destination
 	if destination == "" {
@@ -104,6 +128,14 @@ func (b *Backend) Write(ctx context.Context, destination string, records storage
 		}
 	}
 
+	var ipcOpts []ipc.Option
+	if !isParquet(destination) {
+		var err error
+		if ipcOpts, err = ipcWriteOptions(opts); err != nil {
+			return err
+		}
+	}
+
 	// Create the file
 	file, err := os.Create(destination)
 	if err != nil {
@@ -111,8 +143,12 @@ func (b *Backend) Write(ctx context.Context, destination string, records storage
 	}
 	defer func() { _ = file.Close() }()
 
+	if isParquet(destination) {
+		return writeParquet(ctx, file, records, opts)
+	}
+
 	// Create Arrow IPC file writer
-	writer, err := ipc.NewFileWriter(file, ipc.WithSchema(records.Schema()))
+	writer, err := ipc.NewFileWriter(file, append(ipcOpts, ipc.WithSchema(records.Schema()))...)
 	if err != nil {
 		return fmt.Errorf("failed to create Arrow file writer: %w", err)
 	}
@@ -138,7 +174,7 @@ func (b *Backend) Write(ctx context.Context, destination string, records storage
 	return nil
 }
 
-// Scan implements storage.Backend.Scan for Arrow files.
+// Scan implements storage.Backend.Scan for Arrow IPC and Parquet files.
 func (b *Backend) Scan(ctx context.Context, pattern string) ([]storage.SourceInfo, error) {
 	// Use filepath.Glob to find matching files
 	matches, err := filepath.Glob(pattern)
@@ -150,7 +186,12 @@ func (b *Backend) Scan(ctx context.Context, pattern string) ([]storage.SourceInf
 	for _, match := range matches {
 		// Only include files with Arrow extensions
 		ext := strings.ToLower(filepath.Ext(match))
-		if ext != ".arrow" && ext != ".ipc" {
+		fileType := "arrow"
+		switch ext {
+		case ".arrow", ".ipc":
+		case ".parquet":
+			fileType = "parquet"
+		default:
 			continue
 		}
 
@@ -173,7 +214,7 @@ func (b *Backend) Scan(ctx context.Context, pattern string) ([]storage.SourceInf
 			Schema:   schema,
 			Modified: info.ModTime().Unix(),
 			Metadata: map[string]string{
-				"type": "arrow",
+				"type": fileType,
 				"ext":  ext,
 			},
 		}
@@ -184,7 +225,7 @@ func (b *Backend) Scan(ctx context.Context, pattern string) ([]storage.SourceInf
 	return sources, nil
 }
 
-// Schema implements storage.Backend.Schema for Arrow files.
+// Schema implements storage.Backend.Schema for Arrow IPC and Parquet files.
 func (b *Backend) Schema(_ context.Context, source string) (*arrow.Schema, error) {
 	// Open the file
 	file, err := os.Open(source)
@@ -196,6 +237,14 @@ func (b *Backend) Schema(_ context.Context, source string) (*arrow.Schema, error
 	}
 	defer func() { _ = file.Close() }()
 
+	if isParquet(source) {
+		_, arrowReader, err := openParquet(file, 0)
+		if err != nil {
+			return nil, err
+		}
+		return arrowReader.Schema()
+	}
+
 	// Create Arrow IPC file reader
 	reader, err := ipc.NewFileReader(file)
 	if err != nil {
@@ -220,10 +269,95 @@ type recordReader struct {
 	opts   storage.ReadOptions
 	ctx    context.Context
 
+	pushdown
+	current arrow.Record
+
 	currentIndex int
 	err          error
 }
 
+// pushdown applies the projection and predicate of read options to records.
+type pushdown struct {
+	// schema and indices describe the projected output; indices is nil
+	// when every column is read
+	schema    *arrow.Schema
+	indices   []int
+	predicate storage.RecordPredicate
+}
+
+// newPushdown resolves the projection and predicate in opts against the
+// schema of the source.
+func newPushdown(schema *arrow.Schema, opts storage.ReadOptions) (pushdown, error) {
+	p := pushdown{schema: schema}
+
+	if opts.Filter != nil {
+		predicate, ok := opts.Filter.(storage.RecordPredicate)
+		if !ok {
+			return pushdown{}, fmt.Errorf("%w: filter cannot be evaluated on records: %T", storage.ErrUnsupportedOperation, opts.Filter)
+		}
+		p.predicate = predicate
+	}
+
+	if len(opts.Columns) == 0 {
+		return p, nil
+	}
+	fields := make([]arrow.Field, len(opts.Columns))
+	p.indices = make([]int, len(opts.Columns))
+	for i, name := range opts.Columns {
+		found := schema.FieldIndices(name)
+		if len(found) == 0 {
+			return pushdown{}, fmt.Errorf("%w: column not found: %s", storage.ErrSchemaConflict, name)
+		}
+		p.indices[i] = found[0]
+		fields[i] = schema.Field(found[0])
+	}
+	metadata := schema.Metadata()
+	p.schema = arrow.NewSchema(fields, &metadata)
+	return p, nil
+}
+
+// apply filters a record by the predicate and then projects it.
+// It takes ownership of record.
+func (p pushdown) apply(record arrow.Record) (arrow.Record, error) {
+	if p.predicate != nil {
+		filtered, err := p.filter(record)
+		record.Release()
+		if err != nil {
+			return nil, err
+		}
+		record = filtered
+	}
+
+	if p.indices == nil {
+		return record, nil
+	}
+	defer record.Release()
+	columns := make([]arrow.Array, len(p.indices))
+	for i, idx := range p.indices {
+		columns[i] = record.Column(idx)
+	}
+	return array.NewRecord(p.schema, columns, record.NumRows()), nil
+}
+
+// filter returns the rows of record that match the predicate. Rows where
+// the predicate is null are dropped.
+func (p pushdown) filter(record arrow.Record) (arrow.Record, error) {
+	mask, err := p.predicate.EvaluateRecord(record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate filter %s: %w", p.predicate, err)
+	}
+	defer mask.Release()
+
+	if mask.DataType().ID() != arrow.BOOL {
+		return nil, fmt.Errorf("failed to apply filter %s: filter must be boolean, got %s", p.predicate, mask.DataType())
+	}
+	filtered, err := compute.FilterRecordBatch(context.Background(), record, mask, compute.DefaultFilterOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply filter %s: %w", p.predicate, err)
+	}
+	return filtered, nil
+}
+
 // Next implements storage.RecordReader.Next.
 func (r *recordReader) Next() bool {
 	if r.err != nil {
@@ -261,13 +395,25 @@ func (r *recordReader) Record() arrow.Record {
 		return nil
 	}
 
+	record, err = r.apply(record)
+	if err != nil {
+		r.err = err
+		return nil
+	}
+
+	// The record stays valid until the next call to Record or Close
+	if r.current != nil {
+		r.current.Release()
+	}
+	r.current = record
+
 	r.currentIndex++
 	return record
 }
 
 // Schema implements storage.RecordReader.Schema.
 func (r *recordReader) Schema() *arrow.Schema {
-	return r.reader.Schema()
+	return r.schema
 }
 
 // Err implements storage.RecordReader.Err.
@@ -277,6 +423,10 @@ func (r *recordReader) Err() error {
 
 // Close implements storage.RecordReader.Close.
 func (r *recordReader) Close() error {
+	if r.current != nil {
+		r.current.Release()
+		r.current = nil
+	}
 	if r.reader != nil {
 		_ = r.reader.Close()
 	}
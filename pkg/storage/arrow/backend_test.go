@@ -10,6 +10,7 @@ import (
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/ipc"
 	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/felixgeelhaar/GopherFrame/pkg/expr"
 	"github.com/felixgeelhaar/GopherFrame/pkg/storage"
 )
 
@@ -349,6 +350,79 @@ func TestBackend_Scan(t *testing.T) {
 }
 
 // mockRecordReader implements storage.RecordReader for testing
+func TestBackend_Read_ProjectionAndFilter(t *testing.T) {
+	backend := NewBackend()
+	defer func() { _ = backend.Close() }()
+
+	pool := memory.NewGoAllocator()
+	schema := arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+			{Name: "name", Type: arrow.BinaryTypes.String},
+			{Name: "value", Type: arrow.PrimitiveTypes.Float64},
+		},
+		nil,
+	)
+
+	builder := array.NewRecordBuilder(pool, schema)
+	defer builder.Release()
+	builder.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2, 3, 4}, nil)
+	builder.Field(1).(*array.StringBuilder).AppendValues([]string{"a", "b", "c", "d"}, nil)
+	builder.Field(2).(*array.Float64Builder).AppendValues([]float64{10, 20, 30, 40}, nil)
+	record := builder.NewRecord()
+	defer record.Release()
+
+	filename := filepath.Join(t.TempDir(), "pushdown.arrow")
+	ctx := context.Background()
+	mockWriter := &mockRecordReader{records: []arrow.Record{record}, schema: schema}
+	if err := backend.Write(ctx, filename, mockWriter, storage.WriteOptions{Overwrite: true}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	readOpts := storage.ReadOptions{
+		Columns: []string{"name"},
+		Filter:  expr.Col("value").Gt(expr.Lit(15.0)),
+	}
+	reader, err := backend.Read(ctx, filename, readOpts)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	if names := reader.Schema().Fields(); len(names) != 1 || names[0].Name != "name" {
+		t.Errorf("Expected projected schema [name], got %v", reader.Schema())
+	}
+
+	if !reader.Next() {
+		t.Fatalf("Expected a record, got error: %v", reader.Err())
+	}
+	got := reader.Record()
+	if got == nil {
+		t.Fatalf("Record failed: %v", reader.Err())
+	}
+	if got.NumCols() != 1 || got.NumRows() != 3 {
+		t.Fatalf("Expected 1 column and 3 rows, got %d and %d", got.NumCols(), got.NumRows())
+	}
+	names := got.Column(0).(*array.String)
+	if names.Value(0) != "b" || names.Value(2) != "d" {
+		t.Errorf("Expected names [b c d], got %v", names)
+	}
+
+	// Unknown columns and filters that cannot be evaluated are rejected up front
+	if _, err := backend.Read(ctx, filename, storage.ReadOptions{Columns: []string{"missing"}}); err == nil {
+		t.Error("Read with unknown column should return error")
+	}
+	if _, err := backend.Read(ctx, filename, storage.ReadOptions{Filter: textPredicate("value > 15")}); err == nil {
+		t.Error("Read with a filter that cannot be evaluated should return error")
+	}
+}
+
+// textPredicate is a storage.Predicate that is not a storage.RecordPredicate.
+type textPredicate string
+
+func (p textPredicate) String() string    { return string(p) }
+func (p textPredicate) Columns() []string { return nil }
+
 type mockRecordReader struct {
 	records []arrow.Record
 	schema  *arrow.Schema
@@ -0,0 +1,241 @@
+package arrow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/felixgeelhaar/GopherFrame/pkg/storage"
+)
+
+// ParquetOptionsKey is the WriteOptions.Options key for a
+// storage.ParquetWriteOptions used when writing Parquet files.
+const ParquetOptionsKey = "parquet"
+
+// defaultParquetBatchSize is the number of rows per record read from Parquet
+// files when ReadOptions.BatchSize is not set.
+const defaultParquetBatchSize = 64 * 1024
+
+// isParquet reports whether path names a Parquet file.
+func isParquet(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".parquet")
+}
+
+// ipcWriteOptions maps WriteOptions.Compression to Arrow IPC body compression,
+// which supports only LZ4 frames and zstd.
+func ipcWriteOptions(opts storage.WriteOptions) ([]ipc.Option, error) {
+	switch strings.ToLower(opts.Compression) {
+	case "", "none":
+		return nil, nil
+	case "lz4":
+		return []ipc.Option{ipc.WithLZ4()}, nil
+	case "zstd":
+		return []ipc.Option{ipc.WithZstd()}, nil
+	default:
+		return nil, fmt.Errorf("%w: Arrow IPC files support lz4 and zstd compression, got %q", storage.ErrUnsupportedOperation, opts.Compression)
+	}
+}
+
+// parquetWriteOptions returns the Parquet options in opts.Options, with
+// Compression and BatchSize as defaults for the codec and row group size.
+func parquetWriteOptions(opts storage.WriteOptions) (storage.ParquetWriteOptions, error) {
+	var parquetOpts storage.ParquetWriteOptions
+	if value, ok := opts.Options[ParquetOptionsKey]; ok {
+		if parquetOpts, ok = value.(storage.ParquetWriteOptions); !ok {
+			return parquetOpts, fmt.Errorf("%w: option %q must be a storage.ParquetWriteOptions, got %T",
+				storage.ErrUnsupportedOperation, ParquetOptionsKey, value)
+		}
+	}
+	if parquetOpts.Compression == "" {
+		parquetOpts.Compression = opts.Compression
+	}
+	if parquetOpts.RowGroupSize == 0 && opts.BatchSize > 0 {
+		parquetOpts.RowGroupSize = int64(opts.BatchSize)
+	}
+	return parquetOpts, nil
+}
+
+// writeParquet writes records to w as Parquet, one or more row groups per record.
+func writeParquet(ctx context.Context, w io.Writer, records storage.RecordReader, opts storage.WriteOptions) error {
+	parquetOpts, err := parquetWriteOptions(opts)
+	if err != nil {
+		return err
+	}
+	writer, err := storage.NewParquetWriter(records.Schema(), w, memory.DefaultAllocator, parquetOpts)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = writer.Close() }()
+
+	for records.Next() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := writer.Write(records.Record()); err != nil {
+			return fmt.Errorf("failed to write record: %w", err)
+		}
+	}
+	if err := records.Err(); err != nil {
+		return fmt.Errorf("error reading records: %w", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to close Parquet writer: %w", err)
+	}
+	return nil
+}
+
+// openParquet opens a Parquet file for reading as Arrow records.
+func openParquet(f *os.File, batchSize int) (*file.Reader, *pqarrow.FileReader, error) {
+	if batchSize <= 0 {
+		batchSize = defaultParquetBatchSize
+	}
+	pool := memory.DefaultAllocator
+	parquetReader, err := file.NewParquetReader(f, file.WithReadProps(parquet.NewReaderProperties(pool)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create Parquet reader: %w", err)
+	}
+	arrowReader, err := pqarrow.NewFileReader(parquetReader, pqarrow.ArrowReadProperties{BatchSize: int64(batchSize)}, pool)
+	if err != nil {
+		_ = parquetReader.Close()
+		return nil, nil, fmt.Errorf("failed to create Arrow reader: %w", err)
+	}
+	return parquetReader, arrowReader, nil
+}
+
+// parquetRecordReader reads a Parquet file in batches of ReadOptions.BatchSize
+// rows to implement storage.RecordReader.
+type parquetRecordReader struct {
+	file          *os.File
+	parquetReader *file.Reader
+	records       pqarrow.RecordReader
+	opts          storage.ReadOptions
+	ctx           context.Context
+
+	pushdown
+	current arrow.Record
+	rows    int64
+	err     error
+}
+
+func newParquetRecordReader(ctx context.Context, f *os.File, opts storage.ReadOptions) (*parquetRecordReader, error) {
+	parquetReader, arrowReader, err := openParquet(f, opts.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	r := &parquetRecordReader{file: f, parquetReader: parquetReader, opts: opts, ctx: ctx}
+
+	schema, err := arrowReader.Schema()
+	if err != nil {
+		_ = r.Close()
+		return nil, fmt.Errorf("failed to read Parquet schema: %w", err)
+	}
+	if r.pushdown, err = newPushdown(schema, opts); err != nil {
+		_ = r.Close()
+		return nil, err
+	}
+	if r.records, err = arrowReader.GetRecordReader(ctx, nil, nil); err != nil {
+		_ = r.Close()
+		return nil, fmt.Errorf("failed to read Parquet file: %w", err)
+	}
+	return r, nil
+}
+
+// Next implements storage.RecordReader.Next. It reads the next batch,
+// skipping batches in which no row matches the filter.
+func (r *parquetRecordReader) Next() bool {
+	if r.err != nil {
+		return false
+	}
+	if r.current != nil {
+		r.current.Release()
+		r.current = nil
+	}
+
+	for {
+		if r.ctx.Err() != nil {
+			r.err = r.ctx.Err()
+			return false
+		}
+		if r.opts.Limit > 0 && r.rows >= r.opts.Limit {
+			return false
+		}
+		if !r.records.Next() {
+			if err := r.records.Err(); err != nil && !errors.Is(err, io.EOF) {
+				r.err = fmt.Errorf("failed to read Parquet file: %w", err)
+			}
+			return false
+		}
+
+		record := r.records.Record()
+		record.Retain()
+		record, err := r.apply(record)
+		if err != nil {
+			r.err = err
+			return false
+		}
+		if record.NumRows() == 0 {
+			record.Release()
+			continue
+		}
+		if r.opts.Limit > 0 && r.rows+record.NumRows() > r.opts.Limit {
+			limited := record.NewSlice(0, r.opts.Limit-r.rows)
+			record.Release()
+			record = limited
+		}
+		r.rows += record.NumRows()
+		r.current = record
+		return true
+	}
+}
+
+// Record implements storage.RecordReader.Record. The record stays valid
+// until the next call to Next or Close.
+func (r *parquetRecordReader) Record() arrow.Record {
+	return r.current
+}
+
+// Schema implements storage.RecordReader.Schema.
+func (r *parquetRecordReader) Schema() *arrow.Schema {
+	return r.schema
+}
+
+// Err implements storage.RecordReader.Err.
+func (r *parquetRecordReader) Err() error {
+	return r.err
+}
+
+// Close implements storage.RecordReader.Close.
+func (r *parquetRecordReader) Close() error {
+	if r.current != nil {
+		r.current.Release()
+		r.current = nil
+	}
+	if r.records != nil {
+		r.records.Release()
+		r.records = nil
+	}
+	// Closing the Parquet reader also closes the file
+	if r.parquetReader != nil {
+		err := r.parquetReader.Close()
+		r.parquetReader = nil
+		r.file = nil
+		return err
+	}
+	if r.file != nil {
+		err := r.file.Close()
+		r.file = nil
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package arrow
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/felixgeelhaar/GopherFrame/pkg/expr"
+	"github.com/felixgeelhaar/GopherFrame/pkg/storage"
+)
+
+func newParquetTestRecord(t *testing.T) arrow.Record {
+	t.Helper()
+	pool := memory.NewGoAllocator()
+	schema := arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+			{Name: "name", Type: arrow.BinaryTypes.String},
+		},
+		nil,
+	)
+	builder := array.NewRecordBuilder(pool, schema)
+	defer builder.Release()
+	builder.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2, 3, 4, 5}, nil)
+	builder.Field(1).(*array.StringBuilder).AppendValues([]string{"a", "b", "c", "d", "e"}, nil)
+	return builder.NewRecord()
+}
+
+func TestBackend_Parquet_WriteOptionsAndRead(t *testing.T) {
+	backend := NewBackend()
+	defer func() { _ = backend.Close() }()
+
+	record := newParquetTestRecord(t)
+	defer record.Release()
+
+	filename := filepath.Join(t.TempDir(), "data.parquet")
+	ctx := context.Background()
+	writeOpts := storage.WriteOptions{
+		Compression: "gzip",
+		Options: map[string]interface{}{
+			ParquetOptionsKey: storage.ParquetWriteOptions{
+				RowGroupSize: 2,
+				Metadata:     map[string]string{"writer": "backend"},
+			},
+		},
+	}
+	mockWriter := &mockRecordReader{records: []arrow.Record{record}, schema: record.Schema()}
+	if err := backend.Write(ctx, filename, mockWriter, writeOpts); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	reader, err := file.OpenParquetFile(filename, false)
+	if err != nil {
+		t.Fatalf("OpenParquetFile failed: %v", err)
+	}
+	meta := reader.MetaData()
+	if len(meta.RowGroups) != 3 {
+		t.Errorf("Expected 3 row groups, got %d", len(meta.RowGroups))
+	}
+	column, err := meta.RowGroup(0).ColumnChunk(0)
+	if err != nil {
+		t.Fatalf("ColumnChunk failed: %v", err)
+	}
+	if column.Compression() != compress.Codecs.Gzip {
+		t.Errorf("Expected gzip compression from WriteOptions.Compression, got %v", column.Compression())
+	}
+	if value := meta.KeyValueMetadata().FindValue("writer"); value == nil || *value != "backend" {
+		t.Errorf("Expected writer=backend metadata, got %v", value)
+	}
+	_ = reader.Close()
+
+	schema, err := backend.Schema(ctx, filename)
+	if err != nil {
+		t.Fatalf("Schema failed: %v", err)
+	}
+	if schema.NumFields() != 2 || schema.Field(0).Name != "id" || schema.Field(1).Name != "name" {
+		t.Errorf("Expected fields [id name], got %v", schema)
+	}
+
+	rr, err := backend.Read(ctx, filename, storage.ReadOptions{
+		Columns:   []string{"name"},
+		Filter:    expr.Col("id").Gt(expr.Lit(int64(1))),
+		Limit:     3,
+		BatchSize: 2,
+	})
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	defer func() { _ = rr.Close() }()
+
+	var names []string
+	for rr.Next() {
+		col := rr.Record().Column(0).(*array.String)
+		for i := 0; i < col.Len(); i++ {
+			names = append(names, col.Value(i))
+		}
+	}
+	if err := rr.Err(); err != nil {
+		t.Fatalf("Read error: %v", err)
+	}
+	if len(names) != 3 || names[0] != "b" || names[2] != "d" {
+		t.Errorf("Expected names [b c d], got %v", names)
+	}
+}
+
+func TestBackend_Write_IPCCompression(t *testing.T) {
+	backend := NewBackend()
+	defer func() { _ = backend.Close() }()
+
+	record := newParquetTestRecord(t)
+	defer record.Release()
+	ctx := context.Background()
+
+	for _, codec := range []string{"lz4", "zstd"} {
+		filename := filepath.Join(t.TempDir(), codec+".arrow")
+		mockWriter := &mockRecordReader{records: []arrow.Record{record}, schema: record.Schema()}
+		if err := backend.Write(ctx, filename, mockWriter, storage.WriteOptions{Compression: codec}); err != nil {
+			t.Fatalf("Write with %s failed: %v", codec, err)
+		}
+
+		reader, err := backend.Read(ctx, filename, storage.ReadOptions{})
+		if err != nil {
+			t.Fatalf("Read of %s file failed: %v", codec, err)
+		}
+		if !reader.Next() || reader.Record().NumRows() != 5 {
+			t.Errorf("Expected 5 rows from %s file, got error %v", codec, reader.Err())
+		}
+		_ = reader.Close()
+	}
+
+	mockWriter := &mockRecordReader{records: []arrow.Record{record}, schema: record.Schema()}
+	err := backend.Write(ctx, filepath.Join(t.TempDir(), "snappy.arrow"), mockWriter, storage.WriteOptions{Compression: "snappy"})
+	if !errors.Is(err, storage.ErrUnsupportedOperation) {
+		t.Errorf("Expected ErrUnsupportedOperation for snappy IPC compression, got %v", err)
+	}
+
+	err = backend.Write(ctx, filepath.Join(t.TempDir(), "bad.parquet"), mockWriter, storage.WriteOptions{
+		Options: map[string]interface{}{ParquetOptionsKey: "zstd"},
+	})
+	if !errors.Is(err, storage.ErrUnsupportedOperation) {
+		t.Errorf("Expected ErrUnsupportedOperation for malformed Parquet options, got %v", err)
+	}
+}
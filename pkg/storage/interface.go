@@ -59,9 +59,11 @@ type ReadOptions struct {
 	// Empty slice means read all columns.
 	Columns []string
 
-	// Filter specifies row-level filtering predicate.
-	// Backend implementations may push down filters for optimization.
-	Filter string
+	// Filter specifies a row-level filtering predicate, usually an expr.Expr.
+	// Only rows matching it are returned. Backends may also use it to skip
+	// data without reading it, for example by checking column statistics.
+	// Nil means all rows.
+	Filter Predicate
 
 	// Limit specifies maximum number of rows to read.
 	// 0 means no limit.
@@ -78,6 +80,27 @@ type ReadOptions struct {
 	Options map[string]interface{}
 }
 
+// Predicate is a row filtering expression. It is implemented by expr.Expr;
+// the interface is declared here because pkg/expr depends on pkg/core, which
+// depends on this package.
+type Predicate interface {
+	// String returns a human-readable representation of the predicate.
+	String() string
+
+	// Columns returns the names of the columns the predicate reads.
+	Columns() []string
+}
+
+// RecordPredicate is a Predicate that can be evaluated on a record, which
+// backends use to filter the rows they read. expr.Expr implements it.
+type RecordPredicate interface {
+	Predicate
+
+	// EvaluateRecord returns a boolean array that is true for the rows of
+	// record that match the predicate.
+	EvaluateRecord(record arrow.Record) (arrow.Array, error)
+}
+
 // WriteOptions contains configuration for write operations.
 type WriteOptions struct {
 	// Overwrite specifies whether to overwrite existing data.
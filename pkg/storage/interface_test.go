@@ -49,6 +49,15 @@ func TestSourceInfo(t *testing.T) {
 	}
 }
 
+// columnPredicate is a minimal Predicate; pkg/expr cannot be imported here.
+type columnPredicate struct {
+	column string
+	text   string
+}
+
+func (p columnPredicate) String() string    { return p.text }
+func (p columnPredicate) Columns() []string { return []string{p.column} }
+
 func TestReadOptions(t *testing.T) {
 	schema := arrow.NewSchema(
 		[]arrow.Field{
@@ -60,7 +69,7 @@ func TestReadOptions(t *testing.T) {
 
 	opts := ReadOptions{
 		Columns:   []string{"id", "name"},
-		Filter:    "id > 100",
+		Filter:    columnPredicate{column: "id", text: "id > 100"},
 		Limit:     500,
 		BatchSize: 1000,
 		Schema:    schema,
@@ -77,10 +86,14 @@ func TestReadOptions(t *testing.T) {
 		t.Errorf("Expected columns [id, name], got %v", opts.Columns)
 	}
 
-	if opts.Filter != "id > 100" {
+	if opts.Filter.String() != "id > 100" {
 		t.Errorf("Expected filter 'id > 100', got %s", opts.Filter)
 	}
 
+	if cols := opts.Filter.Columns(); len(cols) != 1 || cols[0] != "id" {
+		t.Errorf("Expected filter columns [id], got %v", cols)
+	}
+
 	if opts.Limit != 500 {
 		t.Errorf("Expected limit 500, got %d", opts.Limit)
 	}
@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+)
+
+// ParquetWriteOptions configures how Parquet files are encoded. The zero value
+// writes uncompressed files with dictionary encoding and statistics enabled,
+// which are the Parquet library defaults.
+type ParquetWriteOptions struct {
+	// Compression is the codec: "snappy", "zstd", "gzip", "lz4", "brotli"
+	// or "none". Empty means "none".
+	Compression string
+
+	// CompressionLevel is passed to codecs that support levels (zstd, gzip
+	// and brotli). Zero selects the codec's default level.
+	CompressionLevel int
+
+	// RowGroupSize is the maximum number of rows per row group. Zero writes
+	// each DataFrame as a single row group.
+	RowGroupSize int64
+
+	// DataPageSize is the target size of a data page in bytes. Zero uses
+	// the library default of 1 MiB.
+	DataPageSize int64
+
+	// DisableDictionary turns dictionary encoding off for every column
+	// not listed in Dictionary.
+	DisableDictionary bool
+
+	// Dictionary enables or disables dictionary encoding per column,
+	// overriding DisableDictionary.
+	Dictionary map[string]bool
+
+	// DisableStatistics stops min/max/null-count statistics from being
+	// written. Readers then cannot skip row groups by filter.
+	DisableStatistics bool
+
+	// Metadata is stored as key-value metadata in the file footer.
+	Metadata map[string]string
+}
+
+// parquetCodecs maps codec names to Parquet compression codecs. Parquet's
+// "lz4" is the framing-free LZ4_RAW codec; the legacy Hadoop framing is
+// deprecated and cannot be written.
+var parquetCodecs = map[string]compress.Compression{
+	"":       compress.Codecs.Uncompressed,
+	"none":   compress.Codecs.Uncompressed,
+	"snappy": compress.Codecs.Snappy,
+	"zstd":   compress.Codecs.Zstd,
+	"gzip":   compress.Codecs.Gzip,
+	"lz4":    compress.Codecs.Lz4Raw,
+	"brotli": compress.Codecs.Brotli,
+}
+
+// WriterProperties validates the options and converts them to Parquet
+// writer properties with buffers allocated from pool.
+func (o ParquetWriteOptions) WriterProperties(pool memory.Allocator) (*parquet.WriterProperties, error) {
+	name := strings.ToLower(o.Compression)
+	codec, ok := parquetCodecs[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown Parquet compression codec %q", ErrUnsupportedOperation, o.Compression)
+	}
+	if o.RowGroupSize < 0 {
+		return nil, fmt.Errorf("row group size must not be negative, got %d", o.RowGroupSize)
+	}
+	if o.DataPageSize < 0 {
+		return nil, fmt.Errorf("data page size must not be negative, got %d", o.DataPageSize)
+	}
+
+	props := []parquet.WriterProperty{
+		parquet.WithAllocator(pool),
+		parquet.WithCompression(codec),
+		parquet.WithDictionaryDefault(!o.DisableDictionary),
+		parquet.WithStats(!o.DisableStatistics),
+	}
+	if o.CompressionLevel != 0 {
+		switch name {
+		case "zstd", "gzip", "brotli":
+			props = append(props, parquet.WithCompressionLevel(o.CompressionLevel))
+		default:
+			return nil, fmt.Errorf("%w: compression codec %q does not support levels", ErrUnsupportedOperation, o.Compression)
+		}
+	}
+	if o.RowGroupSize > 0 {
+		props = append(props, parquet.WithMaxRowGroupLength(o.RowGroupSize))
+	}
+	if o.DataPageSize > 0 {
+		props = append(props, parquet.WithDataPageSize(o.DataPageSize))
+	}
+	for column, enabled := range o.Dictionary {
+		props = append(props, parquet.WithDictionaryFor(column, enabled))
+	}
+	return parquet.NewWriterProperties(props...), nil
+}
+
+// NewParquetWriter creates a Parquet writer for schema on w, configured by
+// opts. The key-value metadata is added to the footer written by Close, and
+// closing the writer also closes w if it is an io.Closer.
+func NewParquetWriter(schema *arrow.Schema, w io.Writer, pool memory.Allocator, opts ParquetWriteOptions) (*pqarrow.FileWriter, error) {
+	for column := range opts.Dictionary {
+		if len(schema.FieldIndices(column)) == 0 {
+			return nil, fmt.Errorf("%w: dictionary option for unknown column: %s", ErrSchemaConflict, column)
+		}
+	}
+	props, err := opts.WriterProperties(pool)
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := pqarrow.NewFileWriter(schema, w, props, pqarrow.NewArrowWriterProperties(pqarrow.WithAllocator(pool)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Parquet writer: %w", err)
+	}
+	for _, key := range slices.Sorted(maps.Keys(opts.Metadata)) {
+		if err := writer.AppendKeyValueMetadata(key, opts.Metadata[key]); err != nil {
+			_ = writer.Close()
+			return nil, fmt.Errorf("failed to add Parquet metadata %q: %w", key, err)
+		}
+	}
+	return writer, nil
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParquetWriteOptions_WriterProperties(t *testing.T) {
+	column := "id"
+
+	tests := []struct {
+		name    string
+		opts    ParquetWriteOptions
+		codec   compress.Compression
+		wantErr bool
+	}{
+		{name: "zero value", opts: ParquetWriteOptions{}, codec: compress.Codecs.Uncompressed},
+		{name: "none", opts: ParquetWriteOptions{Compression: "none"}, codec: compress.Codecs.Uncompressed},
+		{name: "snappy", opts: ParquetWriteOptions{Compression: "snappy"}, codec: compress.Codecs.Snappy},
+		{name: "zstd with level", opts: ParquetWriteOptions{Compression: "ZSTD", CompressionLevel: 9}, codec: compress.Codecs.Zstd},
+		{name: "gzip", opts: ParquetWriteOptions{Compression: "gzip"}, codec: compress.Codecs.Gzip},
+		{name: "lz4", opts: ParquetWriteOptions{Compression: "lz4"}, codec: compress.Codecs.Lz4Raw},
+		{name: "brotli", opts: ParquetWriteOptions{Compression: "brotli", CompressionLevel: 5}, codec: compress.Codecs.Brotli},
+		{name: "unknown codec", opts: ParquetWriteOptions{Compression: "lzo"}, wantErr: true},
+		{name: "level on snappy", opts: ParquetWriteOptions{Compression: "snappy", CompressionLevel: 3}, wantErr: true},
+		{name: "negative row group size", opts: ParquetWriteOptions{RowGroupSize: -1}, wantErr: true},
+		{name: "negative page size", opts: ParquetWriteOptions{DataPageSize: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			props, err := tt.opts.WriterProperties(memory.DefaultAllocator)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.codec, props.CompressionFor(column))
+			if tt.opts.CompressionLevel != 0 {
+				assert.Equal(t, tt.opts.CompressionLevel, props.CompressionLevelFor(column))
+			}
+		})
+	}
+}
+
+func TestParquetWriteOptions_EncodingProperties(t *testing.T) {
+	opts := ParquetWriteOptions{
+		RowGroupSize:      1000,
+		DataPageSize:      4096,
+		DisableDictionary: true,
+		Dictionary:        map[string]bool{"region": true},
+		DisableStatistics: true,
+	}
+	props, err := opts.WriterProperties(memory.DefaultAllocator)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(1000), props.MaxRowGroupLength())
+	assert.Equal(t, int64(4096), props.DataPageSize())
+	assert.False(t, props.DictionaryEnabledFor("id"))
+	assert.True(t, props.DictionaryEnabledFor("region"))
+	assert.False(t, props.StatisticsEnabledFor("id"))
+}
+
+func TestNewParquetWriter(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}}, nil)
+
+	var buf bytes.Buffer
+	writer, err := NewParquetWriter(schema, &buf, memory.DefaultAllocator, ParquetWriteOptions{
+		Compression: "snappy",
+		Metadata:    map[string]string{"owner": "analytics"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	assert.Positive(t, buf.Len())
+
+	_, err = NewParquetWriter(schema, &buf, memory.DefaultAllocator, ParquetWriteOptions{
+		Dictionary: map[string]bool{"missing": false},
+	})
+	assert.ErrorIs(t, err, ErrSchemaConflict)
+
+	_, err = NewParquetWriter(schema, &buf, memory.DefaultAllocator, ParquetWriteOptions{Compression: "lzo"})
+	assert.ErrorIs(t, err, ErrUnsupportedOperation)
+}
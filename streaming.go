@@ -1,11 +1,9 @@
 package gopherframe
 
 import (
-	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
-	"os"
-	"strconv"
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
@@ -13,40 +11,148 @@ import (
 )
 
 // DataFrameIterator allows processing DataFrames in chunks to handle
-// datasets larger than available memory.
+// datasets larger than available memory. Chunks are read from the source
+// one at a time as they are requested, so only the current chunk is held in
+// memory. Each chunk belongs to the caller, who may release it once done.
+//
+// Example:
+//
+//	it, err := ReadCSVChunked("large.csv", 100_000)
+//	if err != nil {
+//	    return err
+//	}
+//	defer it.Close()
+//	for it.HasNext() {
+//	    chunk := it.Next()
+//	    process(chunk)
+//	    chunk.Release()
+//	}
+//	if err := it.Err(); err != nil {
+//	    return err
+//	}
 type DataFrameIterator struct {
-	chunks []*DataFrame
-	index  int
+	source  chunkSource
+	pending *DataFrame
+	done    bool
+	err     error
 }
 
-// Next returns the next chunk DataFrame, or nil when exhausted.
+// chunkSource produces the chunks of a DataFrameIterator.
+type chunkSource interface {
+	// next returns the next chunk, or io.EOF after the last one.
+	next() (*DataFrame, error)
+
+	// reset restarts the source at its first chunk.
+	reset() error
+
+	// count returns the total number of chunks.
+	count() int
+
+	// close releases the resources held by the source.
+	close() error
+}
+
+// rejectSource is implemented by chunk sources that can drop bad records.
+type rejectSource interface {
+	// rejectedRows returns the records rejected since the last reset.
+	rejectedRows() *DataFrame
+}
+
+// Next returns the next chunk DataFrame, or nil when exhausted or after an
+// error, which is reported by Err.
 func (it *DataFrameIterator) Next() *DataFrame {
-	if it.index >= len(it.chunks) {
+	if !it.HasNext() {
 		return nil
 	}
-	df := it.chunks[it.index]
-	it.index++
+	df := it.pending
+	it.pending = nil
 	return df
 }
 
-// HasNext returns true if there are more chunks available.
+// HasNext returns true if there are more chunks available. It reads the next
+// chunk from the source if it has not been read yet.
 func (it *DataFrameIterator) HasNext() bool {
-	return it.index < len(it.chunks)
+	if it.pending != nil {
+		return true
+	}
+	if it.done || it.err != nil {
+		return false
+	}
+
+	df, err := it.source.next()
+	switch {
+	case errors.Is(err, io.EOF):
+		it.done = true
+		_ = it.source.close()
+		return false
+	case err != nil:
+		it.err = err
+		_ = it.source.close()
+		return false
+	}
+	it.pending = df
+	return true
 }
 
-// Reset resets the iterator to the beginning.
+// Err returns the error that stopped the iteration, if any.
+func (it *DataFrameIterator) Err() error {
+	return it.err
+}
+
+// Reset resets the iterator to the beginning. For file-backed iterators
+// this reopens the file.
 func (it *DataFrameIterator) Reset() {
-	it.index = 0
+	if it.pending != nil {
+		it.pending.Release()
+		it.pending = nil
+	}
+	it.done = false
+	it.err = it.source.reset()
+}
+
+// Rejects returns the lines rejected under BadRecordCollect since the
+// iterator was created or last reset, as a DataFrame with the columns
+// line_number (int64), line and error (string). It is empty for other
+// policies and for iterators that do not reject records.
+func (it *DataFrameIterator) Rejects() *DataFrame {
+	if source, ok := it.source.(rejectSource); ok {
+		return source.rejectedRows()
+	}
+	return newRejectsDataFrame(nil, nil, nil)
 }
 
 // Len returns the total number of chunks.
+//
+// Deprecated: for file-backed iterators Len parses the whole file to count
+// its rows. Iterate with HasNext and Next instead.
 func (it *DataFrameIterator) Len() int {
-	return len(it.chunks)
+	return it.source.count()
+}
+
+// Close releases the file or other resources behind the iterator. Chunks
+// already returned by Next remain valid. It is safe to call more than once.
+func (it *DataFrameIterator) Close() error {
+	if it.pending != nil {
+		it.pending.Release()
+		it.pending = nil
+	}
+	it.done = true
+	return it.source.close()
 }
 
 // ReadCSVChunked reads a CSV file in chunks of the specified size.
 // Returns an iterator that yields DataFrames one chunk at a time.
+// Compressed files are decompressed as they are read, like in
+// ReadCSVWithOptions.
 func ReadCSVChunked(filename string, chunkSize int) (*DataFrameIterator, error) {
+	return ReadCSVChunkedWithOptions(filename, chunkSize, CSVReadOptions{})
+}
+
+// ReadCSVChunkedWithOptions is ReadCSVChunked with the CSV parsed as described
+// by opts. Column types are resolved once from the first chunk and enforced
+// on later chunks; a value that does not match its column's type stops the
+// iteration with an error giving its row number.
+func ReadCSVChunkedWithOptions(filename string, chunkSize int, opts CSVReadOptions) (*DataFrameIterator, error) {
 	if err := validateFilePath(filename); err != nil {
 		return nil, err
 	}
@@ -54,52 +160,103 @@ func ReadCSVChunked(filename string, chunkSize int) (*DataFrameIterator, error)
 		return nil, fmt.Errorf("chunk size must be positive")
 	}
 
-	f, err := os.Open(filename)
+	source := &csvChunkSource{filename: filename, chunkSize: chunkSize, opts: opts}
+	if err := source.open(); err != nil {
+		return nil, err
+	}
+	return &DataFrameIterator{source: source}, nil
+}
+
+// csvChunkSource reads chunks of rows from an open CSV file.
+type csvChunkSource struct {
+	filename  string
+	chunkSize int
+	opts      CSVReadOptions
+
+	file   io.ReadCloser
+	reader *csvReader
+	schema *arrow.Schema
+	row    int // 1-based number of the next data row
+}
+
+func (s *csvChunkSource) open() error {
+	f, err := openCompressed(s.filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open CSV file: %w", err)
+		return fmt.Errorf("failed to open CSV file: %w", err)
 	}
-	defer func() { _ = f.Close() }()
+	reader, err := newCSVReader(f, s.opts)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	s.file, s.reader, s.row = f, reader, 1
+	return nil
+}
 
-	reader := csv.NewReader(f)
+func (s *csvChunkSource) next() (*DataFrame, error) {
+	if s.reader == nil {
+		return nil, io.EOF
+	}
+	rows, err := s.reader.readRows(s.chunkSize)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, io.EOF
+	}
 
-	// Read header
-	header, err := reader.Read()
+	if s.schema == nil {
+		s.schema = s.reader.resolveSchema(rows, defaultCSVInferRows)
+	}
+	record, err := s.reader.buildRecord(s.schema, rows, s.row)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+		return nil, err
 	}
+	defer record.Release()
+	s.row += len(rows)
+	return NewDataFrame(record), nil
+}
 
-	var chunks []*DataFrame
+// reset reopens the file. The schema resolved from the first chunk is kept.
+func (s *csvChunkSource) reset() error {
+	_ = s.close()
+	return s.open()
+}
 
-	for {
-		// Read chunkSize rows
-		var rows [][]string
-		for i := 0; i < chunkSize; i++ {
-			row, err := reader.Read()
-			if err == io.EOF {
-				break
-			}
-			if err != nil {
-				return nil, fmt.Errorf("failed to read CSV row: %w", err)
-			}
-			rows = append(rows, row)
-		}
+func (s *csvChunkSource) count() int {
+	f, err := openCompressed(s.filename)
+	if err != nil {
+		return 0
+	}
+	defer func() { _ = f.Close() }()
+	reader, err := newCSVReader(f, s.opts)
+	if err != nil {
+		return 0
+	}
 
-		if len(rows) == 0 {
+	rows := 0
+	for {
+		if _, err := reader.next(); err != nil {
 			break
 		}
-
-		df, err := csvRowsToDataFrame(header, rows)
-		if err != nil {
-			return nil, err
-		}
-		chunks = append(chunks, df)
+		rows++
 	}
+	return (rows + s.chunkSize - 1) / s.chunkSize
+}
 
-	return &DataFrameIterator{chunks: chunks}, nil
+func (s *csvChunkSource) close() error {
+	s.reader = nil
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
 }
 
-// ForEachChunk applies a function to each chunk in the iterator.
-// This is a convenience method for processing all chunks.
+// ForEachChunk applies a function to each chunk in the iterator, starting
+// from the first chunk. It stops at the first error returned by fn or by
+// the source.
 func (it *DataFrameIterator) ForEachChunk(fn func(chunk *DataFrame) error) error {
 	it.Reset()
 	for it.HasNext() {
@@ -108,7 +265,7 @@ func (it *DataFrameIterator) ForEachChunk(fn func(chunk *DataFrame) error) error
 			return err
 		}
 	}
-	return nil
+	return it.Err()
 }
 
 // Collect reads all chunks and concatenates them into a single DataFrame.
@@ -116,161 +273,86 @@ func (it *DataFrameIterator) ForEachChunk(fn func(chunk *DataFrame) error) error
 func (it *DataFrameIterator) Collect() (*DataFrame, error) {
 	it.Reset()
 
-	if !it.HasNext() {
-		return nil, fmt.Errorf("no chunks to collect")
+	var chunks []*DataFrame
+	defer func() { releaseChunks(chunks) }()
+	for it.HasNext() {
+		chunks = append(chunks, it.Next())
 	}
-
-	first := it.Next()
-	if !it.HasNext() {
-		return first, nil
+	if err := it.Err(); err != nil {
+		return nil, err
 	}
-
-	// For simplicity, collect all rows and rebuild
-	// A more efficient implementation would use Arrow's RecordBatch concatenation
-	pool := memory.NewGoAllocator()
-	schema := first.coreDF.Schema()
-	numCols := int(first.NumCols())
-
-	// Collect all values per column across chunks
-	colValues := make([][]interface{}, numCols)
-	for i := range colValues {
-		colValues[i] = make([]interface{}, 0)
+	switch len(chunks) {
+	case 0:
+		return nil, fmt.Errorf("no chunks to collect")
+	case 1:
+		df := chunks[0]
+		chunks = nil
+		return df, nil
 	}
+	return concatDataFrames(chunks)
+}
 
-	// Helper to extract all values from a DataFrame
-	extractValues := func(df *DataFrame) {
-		record := df.coreDF.Record()
-		for row := 0; row < int(df.NumRows()); row++ {
-			for col := 0; col < numCols; col++ {
-				arr := record.Column(col)
-				if arr.IsNull(row) {
-					colValues[col] = append(colValues[col], nil)
-				} else {
-					colValues[col] = append(colValues[col], getTypedValue(arr, row))
-				}
-			}
-		}
+// Concat stacks the rows of DataFrames with identical schemas into a new
+// DataFrame, such as the per-chunk results of a streaming aggregation. The
+// inputs are not released.
+func Concat(dfs ...*DataFrame) *DataFrame {
+	if len(dfs) == 0 {
+		return &DataFrame{err: fmt.Errorf("no DataFrames to concatenate")}
 	}
-
-	// Process first chunk
-	extractValues(first)
-
-	// Process remaining chunks
-	for it.HasNext() {
-		chunk := it.Next()
-		extractValues(chunk)
+	result, err := concatDataFrames(dfs)
+	if err != nil {
+		return &DataFrame{err: err}
 	}
+	return result
+}
 
-	totalRows := len(colValues[0])
-
-	// Build result arrays
-	var resultColumns []arrow.Array
-	for i, field := range schema.Fields() {
-		switch field.Type.ID() {
-		case arrow.FLOAT64:
-			b := array.NewFloat64Builder(pool)
-			for _, v := range colValues[i] {
-				if v == nil {
-					b.AppendNull()
-				} else {
-					b.Append(v.(float64))
-				}
-			}
-			resultColumns = append(resultColumns, b.NewArray())
-			b.Release()
-		case arrow.INT64:
-			b := array.NewInt64Builder(pool)
-			for _, v := range colValues[i] {
-				if v == nil {
-					b.AppendNull()
-				} else {
-					b.Append(v.(int64))
-				}
-			}
-			resultColumns = append(resultColumns, b.NewArray())
-			b.Release()
-		case arrow.STRING:
-			b := array.NewStringBuilder(pool)
-			for _, v := range colValues[i] {
-				if v == nil {
-					b.AppendNull()
-				} else {
-					b.Append(v.(string))
-				}
-			}
-			resultColumns = append(resultColumns, b.NewArray())
-			b.Release()
-		default:
-			b := array.NewStringBuilder(pool)
-			for _, v := range colValues[i] {
-				if v == nil {
-					b.AppendNull()
-				} else {
-					b.Append(fmt.Sprintf("%v", v))
-				}
-			}
-			resultColumns = append(resultColumns, b.NewArray())
-			b.Release()
+// concatDataFrames concatenates DataFrames with identical schemas into a new
+// DataFrame. The inputs are not released.
+func concatDataFrames(dfs []*DataFrame) (*DataFrame, error) {
+	first := dfs[0]
+	schema := first.Schema()
+	records := make([]arrow.Record, len(dfs))
+	var totalRows int64
+	for i, df := range dfs {
+		if df.Err() != nil {
+			return nil, df.Err()
+		}
+		if !df.Schema().Equal(schema) {
+			return nil, fmt.Errorf("chunk %d schema differs from the first chunk: %s", i+1, df.Schema())
 		}
+		records[i] = df.coreDF.Record()
+		totalRows += records[i].NumRows()
 	}
 
-	record := array.NewRecord(schema, resultColumns, int64(totalRows))
-	return NewDataFrame(record), nil
-}
-
-// csvRowsToDataFrame converts CSV rows to a DataFrame with type inference.
-func csvRowsToDataFrame(header []string, rows [][]string) (*DataFrame, error) {
 	pool := memory.NewGoAllocator()
-	numCols := len(header)
-
-	// Infer types from first row
-	types := make([]arrow.DataType, numCols)
-	for i := range types {
-		types[i] = arrow.BinaryTypes.String // default
-		if len(rows) > 0 && i < len(rows[0]) {
-			if _, err := strconv.ParseFloat(rows[0][i], 64); err == nil {
-				types[i] = arrow.PrimitiveTypes.Float64
+	columns := make([]arrow.Array, schema.NumFields())
+	defer func() {
+		for _, col := range columns {
+			if col != nil {
+				col.Release()
 			}
 		}
-	}
-
-	var fields []arrow.Field
-	var columns []arrow.Array
-
-	for i := 0; i < numCols; i++ {
-		fields = append(fields, arrow.Field{Name: header[i], Type: types[i]})
-
-		switch types[i].ID() {
-		case arrow.FLOAT64:
-			b := array.NewFloat64Builder(pool)
-			for _, row := range rows {
-				if i < len(row) {
-					if v, err := strconv.ParseFloat(row[i], 64); err == nil {
-						b.Append(v)
-					} else {
-						b.AppendNull()
-					}
-				} else {
-					b.AppendNull()
-				}
-			}
-			columns = append(columns, b.NewArray())
-			b.Release()
-		default:
-			b := array.NewStringBuilder(pool)
-			for _, row := range rows {
-				if i < len(row) {
-					b.Append(row[i])
-				} else {
-					b.AppendNull()
-				}
-			}
-			columns = append(columns, b.NewArray())
-			b.Release()
+	}()
+	for i := range columns {
+		parts := make([]arrow.Array, len(records))
+		for j, record := range records {
+			parts[j] = record.Column(i)
+		}
+		col, err := array.Concatenate(parts, pool)
+		if err != nil {
+			return nil, fmt.Errorf("failed to concatenate column %s: %w", schema.Field(i).Name, err)
 		}
+		columns[i] = col
 	}
 
-	schema := arrow.NewSchema(fields, nil)
-	record := array.NewRecord(schema, columns, int64(len(rows)))
+	record := array.NewRecord(schema, columns, totalRows)
+	defer record.Release()
 	return NewDataFrame(record), nil
 }
+
+// releaseChunks releases a list of DataFrames.
+func releaseChunks(chunks []*DataFrame) {
+	for _, chunk := range chunks {
+		chunk.Release()
+	}
+}
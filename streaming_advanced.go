@@ -3,10 +3,14 @@ package gopherframe
 import (
 	"context"
 	"fmt"
-	"os"
 	"path/filepath"
-	"strings"
+	"slices"
 	"sync"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/felixgeelhaar/GopherFrame/pkg/expr"
 )
 
 // --- Backpressure Streaming ---
@@ -18,12 +22,46 @@ type StreamingReader struct {
 	chunkCh  chan *DataFrame
 	errCh    chan error
 	cancelFn context.CancelFunc
+
+	// iterator is the chunk source of streams over a DataFrameIterator
+	iterator *DataFrameIterator
 }
 
 // ReadCSVStreaming creates a backpressure-aware streaming CSV reader.
 // bufferSize controls how many chunks can be buffered ahead of the consumer.
 // The channel blocks the producer when the buffer is full.
 func ReadCSVStreaming(filename string, chunkSize, bufferSize int) (*StreamingReader, error) {
+	return ReadCSVStreamingWithOptions(filename, chunkSize, bufferSize, CSVReadOptions{})
+}
+
+// ReadCSVStreamingWithOptions is ReadCSVStreaming with the CSV parsed as
+// described by opts.
+func ReadCSVStreamingWithOptions(filename string, chunkSize, bufferSize int, opts CSVReadOptions) (*StreamingReader, error) {
+	if err := validateFilePath(filename); err != nil {
+		return nil, err
+	}
+	if chunkSize <= 0 || bufferSize <= 0 {
+		return nil, fmt.Errorf("chunk size and buffer size must be positive")
+	}
+
+	it, err := ReadCSVChunkedWithOptions(filename, chunkSize, opts)
+	if err != nil {
+		return nil, err
+	}
+	return streamIterator(it, bufferSize), nil
+}
+
+// ReadNDJSONStreaming creates a backpressure-aware streaming NDJSON reader
+// that fails on the first malformed line. bufferSize controls how many
+// chunks can be buffered ahead of the consumer.
+func ReadNDJSONStreaming(filename string, chunkSize, bufferSize int) (*StreamingReader, error) {
+	return ReadNDJSONStreamingWithOptions(filename, chunkSize, bufferSize, NDJSONReadOptions{})
+}
+
+// ReadNDJSONStreamingWithOptions is ReadNDJSONStreaming with the lines read
+// as described by opts, like ReadNDJSONChunkedWithOptions. Rejected lines
+// are available from Rejects.
+func ReadNDJSONStreamingWithOptions(filename string, chunkSize, bufferSize int, opts NDJSONReadOptions) (*StreamingReader, error) {
 	if err := validateFilePath(filename); err != nil {
 		return nil, err
 	}
@@ -31,30 +69,42 @@ func ReadCSVStreaming(filename string, chunkSize, bufferSize int) (*StreamingRea
 		return nil, fmt.Errorf("chunk size and buffer size must be positive")
 	}
 
+	it, err := ReadNDJSONChunkedWithOptions(filename, chunkSize, opts)
+	if err != nil {
+		return nil, err
+	}
+	return streamIterator(it, bufferSize), nil
+}
+
+// streamIterator sends the chunks of it to a StreamingReader from a
+// goroutine, closing the iterator when done.
+func streamIterator(it *DataFrameIterator, bufferSize int) *StreamingReader {
 	ctx, cancel := context.WithCancel(context.Background())
 	sr := &StreamingReader{
 		chunkCh:  make(chan *DataFrame, bufferSize),
 		errCh:    make(chan error, 1),
 		cancelFn: cancel,
+		iterator: it,
 	}
 
 	go func() {
 		defer close(sr.chunkCh)
-		it, err := ReadCSVChunked(filename, chunkSize)
-		if err != nil {
-			sr.errCh <- err
-			return
-		}
+		defer func() { _ = it.Close() }()
 		for it.HasNext() {
+			chunk := it.Next()
 			select {
 			case <-ctx.Done():
+				chunk.Release()
 				return
-			case sr.chunkCh <- it.Next():
+			case sr.chunkCh <- chunk:
 			}
 		}
+		if err := it.Err(); err != nil {
+			sr.errCh <- err
+		}
 	}()
 
-	return sr, nil
+	return sr
 }
 
 // Chunks returns the channel of DataFrames. Range over this to consume chunks.
@@ -77,93 +127,191 @@ func (sr *StreamingReader) Cancel() {
 	sr.cancelFn()
 }
 
+// Rejects returns the lines rejected so far by a streaming NDJSON reader
+// under BadRecordCollect, like DataFrameIterator.Rejects. All rejects are
+// included once the Chunks channel is closed.
+func (sr *StreamingReader) Rejects() *DataFrame {
+	if sr.iterator == nil {
+		return newRejectsDataFrame(nil, nil, nil)
+	}
+	return sr.iterator.Rejects()
+}
+
 // --- Partition Pruning ---
 
 // ReadPartitionedWithPruning reads a partitioned dataset, skipping partitions
-// that don't match the given predicates. This avoids reading unnecessary files.
+// that cannot match predicate. This avoids reading unnecessary files.
+//
+// predicate is split into its AND-ed parts. Parts that read only partition
+// columns, such as ranges on a date partition or ORs of values, are
+// evaluated against the partition values of each directory before any file
+// is opened, and only directories where all of them are true are read. The
+// other parts are applied to the rows that were read. A nil predicate reads
+// every partition. Files are read and partition columns typed like in
+// ReadPartitioned.
+//
+// Example:
 //
-// predicates is a map of partition column name to allowed values.
-// Only partitions where all predicates match are read.
-func ReadPartitionedWithPruning(basePath string, predicates map[string][]string) (*DataFrame, error) {
+//	df, err := ReadPartitionedWithPruning("events", Col("day").Ge(Lit(start)).
+//	    And(Col("country").Eq(Lit("US")).Or(Col("country").Eq(Lit("CA")))).
+//	    And(Col("amount").Gt(Lit(100.0))))
+func ReadPartitionedWithPruning(basePath string, predicate expr.Expr) (*DataFrame, error) {
 	if err := validateFilePath(basePath); err != nil {
 		return nil, err
 	}
 
-	predicateSet := make(map[string]map[string]bool)
-	for col, vals := range predicates {
-		predicateSet[col] = make(map[string]bool)
-		for _, v := range vals {
-			predicateSet[col][v] = true
+	files, columns, err := listPartitionFiles(basePath)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no data files found in %s", basePath)
+	}
+	// Types are inferred from every partition so they do not depend on the predicate
+	types := partitionTypes(files, columns, PartitionReadOptions{})
+
+	var partitionPreds, rowPreds []expr.Expr
+	if predicate != nil {
+		for _, conjunct := range splitConjunction(predicate) {
+			if onlyPartitionColumns(conjunct, columns) {
+				partitionPreds = append(partitionPreds, conjunct)
+			} else {
+				rowPreds = append(rowPreds, conjunct)
+			}
 		}
 	}
 
-	var allDFs []*DataFrame
+	matching, err := prunePartitionFiles(files, columns, types, partitionPreds)
+	if err != nil {
+		return nil, err
+	}
+	if len(matching) == 0 {
+		return nil, fmt.Errorf("no matching partitions found in %s", basePath)
+	}
+
+	df, err := readPartitionFiles(matching, columns, types)
+	if err != nil {
+		return nil, err
+	}
+	if len(rowPreds) == 0 {
+		return df, nil
+	}
+	filtered := df.Filter(combineConjunction(rowPreds))
+	df.Release()
+	if filtered.Err() != nil {
+		return nil, fmt.Errorf("failed to filter partitioned dataset: %w", filtered.Err())
+	}
+	return filtered, nil
+}
 
-	err := filepath.Walk(basePath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+// onlyPartitionColumns reports whether e reads no column but partition
+// columns.
+func onlyPartitionColumns(e expr.Expr, columns []string) bool {
+	for _, col := range e.Columns() {
+		if !slices.Contains(columns, col) {
+			return false
 		}
-		if info.IsDir() || !strings.HasSuffix(info.Name(), ".csv") {
-			return nil
+	}
+	return true
+}
+
+// prunePartitionFiles returns the files in the directories whose partition
+// values make every predicate true. The predicates are evaluated once per
+// directory, on a DataFrame with one row per directory.
+func prunePartitionFiles(files []partitionFile, columns []string, types map[string]arrow.DataType, predicates []expr.Expr) ([]partitionFile, error) {
+	if len(predicates) == 0 {
+		return files, nil
+	}
+
+	var dirs []partitionFile
+	dirIndex := make(map[string]int)
+	fileDirs := make([]int, len(files))
+	for i, file := range files {
+		dir := filepath.Dir(file.path)
+		idx, ok := dirIndex[dir]
+		if !ok {
+			idx = len(dirs)
+			dirIndex[dir] = idx
+			dirs = append(dirs, file)
 		}
+		fileDirs[i] = idx
+	}
 
-		// Extract partition columns from path
-		relPath, _ := filepath.Rel(basePath, filepath.Dir(path))
-		parts := strings.Split(relPath, string(filepath.Separator))
-
-		// Check if this partition matches all predicates
-		for _, part := range parts {
-			if strings.Contains(part, "=") {
-				kv := strings.SplitN(part, "=", 2)
-				if len(kv) == 2 {
-					col, val := kv[0], kv[1]
-					if allowed, ok := predicateSet[col]; ok {
-						if !allowed[val] {
-							return nil // Prune: skip this partition
-						}
-					}
-				}
+	pool := memory.NewGoAllocator()
+	fields := make([]arrow.Field, len(columns))
+	arrays := make([]arrow.Array, len(columns))
+	defer func() {
+		for _, arr := range arrays {
+			if arr != nil {
+				arr.Release()
 			}
 		}
-
-		// Read the matching partition
-		df, err := ReadCSV(path)
+	}()
+	for i, column := range columns {
+		values := make([]arrow.Array, len(dirs))
+		for j, dir := range dirs {
+			arr, err := dir.partitionArray(column, types[column], 1, pool)
+			if err != nil {
+				releaseArrays(values[:j])
+				return nil, fmt.Errorf("failed to read partition %s: %w", filepath.Dir(dir.path), err)
+			}
+			values[j] = arr
+		}
+		arr, err := array.Concatenate(values, pool)
+		releaseArrays(values)
 		if err != nil {
-			return fmt.Errorf("failed to read %s: %w", path, err)
+			return nil, fmt.Errorf("failed to build partition column %s: %w", column, err)
 		}
-
-		// Add partition columns
-		for _, part := range parts {
-			if strings.Contains(part, "=") {
-				kv := strings.SplitN(part, "=", 2)
-				if len(kv) == 2 {
-					df = df.WithColumn(kv[0], Lit(kv[1]))
-				}
-			}
+		fields[i] = arrow.Field{Name: column, Type: arr.DataType(), Nullable: true}
+		arrays[i] = arr
+	}
+	record := array.NewRecord(arrow.NewSchema(fields, nil), arrays, int64(len(dirs)))
+	partitions := NewDataFrame(record)
+	record.Release()
+	defer partitions.Release()
+
+	keep := make([]bool, len(dirs))
+	for i := range keep {
+		keep[i] = true
+	}
+	for _, predicate := range predicates {
+		result, err := predicate.Evaluate(partitions.coreDF)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate partition predicate %s: %w", predicate, err)
 		}
-
-		allDFs = append(allDFs, df)
-		return nil
-	})
-	if err != nil {
-		return nil, err
+		matches, ok := result.(*array.Boolean)
+		if !ok {
+			result.Release()
+			return nil, fmt.Errorf("partition predicate %s is not boolean: %s", predicate, result.DataType())
+		}
+		for i := range keep {
+			// Like in Filter, a null predicate does not match
+			keep[i] = keep[i] && matches.IsValid(i) && matches.Value(i)
+		}
+		result.Release()
 	}
 
-	if len(allDFs) == 0 {
-		return nil, fmt.Errorf("no matching partitions found in %s", basePath)
-	}
-	if len(allDFs) == 1 {
-		return allDFs[0], nil
+	var matching []partitionFile
+	for i, file := range files {
+		if keep[fileDirs[i]] {
+			matching = append(matching, file)
+		}
 	}
+	return matching, nil
+}
 
-	it := &DataFrameIterator{chunks: allDFs}
-	return it.Collect()
+// releaseArrays releases every array of arrs.
+func releaseArrays(arrs []arrow.Array) {
+	for _, arr := range arrs {
+		arr.Release()
+	}
 }
 
 // --- Multi-file Parallel Reads ---
 
 // ReadCSVParallel reads multiple CSV files concurrently and returns a combined DataFrame.
-// maxWorkers controls the number of concurrent file readers.
+// maxWorkers controls the number of concurrent file readers. Compressed files
+// are decompressed like in ReadCSV.
 func ReadCSVParallel(filenames []string, maxWorkers int) (*DataFrame, error) {
 	if len(filenames) == 0 {
 		return nil, fmt.Errorf("no files to read")
@@ -208,11 +356,13 @@ func ReadCSVParallel(filenames []string, maxWorkers int) (*DataFrame, error) {
 		return dfs[0], nil
 	}
 
-	it := &DataFrameIterator{chunks: dfs}
-	return it.Collect()
+	return concatDataFrames(dfs)
 }
 
-// ReadJSONParallel reads multiple JSON files concurrently.
+// ReadJSONParallel reads multiple JSON files concurrently. Files named
+// *.ndjson or *.jsonl, optionally followed by a compression extension such
+// as .zst, are read as newline-delimited JSON; compressed files are
+// decompressed like in ReadJSON.
 func ReadJSONParallel(filenames []string, maxWorkers int) (*DataFrame, error) {
 	if len(filenames) == 0 {
 		return nil, fmt.Errorf("no files to read")
@@ -237,7 +387,11 @@ func ReadJSONParallel(filenames []string, maxWorkers int) (*DataFrame, error) {
 			defer wg.Done()
 			sem <- struct{}{}
 			defer func() { <-sem }()
-			df, err := ReadJSON(fn)
+			read := ReadJSON
+			if isNDJSONFile(fn) {
+				read = ReadNDJSON
+			}
+			df, err := read(fn)
 			results[idx] = result{df: df, err: err, idx: idx}
 		}(i, filename)
 	}
@@ -255,6 +409,5 @@ func ReadJSONParallel(filenames []string, maxWorkers int) (*DataFrame, error) {
 		return dfs[0], nil
 	}
 
-	it := &DataFrameIterator{chunks: dfs}
-	return it.Collect()
+	return concatDataFrames(dfs)
 }
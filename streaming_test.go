@@ -1,10 +1,13 @@
 package gopherframe
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -76,6 +79,64 @@ func TestReadCSVChunked_ForEachChunk(t *testing.T) {
 	assert.Equal(t, int64(4), totalRows)
 }
 
+func TestReadCSVChunked_MergeSketches(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "events.csv")
+
+	data := "region,user,latency\n"
+	for i := 0; i < 3000; i++ {
+		data += fmt.Sprintf("r%d,%d,%d.5\n", i%3, (i*7)%911, (i*37)%1000)
+	}
+	require.NoError(t, os.WriteFile(path, []byte(data), 0600))
+
+	it, err := ReadCSVChunked(path, 400)
+	require.NoError(t, err)
+	defer it.Close()
+
+	// Sketch every chunk, then merge the sketches of all chunks
+	var partials []*DataFrame
+	defer func() { releaseChunks(partials) }()
+	err = it.ForEachChunk(func(chunk *DataFrame) error {
+		defer chunk.Release()
+		partial := chunk.GroupBy("region").Agg(
+			ApproxCountDistinct("user", 0).Sketch(),
+			ApproxQuantile("latency", 0.5, 0).Sketch(),
+		)
+		partials = append(partials, partial)
+		return partial.Err()
+	})
+	require.NoError(t, err)
+	require.Len(t, partials, 8)
+
+	combined := Concat(partials...)
+	require.NoError(t, combined.Err())
+	defer combined.Release()
+	merged := combined.GroupBy("region").Agg(
+		MergeCountDistinctSketches("user_approx_count_distinct"),
+		MergeQuantileSketches("latency_approx_p50", 0.5),
+	)
+	require.NoError(t, merged.Err())
+	defer merged.Release()
+
+	all, err := it.Collect()
+	require.NoError(t, err)
+	defer all.Release()
+	direct := all.GroupBy("region").Agg(ApproxCountDistinct("user", 0), ApproxQuantile("latency", 0.5, 0), Median("latency"))
+	require.NoError(t, direct.Err())
+	defer direct.Release()
+
+	// Distinct count sketches merge exactly; quantiles within the sketch's accuracy
+	assert.True(t, array.Equal(direct.Record().Column(1), merged.Record().Column(1)))
+	for i := 0; i < 3; i++ {
+		exact := direct.Record().Column(3).(*array.Float64).Value(i)
+		assert.InDelta(t, exact, merged.Record().Column(2).(*array.Float64).Value(i), 5)
+		assert.InDelta(t, exact, direct.Record().Column(2).(*array.Float64).Value(i), 5)
+		assert.InDelta(t, 911, direct.Record().Column(1).(*array.Int64).Value(i), 15)
+	}
+
+	assert.Error(t, Concat().Err())
+}
+
 func TestReadCSVChunked_InvalidFile(t *testing.T) {
 	_, err := ReadCSVChunked("/nonexistent.csv", 10)
 	assert.Error(t, err)
@@ -85,3 +146,83 @@ func TestReadCSVChunked_InvalidChunkSize(t *testing.T) {
 	_, err := ReadCSVChunked("test.csv", 0)
 	assert.Error(t, err)
 }
+
+func TestReadCSVChunked_ReadsLazilyAndEnforcesSchema(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "test.csv")
+
+	// The first chunk fixes "value" as int64; row 5 does not fit
+	data := "id,value\n1,10\n2,20\n3,30\n4,40\n5,oops\n6,60\n"
+	require.NoError(t, os.WriteFile(path, []byte(data), 0600))
+
+	it, err := ReadCSVChunked(path, 2)
+	require.NoError(t, err)
+	defer func() { _ = it.Close() }()
+
+	first := it.Next()
+	require.NotNil(t, first)
+	assert.Equal(t, arrow.PrimitiveTypes.Int64, first.Schema().Field(1).Type)
+
+	second := it.Next()
+	require.NotNil(t, second)
+	assert.True(t, second.Schema().Equal(first.Schema()))
+	first.Release()
+	second.Release()
+
+	assert.Nil(t, it.Next())
+	require.Error(t, it.Err())
+	assert.Contains(t, it.Err().Error(), `cannot parse "oops" as int64 at row 5`)
+	assert.False(t, it.HasNext())
+
+	_, err = it.Collect()
+	assert.Error(t, err)
+}
+
+func TestReadCSVChunked_CloseAndReset(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "test.csv")
+	require.NoError(t, os.WriteFile(path, []byte("x\n1\n2\n3\n"), 0600))
+
+	it, err := ReadCSVChunked(path, 2)
+	require.NoError(t, err)
+
+	require.NotNil(t, it.Next())
+	require.NoError(t, it.Close())
+	assert.False(t, it.HasNext())
+	assert.Nil(t, it.Next())
+	require.NoError(t, it.Close(), "Close is idempotent")
+
+	// Reset reopens the file from the first row
+	it.Reset()
+	require.NoError(t, it.Err())
+	rows := int64(0)
+	for it.HasNext() {
+		chunk := it.Next()
+		rows += chunk.NumRows()
+		chunk.Release()
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, int64(3), rows)
+}
+
+func TestReadCSVStreaming_ReportsChunkErrors(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "test.csv")
+	require.NoError(t, os.WriteFile(path, []byte("x\n1\n2\nthree\n"), 0600))
+
+	sr, err := ReadCSVStreaming(path, 2, 1)
+	require.NoError(t, err)
+
+	chunks := 0
+	for chunk := range sr.Chunks() {
+		chunks++
+		chunk.Release()
+	}
+	assert.Equal(t, 1, chunks)
+	err = sr.Err()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "at row 3")
+
+	_, err = ReadCSVStreaming(filepath.Join(tmpDir, "missing.csv"), 2, 1)
+	assert.Error(t, err)
+}
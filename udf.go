@@ -185,8 +185,21 @@ func (s *scalarUDFExpr) extractRow(record arrow.Record, colIndices map[string]in
 	return row
 }
 
-func (s *scalarUDFExpr) Name() string   { return "scalar_udf" }
-func (s *scalarUDFExpr) String() string { return "ScalarUDF(...)" }
+func (s *scalarUDFExpr) EvaluateRecord(record arrow.Record) (arrow.Array, error) {
+	return expr.EvaluateRecord(s, record)
+}
+
+func (s *scalarUDFExpr) Name() string          { return "scalar_udf" }
+func (s *scalarUDFExpr) String() string        { return "ScalarUDF(...)" }
+func (s *scalarUDFExpr) Children() []expr.Expr { return nil }
+func (s *scalarUDFExpr) Op() string            { return "scalar_udf" }
+func (s *scalarUDFExpr) Columns() []string     { return s.inputCols }
+func (s *scalarUDFExpr) WithChildren(children []expr.Expr) (expr.Expr, error) {
+	if len(children) != 0 {
+		return nil, fmt.Errorf("scalar_udf expression has no children, got %d", len(children))
+	}
+	return s, nil
+}
 func (s *scalarUDFExpr) Add(other expr.Expr) expr.Expr {
 	return expr.NewBinaryExpr(s, other, "add")
 }
@@ -208,6 +221,36 @@ func (s *scalarUDFExpr) Lt(other expr.Expr) expr.Expr {
 func (s *scalarUDFExpr) Eq(other expr.Expr) expr.Expr {
 	return expr.NewBinaryExpr(s, other, "eq")
 }
+func (s *scalarUDFExpr) Ne(other expr.Expr) expr.Expr {
+	return expr.NewBinaryExpr(s, other, "not_equal")
+}
+func (s *scalarUDFExpr) Ge(other expr.Expr) expr.Expr {
+	return expr.NewBinaryExpr(s, other, "greater_equal")
+}
+func (s *scalarUDFExpr) Le(other expr.Expr) expr.Expr {
+	return expr.NewBinaryExpr(s, other, "less_equal")
+}
+func (s *scalarUDFExpr) And(other expr.Expr) expr.Expr {
+	return expr.NewBinaryExpr(s, other, "and")
+}
+func (s *scalarUDFExpr) Or(other expr.Expr) expr.Expr {
+	return expr.NewBinaryExpr(s, other, "or")
+}
+func (s *scalarUDFExpr) Not() expr.Expr       { return expr.NewUnaryExpr(s, "not") }
+func (s *scalarUDFExpr) IsNull() expr.Expr    { return expr.NewUnaryExpr(s, "is_null") }
+func (s *scalarUDFExpr) IsNotNull() expr.Expr { return expr.NewUnaryExpr(s, "is_not_null") }
+func (s *scalarUDFExpr) FillNull(value expr.Expr) expr.Expr {
+	return expr.Coalesce(s, value)
+}
+func (s *scalarUDFExpr) NullIf(value expr.Expr) expr.Expr {
+	return expr.NewBinaryExpr(s, value, "null_if")
+}
+func (s *scalarUDFExpr) Cast(dataType arrow.DataType) expr.Expr {
+	return expr.NewCastExpr(s, dataType, expr.CastStrict)
+}
+func (s *scalarUDFExpr) TryCast(dataType arrow.DataType) expr.Expr {
+	return expr.NewCastExpr(s, dataType, expr.CastSafe)
+}
 func (s *scalarUDFExpr) Contains(sub expr.Expr) expr.Expr {
 	return expr.NewBinaryExpr(s, sub, "contains")
 }
@@ -290,8 +333,21 @@ func (v *vectorUDFExpr) Evaluate(df *core.DataFrame) (arrow.Array, error) {
 	return v.fn(columns)
 }
 
-func (v *vectorUDFExpr) Name() string   { return "vector_udf" }
-func (v *vectorUDFExpr) String() string { return "VectorUDF(...)" }
+func (v *vectorUDFExpr) EvaluateRecord(record arrow.Record) (arrow.Array, error) {
+	return expr.EvaluateRecord(v, record)
+}
+
+func (v *vectorUDFExpr) Name() string          { return "vector_udf" }
+func (v *vectorUDFExpr) String() string        { return "VectorUDF(...)" }
+func (v *vectorUDFExpr) Children() []expr.Expr { return nil }
+func (v *vectorUDFExpr) Op() string            { return "vector_udf" }
+func (v *vectorUDFExpr) Columns() []string     { return v.inputCols }
+func (v *vectorUDFExpr) WithChildren(children []expr.Expr) (expr.Expr, error) {
+	if len(children) != 0 {
+		return nil, fmt.Errorf("vector_udf expression has no children, got %d", len(children))
+	}
+	return v, nil
+}
 func (v *vectorUDFExpr) Add(other expr.Expr) expr.Expr {
 	return expr.NewBinaryExpr(v, other, "add")
 }
@@ -313,6 +369,36 @@ func (v *vectorUDFExpr) Lt(other expr.Expr) expr.Expr {
 func (v *vectorUDFExpr) Eq(other expr.Expr) expr.Expr {
 	return expr.NewBinaryExpr(v, other, "eq")
 }
+func (v *vectorUDFExpr) Ne(other expr.Expr) expr.Expr {
+	return expr.NewBinaryExpr(v, other, "not_equal")
+}
+func (v *vectorUDFExpr) Ge(other expr.Expr) expr.Expr {
+	return expr.NewBinaryExpr(v, other, "greater_equal")
+}
+func (v *vectorUDFExpr) Le(other expr.Expr) expr.Expr {
+	return expr.NewBinaryExpr(v, other, "less_equal")
+}
+func (v *vectorUDFExpr) And(other expr.Expr) expr.Expr {
+	return expr.NewBinaryExpr(v, other, "and")
+}
+func (v *vectorUDFExpr) Or(other expr.Expr) expr.Expr {
+	return expr.NewBinaryExpr(v, other, "or")
+}
+func (v *vectorUDFExpr) Not() expr.Expr       { return expr.NewUnaryExpr(v, "not") }
+func (v *vectorUDFExpr) IsNull() expr.Expr    { return expr.NewUnaryExpr(v, "is_null") }
+func (v *vectorUDFExpr) IsNotNull() expr.Expr { return expr.NewUnaryExpr(v, "is_not_null") }
+func (v *vectorUDFExpr) FillNull(value expr.Expr) expr.Expr {
+	return expr.Coalesce(v, value)
+}
+func (v *vectorUDFExpr) NullIf(value expr.Expr) expr.Expr {
+	return expr.NewBinaryExpr(v, value, "null_if")
+}
+func (v *vectorUDFExpr) Cast(dataType arrow.DataType) expr.Expr {
+	return expr.NewCastExpr(v, dataType, expr.CastStrict)
+}
+func (v *vectorUDFExpr) TryCast(dataType arrow.DataType) expr.Expr {
+	return expr.NewCastExpr(v, dataType, expr.CastSafe)
+}
 func (v *vectorUDFExpr) Contains(sub expr.Expr) expr.Expr {
 	return expr.NewBinaryExpr(v, sub, "contains")
 }
@@ -177,107 +177,57 @@ func (df *DataFrame) PivotMulti(indexCols []string, pivotCol string, valueCols [
 	pool := memory.NewGoAllocator()
 	record := df.coreDF.Record()
 	schema := record.Schema()
-	numRows := int(record.NumRows())
 
 	pivotIdx := findColIdx(schema, pivotCol)
 	pivotArr := record.Column(pivotIdx)
-
-	// Get unique pivot values
-	pivotValues := make(map[string]bool)
-	for i := 0; i < numRows; i++ {
-		if !pivotArr.IsNull(i) {
-			pivotValues[getStringValue(pivotArr, i)] = true
-		}
-	}
-	pivotKeys := sortedKeys(pivotValues)
-
-	// Build lookup: compositeKey + pivotValue -> map[valueCol]value
-	type cellKey struct {
-		indexKey string
-		pivotVal string
-	}
-	lookup := make(map[cellKey]map[string]interface{})
-	indexKeySet := make(map[string]bool)
-	var indexKeyOrder []string
-
 	indexIndices := make([]int, len(indexCols))
 	for i, col := range indexCols {
 		indexIndices[i] = findColIdx(schema, col)
 	}
 
-	for i := 0; i < numRows; i++ {
-		var parts []string
-		for _, idx := range indexIndices {
-			parts = append(parts, getStringValue(record.Column(idx), i))
-		}
-		ik := fmt.Sprintf("%v", parts)
-		if !indexKeySet[ik] {
-			indexKeySet[ik] = true
-			indexKeyOrder = append(indexKeyOrder, ik)
-		}
-
-		pv := getStringValue(pivotArr, i)
-		ck := cellKey{indexKey: ik, pivotVal: pv}
-		if lookup[ck] == nil {
-			lookup[ck] = make(map[string]interface{})
-		}
-		for _, vc := range valueCols {
-			vcIdx := findColIdx(schema, vc)
-			lookup[ck][vc] = getTypedValue(record.Column(vcIdx), i)
-		}
-	}
-
-	// Build index first-row mapping
-	indexFirstRow := make(map[string]int)
-	for i := 0; i < numRows; i++ {
-		var parts []string
-		for _, idx := range indexIndices {
-			parts = append(parts, getStringValue(record.Column(idx), i))
-		}
-		ik := fmt.Sprintf("%v", parts)
-		if _, exists := indexFirstRow[ik]; !exists {
-			indexFirstRow[ik] = i
-		}
+	indexRows, pivotRows, cells, err := pivotCells(record, indexIndices, pivotIdx)
+	if err != nil {
+		return &DataFrame{err: err}
 	}
 
-	resultRows := len(indexKeyOrder)
+	resultRows := len(indexRows)
 	var resultFields []arrow.Field
 	var resultColumns []arrow.Array
+	defer func() {
+		for _, col := range resultColumns {
+			col.Release()
+		}
+	}()
 
 	// Add index columns
-	for _, ic := range indexCols {
-		idx := findColIdx(schema, ic)
-		resultFields = append(resultFields, schema.Field(idx))
-		srcArr := record.Column(idx)
-		b := array.NewStringBuilder(pool)
-		for _, ik := range indexKeyOrder {
-			ri := indexFirstRow[ik]
-			b.Append(getStringValue(srcArr, ri))
+	for _, idx := range indexIndices {
+		col, err := takeWithNulls(record.Column(idx), indexRows)
+		if err != nil {
+			return &DataFrame{err: fmt.Errorf("failed to build index column: %w", err)}
 		}
-		resultColumns = append(resultColumns, b.NewArray())
-		b.Release()
+		resultFields = append(resultFields, schema.Field(idx))
+		resultColumns = append(resultColumns, col)
 	}
 
 	// Add pivoted value columns: <pivotVal>_<valueCol>
-	for _, pv := range pivotKeys {
+	for p, rows := range cells {
+		pv := getStringValue(pivotArr, pivotRows[p])
 		for _, vc := range valueCols {
-			colName := pv + "_" + vc
-			resultFields = append(resultFields, arrow.Field{Name: colName, Type: arrow.PrimitiveTypes.Float64})
+			valueArr := record.Column(findColIdx(schema, vc))
+			resultFields = append(resultFields, arrow.Field{Name: pv + "_" + vc, Type: arrow.PrimitiveTypes.Float64})
 			b := array.NewFloat64Builder(pool)
-			for _, ik := range indexKeyOrder {
-				ck := cellKey{indexKey: ik, pivotVal: pv}
-				vals := lookup[ck]
-				if vals == nil || vals[vc] == nil {
+			for _, ri := range rows {
+				if ri < 0 {
+					b.AppendNull()
+					continue
+				}
+				switch v := getTypedValue(valueArr, ri).(type) {
+				case float64:
+					b.Append(v)
+				case int64:
+					b.Append(float64(v))
+				default:
 					b.AppendNull()
-				} else {
-					switch v := vals[vc].(type) {
-					case float64:
-						b.Append(v)
-					case int64:
-						b.Append(float64(v))
-					default:
-						b.AppendNull()
-					}
 				}
 			}
 			resultColumns = append(resultColumns, b.NewArray())
@@ -5,7 +5,10 @@ import (
 	"math"
 	"strings"
 
+	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/felixgeelhaar/GopherFrame/pkg/rowhash"
+	"github.com/felixgeelhaar/GopherFrame/pkg/sketch"
 )
 
 // ColumnStats holds descriptive statistics for a single column.
@@ -15,7 +18,7 @@ type ColumnStats struct {
 	Count      int64
 	NullCount  int64
 	NullPct    float64
-	Unique     int64
+	Unique     int64 // estimated with a HyperLogLog sketch, within about 1%
 	Min        interface{}
 	Max        interface{}
 	Mean       float64
@@ -55,14 +58,12 @@ func (df *DataFrame) Describe() ([]ColumnStats, error) {
 			cs.NullPct = float64(cs.NullCount) / float64(numRows) * 100
 		}
 
-		// Count unique values
-		unique := make(map[string]bool)
-		for j := 0; j < numRows; j++ {
-			if !col.IsNull(j) {
-				unique[getStringValue(col, j)] = true
-			}
+		// Estimate unique values, in constant memory however many there are
+		unique, err := approxDistinct(col)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count unique values of %s: %w", field.Name, err)
 		}
-		cs.Unique = int64(len(unique))
+		cs.Unique = unique
 
 		// Numeric stats
 		switch a := col.(type) {
@@ -354,6 +355,25 @@ func (df *DataFrame) Validate(rules ...ValidationRule) ValidationResult {
 	return result
 }
 
+// approxDistinct estimates the number of distinct non-null values of a
+// column from the typed hashes of its values.
+func approxDistinct(col arrow.Array) (int64, error) {
+	keys, err := rowhash.NewKeys(col)
+	if err != nil {
+		return 0, err
+	}
+	hll, err := sketch.NewHyperLogLog(sketch.DefaultPrecision)
+	if err != nil {
+		return 0, err
+	}
+	for row := 0; row < keys.Len(); row++ {
+		if !keys.HasNull(row) {
+			hll.AddHash(keys.Hash(row))
+		}
+	}
+	return int64(hll.Estimate()), nil
+}
+
 // DescribeString returns a formatted string representation of Describe() output.
 func (df *DataFrame) DescribeString() string {
 	stats, err := df.Describe()